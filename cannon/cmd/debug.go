@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"debug/elf"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+var (
+	DebugELFFlag = &cli.PathFlag{
+		Name:      "elf",
+		Usage:     "path of the ELF file of the program, to look up symbols in. Overrides --meta.",
+		TakesFile: true,
+		Required:  false,
+	}
+	DebugListenFlag = &cli.StringFlag{
+		Name:     "listen",
+		Usage:    "TCP address to serve the debugger on, e.g. 127.0.0.1:7777. The debugger reads commands from stdin if empty.",
+		Required: false,
+	}
+)
+
+// debugMaxMemoryDump bounds the size of a single memory dump.
+const debugMaxMemoryDump = 4096
+
+var errDebugQuit = errors.New("quit")
+
+const debugHelp = `Commands:
+  info                     show the step, PC, instruction, pre-image and state hash
+  regs                     show the registers of the current thread
+  mem <addr> [len]         dump len (default 64) bytes of memory at addr
+  break <symbol|0xaddr>    add a breakpoint at the start of a symbol, or at an address
+  delete <id>              remove a breakpoint
+  breakpoints              list the breakpoints
+  watch <preimages|hints>  report, and stop at, pre-image reads or hints
+  unwatch <preimages|hints>
+  step [n]                 execute n (default 1) instructions
+  continue                 execute until a breakpoint, a watched event or the exit of the program
+  goto <step>              execute until the step, ignoring breakpoints and watches
+  write <path>             write the current state, or a binary snapshot if the path ends in ` + binarySnapshotExt + `
+  quit                     end the debugging session`
+
+type debugBreakpoint struct {
+	id   int
+	addr uint64
+	desc string
+}
+
+// debugOracle forwards to the pre-image server, and records the hints of the guest to report them.
+type debugOracle struct {
+	po    mipsevm.PreimageOracle
+	hints [][]byte
+}
+
+func (o *debugOracle) Hint(v []byte) {
+	o.hints = append(o.hints, append([]byte(nil), v...))
+	o.po.Hint(v)
+}
+
+func (o *debugOracle) GetPreimage(k [32]byte) []byte {
+	return o.po.GetPreimage(k)
+}
+
+var _ mipsevm.PreimageOracle = (*debugOracle)(nil)
+
+// Debugger executes a VM interactively, with commands of a line-based protocol.
+// Every command is answered with its output, followed by a line with either "ok" or "error: <message>".
+type Debugger struct {
+	state  mipsevm.FPVMState
+	stepFn StepFn
+	meta   *mipsevm.Metadata
+	oracle *debugOracle
+
+	breakpoints    []debugBreakpoint
+	nextBreakpoint int
+	watchPreimages bool
+	watchHints     bool
+}
+
+func NewDebugger(state mipsevm.FPVMState, po mipsevm.PreimageOracle, meta *mipsevm.Metadata, l log.Logger) (*Debugger, error) {
+	oracle := &debugOracle{po: po}
+	outLog := &mipsevm.LoggingWriter{Name: "program std-out", Log: l}
+	errLog := &mipsevm.LoggingWriter{Name: "program std-err", Log: l}
+	us, err := newFPVM(state, oracle, outLog, errLog)
+	if err != nil {
+		return nil, err
+	}
+	return &Debugger{
+		state:          state,
+		stepFn:         us.Step,
+		meta:           meta,
+		oracle:         oracle,
+		nextBreakpoint: 1,
+	}, nil
+}
+
+// Serve executes the commands read from r, and writes the responses to w, until the quit command or the end of r.
+// It returns errDebugQuit if the session ended with the quit command.
+// Execution is interrupted, with an error response, when ctx is done.
+func (d *Debugger) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	out := bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		err := d.execute(ctx, out, args[0], args[1:])
+		if errors.Is(err, errDebugQuit) {
+			_, _ = fmt.Fprintln(out, "ok")
+			_ = out.Flush()
+			return err
+		} else if err != nil {
+			_, _ = fmt.Fprintf(out, "error: %v\n", err)
+		} else {
+			_, _ = fmt.Fprintln(out, "ok")
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (d *Debugger) execute(ctx context.Context, w io.Writer, cmd string, args []string) error {
+	switch cmd {
+	case "help", "h":
+		_, _ = fmt.Fprintln(w, debugHelp)
+		return nil
+	case "info", "i":
+		return d.info(w)
+	case "regs", "r":
+		return d.regs(w)
+	case "mem", "m":
+		return d.mem(w, args)
+	case "break", "b":
+		return d.addBreakpoint(w, args)
+	case "delete", "d":
+		return d.deleteBreakpoint(args)
+	case "breakpoints":
+		for _, bp := range d.breakpoints {
+			_, _ = fmt.Fprintf(w, "%d: %s at 0x%x\n", bp.id, bp.desc, bp.addr)
+		}
+		return nil
+	case "watch", "unwatch":
+		if len(args) != 1 {
+			return fmt.Errorf("expected 'preimages' or 'hints'")
+		}
+		switch args[0] {
+		case "preimages":
+			d.watchPreimages = cmd == "watch"
+		case "hints":
+			d.watchHints = cmd == "watch"
+		default:
+			return fmt.Errorf("cannot watch %q, expected 'preimages' or 'hints'", args[0])
+		}
+		return nil
+	case "step", "s":
+		n := uint64(1)
+		if len(args) > 0 {
+			var err error
+			if n, err = strconv.ParseUint(args[0], 0, 64); err != nil {
+				return fmt.Errorf("invalid step count %q: %w", args[0], err)
+			}
+		}
+		for i := uint64(0); i < n; i++ {
+			if i > 0 && d.state.GetExited() {
+				break
+			}
+			if _, err := d.step(w, true); err != nil {
+				return err
+			}
+		}
+		return d.location(w)
+	case "continue", "c":
+		return d.cont(ctx, w)
+	case "goto", "g":
+		if len(args) != 1 {
+			return fmt.Errorf("expected a step")
+		}
+		target, err := strconv.ParseUint(args[0], 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid step %q: %w", args[0], err)
+		}
+		if target < d.state.GetStep() {
+			return fmt.Errorf("cannot go back to step %d from step %d", target, d.state.GetStep())
+		}
+		for d.state.GetStep() < target && !d.state.GetExited() {
+			if d.state.GetStep()%100 == 0 { // don't do the ctx err check (includes lock) too often
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			if _, err := d.step(w, false); err != nil {
+				return err
+			}
+		}
+		return d.location(w)
+	case "write", "w":
+		if len(args) != 1 {
+			return fmt.Errorf("expected a path")
+		}
+		return writeState(args[0], d.state)
+	case "quit", "q":
+		return errDebugQuit
+	default:
+		return fmt.Errorf("unknown command %q, see help", cmd)
+	}
+}
+
+// step executes a single instruction, and reports the pre-image reads and hints of the step if report is true.
+// It returns true if a watched event happened.
+func (d *Debugger) step(w io.Writer, report bool) (bool, error) {
+	if d.state.GetExited() {
+		return false, fmt.Errorf("program exited with code %d", d.state.GetExitCode())
+	}
+	prevPreimageOffset := d.state.GetPreimageOffset()
+	d.oracle.hints = d.oracle.hints[:0]
+	if _, err := d.stepFn(false); err != nil {
+		return false, fmt.Errorf("failed at step %d (PC: %08x): %w", d.state.GetStep(), d.state.GetPC(), err)
+	}
+	watched := false
+	if offset := d.state.GetPreimageOffset(); offset > prevPreimageOffset {
+		if report && d.watchPreimages {
+			_, _ = fmt.Fprintf(w, "pre-image read: key %s, offset %d to %d\n", d.state.GetPreimageKey(), prevPreimageOffset, offset)
+		}
+		watched = watched || d.watchPreimages
+	}
+	for _, hint := range d.oracle.hints {
+		if report && d.watchHints {
+			_, _ = fmt.Fprintf(w, "hint: %s\n", formatHint(hint))
+		}
+		watched = watched || d.watchHints
+	}
+	return watched, nil
+}
+
+func (d *Debugger) cont(ctx context.Context, w io.Writer) error {
+	for first := true; !d.state.GetExited(); first = false {
+		if d.state.GetStep()%100 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if bp, ok := d.breakpointAt(d.state.GetPC()); ok && !first {
+			_, _ = fmt.Fprintf(w, "breakpoint %d: %s\n", bp.id, bp.desc)
+			break
+		}
+		watched, err := d.step(w, true)
+		if err != nil {
+			return err
+		}
+		if watched {
+			break
+		}
+	}
+	if d.state.GetExited() {
+		_, _ = fmt.Fprintf(w, "exited with code %d\n", d.state.GetExitCode())
+	}
+	return d.location(w)
+}
+
+func (d *Debugger) breakpointAt(pc uint64) (debugBreakpoint, bool) {
+	for _, bp := range d.breakpoints {
+		if bp.addr == pc {
+			return bp, true
+		}
+	}
+	return debugBreakpoint{}, false
+}
+
+func (d *Debugger) addBreakpoint(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a symbol or an address")
+	}
+	bp := debugBreakpoint{id: d.nextBreakpoint, desc: args[0]}
+	if strings.HasPrefix(args[0], "0x") {
+		addr, err := strconv.ParseUint(args[0], 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %w", args[0], err)
+		}
+		bp.addr = addr
+		bp.desc = d.meta.LookupSymbol(addr)
+	} else {
+		found := false
+		for _, sym := range d.meta.Symbols {
+			if sym.Name == args[0] {
+				bp.addr, found = sym.Start, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown symbol %q", args[0])
+		}
+	}
+	d.nextBreakpoint++
+	d.breakpoints = append(d.breakpoints, bp)
+	_, _ = fmt.Fprintf(w, "breakpoint %d: %s at 0x%x\n", bp.id, bp.desc, bp.addr)
+	return nil
+}
+
+func (d *Debugger) deleteBreakpoint(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a breakpoint id")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid breakpoint id %q: %w", args[0], err)
+	}
+	for i, bp := range d.breakpoints {
+		if bp.id == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no breakpoint %d", id)
+}
+
+func (d *Debugger) location(w io.Writer) error {
+	_, _ = fmt.Fprintf(w, "step %d: pc 0x%x insn 0x%08x in %s\n",
+		d.state.GetStep(), d.state.GetPC(), d.state.GetInstruction(), d.meta.LookupSymbol(d.state.GetPC()))
+	return nil
+}
+
+func (d *Debugger) info(w io.Writer) error {
+	if err := d.location(w); err != nil {
+		return err
+	}
+	stateHash, err := d.state.EncodeWitness().StateHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash state: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "exited: %v, exit code: %d\n", d.state.GetExited(), d.state.GetExitCode())
+	_, _ = fmt.Fprintf(w, "pre-image key: %s, offset: %d\n", d.state.GetPreimageKey(), d.state.GetPreimageOffset())
+	_, _ = fmt.Fprintf(w, "memory: %d pages, %s\n", d.state.GetMemory().PageCount(), d.state.GetMemory().Usage())
+	_, _ = fmt.Fprintf(w, "state hash: %s\n", stateHash)
+	return nil
+}
+
+func (d *Debugger) regs(w io.Writer) error {
+	regs, hi, lo, err := debugRegisters(d.state)
+	if err != nil {
+		return err
+	}
+	for i, r := range regs {
+		_, _ = fmt.Fprintf(w, "r%-2d 0x%x\n", i, r)
+	}
+	_, _ = fmt.Fprintf(w, "hi  0x%x\nlo  0x%x\n", hi, lo)
+	return nil
+}
+
+func (d *Debugger) mem(w io.Writer, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("expected an address and an optional length")
+	}
+	addr, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", args[0], err)
+	}
+	length := uint64(64)
+	if len(args) == 2 {
+		if length, err = strconv.ParseUint(args[1], 0, 64); err != nil {
+			return fmt.Errorf("invalid length %q: %w", args[1], err)
+		}
+	}
+	if length > debugMaxMemoryDump {
+		return fmt.Errorf("length %d exceeds the maximum of %d", length, debugMaxMemoryDump)
+	}
+	data, err := debugReadMemory(d.state, addr, length)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(data); i += 16 {
+		end := min(i+16, len(data))
+		_, _ = fmt.Fprintf(w, "0x%08x: %s\n", addr+uint64(i), hex.EncodeToString(data[i:end]))
+	}
+	return nil
+}
+
+// debugRegisters returns the registers of the state, or of its current thread for a multi-threaded state.
+func debugRegisters(state mipsevm.FPVMState) (regs []uint64, hi uint64, lo uint64, err error) {
+	switch st := state.(type) {
+	case *mipsevm.State:
+		for _, r := range st.Registers {
+			regs = append(regs, uint64(r))
+		}
+		return regs, uint64(st.HI), uint64(st.LO), nil
+	case *mipsevm.MTState:
+		thread := st.CurrentThread()
+		if thread == nil {
+			return nil, 0, 0, errors.New("no current thread")
+		}
+		for _, r := range thread.Registers {
+			regs = append(regs, uint64(r))
+		}
+		return regs, uint64(thread.HI), uint64(thread.LO), nil
+	case *mipsevm.State64:
+		return st.Registers[:], st.HI, st.LO, nil
+	default:
+		return nil, 0, 0, fmt.Errorf("unsupported state type %T", state)
+	}
+}
+
+func debugReadMemory(state mipsevm.FPVMState, addr uint64, length uint64) ([]byte, error) {
+	var r io.Reader
+	switch st := state.(type) {
+	case *mipsevm.State:
+		if addr+length > 1<<32 {
+			return nil, fmt.Errorf("range exceeds the 32-bit address space")
+		}
+		r = st.Memory.ReadMemoryRange(uint32(addr), uint32(length))
+	case *mipsevm.MTState:
+		if addr+length > 1<<32 {
+			return nil, fmt.Errorf("range exceeds the 32-bit address space")
+		}
+		r = st.Memory.ReadMemoryRange(uint32(addr), uint32(length))
+	case *mipsevm.State64:
+		r = st.Memory.ReadMemoryRange(addr, length)
+	default:
+		return nil, fmt.Errorf("unsupported state type %T", state)
+	}
+	return io.ReadAll(r)
+}
+
+// formatHint formats hints, which are usually text, as text if possible and as hex otherwise.
+func formatHint(hint []byte) string {
+	if utf8.Valid(hint) && strings.IndexFunc(string(hint), func(r rune) bool { return r < ' ' }) < 0 {
+		return string(hint)
+	}
+	return "0x" + hex.EncodeToString(hint)
+}
+
+func Debug(ctx *cli.Context) error {
+	return debug(ctx, os.Stdin, os.Stdout, os.Stderr)
+}
+
+func debug(ctx *cli.Context, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	l := Logger(stderr, log.LevelInfo)
+	state, err := loadState(ctx, ctx.Path(RunInputFlag.Name))
+	if err != nil {
+		return err
+	}
+	var meta *mipsevm.Metadata
+	if elfPath := ctx.Path(DebugELFFlag.Name); elfPath != "" {
+		elfProgram, err := elf.Open(elfPath)
+		if err != nil {
+			return fmt.Errorf("failed to open ELF file %q: %w", elfPath, err)
+		}
+		defer elfProgram.Close()
+		if meta, err = mipsevm.MakeMetadata(elfProgram); err != nil {
+			return fmt.Errorf("failed to compute program metadata: %w", err)
+		}
+	} else if meta, err = loadMetadata(l, ctx.Path(RunMetaFlag.Name)); err != nil {
+		return err
+	}
+	addr := ctx.String(DebugListenFlag.Name)
+	serverOut := stdout
+	if addr == "" {
+		// The debugger responds on stdout, so the output of the pre-image server must not be mixed into it.
+		serverOut = stderr
+	}
+	args := preimageServerArgs(ctx)
+	po, err := NewProcessPreimageOracle(args[0], args[1:], serverOut, stderr)
+	if err != nil {
+		return fmt.Errorf("failed to create pre-image oracle process: %w", err)
+	}
+	if err := po.Start(); err != nil {
+		return fmt.Errorf("failed to start pre-image oracle server: %w", err)
+	}
+	defer func() {
+		if err := po.Close(); err != nil {
+			l.Error("failed to close pre-image server", "err", err)
+		}
+	}()
+
+	debugger, err := NewDebugger(state, po, meta, l)
+	if err != nil {
+		return err
+	}
+	if po.cmd != nil {
+		debugger.stepFn = Guard(po.cmd.ProcessState, debugger.stepFn)
+	}
+
+	if addr == "" {
+		if err := debugger.Serve(ctx.Context, stdin, stdout); err != nil && !errors.Is(err, errDebugQuit) {
+			return err
+		}
+		return nil
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	defer listener.Close()
+	go func() {
+		<-ctx.Context.Done()
+		_ = listener.Close()
+	}()
+	l.Info("Serving debugger", "addr", listener.Addr())
+	// Serve one client at a time, keeping the state between connections until a client quits.
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Context.Err() != nil {
+				return ctx.Context.Err()
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		l.Info("Debugger client connected", "remote", conn.RemoteAddr())
+		err = debugger.Serve(ctx.Context, conn, conn)
+		_ = conn.Close()
+		if errors.Is(err, errDebugQuit) {
+			return nil
+		} else if err != nil {
+			l.Warn("Debugger connection failed", "err", err)
+		}
+	}
+}
+
+var DebugCommand = &cli.Command{
+	Name:  "debug",
+	Usage: "Debug the guest program interactively.",
+	Description: "Loads a VM state and serves a debugger with breakpoints, stepping, register and memory inspection, " +
+		"and reports of pre-image reads and hints, over a line-based protocol on stdin or a TCP address. " +
+		"Every command is answered with its output, followed by a line with 'ok' or 'error: <message>'. " +
+		"The pre-image server command is passed after --, like with the run command. Send 'help' to list the commands.",
+	Action: Debug,
+	Flags: []cli.Flag{
+		RunInputFlag,
+		VMTypeFlag,
+		RunMetaFlag,
+		DebugELFFlag,
+		DebugListenFlag,
+	},
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+type stubOracle struct {
+	hints []string
+}
+
+func (o *stubOracle) Hint(v []byte) {
+	o.hints = append(o.hints, string(v))
+}
+
+func (o *stubOracle) GetPreimage(k [32]byte) []byte {
+	panic("unexpected pre-image request")
+}
+
+func debugSession(t *testing.T, d *Debugger, commands ...string) []string {
+	var out bytes.Buffer
+	err := d.Serve(context.Background(), strings.NewReader(strings.Join(commands, "\n")), &out)
+	if !errors.Is(err, errDebugQuit) {
+		require.NoError(t, err)
+	}
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestDebuggerHello(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+	state, err := mipsevm.LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, mipsevm.PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, mipsevm.PatchStack(state), "add initial stack")
+	meta, err := mipsevm.MakeMetadata(elfProgram)
+	require.NoError(t, err)
+	var mainPC uint64
+	for _, sym := range meta.Symbols {
+		if sym.Name == "main.main" {
+			mainPC = sym.Start
+		}
+	}
+	require.NotZero(t, mainPC)
+
+	d, err := NewDebugger(state, &stubOracle{}, meta, log.New())
+	require.NoError(t, err)
+
+	out := debugSession(t, d, "break main.main", "break no.such.symbol", "continue")
+	require.Equal(t, fmt.Sprintf("breakpoint 1: main.main at 0x%x", mainPC), out[0])
+	require.Equal(t, "ok", out[1])
+	require.Equal(t, `error: unknown symbol "no.such.symbol"`, out[2])
+	require.Equal(t, "breakpoint 1: main.main", out[3])
+	require.Equal(t, uint64(state.PC), mainPC)
+	breakStep := state.Step
+
+	out = debugSession(t, d, "step 2", "regs", "mem 0x1000 20", "goto 1")
+	require.Equal(t, fmt.Sprintf("step %d: pc 0x%x insn 0x%08x in main.main", breakStep+2, state.PC, state.GetInstruction()), out[0])
+	require.Contains(t, out, fmt.Sprintf("r29 0x%x", state.Registers[29]))
+	require.Contains(t, out, "0x00001000: 00000000000000000000000000000000")
+	require.Contains(t, out, "0x00001010: 00000000")
+	require.Equal(t, fmt.Sprintf("error: cannot go back to step 1 from step %d", breakStep+2), out[len(out)-1])
+
+	out = debugSession(t, d, "delete 1", "breakpoints", "continue", "step", "quit")
+	require.True(t, state.Exited)
+	require.Equal(t, []string{
+		"ok",
+		"ok",
+		"exited with code 0",
+		fmt.Sprintf("step %d: pc 0x%x insn 0x%08x in %s", state.Step, state.PC, state.GetInstruction(), meta.LookupSymbol(uint64(state.PC))),
+		"ok",
+		"error: program exited with code 0",
+		"ok",
+	}, out)
+}
+
+func TestDebuggerWatchHints(t *testing.T) {
+	state := &mipsevm.State{PC: 0x1000, NextPC: 0x1004, Memory: mipsevm.NewMemory()}
+	state.Memory.SetMemory(0x1000, 0x0000000C) // syscall
+	state.Memory.SetMemory(0x1004, 0x0000000C) // syscall
+	require.NoError(t, state.Memory.SetMemoryRange(0x2000, bytes.NewReader([]byte{0, 0, 0, 5, 'h', 'e', 'l', 'l', 'o'})))
+	state.Registers[2] = 4004 // write
+	state.Registers[4] = 4    // hint write fd
+	state.Registers[5] = 0x2000
+	state.Registers[6] = 9
+	oracle := &stubOracle{}
+	d, err := NewDebugger(state, oracle, &mipsevm.Metadata{}, log.New())
+	require.NoError(t, err)
+
+	out := debugSession(t, d, "watch hints", "continue", "watch other")
+	require.Equal(t, []string{
+		"ok",
+		"hint: hello",
+		"step 1: pc 0x1004 insn 0x0000000c in !unknown",
+		"ok",
+		`error: cannot watch "other", expected 'preimages' or 'hints'`,
+	}, out)
+	require.Equal(t, []string{"hello"}, oracle.hints, "hints must be forwarded to the pre-image server")
+}
+
+func TestDebugKeepsServerOutputOffProtocol(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+	require.NoError(t, writeJSON(statePath, &mipsevm.State{PC: 0x1000, NextPC: 0x1004, Memory: mipsevm.NewMemory()}))
+	stderr, err := os.CreateTemp(dir, "stderr")
+	require.NoError(t, err)
+	defer stderr.Close()
+
+	var stdout bytes.Buffer
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{{
+		Name:  DebugCommand.Name,
+		Flags: DebugCommand.Flags,
+		Action: func(ctx *cli.Context) error {
+			return debug(ctx, strings.NewReader("breakpoints\nquit\n"), &stdout, stderr)
+		},
+	}}
+	// The pre-image server logs to stdout, like the op-program host
+	err = app.Run([]string{"cannon", "debug", "--input", statePath, "--meta", "", "--", "sh", "-c", "echo host log"})
+	require.NoError(t, err)
+
+	require.Equal(t, "ok\nok\n", stdout.String(), "protocol stream must only carry responses")
+	logs, err := os.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(logs), "host log")
+}
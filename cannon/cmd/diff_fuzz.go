@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+var (
+	DiffFuzzSeedFlag = &cli.Int64Flag{
+		Name:  "seed",
+		Usage: "seed of the first random program, the following programs use the next seeds. Random if 0.",
+	}
+	DiffFuzzProgramsFlag = &cli.IntFlag{
+		Name:  "programs",
+		Usage: "number of random programs to execute",
+		Value: 1000,
+	}
+	DiffFuzzLengthFlag = &cli.IntFlag{
+		Name:  "length",
+		Usage: "number of instructions of each random program",
+		Value: 50,
+	}
+	DiffFuzzStepsFlag = &cli.IntFlag{
+		Name:  "steps",
+		Usage: "maximum number of steps to execute of each program",
+		Value: 500,
+	}
+	DiffFuzzInputFlag = &cli.PathFlag{
+		Name:      "input",
+		Usage:     "path of a program that diverged before, to reproduce the divergence instead of executing random programs",
+		TakesFile: true,
+	}
+	DiffFuzzOutputDirFlag = &cli.PathFlag{
+		Name:  "output-dir",
+		Usage: "directory to write programs that diverge to, to reproduce them with --input",
+		Value: ".",
+	}
+	DiffFuzzEVMTraceFlag = &cli.BoolFlag{
+		Name:  "evm-trace",
+		Usage: "trace the execution of the MIPS contract to stdout",
+	}
+)
+
+func DiffFuzz(ctx *cli.Context) error {
+	l := Logger(os.Stderr, log.LevelInfo)
+	contracts, err := mipsevm.LoadContracts()
+	if err != nil {
+		return fmt.Errorf("failed to load contracts: %w", err)
+	}
+	addrs := &mipsevm.Addresses{
+		MIPS:         common.Address{0: 0xff, 19: 1},
+		Oracle:       common.Address{0: 0xff, 19: 2},
+		Sender:       common.Address{0x13, 0x37},
+		FeeRecipient: common.Address{0xaa},
+	}
+	newEVM := func() *mipsevm.MIPSEVM {
+		evm := mipsevm.NewMIPSEVM(contracts, addrs)
+		if ctx.Bool(DiffFuzzEVMTraceFlag.Name) {
+			evm.SetTracer(logger.NewMarkdownLogger(&logger.Config{}, os.Stdout))
+		}
+		return evm
+	}
+	steps := ctx.Int(DiffFuzzStepsFlag.Name)
+
+	if input := ctx.Path(DiffFuzzInputFlag.Name); input != "" {
+		prog, err := loadJSON[mipsevm.DiffProgram](input)
+		if err != nil {
+			return fmt.Errorf("invalid input program (%v): %w", input, err)
+		}
+		err = mipsevm.DiffExecute(newEVM(), prog, steps)
+		if errors.Is(err, mipsevm.ErrDiffSkipped) {
+			l.Warn("Skipped, agreement can not be verified", "input", input, "err", err)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		l.Info("No divergence", "input", input)
+		return nil
+	}
+
+	seed := ctx.Int64(DiffFuzzSeedFlag.Name)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	programs := ctx.Int(DiffFuzzProgramsFlag.Name)
+	length := ctx.Int(DiffFuzzLengthFlag.Name)
+	outDir := ctx.Path(DiffFuzzOutputDirFlag.Name)
+	l.Info("Executing random programs", "seed", seed, "programs", programs, "length", length, "steps", steps)
+	divergences := 0
+	skipped := 0
+	for i := 0; i < programs; i++ {
+		if err := ctx.Context.Err(); err != nil {
+			return err
+		}
+		progSeed := seed + int64(i)
+		prog := mipsevm.RandomDiffProgram(rand.New(rand.NewSource(progSeed)), length)
+		err := mipsevm.DiffExecute(newEVM(), prog, steps)
+		var divergence *mipsevm.Divergence
+		if errors.As(err, &divergence) {
+			divergences++
+			path := filepath.Join(outDir, fmt.Sprintf("divergence-%d.json", progSeed))
+			l.Error("Divergence", "seed", progSeed, "program", path, "err", divergence)
+			if err := writeJSON(path, prog); err != nil {
+				return fmt.Errorf("failed to write diverging program: %w", err)
+			}
+		} else if errors.Is(err, mipsevm.ErrDiffSkipped) {
+			skipped++
+			l.Debug("Skipped", "seed", progSeed, "err", err)
+		} else if err != nil {
+			return fmt.Errorf("failed to execute program with seed %d: %w", progSeed, err)
+		}
+	}
+	if divergences > 0 {
+		return fmt.Errorf("%d of %d programs diverged", divergences, programs)
+	}
+	l.Info("No divergences", "programs", programs, "skipped", skipped)
+	return nil
+}
+
+var DiffFuzzCommand = &cli.Command{
+	Name:  "diff-fuzz",
+	Usage: "Compare the Go VM against the MIPS contract with random programs",
+	Description: "Executes random programs, that cover every implemented instruction, step by step with both the Go VM and the MIPS contract, " +
+		"and reports the programs that they produce different post-states for. Any divergence is a consensus bug in the fault proof VM.",
+	Action: DiffFuzz,
+	Flags: []cli.Flag{
+		DiffFuzzSeedFlag,
+		DiffFuzzProgramsFlag,
+		DiffFuzzLengthFlag,
+		DiffFuzzStepsFlag,
+		DiffFuzzInputFlag,
+		DiffFuzzOutputDirFlag,
+		DiffFuzzEVMTraceFlag,
+	},
+}
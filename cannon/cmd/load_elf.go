@@ -18,7 +18,7 @@ var (
 	}
 	LoadELFPatchFlag = &cli.StringSliceFlag{
 		Name:     "patch",
-		Usage:    "Type of patching to do",
+		Usage:    "Type of patching to do. The go patch is not applied to multi-threaded VMs as the Go runtime can run normally.",
 		Value:    cli.NewStringSlice("go", "stack"),
 		Required: false,
 	}
@@ -43,33 +43,81 @@ func LoadELF(ctx *cli.Context) error {
 		return fmt.Errorf("failed to open ELF file %q: %w", elfPath, err)
 	}
 	if elfProgram.Machine != elf.EM_MIPS {
-		return fmt.Errorf("ELF is not big-endian MIPS R3000, but got %q", elfProgram.Machine.String())
+		return fmt.Errorf("ELF is not big-endian MIPS, but got %q", elfProgram.Machine.String())
 	}
+	var state any
+	switch vmType := ctx.String(VMTypeFlag.Name); vmType {
+	case vmTypeCannon, vmTypeCannonMT:
+		st, err := loadELF32(elfProgram, ctx.StringSlice(LoadELFPatchFlag.Name), vmType == vmTypeCannonMT)
+		if err != nil {
+			return err
+		}
+		state = st
+		if vmType == vmTypeCannonMT {
+			state = mipsevm.NewMTState(st)
+		}
+	case vmTypeCannon64:
+		st, err := loadELF64(elfProgram, ctx.StringSlice(LoadELFPatchFlag.Name))
+		if err != nil {
+			return err
+		}
+		state = st
+	default:
+		return fmt.Errorf("unknown VM type %q", vmType)
+	}
+	meta, err := mipsevm.MakeMetadata(elfProgram)
+	if err != nil {
+		return fmt.Errorf("failed to compute program metadata: %w", err)
+	}
+	if err := writeJSON[*mipsevm.Metadata](ctx.Path(LoadELFMetaFlag.Name), meta); err != nil {
+		return fmt.Errorf("failed to output metadata: %w", err)
+	}
+	return writeJSON[any](ctx.Path(LoadELFOutFlag.Name), state)
+}
+
+func loadELF32(elfProgram *elf.File, patches []string, multiThreaded bool) (*mipsevm.State, error) {
 	state, err := mipsevm.LoadELF(elfProgram)
 	if err != nil {
-		return fmt.Errorf("failed to load ELF data into VM state: %w", err)
+		return nil, fmt.Errorf("failed to load ELF data into VM state: %w", err)
 	}
-	for _, typ := range ctx.StringSlice(LoadELFPatchFlag.Name) {
+	for _, typ := range patches {
 		switch typ {
 		case "stack":
 			err = mipsevm.PatchStack(state)
 		case "go":
+			if multiThreaded {
+				continue
+			}
 			err = mipsevm.PatchGo(elfProgram, state)
 		default:
-			return fmt.Errorf("unrecognized form of patching: %q", typ)
+			return nil, fmt.Errorf("unrecognized form of patching: %q", typ)
 		}
 		if err != nil {
-			return fmt.Errorf("failed to apply patch %s: %w", typ, err)
+			return nil, fmt.Errorf("failed to apply patch %s: %w", typ, err)
 		}
 	}
-	meta, err := mipsevm.MakeMetadata(elfProgram)
+	return state, nil
+}
+
+func loadELF64(elfProgram *elf.File, patches []string) (*mipsevm.State64, error) {
+	state, err := mipsevm.LoadELF64(elfProgram)
 	if err != nil {
-		return fmt.Errorf("failed to compute program metadata: %w", err)
+		return nil, fmt.Errorf("failed to load ELF data into VM state: %w", err)
 	}
-	if err := writeJSON[*mipsevm.Metadata](ctx.Path(LoadELFMetaFlag.Name), meta); err != nil {
-		return fmt.Errorf("failed to output metadata: %w", err)
+	for _, typ := range patches {
+		switch typ {
+		case "stack":
+			err = mipsevm.PatchStack64(state)
+		case "go":
+			err = mipsevm.PatchGo64(elfProgram, state)
+		default:
+			return nil, fmt.Errorf("unrecognized form of patching: %q", typ)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply patch %s: %w", typ, err)
+		}
 	}
-	return writeJSON[*mipsevm.State](ctx.Path(LoadELFOutFlag.Name), state)
+	return state, nil
 }
 
 var LoadELFCommand = &cli.Command{
@@ -82,5 +130,6 @@ var LoadELFCommand = &cli.Command{
 		LoadELFPatchFlag,
 		LoadELFOutFlag,
 		LoadELFMetaFlag,
+		VMTypeFlag,
 	},
 }
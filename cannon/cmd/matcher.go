@@ -8,7 +8,7 @@ import (
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
 )
 
-type StepMatcher func(st *mipsevm.State) bool
+type StepMatcher func(st mipsevm.FPVMState) bool
 
 type StepMatcherFlag struct {
 	repr    string
@@ -25,34 +25,75 @@ func MustStepMatcherFlag(pattern string) *StepMatcherFlag {
 
 func (m *StepMatcherFlag) Set(value string) error {
 	m.repr = value
-	if value == "" || value == "never" {
-		m.matcher = func(st *mipsevm.State) bool {
-			return false
-		}
-	} else if value == "always" {
-		m.matcher = func(st *mipsevm.State) bool {
-			return true
+	// A comma-separated list of patterns matches any step that is matched by one of the patterns.
+	// Exact steps are collected into a set, so long lists of them are checked with a single lookup per step.
+	exact := make(map[uint64]struct{})
+	var matchers []StepMatcher
+	for _, part := range strings.Split(value, ",") {
+		if strings.HasPrefix(part, "=") {
+			when, err := parseExactStep(part)
+			if err != nil {
+				return err
+			}
+			exact[when] = struct{}{}
+			continue
 		}
-	} else if strings.HasPrefix(value, "=") {
-		when, err := strconv.ParseUint(value[1:], 0, 64)
+		matcher, err := parseStepMatcher(part)
 		if err != nil {
-			return fmt.Errorf("failed to parse step number: %w", err)
+			return err
 		}
-		m.matcher = func(st *mipsevm.State) bool {
-			return st.Step == when
+		matchers = append(matchers, matcher)
+	}
+	if len(exact) > 0 {
+		matchers = append(matchers, func(st mipsevm.FPVMState) bool {
+			_, ok := exact[st.GetStep()]
+			return ok
+		})
+	}
+	if len(matchers) == 1 {
+		m.matcher = matchers[0]
+		return nil
+	}
+	m.matcher = func(st mipsevm.FPVMState) bool {
+		for _, matcher := range matchers {
+			if matcher(st) {
+				return true
+			}
 		}
+		return false
+	}
+	return nil
+}
+
+func parseExactStep(value string) (uint64, error) {
+	when, err := strconv.ParseUint(value[1:], 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse step number: %w", err)
+	}
+	return when, nil
+}
+
+// parseStepMatcher parses a pattern other than an exact step, which Set collects separately.
+func parseStepMatcher(value string) (StepMatcher, error) {
+	if value == "" || value == "never" {
+		return func(st mipsevm.FPVMState) bool {
+			return false
+		}, nil
+	} else if value == "always" {
+		return func(st mipsevm.FPVMState) bool {
+			return true
+		}, nil
 	} else if strings.HasPrefix(value, "%") {
 		when, err := strconv.ParseUint(value[1:], 0, 64)
 		if err != nil {
-			return fmt.Errorf("failed to parse step interval number: %w", err)
-		}
-		m.matcher = func(st *mipsevm.State) bool {
-			return st.Step%when == 0
+			return nil, fmt.Errorf("failed to parse step interval number: %w", err)
 		}
+		return func(st mipsevm.FPVMState) bool {
+			return st.GetStep()%when == 0
+		}, nil
 	} else {
-		return fmt.Errorf("unrecognized step matcher: %q", value)
+		return nil, fmt.Errorf("unrecognized step matcher: %q", value)
 	}
-	return nil
 }
 
 func (m *StepMatcherFlag) String() string {
@@ -61,7 +102,7 @@ func (m *StepMatcherFlag) String() string {
 
 func (m *StepMatcherFlag) Matcher() StepMatcher {
 	if m.matcher == nil { // Set(value) is not called for omitted inputs, default to never matching.
-		return func(st *mipsevm.State) bool {
+		return func(st mipsevm.FPVMState) bool {
 			return false
 		}
 	}
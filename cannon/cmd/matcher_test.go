@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestStepMatcherFlag(t *testing.T) {
+	matches := func(pattern string, steps ...uint64) []bool {
+		flag := MustStepMatcherFlag(pattern)
+		var out []bool
+		for _, step := range steps {
+			out = append(out, flag.Matcher()(&mipsevm.State{Step: step}))
+		}
+		return out
+	}
+
+	t.Run("Never", func(t *testing.T) {
+		require.Equal(t, []bool{false, false}, matches("", 0, 1))
+		require.Equal(t, []bool{false, false}, matches("never", 0, 1))
+	})
+	t.Run("Always", func(t *testing.T) {
+		require.Equal(t, []bool{true, true}, matches("always", 0, 1))
+	})
+	t.Run("Exact", func(t *testing.T) {
+		require.Equal(t, []bool{false, true, false}, matches("=5", 4, 5, 6))
+	})
+	t.Run("Interval", func(t *testing.T) {
+		require.Equal(t, []bool{true, false, true}, matches("%5", 0, 4, 10))
+	})
+	t.Run("List", func(t *testing.T) {
+		require.Equal(t, []bool{true, true, false, true, true}, matches("=3,=7,%10", 3, 7, 8, 10, 20))
+	})
+	t.Run("ExactList", func(t *testing.T) {
+		require.Equal(t, []bool{false, true, true, false, true}, matches("=3,=7,=3,=1000000", 2, 3, 7, 8, 1000000))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, pattern := range []string{"=x", "%", "=1,foo", "=1,=y"} {
+			require.Error(t, new(StepMatcherFlag).Set(pattern), pattern)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+var (
+	ResumeBeforeFlag = &cli.Uint64Flag{
+		Name:     "before",
+		Usage:    "only resume from a snapshot taken before this step. Resumes from the latest snapshot if 0.",
+		Required: false,
+	}
+)
+
+type snapshotFile struct {
+	step uint64
+	path string
+}
+
+// findSnapshots lists the snapshots matching the snapshot file name format, ordered from the latest step.
+func findSnapshots(snapshotFmt string) ([]snapshotFile, error) {
+	dir := filepath.Dir(snapshotFmt)
+	nameFmt := filepath.Base(snapshotFmt)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots in %q: %w", dir, err)
+	}
+	var snapshots []snapshotFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var step uint64
+		if _, err := fmt.Sscanf(entry.Name(), nameFmt, &step); err != nil {
+			continue
+		}
+		// Sscanf ignores any trailing text, so check the name is exactly the formatted one
+		if fmt.Sprintf(nameFmt, step) != entry.Name() {
+			continue
+		}
+		snapshots = append(snapshots, snapshotFile{step: step, path: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].step > snapshots[j].step
+	})
+	return snapshots, nil
+}
+
+// loadLatestSnapshot loads the latest valid snapshot before the given step, or any step if before is 0.
+// Snapshots that fail to load, for example because they were only partially written, are skipped.
+// Returns nil if there is no valid snapshot.
+func loadLatestSnapshot(ctx *cli.Context, l log.Logger, snapshotFmt string, before uint64) (mipsevm.FPVMState, error) {
+	snapshots, err := findSnapshots(snapshotFmt)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if before != 0 && snapshot.step >= before {
+			continue
+		}
+		state, err := loadState(ctx, snapshot.path)
+		if err != nil {
+			l.Warn("Skipping invalid snapshot", "path", snapshot.path, "err", err)
+			continue
+		}
+		if state.GetStep() != snapshot.step {
+			l.Warn("Skipping snapshot with unexpected step", "path", snapshot.path, "step", state.GetStep())
+			continue
+		}
+		return state, nil
+	}
+	return nil, nil
+}
+
+func Resume(ctx *cli.Context) error {
+	l := Logger(os.Stderr, log.LevelInfo)
+	state, err := loadLatestSnapshot(ctx, l, ctx.String(RunSnapshotFmtFlag.Name), ctx.Uint64(ResumeBeforeFlag.Name))
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		l.Info("Resuming from snapshot", "step", state.GetStep())
+	} else {
+		l.Info("No snapshot found, starting from input state")
+		state, err = loadState(ctx, ctx.Path(RunInputFlag.Name))
+		if err != nil {
+			return err
+		}
+	}
+	return run(ctx, l, state)
+}
+
+var ResumeCommand = &cli.Command{
+	Name:  "resume",
+	Usage: "Resume running VM step(s) from the latest snapshot.",
+	Description: "Resume running VM step(s) from the latest valid snapshot matching --snapshot-fmt, " +
+		"or from the input state if there is none. The pre-image server is started for the resumed run, " +
+		"and all other options match the run command.",
+	Action: Resume,
+	Flags:  append([]cli.Flag{ResumeBeforeFlag}, RunCommand.Flags...),
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestFindSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"state-100.bin", "state-2000.bin", "state-30.bin", "state-40.json", "state-50.bin.tmp", "other.bin"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "state-60.bin"), 0755))
+
+	snapshots, err := findSnapshots(filepath.Join(dir, "state-%d.bin"))
+	require.NoError(t, err)
+	require.Equal(t, []snapshotFile{
+		{step: 2000, path: filepath.Join(dir, "state-2000.bin")},
+		{step: 100, path: filepath.Join(dir, "state-100.bin")},
+		{step: 30, path: filepath.Join(dir, "state-30.bin")},
+	}, snapshots)
+
+	snapshots, err = findSnapshots(filepath.Join(dir, "missing", "state-%d.bin"))
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
+
+func TestWriteBinarySnapshot(t *testing.T) {
+	dir := t.TempDir()
+	state := &mipsevm.State{Memory: mipsevm.NewMemory(), PC: 4, NextPC: 8, Step: 42}
+	state.Memory.SetMemory(0x1000, 0xaabbccdd)
+
+	path := filepath.Join(dir, "state-42.bin")
+	require.NoError(t, writeState(path, state))
+	binary, err := isBinarySnapshot(path)
+	require.NoError(t, err)
+	require.True(t, binary)
+	loaded, err := loadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, state.EncodeWitness(), loaded.EncodeWitness())
+
+	jsonPath := filepath.Join(dir, "state-42.json")
+	require.NoError(t, writeState(jsonPath, state))
+	binary, err = isBinarySnapshot(jsonPath)
+	require.NoError(t, err)
+	require.False(t, binary)
+
+	err = writeState(filepath.Join(dir, "mt.bin"), mipsevm.NewMTState(state))
+	require.ErrorContains(t, err, "not supported")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"time"
@@ -21,19 +22,19 @@ import (
 var (
 	RunInputFlag = &cli.PathFlag{
 		Name:      "input",
-		Usage:     "path of input JSON state. Stdin if left empty.",
+		Usage:     "path of input JSON state or binary snapshot. Stdin if left empty.",
 		TakesFile: true,
 		Value:     "state.json",
 		Required:  true,
 	}
 	RunOutputFlag = &cli.PathFlag{
 		Name:      "output",
-		Usage:     "path of output JSON state. Not written if empty, use - to write to Stdout.",
+		Usage:     "path of output JSON state, or binary snapshot if the path ends in " + binarySnapshotExt + ". Not written if empty, use - to write to Stdout.",
 		TakesFile: true,
 		Value:     "out.json",
 		Required:  false,
 	}
-	patternHelp    = "'never' (default), 'always', '=123' at exactly step 123, '%123' for every 123 steps"
+	patternHelp    = "'never' (default), 'always', '=123' at exactly step 123, '%123' for every 123 steps. Comma-separate patterns to match any of them, e.g. '%1000,=123'"
 	RunProofAtFlag = &cli.GenericFlag{
 		Name:     "proof-at",
 		Usage:    "step pattern to output proof at: " + patternHelp,
@@ -54,7 +55,7 @@ var (
 	}
 	RunSnapshotFmtFlag = &cli.StringFlag{
 		Name:     "snapshot-fmt",
-		Usage:    "format for snapshot output file names.",
+		Usage:    "format for snapshot output file names. Snapshots are written in the compact binary format if the name ends in " + binarySnapshotExt + ".",
 		Value:    "state-%d.json",
 		Required: false,
 	}
@@ -90,6 +91,24 @@ var (
 		Name:  "pprof.cpu",
 		Usage: "enable pprof cpu profiling",
 	}
+	RunProfileFlag = &cli.PathFlag{
+		Name:      "profile",
+		Usage:     "path to write a profile of the guest program to, symbolized with the --meta file. Not profiled if empty.",
+		TakesFile: true,
+		Required:  false,
+	}
+	RunProfileFreqFlag = &cli.Uint64Flag{
+		Name:     "profile.freq",
+		Usage:    "number of steps between samples of the guest program profile",
+		Value:    100,
+		Required: false,
+	}
+	RunProfileFormatFlag = &cli.StringFlag{
+		Name:     "profile.format",
+		Usage:    "format of the guest program profile: 'pprof' for go tool pprof, or 'folded' for flamegraph tools",
+		Value:    "pprof",
+		Required: false,
+	}
 )
 
 type Proof struct {
@@ -128,7 +147,8 @@ type ProcessPreimageOracle struct {
 
 const clientPollTimeout = time.Second * 15
 
-func NewProcessPreimageOracle(name string, args []string) (*ProcessPreimageOracle, error) {
+// NewProcessPreimageOracle creates a pre-image oracle served by the given command, which writes its output to stdout and stderr.
+func NewProcessPreimageOracle(name string, args []string, stdout io.Writer, stderr io.Writer) (*ProcessPreimageOracle, error) {
 	if name == "" {
 		return &ProcessPreimageOracle{}, nil
 	}
@@ -143,8 +163,8 @@ func NewProcessPreimageOracle(name string, args []string) (*ProcessPreimageOracl
 	}
 
 	cmd := exec.Command(name, args...) // nosemgrep
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	cmd.ExtraFiles = []*os.File{
 		hOracleRW.Reader(),
 		hOracleRW.Writer(),
@@ -234,12 +254,15 @@ func Run(ctx *cli.Context) error {
 		defer profile.Start(profile.NoShutdownHook, profile.ProfilePath("."), profile.CPUProfile).Stop()
 	}
 
-	state, err := loadJSON[mipsevm.State](ctx.Path(RunInputFlag.Name))
+	state, err := loadState(ctx, ctx.Path(RunInputFlag.Name))
 	if err != nil {
 		return err
 	}
+	return run(ctx, Logger(os.Stderr, log.LevelInfo), state)
+}
 
-	l := Logger(os.Stderr, log.LevelInfo)
+// run executes the state, with the pre-image server and the options of the RunCommand flags.
+func run(ctx *cli.Context, l log.Logger, state mipsevm.FPVMState) error {
 	outLog := &mipsevm.LoggingWriter{Name: "program std-out", Log: l}
 	errLog := &mipsevm.LoggingWriter{Name: "program std-err", Log: l}
 
@@ -249,19 +272,14 @@ func Run(ctx *cli.Context) error {
 	}
 	stopAtPreimageLargerThan := ctx.Int(RunStopAtPreimageLargerThanFlag.Name)
 
-	// split CLI args after first '--'
-	args := ctx.Args().Slice()
-	for i, arg := range args {
-		if arg == "--" {
-			args = args[i+1:]
-			break
-		}
-	}
-	if len(args) == 0 {
-		args = []string{""}
+	profilePath := ctx.Path(RunProfileFlag.Name)
+	profileFormat := ctx.String(RunProfileFormatFlag.Name)
+	if profileFormat != "pprof" && profileFormat != "folded" {
+		return fmt.Errorf("invalid profile format %q, must be either 'pprof' or 'folded'", profileFormat)
 	}
 
-	po, err := NewProcessPreimageOracle(args[0], args[1:])
+	args := preimageServerArgs(ctx)
+	po, err := NewProcessPreimageOracle(args[0], args[1:], os.Stdout, os.Stderr)
 	if err != nil {
 		return fmt.Errorf("failed to create pre-image oracle process: %w", err)
 	}
@@ -279,19 +297,20 @@ func Run(ctx *cli.Context) error {
 	snapshotAt := ctx.Generic(RunSnapshotAtFlag.Name).(*StepMatcherFlag).Matcher()
 	infoAt := ctx.Generic(RunInfoAtFlag.Name).(*StepMatcherFlag).Matcher()
 
-	var meta *mipsevm.Metadata
-	if metaPath := ctx.Path(RunMetaFlag.Name); metaPath == "" {
-		l.Info("no metadata file specified, defaulting to empty metadata")
-		meta = &mipsevm.Metadata{Symbols: nil} // provide empty metadata by default
-	} else {
-		if m, err := loadJSON[mipsevm.Metadata](metaPath); err != nil {
-			return fmt.Errorf("failed to load metadata: %w", err)
-		} else {
-			meta = m
-		}
+	meta, err := loadMetadata(l, ctx.Path(RunMetaFlag.Name))
+	if err != nil {
+		return err
 	}
 
-	us := mipsevm.NewInstrumentedState(state, po, outLog, errLog)
+	var profiler *mipsevm.Profiler
+	if profilePath != "" {
+		profiler = mipsevm.NewProfiler(meta, ctx.Uint64(RunProfileFreqFlag.Name))
+	}
+
+	us, err := newFPVM(state, po, outLog, errLog)
+	if err != nil {
+		return err
+	}
 	proofFmt := ctx.String(RunProofFmtFlag.Name)
 	snapshotFmt := ctx.String(RunSnapshotFmtFlag.Name)
 
@@ -301,34 +320,38 @@ func Run(ctx *cli.Context) error {
 	}
 
 	start := time.Now()
-	startStep := state.Step
+	startStep := state.GetStep()
 
 	// avoid symbol lookups every instruction by preparing a matcher func
 	sleepCheck := meta.SymbolMatcher("runtime.notesleep")
+	if _, ok := state.(*mipsevm.MTState); ok {
+		// Threads legitimately sleep while others make progress when running multi-threaded
+		sleepCheck = func(addr uint64) bool { return false }
+	}
 
-	for !state.Exited {
-		if state.Step%100 == 0 { // don't do the ctx err check (includes lock) too often
+	for !state.GetExited() {
+		if state.GetStep()%100 == 0 { // don't do the ctx err check (includes lock) too often
 			if err := ctx.Context.Err(); err != nil {
 				return err
 			}
 		}
 
-		step := state.Step
+		step := state.GetStep()
 
 		if infoAt(state) {
 			delta := time.Since(start)
 			l.Info("processing",
 				"step", step,
-				"pc", mipsevm.HexU32(state.PC),
-				"insn", mipsevm.HexU32(state.Memory.GetMemory(state.PC)),
+				"pc", mipsevm.HexU64(state.GetPC()),
+				"insn", mipsevm.HexU32(state.GetInstruction()),
 				"ips", float64(step-startStep)/(float64(delta)/float64(time.Second)),
-				"pages", state.Memory.PageCount(),
-				"mem", state.Memory.Usage(),
-				"name", meta.LookupSymbol(state.PC),
+				"pages", state.GetMemory().PageCount(),
+				"mem", state.GetMemory().Usage(),
+				"name", meta.LookupSymbol(state.GetPC()),
 			)
 		}
 
-		if sleepCheck(state.PC) { // don't loop forever when we get stuck because of an unexpected bad program
+		if sleepCheck(state.GetPC()) { // don't loop forever when we get stuck because of an unexpected bad program
 			return fmt.Errorf("got stuck in Go sleep at step %d", step)
 		}
 
@@ -337,12 +360,16 @@ func Run(ctx *cli.Context) error {
 		}
 
 		if snapshotAt(state) {
-			if err := writeJSON(fmt.Sprintf(snapshotFmt, step), state); err != nil {
+			if err := writeState(fmt.Sprintf(snapshotFmt, step), state); err != nil {
 				return fmt.Errorf("failed to write state snapshot: %w", err)
 			}
 		}
 
-		prevPreimageOffset := state.PreimageOffset
+		if profiler != nil {
+			profiler.Observe(state)
+		}
+
+		prevPreimageOffset := state.GetPreimageOffset()
 
 		if proofAt(state) {
 			preStateHash, err := state.EncodeWitness().StateHash()
@@ -351,7 +378,7 @@ func Run(ctx *cli.Context) error {
 			}
 			witness, err := stepFn(true)
 			if err != nil {
-				return fmt.Errorf("failed at proof-gen step %d (PC: %08x): %w", step, state.PC, err)
+				return fmt.Errorf("failed at proof-gen step %d (PC: %08x): %w", step, state.GetPC(), err)
 			}
 			postStateHash, err := state.EncodeWitness().StateHash()
 			if err != nil {
@@ -362,7 +389,7 @@ func Run(ctx *cli.Context) error {
 				Pre:       preStateHash,
 				Post:      postStateHash,
 				StateData: witness.State,
-				ProofData: witness.MemProof,
+				ProofData: witness.ProofData(),
 			}
 			if witness.HasPreimage() {
 				proof.OracleKey = witness.PreimageKey[:]
@@ -375,11 +402,11 @@ func Run(ctx *cli.Context) error {
 		} else {
 			_, err = stepFn(false)
 			if err != nil {
-				return fmt.Errorf("failed at step %d (PC: %08x): %w", step, state.PC, err)
+				return fmt.Errorf("failed at step %d (PC: %08x): %w", step, state.GetPC(), err)
 			}
 		}
 
-		if preimageRead := state.PreimageOffset > prevPreimageOffset; preimageRead {
+		if preimageRead := state.GetPreimageOffset() > prevPreimageOffset; preimageRead {
 			if stopAtPreimageType == "any" {
 				break
 			}
@@ -388,7 +415,7 @@ func Run(ctx *cli.Context) error {
 				if stopAtPreimageType == "global" {
 					keyType = byte(preimage.Keccak256KeyType)
 				}
-				if state.PreimageKey.Bytes()[0] == keyType {
+				if state.GetPreimageKey().Bytes()[0] == keyType {
 					break
 				}
 			}
@@ -398,12 +425,63 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
-	if err := writeJSON(ctx.Path(RunOutputFlag.Name), state); err != nil {
+	if err := writeState(ctx.Path(RunOutputFlag.Name), state); err != nil {
 		return fmt.Errorf("failed to write state output: %w", err)
 	}
+	if profiler != nil {
+		if err := writeProfile(profilePath, profileFormat, profiler); err != nil {
+			return fmt.Errorf("failed to write profile: %w", err)
+		}
+	}
 	return nil
 }
 
+// preimageServerArgs returns the pre-image server command and its arguments, after the first '--' of the CLI args.
+// The command is empty if there is no pre-image server.
+func preimageServerArgs(ctx *cli.Context) []string {
+	args := ctx.Args().Slice()
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	return args
+}
+
+// loadMetadata loads the metadata for symbol lookups, or empty metadata if no path is specified.
+func loadMetadata(l log.Logger, metaPath string) (*mipsevm.Metadata, error) {
+	if metaPath == "" {
+		l.Info("no metadata file specified, defaulting to empty metadata")
+		return &mipsevm.Metadata{Symbols: nil}, nil // provide empty metadata by default
+	}
+	meta, err := loadJSON[mipsevm.Metadata](metaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	return meta, nil
+}
+
+func writeProfile(path string, format string, profiler *mipsevm.Profiler) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if format == "folded" {
+		err = profiler.WriteFolded(f)
+	} else {
+		err = profiler.WritePprof(f)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 var RunCommand = &cli.Command{
 	Name:        "run",
 	Usage:       "Run VM step(s) and generate proof data to replicate onchain.",
@@ -412,6 +490,7 @@ var RunCommand = &cli.Command{
 	Flags: []cli.Flag{
 		RunInputFlag,
 		RunOutputFlag,
+		VMTypeFlag,
 		RunProofAtFlag,
 		RunProofFmtFlag,
 		RunSnapshotAtFlag,
@@ -422,5 +501,8 @@ var RunCommand = &cli.Command{
 		RunMetaFlag,
 		RunInfoAtFlag,
 		RunPProfCPU,
+		RunProfileFlag,
+		RunProfileFreqFlag,
+		RunProfileFormatFlag,
 	},
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+// binarySnapshotExt is the file extension that selects the binary snapshot format when writing a state.
+const binarySnapshotExt = ".bin"
+
+// isBinarySnapshot returns true if the file at the given path is a binary snapshot rather than a JSON state.
+func isBinarySnapshot(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open file %q: %w", path, err)
+	}
+	defer f.Close()
+	header := make([]byte, len(mipsevm.SnapshotMagic))
+	if _, err := io.ReadFull(f, header); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	return mipsevm.IsSnapshot(header), nil
+}
+
+func loadSnapshot(path string) (*mipsevm.State, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %q: %w", path, err)
+	}
+	defer f.Close()
+	state, err := mipsevm.DecodeSnapshot(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %q: %w", path, err)
+	}
+	return state, nil
+}
+
+// writeState writes the state as JSON, or in the binary snapshot format if the path ends in binarySnapshotExt.
+func writeState(path string, state mipsevm.FPVMState) error {
+	if !strings.HasSuffix(path, binarySnapshotExt) {
+		return writeJSON(path, state)
+	}
+	st, ok := state.(*mipsevm.State)
+	if !ok {
+		return fmt.Errorf("binary snapshots are not supported for %T", state)
+	}
+	f, err := ioutil.NewAtomicWriterCompressed(path, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	if err := st.EncodeSnapshot(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to finish write: %w", err)
+	}
+	return nil
+}
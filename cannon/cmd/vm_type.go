@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+const (
+	vmTypeCannon   = "cannon"
+	vmTypeCannonMT = "cannon-mt"
+	vmTypeCannon64 = "cannon64"
+)
+
+var VMTypeFlag = &cli.StringFlag{
+	Name:  "type",
+	Usage: "VM type, one of 'cannon' (single-threaded), 'cannon-mt' (multi-threaded) or 'cannon64' (single-threaded MIPS64)",
+	Value: vmTypeCannon,
+}
+
+// loadState loads a JSON state of the VM type selected by VMTypeFlag, or a binary snapshot.
+func loadState(ctx *cli.Context, path string) (mipsevm.FPVMState, error) {
+	vmType := ctx.String(VMTypeFlag.Name)
+	if path != "" {
+		if binary, err := isBinarySnapshot(path); err != nil {
+			return nil, err
+		} else if binary {
+			if vmType != vmTypeCannon {
+				return nil, fmt.Errorf("binary snapshots are not supported for VM type %q", vmType)
+			}
+			return loadSnapshot(path)
+		}
+	}
+	switch vmType {
+	case vmTypeCannon:
+		state, err := loadJSON[mipsevm.State](path)
+		if err != nil {
+			return nil, err
+		}
+		return state, nil
+	case vmTypeCannonMT:
+		state, err := loadJSON[mipsevm.MTState](path)
+		if err != nil {
+			return nil, err
+		}
+		return state, nil
+	case vmTypeCannon64:
+		state, err := loadJSON[mipsevm.State64](path)
+		if err != nil {
+			return nil, err
+		}
+		return state, nil
+	default:
+		return nil, fmt.Errorf("unknown VM type %q", vmType)
+	}
+}
+
+// newFPVM creates a VM to execute steps against the supplied state.
+func newFPVM(state mipsevm.FPVMState, po mipsevm.PreimageOracle, stdOut, stdErr *mipsevm.LoggingWriter) (mipsevm.FPVM, error) {
+	switch st := state.(type) {
+	case *mipsevm.State:
+		return mipsevm.NewInstrumentedState(st, po, stdOut, stdErr), nil
+	case *mipsevm.MTState:
+		return mipsevm.NewMTInstrumentedState(st, po, stdOut, stdErr), nil
+	case *mipsevm.State64:
+		return mipsevm.NewInstrumentedState64(st, po, stdOut, stdErr), nil
+	default:
+		return nil, fmt.Errorf("unsupported state type %T", state)
+	}
+}
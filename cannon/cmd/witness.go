@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
 	"github.com/urfave/cli/v2"
 )
 
@@ -25,7 +24,7 @@ var (
 func Witness(ctx *cli.Context) error {
 	input := ctx.Path(WitnessInputFlag.Name)
 	output := ctx.Path(WitnessOutputFlag.Name)
-	state, err := loadJSON[mipsevm.State](input)
+	state, err := loadState(ctx, input)
 	if err != nil {
 		return fmt.Errorf("invalid input state (%v): %w", input, err)
 	}
@@ -51,5 +50,6 @@ var WitnessCommand = &cli.Command{
 	Flags: []cli.Flag{
 		WitnessInputFlag,
 		WitnessOutputFlag,
+		VMTypeFlag,
 	},
 }
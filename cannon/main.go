@@ -22,6 +22,9 @@ func main() {
 		cmd.LoadELFCommand,
 		cmd.WitnessCommand,
 		cmd.RunCommand,
+		cmd.ResumeCommand,
+		cmd.DiffFuzzCommand,
+		cmd.DebugCommand,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 
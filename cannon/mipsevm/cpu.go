@@ -0,0 +1,189 @@
+package mipsevm
+
+import "math/bits"
+
+// Word is the register, address and memory word type of the 32-bit and 64-bit VMs.
+type Word interface {
+	uint32 | uint64
+}
+
+// wordBits returns the size of a word in bits.
+func wordBits[W Word]() uint64 {
+	return uint64(bits.OnesCount64(uint64(^W(0))))
+}
+
+// signExtend sign-extends the lowest idx bits of dat to a word.
+func signExtend[W Word](dat W, idx uint64) W {
+	shift := 64 - idx
+	return W(uint64(int64(uint64(dat)<<shift) >> shift))
+}
+
+// signed returns the value of a word as a signed integer.
+func signed[W Word](v W) int64 {
+	return int64(signExtend(uint64(v), wordBits[W]()))
+}
+
+// cpuState points to the program counters, HI/LO and general purpose registers of the single-threaded or 64-bit VM
+// state, or of a thread of the multi-threaded VM, so all VMs execute instructions with the same logic.
+type cpuState[W Word] struct {
+	PC        *W
+	NextPC    *W
+	LO        *W
+	HI        *W
+	Registers *[32]W
+}
+
+// fetchOperands returns the source operand values of the instruction, the rt register,
+// and the register that the result of the instruction is written to.
+func fetchOperands[W Word](registers *[32]W, insn uint32) (rs W, rt W, rtReg uint32, rdReg uint32) {
+	opcode := insn >> 26 // 6-bits
+	rtReg = (insn >> 16) & 0x1F
+
+	// R-type or I-type (stores rt)
+	rs = registers[(insn>>21)&0x1F]
+	rdReg = rtReg
+	if opcode == 0 || opcode == 0x1c {
+		// R-type (stores rd)
+		rt = registers[rtReg]
+		rdReg = (insn >> 11) & 0x1F
+	} else if wordBits[W]() == 64 && (opcode == 0x1a || opcode == 0x1b) {
+		// ldl and ldr merge the loaded value with rt
+		rt = registers[rtReg]
+	} else if opcode < 0x20 {
+		// rt is SignExtImm
+		// don't sign extend for andi, ori, xori
+		if opcode == 0xC || opcode == 0xD || opcode == 0xe {
+			// ZeroExtImm
+			rt = W(insn & 0xFFFF)
+		} else {
+			// SignExtImm
+			rt = signExtend(W(insn&0xFFFF), 16)
+		}
+	} else if opcode >= 0x28 || opcode == 0x22 || opcode == 0x26 {
+		// store rt value with store
+		rt = registers[rtReg]
+
+		// store actual rt with lwl and lwr
+		rdReg = rtReg
+	}
+	return rs, rt, rtReg, rdReg
+}
+
+func handleBranch[W Word](cpu cpuState[W], opcode uint32, insn uint32, rtReg uint32, rs W) error {
+	if *cpu.NextPC != *cpu.PC+4 {
+		panic("branch in delay slot")
+	}
+
+	shouldBranch := false
+	if opcode == 4 || opcode == 5 { // beq/bne
+		rt := cpu.Registers[rtReg]
+		shouldBranch = (rs == rt && opcode == 4) || (rs != rt && opcode == 5)
+	} else if opcode == 6 {
+		shouldBranch = signed(rs) <= 0 // blez
+	} else if opcode == 7 {
+		shouldBranch = signed(rs) > 0 // bgtz
+	} else if opcode == 1 {
+		// regimm
+		rtv := (insn >> 16) & 0x1F
+		if rtv == 0 { // bltz
+			shouldBranch = signed(rs) < 0
+		}
+		if rtv == 1 { // bgez
+			shouldBranch = signed(rs) >= 0
+		}
+	}
+
+	prevPC := *cpu.PC
+	*cpu.PC = *cpu.NextPC // execute the delay slot first
+	if shouldBranch {
+		*cpu.NextPC = prevPC + 4 + (signExtend(W(insn&0xFFFF), 16) << 2) // then continue with the instruction the branch jumps to.
+	} else {
+		*cpu.NextPC = *cpu.NextPC + 4 // branch not taken
+	}
+	return nil
+}
+
+// handleHiLo executes the instructions that use the HI and LO registers.
+// The 32-bit operations sign-extend their results to the word size. The 64-bit operations are only executed by the
+// 64-bit VM.
+func handleHiLo[W Word](cpu cpuState[W], fun uint32, rs W, rt W, storeReg uint32) error {
+	val := W(0)
+	switch fun {
+	case 0x10: // mfhi
+		val = *cpu.HI
+	case 0x11: // mthi
+		*cpu.HI = rs
+	case 0x12: // mflo
+		val = *cpu.LO
+	case 0x13: // mtlo
+		*cpu.LO = rs
+	case 0x18: // mult
+		acc := uint64(int64(int32(rs)) * int64(int32(rt)))
+		*cpu.HI = signExtend(W(acc>>32), 32)
+		*cpu.LO = signExtend(W(acc), 32)
+	case 0x19: // multu
+		acc := uint64(uint32(rs)) * uint64(uint32(rt))
+		*cpu.HI = signExtend(W(acc>>32), 32)
+		*cpu.LO = signExtend(W(acc), 32)
+	case 0x1a: // div
+		*cpu.HI = signExtend(W(uint32(int32(rs)%int32(rt))), 32)
+		*cpu.LO = signExtend(W(uint32(int32(rs)/int32(rt))), 32)
+	case 0x1b: // divu
+		*cpu.HI = signExtend(W(uint32(rs)%uint32(rt)), 32)
+		*cpu.LO = signExtend(W(uint32(rs)/uint32(rt)), 32)
+	case 0x1c: // dmult
+		hi, lo := bits.Mul64(uint64(rs), uint64(rt))
+		// correct the unsigned product for negative operands
+		if int64(rs) < 0 {
+			hi -= uint64(rt)
+		}
+		if int64(rt) < 0 {
+			hi -= uint64(rs)
+		}
+		*cpu.HI = W(hi)
+		*cpu.LO = W(lo)
+	case 0x1d: // dmultu
+		hi, lo := bits.Mul64(uint64(rs), uint64(rt))
+		*cpu.HI = W(hi)
+		*cpu.LO = W(lo)
+	case 0x1e: // ddiv
+		*cpu.HI = W(int64(rs) % int64(rt))
+		*cpu.LO = W(int64(rs) / int64(rt))
+	case 0x1f: // ddivu
+		*cpu.HI = rs % rt
+		*cpu.LO = rs / rt
+	}
+
+	if storeReg != 0 {
+		cpu.Registers[storeReg] = val
+	}
+
+	*cpu.PC = *cpu.NextPC
+	*cpu.NextPC = *cpu.NextPC + 4
+	return nil
+}
+
+func handleJump[W Word](cpu cpuState[W], linkReg uint32, dest W) error {
+	if *cpu.NextPC != *cpu.PC+4 {
+		panic("jump in delay slot")
+	}
+	prevPC := *cpu.PC
+	*cpu.PC = *cpu.NextPC
+	*cpu.NextPC = dest
+	if linkReg != 0 {
+		cpu.Registers[linkReg] = prevPC + 8 // set the link-register to the instr after the delay slot instruction.
+	}
+	return nil
+}
+
+func handleRd[W Word](cpu cpuState[W], storeReg uint32, val W, conditional bool) error {
+	if storeReg >= 32 {
+		panic("invalid register")
+	}
+	if storeReg != 0 && conditional {
+		cpu.Registers[storeReg] = val
+	}
+	*cpu.PC = *cpu.NextPC
+	*cpu.NextPC = *cpu.NextPC + 4
+	return nil
+}
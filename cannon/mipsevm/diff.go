@@ -0,0 +1,366 @@
+package mipsevm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+// DiffProgram is a program to execute with both the Go VM and the MIPS contract, to check that they agree.
+type DiffProgram struct {
+	State *State `json:"state"`
+	// Preimages that are available to the program, by pre-image key
+	Preimages map[common.Hash]hexutil.Bytes `json:"preimages"`
+}
+
+// ErrDiffSkipped is returned when both the Go VM and the MIPS contract reject a step,
+// which doesn't show that they agree, as they may reject it for different reasons.
+var ErrDiffSkipped = errors.New("step rejected by both mipsevm and MIPS contract")
+
+// Divergence describes a step that the Go VM and the MIPS contract don't agree on.
+// Any divergence is a consensus bug in the fault proof VM.
+type Divergence struct {
+	Step uint64 `json:"step"`
+	Insn uint32 `json:"insn"`
+	// Pre is the witness of the state before the step
+	Pre     hexutil.Bytes `json:"pre"`
+	GoPost  hexutil.Bytes `json:"goPost,omitempty"`
+	GoErr   string        `json:"goErr,omitempty"`
+	EVMPost hexutil.Bytes `json:"evmPost,omitempty"`
+	EVMErr  string        `json:"evmErr,omitempty"`
+}
+
+func (d *Divergence) Error() string {
+	result := func(post hexutil.Bytes, err string) string {
+		if err != "" {
+			return "error: " + err
+		}
+		return "post-state " + post.String()
+	}
+	return fmt.Sprintf("mipsevm and MIPS contract diverged at step %d (insn: %08x), mipsevm %s, contract %s",
+		d.Step, d.Insn, result(d.GoPost, d.GoErr), result(d.EVMPost, d.EVMErr))
+}
+
+type diffOracle map[common.Hash]hexutil.Bytes
+
+func (o diffOracle) Hint(v []byte) {}
+
+func (o diffOracle) GetPreimage(k [32]byte) []byte {
+	v, ok := o[k]
+	if !ok {
+		panic(fmt.Errorf("unknown pre-image %x", k))
+	}
+	return v
+}
+
+// DiffExecute executes up to maxSteps steps of the program with both the Go VM and the MIPS contract.
+// A *Divergence error is returned at the first step they disagree on.
+// Execution stops without error when the program exits. An ErrDiffSkipped error is returned at a step that both reject,
+// e.g. an invalid instruction, as their agreement can't be verified. The program itself is not modified.
+func DiffExecute(evm *MIPSEVM, prog *DiffProgram, maxSteps int) error {
+	state, err := cloneState(prog.State)
+	if err != nil {
+		return err
+	}
+	us := NewInstrumentedState(state, diffOracle(prog.Preimages), io.Discard, io.Discard)
+	for i := 0; i < maxSteps && !state.Exited; i++ {
+		step := state.Step
+		insn := uint32(0)
+		if state.PC&3 == 0 { // the VM rejects unaligned PCs
+			insn = state.Memory.GetMemory(state.PC)
+		}
+		pre := hexutil.Bytes(state.EncodeWitness())
+		insnProof := state.Memory.MerkleProof(state.PC)
+
+		wit, err := stepRecover(us)
+		if err != nil {
+			// The Go VM rejects the step, so the contract must reject it too. The witness has the same memory proof
+			// as an accepted step would have, from the memory access the Go VM made before rejecting the step.
+			wit := &StepWitness{State: pre, MemProof: append(insnProof[:], us.memProof[:]...)}
+			evmPost, evmErr := evm.ExecuteStep(wit)
+			if evmErr == nil {
+				return &Divergence{Step: step, Insn: insn, Pre: pre, GoErr: err.Error(), EVMPost: evmPost}
+			}
+			return fmt.Errorf("%w at step %d (insn: %08x): mipsevm error: %v, contract error: %v", ErrDiffSkipped, step, insn, err, evmErr)
+		}
+		goPost := hexutil.Bytes(state.EncodeWitness())
+		evmPost, evmErr := evm.ExecuteStep(wit)
+		if evmErr != nil {
+			return &Divergence{Step: step, Insn: insn, Pre: pre, GoPost: goPost, EVMErr: evmErr.Error()}
+		}
+		if !bytes.Equal(goPost, evmPost) {
+			return &Divergence{Step: step, Insn: insn, Pre: pre, GoPost: goPost, EVMPost: evmPost}
+		}
+	}
+	return nil
+}
+
+// stepRecover steps the Go VM, and converts a panic, e.g. on an invalid instruction, into an error.
+func stepRecover(us *InstrumentedState) (wit *StepWitness, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return us.Step(true)
+}
+
+func cloneState(state *State) (*State, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state: %w", err)
+	}
+	var out State
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+	return &out, nil
+}
+
+const (
+	diffCodeBase = 0x0040_0000
+	diffDataBase = 0x1000_0000
+	// number of pages of random data that the program loads from and stores to
+	diffDataPages = 2
+)
+
+// registers that hold pointers into the data pages, and are never written by the generated instructions
+var diffPtrRegs = []uint32{16, 17, 18, 19}
+
+// registers that the generated instructions write to, other than through the zero register
+var diffDstRegs = []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30}
+
+var diffEdgeWords = []uint32{0, 1, 2, 31, 32, 0x7F, 0x80, 0xFF, 0x7FFF, 0x8000, 0xFFFF, 0x7FFF_FFFF, 0x8000_0000, 0xFFFF_FFFE, 0xFFFF_FFFF}
+
+var diffSyscalls = []uint32{sysMmap, sysBrk, sysClone, sysExitGroup, sysRead, sysWrite, sysFcntl, 4000}
+
+type diffGen struct {
+	r        *rand.Rand
+	codeAddr uint32
+	length   int
+	insns    []uint32
+}
+
+type diffInsnGen struct {
+	weight  int
+	control bool
+	gen     func(g *diffGen)
+}
+
+// diffInsnGens generates every instruction that is implemented by the VM, including control flow,
+// loads and stores around page boundaries, and syscalls with pre-image and hint reads and writes.
+var diffInsnGens = []diffInsnGen{
+	// R-type ALU: sll, srl, sra
+	{weight: 3, gen: func(g *diffGen) { g.emit(rtype(0, g.src(), g.dst(), uint32(g.r.Intn(32)), g.pick(0x00, 0x02, 0x03))) }},
+	// R-type ALU: sllv, srlv, srav, movz, movn, add, addu, sub, subu, and, or, xor, nor, slt, sltu
+	{weight: 15, gen: func(g *diffGen) {
+		g.emit(rtype(g.src(), g.src(), g.dst(), 0, g.pick(0x04, 0x06, 0x07, 0x0a, 0x0b, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x2a, 0x2b)))
+	}},
+	// sync
+	{weight: 1, gen: func(g *diffGen) { g.emit(rtype(0, 0, 0, 0, 0x0f)) }},
+	// mfhi, mflo
+	{weight: 2, gen: func(g *diffGen) { g.emit(rtype(0, 0, g.dst(), 0, g.pick(0x10, 0x12))) }},
+	// mthi, mtlo
+	{weight: 2, gen: func(g *diffGen) { g.emit(rtype(g.src(), 0, 0, 0, g.pick(0x11, 0x13))) }},
+	// mult, multu, div, divu
+	{weight: 4, gen: func(g *diffGen) { g.emit(rtype(g.src(), g.src(), 0, 0, g.pick(0x18, 0x19, 0x1a, 0x1b))) }},
+	// SPECIAL2: mul, clz, clo
+	{weight: 3, gen: func(g *diffGen) { g.emit(0x1c<<26 | rtype(g.src(), g.src(), g.dst(), 0, g.pick(0x02, 0x20, 0x21))) }},
+	// I-type ALU: addi, addiu, slti, sltiu, andi, ori, xori, lui
+	{weight: 8, gen: func(g *diffGen) {
+		g.emit(itype(g.pick(0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f), g.src(), g.dst(), g.imm()))
+	}},
+	// loads: lb, lh, lwl, lw, lbu, lhu, lwr, ll
+	{weight: 8, gen: func(g *diffGen) {
+		g.emit(itype(g.pick(0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x30), g.ptr(), g.dst(), g.offset()))
+	}},
+	// stores: sb, sh, swl, sw, swr, sc
+	{weight: 8, gen: func(g *diffGen) {
+		g.emit(itype(g.pick(0x28, 0x29, 0x2a, 0x2b, 0x2e, 0x38), g.ptr(), g.src(), g.offset()))
+	}},
+	// branches: beq, bne, blez, bgtz
+	{weight: 4, control: true, gen: func(g *diffGen) { g.emit(itype(g.pick(0x04, 0x05, 0x06, 0x07), g.src(), g.src(), g.branchOffset())) }},
+	// regimm branches: bltz, bgez
+	{weight: 2, control: true, gen: func(g *diffGen) { g.emit(itype(0x01, g.src(), g.pick(0x00, 0x01), g.branchOffset())) }},
+	// j, jal
+	{weight: 2, control: true, gen: func(g *diffGen) { g.emit(g.pick(0x02, 0x03)<<26 | (g.target()>>2)&0x03FF_FFFF) }},
+	// jr $ra, jalr
+	{weight: 2, control: true, gen: func(g *diffGen) { g.emit(rtype(31, 0, g.pick(0, 31), 0, g.pick(0x08, 0x09))) }},
+	// syscall, with arguments that are mostly valid
+	{weight: 4, gen: func(g *diffGen) {
+		g.emit(itype(0x09, 0, 2, diffSyscalls[g.r.Intn(len(diffSyscalls))])) // addiu $v0, $zero, syscall
+		g.emit(itype(0x09, 0, 4, uint32(g.r.Intn(8))))                       // addiu $a0, $zero, fd
+		g.emit(itype(0x09, g.ptr(), 5, g.offset()))                          // addiu $a1, $ptr, offset
+		g.emit(itype(0x09, 0, 6, uint32(g.r.Intn(9))))                       // addiu $a2, $zero, count
+		g.emit(rtype(0, 0, 0, 0, 0x0c))                                      // syscall
+	}},
+	// invalid instructions, that both the Go VM and the contract must reject
+	{weight: 1, gen: func(g *diffGen) { g.emit(g.pick(0xFFFF_FFFF, 0x0000_003F, 0x7000_003F, 0xFC00_0000)) }},
+}
+
+func rtype(rs uint32, rt uint32, rd uint32, shamt uint32, fun uint32) uint32 {
+	return rs<<21 | rt<<16 | rd<<11 | shamt<<6 | fun
+}
+
+func itype(opcode uint32, rs uint32, rt uint32, imm uint32) uint32 {
+	return opcode<<26 | rs<<21 | rt<<16 | imm&0xFFFF
+}
+
+func (g *diffGen) emit(insn uint32) {
+	g.insns = append(g.insns, insn)
+}
+
+func (g *diffGen) pick(options ...uint32) uint32 {
+	return options[g.r.Intn(len(options))]
+}
+
+func (g *diffGen) src() uint32 {
+	return uint32(g.r.Intn(32))
+}
+
+func (g *diffGen) dst() uint32 {
+	if g.r.Intn(20) == 0 {
+		return 0 // writes to the zero register must be ignored
+	}
+	return diffDstRegs[g.r.Intn(len(diffDstRegs))]
+}
+
+func (g *diffGen) ptr() uint32 {
+	return diffPtrRegs[g.r.Intn(len(diffPtrRegs))]
+}
+
+func (g *diffGen) imm() uint32 {
+	if g.r.Intn(2) == 0 {
+		return diffEdgeWords[g.r.Intn(len(diffEdgeWords))]
+	}
+	return uint32(g.r.Intn(1 << 16))
+}
+
+// offset returns a small memory offset, that is not necessarily aligned
+func (g *diffGen) offset() uint32 {
+	return uint32(g.r.Intn(64) - 32)
+}
+
+// target returns the address of an instruction in, or just after, the program
+func (g *diffGen) target() uint32 {
+	return g.codeAddr + 4*uint32(g.r.Intn(g.length+2))
+}
+
+func (g *diffGen) branchOffset() uint32 {
+	// the offset is relative to the delay slot
+	return uint32(int32(g.target()-(g.codeAddr+4*uint32(len(g.insns)+1))) >> 2)
+}
+
+func (g *diffGen) word() uint32 {
+	switch g.r.Intn(4) {
+	case 0:
+		return diffEdgeWords[g.r.Intn(len(diffEdgeWords))]
+	case 1:
+		return uint32(g.r.Intn(64) - 32)
+	default:
+		return g.r.Uint32()
+	}
+}
+
+// RandomDiffProgram generates a random program of about length instructions, with random registers, memory
+// and pre-image, to execute with DiffExecute.
+// Every instruction that is implemented by the VM can be generated, including syscalls.
+func RandomDiffProgram(r *rand.Rand, length int) *DiffProgram {
+	g := &diffGen{
+		r:        r,
+		codeAddr: diffCodeBase + uint32(r.Intn(16))*PageSize,
+		length:   length,
+	}
+	for len(g.insns) < length {
+		control := g.generate(false)
+		if control {
+			// the delay slot must not be a branch or jump, except to check that it is rejected
+			g.generate(r.Intn(50) != 0)
+		}
+	}
+	// exit when reaching the end of the program
+	g.emit(itype(0x09, 0, 2, sysExitGroup)) // addiu $v0, $zero, exit_group
+	g.emit(itype(0x09, 0, 4, uint32(r.Intn(256))))
+	g.emit(rtype(0, 0, 0, 0, 0x0c))
+
+	state := &State{
+		PC:       g.codeAddr,
+		NextPC:   g.codeAddr + 4,
+		LO:       g.word(),
+		HI:       g.word(),
+		Heap:     0x2000_0000 + uint32(r.Intn(16))*PageSize,
+		Memory:   NewMemory(),
+		Step:     uint64(r.Intn(1000)),
+		LastHint: nil,
+	}
+	for i, insn := range g.insns {
+		state.Memory.SetMemory(g.codeAddr+4*uint32(i), insn)
+	}
+	dataAddr := diffDataBase + uint32(r.Intn(16))*PageSize
+	for addr := dataAddr; addr < dataAddr+diffDataPages*PageSize; addr += 4 {
+		state.Memory.SetMemory(addr, g.word())
+	}
+	for i := range state.Registers {
+		state.Registers[i] = g.word()
+	}
+	state.Registers[0] = 0
+	for _, reg := range diffPtrRegs {
+		switch r.Intn(4) {
+		case 0: // around the boundary between the data pages
+			state.Registers[reg] = dataAddr + PageSize + uint32(r.Intn(64)) - 32
+		case 1: // in a page that is not allocated yet
+			state.Registers[reg] = 0x7000_0000 + uint32(r.Intn(PageSize))
+		default:
+			state.Registers[reg] = dataAddr + 32 + uint32(r.Intn(diffDataPages*PageSize-64))
+		}
+	}
+	state.Registers[31] = g.target()
+
+	// The pre-image that is read by the program, if it doesn't change the key first
+	value := make([]byte, r.Intn(100))
+	r.Read(value)
+	var key common.Hash
+	if r.Intn(2) == 0 {
+		value = value[:min(len(value), 32)]
+		key = common.Hash(preimage.LocalIndexKey(r.Intn(8)).PreimageKey())
+	} else {
+		key = common.Hash(preimage.Keccak256Key(crypto.Keccak256Hash(value)).PreimageKey())
+	}
+	state.PreimageKey = key
+	state.PreimageOffset = uint32(r.Intn(len(value) + 8))
+	return &DiffProgram{
+		State:     state,
+		Preimages: map[common.Hash]hexutil.Bytes{key: value},
+	}
+}
+
+// generate adds a random instruction, or sequence of instructions, and returns whether it is a branch or jump.
+func (g *diffGen) generate(noControl bool) bool {
+	total := 0
+	for _, ig := range diffInsnGens {
+		if !noControl || !ig.control {
+			total += ig.weight
+		}
+	}
+	n := g.r.Intn(total)
+	for _, ig := range diffInsnGens {
+		if noControl && ig.control {
+			continue
+		}
+		if n < ig.weight {
+			ig.gen(g)
+			return ig.control
+		}
+		n -= ig.weight
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,64 @@
+package mipsevm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzDifferential executes random programs with both the Go VM and the MIPS contract, and checks they agree.
+// Programs that diverged before are kept in the testdata corpus, and can be reproduced with `cannon diff-fuzz`.
+func FuzzDifferential(f *testing.F) {
+	contracts, addrs := testContractsSetup(f)
+	for seed := int64(0); seed < 20; seed++ {
+		f.Add(seed, uint8(49))
+	}
+	f.Fuzz(func(t *testing.T, seed int64, length uint8) {
+		prog := RandomDiffProgram(rand.New(rand.NewSource(seed)), int(length)+1)
+		evm := NewMIPSEVM(contracts, addrs)
+		err := DiffExecute(evm, prog, 500)
+		if errors.Is(err, ErrDiffSkipped) {
+			t.Skip(err)
+		}
+		require.NoError(t, err)
+	})
+}
+
+func TestRandomDiffProgramCoverage(t *testing.T) {
+	type op struct{ opcode, fun uint32 }
+	seen := make(map[op]bool)
+	for seed := int64(0); seed < 100; seed++ {
+		prog := RandomDiffProgram(rand.New(rand.NewSource(seed)), 100)
+		for i := uint32(0); i < 100; i++ {
+			insn := prog.State.Memory.GetMemory(prog.State.PC + 4*i)
+			opcode := insn >> 26
+			switch opcode {
+			case 0x00, 0x1c:
+				seen[op{opcode, insn & 0x3F}] = true
+			case 0x01:
+				seen[op{opcode, (insn >> 16) & 0x1F}] = true
+			default:
+				seen[op{opcode, 0}] = true
+			}
+		}
+	}
+
+	var expected []op
+	for _, fun := range []uint32{0x00, 0x02, 0x03, 0x04, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0f, 0x10, 0x11, 0x12, 0x13,
+		0x18, 0x19, 0x1a, 0x1b, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x2a, 0x2b} {
+		expected = append(expected, op{0x00, fun})
+	}
+	for _, fun := range []uint32{0x02, 0x20, 0x21} {
+		expected = append(expected, op{0x1c, fun})
+	}
+	expected = append(expected, op{0x01, 0x00}, op{0x01, 0x01})
+	for _, opcode := range []uint32{0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x28, 0x29, 0x2a, 0x2b, 0x2e, 0x30, 0x38} {
+		expected = append(expected, op{opcode, 0})
+	}
+	for _, o := range expected {
+		require.Truef(t, seen[o], "opcode %#x function %#x must be generated", o.opcode, o.fun)
+	}
+}
@@ -2,6 +2,7 @@ package mipsevm
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
 
 // LoadContracts loads the Cannon contracts, from op-bindings package
@@ -84,6 +86,119 @@ func NewEVMEnv(contracts *Contracts, addrs *Addresses) (*vm.EVM, *state.StateDB)
 	return env, state
 }
 
+// MIPSEVM executes steps with the MIPS contract, to compare the results with the Go VM.
+type MIPSEVM struct {
+	env      *vm.EVM
+	evmState *state.StateDB
+	addrs    *Addresses
+}
+
+func NewMIPSEVM(contracts *Contracts, addrs *Addresses) *MIPSEVM {
+	env, evmState := NewEVMEnv(contracts, addrs)
+	return &MIPSEVM{env, evmState, addrs}
+}
+
+func (m *MIPSEVM) SetTracer(tracer vm.EVMLogger) {
+	m.env.Config.Tracer = tracer
+}
+
+// ExecuteStep is a pure function that computes the poststate from the VM state encoded in the StepWitness.
+// The pre-image in the witness, if any, is loaded into the pre-image oracle contract first.
+// An error is returned if the MIPS contract reverts.
+func (m *MIPSEVM) ExecuteStep(stepWitness *StepWitness) ([]byte, error) {
+	sender := common.Address{0x13, 0x37}
+	startingGas := uint64(30_000_000)
+
+	// we take a snapshot so we can clean up the state, and isolate the logs of this instruction run.
+	snap := m.env.StateDB.Snapshot()
+	defer m.env.StateDB.RevertToSnapshot(snap)
+
+	if stepWitness.HasPreimage() {
+		poInput, err := EncodePreimageOracleInput(stepWitness, LocalContext{})
+		if err != nil {
+			return nil, fmt.Errorf("encode preimage oracle input: %w", err)
+		}
+		_, leftOverGas, err := m.env.Call(vm.AccountRef(sender), m.addrs.Oracle, poInput, startingGas, big.NewInt(0))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load preimage, took %d gas: %w", startingGas-leftOverGas, err)
+		}
+	}
+
+	input, err := EncodeStepInput(stepWitness, LocalContext{})
+	if err != nil {
+		return nil, fmt.Errorf("encode step input: %w", err)
+	}
+	ret, _, err := m.env.Call(vm.AccountRef(sender), m.addrs.MIPS, input, startingGas, big.NewInt(0))
+	if err != nil {
+		return nil, fmt.Errorf("step failed: %w", err)
+	}
+	if len(ret) != 32 {
+		return nil, fmt.Errorf("expected 32-byte state hash, got %d bytes", len(ret))
+	}
+	// remember state hash, to check it against state
+	postHash := common.Hash(*(*[32]byte)(ret))
+	logs := m.evmState.Logs()
+	if len(logs) != 1 {
+		return nil, fmt.Errorf("expected a log with post-state, got %d logs", len(logs))
+	}
+	evmPost := logs[0].Data
+
+	stateHash, err := StateWitness(evmPost).StateHash()
+	if err != nil {
+		return nil, fmt.Errorf("state hash could not be computed: %w", err)
+	}
+	if stateHash != postHash {
+		return nil, fmt.Errorf("logged state hash %s does not match returned state hash %s", stateHash, postHash)
+	}
+	return evmPost, nil
+}
+
+// EncodeStepInput encodes the call to the step function of the MIPS contract.
+func EncodeStepInput(wit *StepWitness, localContext LocalContext) ([]byte, error) {
+	mipsAbi, err := bindings.MIPSMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return mipsAbi.Pack("step", wit.State, wit.MemProof, localContext)
+}
+
+// EncodePreimageOracleInput encodes the call to the pre-image oracle contract that loads the pre-image of the witness.
+func EncodePreimageOracleInput(wit *StepWitness, localContext LocalContext) ([]byte, error) {
+	if wit.PreimageKey == ([32]byte{}) {
+		return nil, errors.New("cannot encode pre-image oracle input, witness has no pre-image to proof")
+	}
+
+	preimageAbi, err := bindings.PreimageOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pre-image oracle ABI: %w", err)
+	}
+
+	switch preimage.KeyType(wit.PreimageKey[0]) {
+	case preimage.LocalKeyType:
+		if len(wit.PreimageValue) > 32+8 {
+			return nil, fmt.Errorf("local pre-image exceeds maximum size of 32 bytes with key 0x%x", wit.PreimageKey)
+		}
+		preimagePart := wit.PreimageValue[8:]
+		var tmp [32]byte
+		copy(tmp[:], preimagePart)
+		return preimageAbi.Pack("loadLocalData",
+			new(big.Int).SetBytes(wit.PreimageKey[1:]),
+			localContext,
+			tmp,
+			new(big.Int).SetUint64(uint64(len(preimagePart))),
+			new(big.Int).SetUint64(uint64(wit.PreimageOffset)),
+		)
+	case preimage.Keccak256KeyType:
+		return preimageAbi.Pack(
+			"loadKeccak256PreimagePart",
+			new(big.Int).SetUint64(uint64(wit.PreimageOffset)),
+			wit.PreimageValue[8:])
+	default:
+		return nil, fmt.Errorf("unsupported pre-image type %d, cannot prepare preimage with key %x offset %d for oracle",
+			wit.PreimageKey[0], wit.PreimageKey, wit.PreimageOffset)
+	}
+}
+
 type testChain struct {
 	startTime uint64
 }
@@ -3,8 +3,6 @@ package mipsevm
 import (
 	"bytes"
 	"debug/elf"
-	"errors"
-	"fmt"
 	"io"
 	"math/big"
 	"os"
@@ -13,11 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
-	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/stretchr/testify/require"
@@ -41,103 +36,22 @@ func MarkdownTracer() vm.EVMLogger {
 	return logger.NewMarkdownLogger(&logger.Config{}, os.Stdout)
 }
 
-type MIPSEVM struct {
-	env      *vm.EVM
-	evmState *state.StateDB
-	addrs    *Addresses
-}
-
-func NewMIPSEVM(contracts *Contracts, addrs *Addresses) *MIPSEVM {
-	env, evmState := NewEVMEnv(contracts, addrs)
-	return &MIPSEVM{env, evmState, addrs}
-}
-
-func (m *MIPSEVM) SetTracer(tracer vm.EVMLogger) {
-	m.env.Config.Tracer = tracer
-}
-
 // Step is a pure function that computes the poststate from the VM state encoded in the StepWitness.
 func (m *MIPSEVM) Step(t *testing.T, stepWitness *StepWitness) []byte {
-	sender := common.Address{0x13, 0x37}
-	startingGas := uint64(30_000_000)
-
-	// we take a snapshot so we can clean up the state, and isolate the logs of this instruction run.
-	snap := m.env.StateDB.Snapshot()
-
 	if stepWitness.HasPreimage() {
 		t.Logf("reading preimage key %x at offset %d", stepWitness.PreimageKey, stepWitness.PreimageOffset)
-		poInput, err := encodePreimageOracleInput(t, stepWitness, LocalContext{})
-		require.NoError(t, err, "encode preimage oracle input")
-		_, leftOverGas, err := m.env.Call(vm.AccountRef(sender), m.addrs.Oracle, poInput, startingGas, big.NewInt(0))
-		require.NoErrorf(t, err, "evm should not fail, took %d gas", startingGas-leftOverGas)
 	}
-
-	input := encodeStepInput(t, stepWitness, LocalContext{})
-	ret, leftOverGas, err := m.env.Call(vm.AccountRef(sender), m.addrs.MIPS, input, startingGas, big.NewInt(0))
+	evmPost, err := m.ExecuteStep(stepWitness)
 	require.NoError(t, err, "evm should not fail")
-	require.Len(t, ret, 32, "expecting 32-byte state hash")
-	// remember state hash, to check it against state
-	postHash := common.Hash(*(*[32]byte)(ret))
-	logs := m.evmState.Logs()
-	require.Equal(t, 1, len(logs), "expecting a log with post-state")
-	evmPost := logs[0].Data
-
-	stateHash, err := StateWitness(evmPost).StateHash()
-	require.NoError(t, err, "state hash could not be computed")
-	require.Equal(t, stateHash, postHash, "logged state must be accurate")
-
-	m.env.StateDB.RevertToSnapshot(snap)
-	t.Logf("EVM step took %d gas, and returned stateHash %s", startingGas-leftOverGas, postHash)
 	return evmPost
 }
 
 func encodeStepInput(t *testing.T, wit *StepWitness, localContext LocalContext) []byte {
-	mipsAbi, err := bindings.MIPSMetaData.GetAbi()
-	require.NoError(t, err)
-
-	input, err := mipsAbi.Pack("step", wit.State, wit.MemProof, localContext)
+	input, err := EncodeStepInput(wit, localContext)
 	require.NoError(t, err)
 	return input
 }
 
-func encodePreimageOracleInput(t *testing.T, wit *StepWitness, localContext LocalContext) ([]byte, error) {
-	if wit.PreimageKey == ([32]byte{}) {
-		return nil, errors.New("cannot encode pre-image oracle input, witness has no pre-image to proof")
-	}
-
-	preimageAbi, err := bindings.PreimageOracleMetaData.GetAbi()
-	require.NoError(t, err, "failed to load pre-image oracle ABI")
-
-	switch preimage.KeyType(wit.PreimageKey[0]) {
-	case preimage.LocalKeyType:
-		if len(wit.PreimageValue) > 32+8 {
-			return nil, fmt.Errorf("local pre-image exceeds maximum size of 32 bytes with key 0x%x", wit.PreimageKey)
-		}
-		preimagePart := wit.PreimageValue[8:]
-		var tmp [32]byte
-		copy(tmp[:], preimagePart)
-		input, err := preimageAbi.Pack("loadLocalData",
-			new(big.Int).SetBytes(wit.PreimageKey[1:]),
-			localContext,
-			tmp,
-			new(big.Int).SetUint64(uint64(len(preimagePart))),
-			new(big.Int).SetUint64(uint64(wit.PreimageOffset)),
-		)
-		require.NoError(t, err)
-		return input, nil
-	case preimage.Keccak256KeyType:
-		input, err := preimageAbi.Pack(
-			"loadKeccak256PreimagePart",
-			new(big.Int).SetUint64(uint64(wit.PreimageOffset)),
-			wit.PreimageValue[8:])
-		require.NoError(t, err)
-		return input, nil
-	default:
-		return nil, fmt.Errorf("unsupported pre-image type %d, cannot prepare preimage with key %x offset %d for oracle",
-			wit.PreimageKey[0], wit.PreimageKey, wit.PreimageOffset)
-	}
-}
-
 func TestEVM(t *testing.T) {
 	testFiles, err := os.ReadDir("open_mips_tests/test/bin")
 	require.NoError(t, err)
@@ -0,0 +1,97 @@
+package mipsevm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FPVMMemory is the memory of a fault proof VM, independent of its address size.
+type FPVMMemory interface {
+	PageCount() int
+	Usage() string
+	MerkleRoot() [32]byte
+}
+
+// FPVMState is the state of a fault proof VM, allowing single-threaded, multi-threaded and 64-bit states
+// to be used interchangeably.
+type FPVMState interface {
+	GetMemory() FPVMMemory
+	// GetPC returns the program counter of the thread that executes the next instruction.
+	GetPC() uint64
+	// GetInstruction returns the instruction at the program counter.
+	GetInstruction() uint32
+	GetStep() uint64
+	GetExited() bool
+	GetExitCode() uint8
+	GetPreimageKey() common.Hash
+	GetPreimageOffset() uint32
+	GetLastHint() hexutil.Bytes
+	VMStatus() uint8
+	EncodeWitness() StateWitness
+}
+
+// FPVM executes steps against an FPVMState.
+type FPVM interface {
+	GetState() FPVMState
+	Step(proof bool) (*StepWitness, error)
+	LastPreimage() []byte
+}
+
+var (
+	_ FPVMState = (*State)(nil)
+	_ FPVMState = (*MTState)(nil)
+	_ FPVMState = (*State64)(nil)
+	_ FPVM      = (*InstrumentedState)(nil)
+	_ FPVM      = (*MTInstrumentedState)(nil)
+	_ FPVM      = (*InstrumentedState64)(nil)
+)
+
+func (s *State) GetMemory() FPVMMemory         { return s.Memory }
+func (s *State) GetPC() uint64                 { return uint64(s.PC) }
+func (s *State) GetInstruction() uint32        { return s.Memory.GetMemory(s.PC) }
+func (s *State) GetStep() uint64               { return s.Step }
+func (s *State) GetExited() bool               { return s.Exited }
+func (s *State) GetExitCode() uint8            { return s.ExitCode }
+func (s *State) GetPreimageKey() common.Hash   { return s.PreimageKey }
+func (s *State) GetPreimageOffset() uint32     { return s.PreimageOffset }
+func (s *State) GetLastHint() hexutil.Bytes    { return s.LastHint }
+func (s *MTState) GetMemory() FPVMMemory       { return s.Memory }
+func (s *MTState) GetStep() uint64             { return s.Step }
+func (s *MTState) GetExited() bool             { return s.Exited }
+func (s *MTState) GetExitCode() uint8          { return s.ExitCode }
+func (s *MTState) GetPreimageKey() common.Hash { return s.PreimageKey }
+func (s *MTState) GetPreimageOffset() uint32   { return s.PreimageOffset }
+func (s *MTState) GetLastHint() hexutil.Bytes  { return s.LastHint }
+func (s *State64) GetMemory() FPVMMemory       { return s.Memory }
+func (s *State64) GetPC() uint64               { return s.PC }
+func (s *State64) GetInstruction() uint32      { return s.Memory.GetWord(s.PC) }
+func (s *State64) GetStep() uint64             { return s.Step }
+func (s *State64) GetExited() bool             { return s.Exited }
+func (s *State64) GetExitCode() uint8          { return s.ExitCode }
+func (s *State64) GetPreimageKey() common.Hash { return s.PreimageKey }
+func (s *State64) GetPreimageOffset() uint32   { return s.PreimageOffset }
+func (s *State64) GetLastHint() hexutil.Bytes  { return s.LastHint }
+
+func (s *MTState) GetPC() uint64 {
+	thread := s.CurrentThread()
+	if thread == nil {
+		return 0
+	}
+	return uint64(thread.PC)
+}
+
+func (s *MTState) GetInstruction() uint32 {
+	return s.Memory.GetMemory(uint32(s.GetPC()))
+}
+
+func (m *InstrumentedState) GetState() FPVMState {
+	return m.state
+}
+
+func (m *MTInstrumentedState) GetState() FPVMState {
+	return m.state
+}
+
+func (m *InstrumentedState64) GetState() FPVMState {
+	return m.state
+}
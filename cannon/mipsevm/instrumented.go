@@ -10,23 +10,9 @@ type PreimageOracle interface {
 }
 
 type InstrumentedState struct {
-	state *State
-
-	stdOut io.Writer
-	stdErr io.Writer
-
-	lastMemAccess   uint32
-	memProofEnabled bool
-	memProof        [28 * 32]byte
-
-	preimageOracle PreimageOracle
+	vmIO[uint32]
 
-	// cached pre-image data, including 8 byte length prefix
-	lastPreimage []byte
-	// key for above preimage
-	lastPreimageKey [32]byte
-	// offset we last read from, or max uint32 if nothing is read this step
-	lastPreimageOffset uint32
+	state *State
 }
 
 const (
@@ -40,23 +26,21 @@ const (
 )
 
 const (
-	MipsEBADF  = 0x9
-	MipsEINVAL = 0x16
+	MipsEBADF     = 0x9
+	MipsEAGAIN    = 0xb
+	MipsEINVAL    = 0x16
+	MipsETIMEDOUT = 0x91
 )
 
 func NewInstrumentedState(state *State, po PreimageOracle, stdOut, stdErr io.Writer) *InstrumentedState {
 	return &InstrumentedState{
-		state:          state,
-		stdOut:         stdOut,
-		stdErr:         stdErr,
-		preimageOracle: po,
+		vmIO:  newVMIO[uint32](po, stdOut, stdErr),
+		state: state,
 	}
 }
 
 func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
-	m.memProofEnabled = proof
-	m.lastMemAccess = ^uint32(0)
-	m.lastPreimageOffset = ^uint32(0)
+	m.startStep(m.state.Memory, proof)
 
 	if proof {
 		insnProof := m.state.Memory.MerkleProof(m.state.PC)
@@ -71,16 +55,7 @@ func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
 	}
 
 	if proof {
-		wit.MemProof = append(wit.MemProof, m.memProof[:]...)
-		if m.lastPreimageOffset != ^uint32(0) {
-			wit.PreimageOffset = m.lastPreimageOffset
-			wit.PreimageKey = m.lastPreimageKey
-			wit.PreimageValue = m.lastPreimage
-		}
+		m.completeWitness(wit)
 	}
 	return
 }
-
-func (m *InstrumentedState) LastPreimage() []byte {
-	return m.lastPreimage
-}
@@ -0,0 +1,40 @@
+package mipsevm
+
+import (
+	"io"
+)
+
+// InstrumentedState64 executes steps of a MIPS64 VM.
+type InstrumentedState64 struct {
+	vmIO[uint64]
+
+	state *State64
+}
+
+func NewInstrumentedState64(state *State64, po PreimageOracle, stdOut, stdErr io.Writer) *InstrumentedState64 {
+	return &InstrumentedState64{
+		vmIO:  newVMIO[uint64](po, stdOut, stdErr),
+		state: state,
+	}
+}
+
+func (m *InstrumentedState64) Step(proof bool) (wit *StepWitness, err error) {
+	m.startStep(m.state.Memory, proof)
+
+	if proof {
+		insnProof := m.state.Memory.MerkleProof(m.state.PC)
+		wit = &StepWitness{
+			State:    m.state.EncodeWitness(),
+			MemProof: insnProof[:],
+		}
+	}
+	err = m.mipsStep()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof {
+		m.completeWitness(wit)
+	}
+	return
+}
@@ -325,7 +325,12 @@ func (m *Memory) ReadMemoryRange(addr uint32, count uint32) io.Reader {
 }
 
 func (m *Memory) Usage() string {
-	total := uint64(len(m.pages)) * PageSize
+	return memoryUsage(len(m.pages))
+}
+
+// memoryUsage formats the size of the given number of allocated pages.
+func memoryUsage(pageCount int) string {
+	total := uint64(pageCount) * PageSize
 	const unit = 1024
 	if total < unit {
 		return fmt.Sprintf("%d B", total)
@@ -338,3 +343,18 @@ func (m *Memory) Usage() string {
 	// KiB, MiB, GiB, TiB, ...
 	return fmt.Sprintf("%.1f %ciB", float64(total)/float64(div), "KMGTPE"[exp])
 }
+
+// readWord, writeWord and merkleProof implement wordMemory for the 32-bit VMs.
+
+func (m *Memory) readWord(addr uint32) uint32 {
+	return m.GetMemory(addr)
+}
+
+func (m *Memory) writeWord(addr uint32, v uint32) {
+	m.SetMemory(addr, v)
+}
+
+func (m *Memory) merkleProof(addr uint32) []byte {
+	proof := m.MerkleProof(addr)
+	return proof[:]
+}
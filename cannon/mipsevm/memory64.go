@@ -0,0 +1,339 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// Page layout of the 64-bit address space. Pages are the same size as in the 32-bit memory.
+const (
+	PageKeySize64  = 64 - PageAddrSize
+	PageKeyMask64  = (1 << PageKeySize64) - 1
+	MemProofLeaf64 = 64 - 5
+	// MemProofSize64 is the size in bytes of a 64-bit memory proof: the leaf and a sibling for each level.
+	MemProofSize64 = (MemProofLeaf64 + 1) * 32
+)
+
+// Memory64 is a merkleized, sparsely allocated 64-bit address space.
+// It mirrors Memory, but is addressed by uint64 and accessed in 8-byte doublewords.
+type Memory64 struct {
+	// generalized index -> merkle root or nil if invalidated
+	nodes map[uint64]*[32]byte
+
+	// pageIndex -> cached page
+	pages map[uint64]*CachedPage
+
+	lastPageKeys [2]uint64
+	lastPage     [2]*CachedPage
+}
+
+func NewMemory64() *Memory64 {
+	return &Memory64{
+		nodes:        make(map[uint64]*[32]byte),
+		pages:        make(map[uint64]*CachedPage),
+		lastPageKeys: [2]uint64{^uint64(0), ^uint64(0)}, // default to invalid keys, to not match any pages
+	}
+}
+
+func (m *Memory64) PageCount() int {
+	return len(m.pages)
+}
+
+func (m *Memory64) ForEachPage(fn func(pageIndex uint64, page *Page) error) error {
+	for pageIndex, cachedPage := range m.pages {
+		if err := fn(pageIndex, cachedPage.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory64) Invalidate(addr uint64) {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+
+	// find page, and invalidate addr within it
+	if p, ok := m.pageLookup(addr >> PageAddrSize); ok {
+		prevValid := p.Ok[1]
+		p.Invalidate(uint32(addr & PageAddrMask))
+		if !prevValid { // if the page was already invalid before, then nodes to mem-root will also still be.
+			return
+		}
+	} else { // no page? nothing to invalidate
+		return
+	}
+
+	// find the gindex of the page covering the address
+	gindex := (uint64(1) << PageKeySize64) | (addr >> PageAddrSize)
+
+	for gindex > 0 {
+		m.nodes[gindex] = nil
+		gindex >>= 1
+	}
+}
+
+func (m *Memory64) MerkleizeSubtree(gindex uint64) [32]byte {
+	l := uint64(bits.Len64(gindex))
+	if l > MemProofLeaf64+1 {
+		panic("gindex too deep")
+	}
+	if l > PageKeySize64 {
+		depthIntoPage := l - 1 - PageKeySize64
+		pageIndex := (gindex >> depthIntoPage) & PageKeyMask64
+		if p, ok := m.pages[pageIndex]; ok {
+			pageGindex := (1 << depthIntoPage) | (gindex & ((1 << depthIntoPage) - 1))
+			return p.MerkleizeSubtree(pageGindex)
+		} else {
+			return zeroHashes[MemProofLeaf64+1-l] // page does not exist
+		}
+	}
+	n, ok := m.nodes[gindex]
+	if !ok {
+		// if the node doesn't exist, the whole sub-tree is zeroed
+		return zeroHashes[MemProofLeaf64+1-l]
+	}
+	if n != nil {
+		return *n
+	}
+	left := m.MerkleizeSubtree(gindex << 1)
+	right := m.MerkleizeSubtree((gindex << 1) | 1)
+	r := HashPair(left, right)
+	m.nodes[gindex] = &r
+	return r
+}
+
+func (m *Memory64) MerkleProof(addr uint64) (out [MemProofSize64]byte) {
+	proof := m.traverseBranch(1, addr, 0)
+	// encode the proof
+	for i := 0; i < MemProofLeaf64+1; i++ {
+		copy(out[i*32:(i+1)*32], proof[i][:])
+	}
+	return out
+}
+
+func (m *Memory64) traverseBranch(parent uint64, addr uint64, depth uint8) (proof [][32]byte) {
+	if depth == MemProofLeaf64 {
+		proof = make([][32]byte, 0, MemProofLeaf64+1)
+		proof = append(proof, m.MerkleizeSubtree(parent))
+		return
+	}
+	if depth > MemProofLeaf64 {
+		panic("traversed too deep")
+	}
+	self := parent << 1
+	sibling := self | 1
+	if addr&(1<<(63-depth)) != 0 {
+		self, sibling = sibling, self
+	}
+	proof = m.traverseBranch(self, addr, depth+1)
+	siblingNode := m.MerkleizeSubtree(sibling)
+	proof = append(proof, siblingNode)
+	return
+}
+
+func (m *Memory64) MerkleRoot() [32]byte {
+	return m.MerkleizeSubtree(1)
+}
+
+func (m *Memory64) pageLookup(pageIndex uint64) (*CachedPage, bool) {
+	// hit caches
+	if pageIndex == m.lastPageKeys[0] {
+		return m.lastPage[0], true
+	}
+	if pageIndex == m.lastPageKeys[1] {
+		return m.lastPage[1], true
+	}
+	p, ok := m.pages[pageIndex]
+
+	// only cache existing pages.
+	if ok {
+		m.lastPageKeys[1] = m.lastPageKeys[0]
+		m.lastPage[1] = m.lastPage[0]
+		m.lastPageKeys[0] = pageIndex
+		m.lastPage[0] = p
+	}
+
+	return p, ok
+}
+
+// SetDoubleword writes the 8-byte value at the given address, which must be aligned to 8 bytes.
+func (m *Memory64) SetDoubleword(addr uint64, v uint64) {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+
+	pageIndex := addr >> PageAddrSize
+	pageAddr := addr & PageAddrMask
+	p, ok := m.pageLookup(pageIndex)
+	if !ok {
+		// allocate the page if we have not already.
+		p = m.AllocPage(pageIndex)
+	} else {
+		m.Invalidate(addr) // invalidate this branch of memory, now that the value changed
+	}
+	binary.BigEndian.PutUint64(p.Data[pageAddr:pageAddr+8], v)
+}
+
+// GetDoubleword reads the 8-byte value at the given address, which must be aligned to 8 bytes.
+func (m *Memory64) GetDoubleword(addr uint64) uint64 {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+	p, ok := m.pageLookup(addr >> PageAddrSize)
+	if !ok {
+		return 0
+	}
+	pageAddr := addr & PageAddrMask
+	return binary.BigEndian.Uint64(p.Data[pageAddr : pageAddr+8])
+}
+
+// GetWord reads the 4-byte value at the given address, which must be aligned to 4 bytes.
+// Instructions remain 32 bits wide in MIPS64, and are fetched with this.
+func (m *Memory64) GetWord(addr uint64) uint32 {
+	// addr must be aligned to 4 bytes
+	if addr&0x3 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+	p, ok := m.pageLookup(addr >> PageAddrSize)
+	if !ok {
+		return 0
+	}
+	pageAddr := addr & PageAddrMask
+	return binary.BigEndian.Uint32(p.Data[pageAddr : pageAddr+4])
+}
+
+func (m *Memory64) AllocPage(pageIndex uint64) *CachedPage {
+	p := &CachedPage{Data: new(Page)}
+	m.pages[pageIndex] = p
+	// make nodes to root
+	k := (1 << PageKeySize64) | pageIndex
+	for k > 0 {
+		m.nodes[k] = nil
+		k >>= 1
+	}
+	return p
+}
+
+type pageEntry64 struct {
+	Index uint64 `json:"index"`
+	Data  *Page  `json:"data"`
+}
+
+func (m *Memory64) MarshalJSON() ([]byte, error) { // nosemgrep
+	pages := make([]pageEntry64, 0, len(m.pages))
+	for k, p := range m.pages {
+		pages = append(pages, pageEntry64{
+			Index: k,
+			Data:  p.Data,
+		})
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Index < pages[j].Index
+	})
+	return json.Marshal(pages)
+}
+
+func (m *Memory64) UnmarshalJSON(data []byte) error {
+	var pages []pageEntry64
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return err
+	}
+	m.nodes = make(map[uint64]*[32]byte)
+	m.pages = make(map[uint64]*CachedPage)
+	m.lastPageKeys = [2]uint64{^uint64(0), ^uint64(0)}
+	m.lastPage = [2]*CachedPage{nil, nil}
+	for i, p := range pages {
+		if p.Index > PageKeyMask64 {
+			return fmt.Errorf("page index %d of entry %d is out of range", p.Index, i)
+		}
+		if _, ok := m.pages[p.Index]; ok {
+			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, p.Index)
+		}
+		m.AllocPage(p.Index).Data = p.Data
+	}
+	return nil
+}
+
+func (m *Memory64) SetMemoryRange(addr uint64, r io.Reader) error {
+	for {
+		pageIndex := addr >> PageAddrSize
+		pageAddr := addr & PageAddrMask
+		p, ok := m.pageLookup(pageIndex)
+		if !ok {
+			p = m.AllocPage(pageIndex)
+		}
+		p.InvalidateFull()
+		n, err := r.Read(p.Data[pageAddr:])
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		addr += uint64(n)
+	}
+}
+
+type memReader64 struct {
+	m     *Memory64
+	addr  uint64
+	count uint64
+}
+
+func (r *memReader64) Read(dest []byte) (n int, err error) {
+	if r.count == 0 {
+		return 0, io.EOF
+	}
+
+	// Keep iterating over memory until we have all our data.
+	// It may wrap around the address range, and may not be aligned
+	endAddr := r.addr + r.count
+
+	pageIndex := r.addr >> PageAddrSize
+	start := r.addr & PageAddrMask
+	end := uint64(PageSize)
+
+	if pageIndex == (endAddr >> PageAddrSize) {
+		end = endAddr & PageAddrMask
+	}
+	p, ok := r.m.pageLookup(pageIndex)
+	if ok {
+		n = copy(dest, p.Data[start:end])
+	} else {
+		n = copy(dest, make([]byte, end-start)) // default to zeroes
+	}
+	r.addr += uint64(n)
+	r.count -= uint64(n)
+	return n, nil
+}
+
+func (m *Memory64) ReadMemoryRange(addr uint64, count uint64) io.Reader {
+	return &memReader64{m: m, addr: addr, count: count}
+}
+
+func (m *Memory64) Usage() string {
+	return memoryUsage(len(m.pages))
+}
+
+// readWord, writeWord and merkleProof implement wordMemory for the 64-bit VM.
+
+func (m *Memory64) readWord(addr uint64) uint64 {
+	return m.GetDoubleword(addr)
+}
+
+func (m *Memory64) writeWord(addr uint64, v uint64) {
+	m.SetDoubleword(addr, v)
+}
+
+func (m *Memory64) merkleProof(addr uint64) []byte {
+	proof := m.MerkleProof(addr)
+	return proof[:]
+}
@@ -0,0 +1,107 @@
+package mipsevm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory64MerkleProof(t *testing.T) {
+	t.Run("nearly empty tree", func(t *testing.T) {
+		m := NewMemory64()
+		m.SetDoubleword(0x10000, 0xaabbccdd_11223344)
+		proof := m.MerkleProof(0x10000)
+		require.Equal(t, uint64(0xaabbccdd_11223344), binary.BigEndian.Uint64(proof[:8]))
+		for i := 0; i < MemProofLeaf64; i++ {
+			require.Equal(t, zeroHashes[i][:], proof[32+i*32:32+i*32+32], "empty siblings")
+		}
+	})
+	t.Run("fuller tree", func(t *testing.T) {
+		m := NewMemory64()
+		m.SetDoubleword(0x10000, 0xaabbccdd)
+		m.SetDoubleword(0x80008, 42)
+		m.SetDoubleword(0x13370000, 123)
+		m.SetDoubleword(0xc000_0000_1000, 7)
+		root := m.MerkleRoot()
+		for _, addr := range []uint64{0x80008, 0xc000_0000_1000} {
+			proof := m.MerkleProof(addr)
+			node := *(*[32]byte)(proof[:32])
+			path := addr >> 5
+			for i := 32; i < len(proof); i += 32 {
+				sib := *(*[32]byte)(proof[i : i+32])
+				if path&1 != 0 {
+					node = HashPair(sib, node)
+				} else {
+					node = HashPair(node, sib)
+				}
+				path >>= 1
+			}
+			require.Equal(t, root, node, "proof must verify")
+		}
+	})
+}
+
+func TestMemory64MerkleRoot(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		m := NewMemory64()
+		root := m.MerkleRoot()
+		require.Equal(t, zeroHashes[MemProofLeaf64], root, "fully zeroed memory should have expected zero hash")
+	})
+	t.Run("empty page", func(t *testing.T) {
+		m := NewMemory64()
+		m.SetDoubleword(0xF000, 0)
+		root := m.MerkleRoot()
+		require.Equal(t, zeroHashes[MemProofLeaf64], root, "fully zeroed memory should have expected zero hash")
+	})
+	t.Run("high page", func(t *testing.T) {
+		m := NewMemory64()
+		m.SetDoubleword(0xFFFF_FFFF_FFFF_FFF8, 1)
+		root := m.MerkleRoot()
+		require.NotEqual(t, zeroHashes[MemProofLeaf64], root, "non-zero memory")
+		m.SetDoubleword(0xFFFF_FFFF_FFFF_FFF8, 0)
+		root = m.MerkleRoot()
+		require.Equal(t, zeroHashes[MemProofLeaf64], root, "zero again")
+	})
+}
+
+func TestMemory64ReadWrite(t *testing.T) {
+	t.Run("doubleword and word", func(t *testing.T) {
+		m := NewMemory64()
+		m.SetDoubleword(0x1_0000_1000, 0x11223344_55667788)
+		require.Equal(t, uint64(0x11223344_55667788), m.GetDoubleword(0x1_0000_1000))
+		require.Equal(t, uint32(0x11223344), m.GetWord(0x1_0000_1000))
+		require.Equal(t, uint32(0x55667788), m.GetWord(0x1_0000_1004))
+		require.Zero(t, m.GetDoubleword(0x1000), "lower 32 bits of the address must not alias")
+	})
+	t.Run("range across pages", func(t *testing.T) {
+		m := NewMemory64()
+		data := bytes.Repeat([]byte("0123456789"), 1000)
+		addr := uint64(0xc000_0000_0ffc)
+		require.NoError(t, m.SetMemoryRange(addr, bytes.NewReader(data)))
+		res, err := io.ReadAll(m.ReadMemoryRange(addr, uint64(len(data))))
+		require.NoError(t, err)
+		require.Equal(t, data, res)
+	})
+	t.Run("unaligned", func(t *testing.T) {
+		m := NewMemory64()
+		require.Panics(t, func() { m.SetDoubleword(4, 1) })
+		require.Panics(t, func() { m.GetDoubleword(4) })
+	})
+}
+
+func TestMemory64JSON(t *testing.T) {
+	m := NewMemory64()
+	m.SetDoubleword(0x8, 123)
+	m.SetDoubleword(0xFFFF_0000_0000_0008, 456)
+	dat, err := json.Marshal(m)
+	require.NoError(t, err)
+	var res Memory64
+	require.NoError(t, json.Unmarshal(dat, &res))
+	require.Equal(t, m.MerkleRoot(), res.MerkleRoot())
+	require.Equal(t, uint64(123), res.GetDoubleword(0x8))
+	require.Equal(t, uint64(456), res.GetDoubleword(0xFFFF_0000_0000_0008))
+}
@@ -0,0 +1,424 @@
+package mipsevm
+
+import (
+	"math/bits"
+)
+
+// Syscall numbers of the MIPS64 n64 ABI.
+const (
+	sysRead64      = 5000
+	sysWrite64     = 5001
+	sysMmap64      = 5009
+	sysBrk64       = 5012
+	sysClone64     = 5055
+	sysFcntl64     = 5070
+	sysExitGroup64 = 5205
+)
+
+// Heap64Start is the initial mmap heap address of a MIPS64 program.
+// It leaves room for the Go runtime, which places its arenas at hinted addresses from 0xc000000000 upwards.
+const Heap64Start = 0x10_00_00_00_00
+
+// brk64Start is returned to the program as the program break, which is never moved.
+const brk64Start = 0x1_00_00_00_00_00
+
+func (m *InstrumentedState64) handleSyscall() error {
+	syscallNum := m.state.Registers[2] // v0
+	v0 := uint64(0)
+	v1 := uint64(0)
+
+	a0 := m.state.Registers[4]
+	a1 := m.state.Registers[5]
+	a2 := m.state.Registers[6]
+
+	switch syscallNum {
+	case sysMmap64:
+		v0 = handleMmap(m.sys(), a0, a1)
+	case sysBrk64:
+		v0 = brk64Start
+	case sysClone64: // clone (not supported)
+		v0 = 1
+	case sysExitGroup64:
+		m.state.Exited = true
+		m.state.ExitCode = uint8(a0)
+		return nil
+	case sysRead64:
+		v0, v1 = m.handleRead(m.sys(), a0, a1, a2)
+	case sysWrite64:
+		v0, v1 = m.handleWrite(m.sys(), a0, a1, a2)
+	case sysFcntl64:
+		v0, v1 = handleFcntl(a0, a1)
+	}
+	m.state.Registers[2] = v0
+	m.state.Registers[7] = v1
+
+	m.state.PC = m.state.NextPC
+	m.state.NextPC = m.state.NextPC + 4
+	return nil
+}
+
+// sys returns the state of the VM that syscalls operate on.
+func (m *InstrumentedState64) sys() sysState[uint64] {
+	return sysState[uint64]{Memory: m, Heap: &m.state.Heap, PreimageKey: &m.state.PreimageKey, PreimageOffset: &m.state.PreimageOffset, LastHint: &m.state.LastHint}
+}
+
+// cpu returns the CPU state of the VM, to execute instructions on.
+func (m *InstrumentedState64) cpu() cpuState[uint64] {
+	return cpuState[uint64]{PC: &m.state.PC, NextPC: &m.state.NextPC, LO: &m.state.LO, HI: &m.state.HI, Registers: &m.state.Registers}
+}
+
+// isMemoryOp64 returns true for the load and store opcodes, which all access the memory doubleword of the
+// effective address. ldl and ldr are the only memory instructions with an opcode below 0x20.
+func isMemoryOp64(opcode uint32) bool {
+	return opcode >= 0x20 || opcode == 0x1a || opcode == 0x1b
+}
+
+// isLoad64 returns true for the memory opcodes of at least 0x28 that load, rather than store, a value.
+func isLoad64(opcode uint32) bool {
+	return opcode == 0x30 || opcode == 0x34 || opcode == 0x37 // ll, lld, ld
+}
+
+func (m *InstrumentedState64) mipsStep() error {
+	if m.state.Exited {
+		return nil
+	}
+	m.state.Step += 1
+	// instruction fetch
+	insn := m.state.Memory.GetWord(m.state.PC)
+	opcode := insn >> 26 // 6-bits
+
+	// j-type j/jal
+	if opcode == 2 || opcode == 3 {
+		linkReg := uint32(0)
+		if opcode == 3 {
+			linkReg = 31
+		}
+		// Take the top bits of the next PC (its 256 MB region), and concatenate with the 26-bit offset
+		target := (m.state.NextPC & 0xFFFFFFFF_F0000000) | uint64((insn&0x03FFFFFF)<<2)
+		return handleJump(m.cpu(), linkReg, target)
+	}
+
+	// register fetch
+	rs, rt, rtReg, rdReg := fetchOperands(&m.state.Registers, insn)
+
+	if (opcode >= 4 && opcode < 8) || opcode == 1 {
+		return handleBranch(m.cpu(), opcode, insn, rtReg, rs)
+	}
+
+	storeAddr := ^uint64(0)
+	// memory fetch (all I-type)
+	// we do the load for stores also
+	mem := uint64(0)
+	if isMemoryOp64(opcode) {
+		// M[R[rs]+SignExtImm]
+		rs += SE64(uint64(insn&0xFFFF), 16)
+		addr := rs &^ 7
+		mem = m.loadMemory(addr)
+		if opcode >= 0x28 && !isLoad64(opcode) {
+			// store
+			storeAddr = addr
+			// store opcodes don't write back to a register
+			rdReg = 0
+		}
+	}
+
+	// ALU
+	val := execute64(insn, rs, rt, mem)
+
+	fun := insn & 0x3f // 6-bits
+	if opcode == 0 && fun >= 8 && fun < 0x20 {
+		if fun == 8 || fun == 9 { // jr/jalr
+			linkReg := uint32(0)
+			if fun == 9 {
+				linkReg = rdReg
+			}
+			return handleJump(m.cpu(), linkReg, rs)
+		}
+
+		if fun == 0xa { // movz
+			return handleRd(m.cpu(), rdReg, rs, rt == 0)
+		}
+		if fun == 0xb { // movn
+			return handleRd(m.cpu(), rdReg, rs, rt != 0)
+		}
+
+		// syscall (can read and write)
+		if fun == 0xC {
+			return m.handleSyscall()
+		}
+
+		// lo and hi registers
+		// can write back
+		if (fun >= 0x10 && fun < 0x14) || fun >= 0x18 {
+			return handleHiLo(m.cpu(), fun, rs, rt, rdReg)
+		}
+	}
+
+	// stupid sc and scd, write a 1 to rt
+	if (opcode == 0x38 || opcode == 0x3c) && rtReg != 0 {
+		m.state.Registers[rtReg] = 1
+	}
+
+	// write memory
+	if storeAddr != ^uint64(0) {
+		m.storeMemory(storeAddr, val)
+	}
+
+	// write back the value to destination register
+	return handleRd(m.cpu(), rdReg, val, true)
+}
+
+// execute64 computes the result of an ALU, load or store instruction.
+// 32-bit operations use the lower half of their operands and sign-extend their result to 64 bits.
+// For memory instructions rs is the effective address and mem the doubleword containing it.
+// Stores return the updated doubleword.
+func execute64(insn uint32, rs uint64, rt uint64, mem uint64) uint64 {
+	opcode := insn >> 26 // 6-bits
+
+	if opcode == 0 || (opcode >= 8 && opcode < 0xF) || opcode == 0x18 || opcode == 0x19 {
+		fun := insn & 0x3f // 6-bits
+		// transform ArithLogI to SPECIAL
+		switch opcode {
+		case 8:
+			fun = 0x20 // addi
+		case 9:
+			fun = 0x21 // addiu
+		case 0xA:
+			fun = 0x2A // slti
+		case 0xB:
+			fun = 0x2B // sltiu
+		case 0xC:
+			fun = 0x24 // andi
+		case 0xD:
+			fun = 0x25 // ori
+		case 0xE:
+			fun = 0x26 // xori
+		case 0x18:
+			fun = 0x2C // daddi
+		case 0x19:
+			fun = 0x2D // daddiu
+		}
+
+		shamt := uint64((insn >> 6) & 0x1F)
+		switch fun {
+		case 0x00: // sll
+			return SE64(uint64(uint32(rt)<<shamt), 32)
+		case 0x02: // srl
+			return SE64(uint64(uint32(rt)>>shamt), 32)
+		case 0x03: // sra
+			return SE64(uint64(int32(rt)>>shamt), 32)
+		case 0x04: // sllv
+			return SE64(uint64(uint32(rt)<<(rs&0x1F)), 32)
+		case 0x06: // srlv
+			return SE64(uint64(uint32(rt)>>(rs&0x1F)), 32)
+		case 0x07: // srav
+			return SE64(uint64(int32(rt)>>(rs&0x1F)), 32)
+		// functs in range [0x8, 0x1f] are handled specially by other functions, except for the 64-bit variable shifts
+		case 0x08: // jr
+			return rs
+		case 0x09: // jalr
+			return rs
+		case 0x0a: // movz
+			return rs
+		case 0x0b: // movn
+			return rs
+		case 0x0c: // syscall
+			return rs
+		// 0x0d - break not supported
+		case 0x0f: // sync
+			return rs
+		case 0x10: // mfhi
+			return rs
+		case 0x11: // mthi
+			return rs
+		case 0x12: // mflo
+			return rs
+		case 0x13: // mtlo
+			return rs
+		case 0x14: // dsllv
+			return rt << (rs & 0x3F)
+		case 0x16: // dsrlv
+			return rt >> (rs & 0x3F)
+		case 0x17: // dsrav
+			return uint64(int64(rt) >> (rs & 0x3F))
+		case 0x18: // mult
+			return rs
+		case 0x19: // multu
+			return rs
+		case 0x1a: // div
+			return rs
+		case 0x1b: // divu
+			return rs
+		case 0x1c: // dmult
+			return rs
+		case 0x1d: // dmultu
+			return rs
+		case 0x1e: // ddiv
+			return rs
+		case 0x1f: // ddivu
+			return rs
+		// The rest includes transformed R-type arith imm instructions
+		case 0x20: // add
+			return SE64(uint64(uint32(rs)+uint32(rt)), 32)
+		case 0x21: // addu
+			return SE64(uint64(uint32(rs)+uint32(rt)), 32)
+		case 0x22: // sub
+			return SE64(uint64(uint32(rs)-uint32(rt)), 32)
+		case 0x23: // subu
+			return SE64(uint64(uint32(rs)-uint32(rt)), 32)
+		case 0x24: // and
+			return rs & rt
+		case 0x25: // or
+			return rs | rt
+		case 0x26: // xor
+			return rs ^ rt
+		case 0x27: // nor
+			return ^(rs | rt)
+		case 0x2a: // slti
+			if int64(rs) < int64(rt) {
+				return 1
+			}
+			return 0
+		case 0x2b: // sltiu
+			if rs < rt {
+				return 1
+			}
+			return 0
+		case 0x2c: // dadd
+			return rs + rt
+		case 0x2d: // daddu
+			return rs + rt
+		case 0x2e: // dsub
+			return rs - rt
+		case 0x2f: // dsubu
+			return rs - rt
+		case 0x38: // dsll
+			return rt << shamt
+		case 0x3a: // dsrl
+			return rt >> shamt
+		case 0x3b: // dsra
+			return uint64(int64(rt) >> shamt)
+		case 0x3c: // dsll32
+			return rt << (shamt + 32)
+		case 0x3e: // dsrl32
+			return rt >> (shamt + 32)
+		case 0x3f: // dsra32
+			return uint64(int64(rt) >> (shamt + 32))
+		default:
+			panic("invalid instruction")
+		}
+	} else {
+		// sub-word memory accesses are addressed within the big-endian doubleword
+		switch opcode {
+		// SPECIAL2
+		case 0x1C:
+			fun := insn & 0x3f // 6-bits
+			switch fun {
+			case 0x2: // mul
+				return SE64(uint64(int32(rs)*int32(rt)), 32)
+			case 0x20, 0x21: // clz, clo
+				v := uint32(rs)
+				if fun == 0x21 {
+					v = ^v
+				}
+				return uint64(bits.LeadingZeros32(v))
+			case 0x24, 0x25: // dclz, dclo
+				if fun == 0x25 {
+					rs = ^rs
+				}
+				return uint64(bits.LeadingZeros64(rs))
+			}
+		case 0x0F: // lui
+			return SE64(uint64(uint32(rt)<<16), 32)
+		case 0x1a: // ldl
+			val := mem << ((rs & 7) * 8)
+			mask := ^uint64(0) << ((rs & 7) * 8)
+			return (rt & ^mask) | val
+		case 0x1b: // ldr
+			val := mem >> (56 - (rs&7)*8)
+			mask := ^uint64(0) >> (56 - (rs&7)*8)
+			return (rt & ^mask) | val
+		case 0x20: // lb
+			return SE64((mem>>(56-(rs&7)*8))&0xFF, 8)
+		case 0x21: // lh
+			return SE64((mem>>(48-(rs&6)*8))&0xFFFF, 16)
+		case 0x22: // lwl
+			w := uint32(mem >> (32 - (rs&4)*8))
+			val := w << ((rs & 3) * 8)
+			mask := uint32(0xFFFFFFFF) << ((rs & 3) * 8)
+			return SE64(uint64((uint32(rt)&^mask)|val), 32)
+		case 0x23: // lw
+			return SE64(mem>>(32-(rs&4)*8), 32)
+		case 0x24: // lbu
+			return (mem >> (56 - (rs&7)*8)) & 0xFF
+		case 0x25: //  lhu
+			return (mem >> (48 - (rs&6)*8)) & 0xFFFF
+		case 0x26: //  lwr
+			w := uint32(mem >> (32 - (rs&4)*8))
+			val := w >> (24 - (rs&3)*8)
+			mask := uint32(0xFFFFFFFF) >> (24 - (rs&3)*8)
+			return SE64(uint64((uint32(rt)&^mask)|val), 32)
+		case 0x27: //  lwu
+			return (mem >> (32 - (rs&4)*8)) & 0xFFFFFFFF
+		case 0x28: //  sb
+			sl := 56 - (rs&7)*8
+			val := (rt & 0xFF) << sl
+			mask := ^(uint64(0xFF) << sl)
+			return (mem & mask) | val
+		case 0x29: //  sh
+			sl := 48 - (rs&6)*8
+			val := (rt & 0xFFFF) << sl
+			mask := ^(uint64(0xFFFF) << sl)
+			return (mem & mask) | val
+		case 0x2a: //  swl
+			sl := 32 - (rs&4)*8
+			w := uint32(mem >> sl)
+			val := uint32(rt) >> ((rs & 3) * 8)
+			mask := uint32(0xFFFFFFFF) >> ((rs & 3) * 8)
+			return setWord64(mem, sl, (w & ^mask)|val)
+		case 0x2b: //  sw
+			return setWord64(mem, 32-(rs&4)*8, uint32(rt))
+		case 0x2c: //  sdl
+			val := rt >> ((rs & 7) * 8)
+			mask := ^uint64(0) >> ((rs & 7) * 8)
+			return (mem & ^mask) | val
+		case 0x2d: //  sdr
+			val := rt << (56 - (rs&7)*8)
+			mask := ^uint64(0) << (56 - (rs&7)*8)
+			return (mem & ^mask) | val
+		case 0x2e: //  swr
+			sl := 32 - (rs&4)*8
+			w := uint32(mem >> sl)
+			val := uint32(rt) << (24 - (rs&3)*8)
+			mask := uint32(0xFFFFFFFF) << (24 - (rs&3)*8)
+			return setWord64(mem, sl, (w & ^mask)|val)
+		case 0x30: //  ll
+			return SE64(mem>>(32-(rs&4)*8), 32)
+		case 0x34: //  lld
+			return mem
+		case 0x37: //  ld
+			return mem
+		case 0x38: //  sc
+			return setWord64(mem, 32-(rs&4)*8, uint32(rt))
+		case 0x3c: //  scd
+			return rt
+		case 0x3f: //  sd
+			return rt
+		default:
+			panic("invalid instruction")
+		}
+	}
+	panic("invalid instruction")
+}
+
+// setWord64 replaces the 32-bit word at the given bit shift of a doubleword.
+func setWord64(mem uint64, shift uint64, w uint32) uint64 {
+	mask := uint64(0xFFFFFFFF) << shift
+	return (mem & ^mask) | (uint64(w) << shift)
+}
+
+// SE64 sign-extends the lowest idx bits of dat to 64 bits.
+func SE64(dat uint64, idx uint64) uint64 {
+	shift := 64 - idx
+	return uint64(int64(dat<<shift) >> shift)
+}
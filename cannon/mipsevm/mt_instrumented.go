@@ -0,0 +1,41 @@
+package mipsevm
+
+import (
+	"io"
+)
+
+// MTInstrumentedState executes steps of a multi-threaded VM.
+type MTInstrumentedState struct {
+	vmIO[uint32]
+
+	state *MTState
+}
+
+func NewMTInstrumentedState(state *MTState, po PreimageOracle, stdOut, stdErr io.Writer) *MTInstrumentedState {
+	return &MTInstrumentedState{
+		vmIO:  newVMIO[uint32](po, stdOut, stdErr),
+		state: state,
+	}
+}
+
+func (m *MTInstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
+	m.startStep(m.state.Memory, proof)
+
+	if proof {
+		insnProof := m.state.Memory.MerkleProof(uint32(m.state.GetPC()))
+		wit = &StepWitness{
+			State:       m.state.EncodeWitness(),
+			ThreadProof: m.state.EncodeThreadProof(),
+			MemProof:    insnProof[:],
+		}
+	}
+	err = m.mipsStep()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof {
+		m.completeWitness(wit)
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MTStateWitnessSize is the size of the multi-threaded state witness encoding in bytes.
+const MTStateWitnessSize = 172
+
+// mtExitCodeWitnessOffset is the offset of the exit code in the multi-threaded state witness.
+const mtExitCodeWitnessOffset = 32*2 + 4*2 + 1 + 4*2
+
+// MTState is the state of a multi-threaded VM.
+// Threads are kept in two stacks. The thread at the top of the stack currently being traversed is the active thread.
+// When a thread is preempted it is moved to the top of the other stack, and once the current stack is empty the
+// direction of traversal is reversed, so every thread is scheduled in turn.
+type MTState struct {
+	Memory *Memory `json:"memory"`
+
+	PreimageKey    common.Hash `json:"preimageKey"`
+	PreimageOffset uint32      `json:"preimageOffset"` // note that the offset includes the 8-byte length prefix
+
+	Heap uint32 `json:"heap"` // to handle mmap growth
+
+	// LLReservationActive is set by a load-linked instruction and cleared by any store to LLAddress, so that a
+	// store-conditional fails if another thread modified the address in between.
+	LLReservationActive bool   `json:"llReservationActive"`
+	LLAddress           uint32 `json:"llAddress"`
+	LLOwnerThread       uint32 `json:"llOwnerThread"`
+
+	ExitCode uint8 `json:"exit"`
+	Exited   bool  `json:"exited"`
+
+	Step                        uint64 `json:"step"`
+	StepsSinceLastContextSwitch uint64 `json:"stepsSinceLastContextSwitch"`
+
+	// Wakeup is the futex address being woken, or FutexEmptyAddr if no wakeup is in progress.
+	Wakeup uint32 `json:"wakeup"`
+
+	TraverseRight    bool           `json:"traverseRight"`
+	LeftThreadStack  []*ThreadState `json:"leftThreadStack"`
+	RightThreadStack []*ThreadState `json:"rightThreadStack"`
+	NextThreadId     uint32         `json:"nextThreadId"`
+
+	// LastHint is optional metadata, and not part of the VM state itself.
+	// See State.LastHint for details.
+	LastHint hexutil.Bytes `json:"lastHint,omitempty"`
+}
+
+// NewMTState converts an initial single-threaded state, such as one created by LoadELF,
+// into a multi-threaded state with a single thread.
+func NewMTState(st *State) *MTState {
+	thread := &ThreadState{
+		ThreadId:  0,
+		FutexAddr: FutexEmptyAddr,
+		PC:        st.PC,
+		NextPC:    st.NextPC,
+		LO:        st.LO,
+		HI:        st.HI,
+		Registers: st.Registers,
+	}
+	return &MTState{
+		Memory:           st.Memory,
+		PreimageKey:      st.PreimageKey,
+		PreimageOffset:   st.PreimageOffset,
+		Heap:             st.Heap,
+		ExitCode:         st.ExitCode,
+		Exited:           st.Exited,
+		Step:             st.Step,
+		Wakeup:           FutexEmptyAddr,
+		LeftThreadStack:  []*ThreadState{thread},
+		RightThreadStack: []*ThreadState{},
+		NextThreadId:     1,
+		LastHint:         st.LastHint,
+	}
+}
+
+func (s *MTState) VMStatus() uint8 {
+	return vmStatus(s.Exited, s.ExitCode)
+}
+
+// ThreadCount returns the number of threads, including any that have exited but have not yet been removed.
+func (s *MTState) ThreadCount() int {
+	return len(s.LeftThreadStack) + len(s.RightThreadStack)
+}
+
+// CurrentThread returns the thread that will be scheduled in the next step.
+func (s *MTState) CurrentThread() *ThreadState {
+	stack := s.activeThreadStack()
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+func (s *MTState) activeThreadStack() []*ThreadState {
+	if s.TraverseRight {
+		return s.RightThreadStack
+	}
+	return s.LeftThreadStack
+}
+
+func (s *MTState) EncodeWitness() StateWitness {
+	out := make([]byte, 0, MTStateWitnessSize)
+	memRoot := s.Memory.MerkleRoot()
+	out = append(out, memRoot[:]...)
+	out = append(out, s.PreimageKey[:]...)
+	out = binary.BigEndian.AppendUint32(out, s.PreimageOffset)
+	out = binary.BigEndian.AppendUint32(out, s.Heap)
+	out = appendBool(out, s.LLReservationActive)
+	out = binary.BigEndian.AppendUint32(out, s.LLAddress)
+	out = binary.BigEndian.AppendUint32(out, s.LLOwnerThread)
+	out = append(out, s.ExitCode)
+	out = appendBool(out, s.Exited)
+	out = binary.BigEndian.AppendUint64(out, s.Step)
+	out = binary.BigEndian.AppendUint64(out, s.StepsSinceLastContextSwitch)
+	out = binary.BigEndian.AppendUint32(out, s.Wakeup)
+	out = appendBool(out, s.TraverseRight)
+	leftRoot := ThreadStackRoot(s.LeftThreadStack)
+	rightRoot := ThreadStackRoot(s.RightThreadStack)
+	out = append(out, leftRoot[:]...)
+	out = append(out, rightRoot[:]...)
+	out = binary.BigEndian.AppendUint32(out, s.NextThreadId)
+	return out
+}
+
+// EncodeThreadProof encodes the current thread followed by the root of the rest of the active thread stack.
+// This is sufficient to prove and update the current thread against the thread stack root in the state witness.
+func (s *MTState) EncodeThreadProof() []byte {
+	stack := s.activeThreadStack()
+	if len(stack) == 0 {
+		return nil
+	}
+	out := stack[len(stack)-1].EncodeThread()
+	otherRoot := ThreadStackRoot(stack[:len(stack)-1])
+	return append(out, otherRoot[:]...)
+}
+
+func appendBool(out []byte, b bool) []byte {
+	if b {
+		return append(out, 1)
+	}
+	return append(out, 0)
+}
@@ -0,0 +1,347 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPC   = 0x1000
+	testAddr = 0x2000
+)
+
+func TestMTStateWitness(t *testing.T) {
+	state := NewMTState(&State{Memory: NewMemory(), PC: testPC, NextPC: testPC + 4, Heap: 0x20000000})
+	state.LeftThreadStack = append(state.LeftThreadStack, &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: 0x4000})
+
+	witness := state.EncodeWitness()
+	require.Len(t, witness, MTStateWitnessSize)
+	hash, err := witness.StateHash()
+	require.NoError(t, err)
+	require.Equal(t, uint8(VMStatusUnfinished), hash[0])
+
+	state.Exited = true
+	state.ExitCode = 1
+	hash, err = state.EncodeWitness().StateHash()
+	require.NoError(t, err)
+	require.Equal(t, uint8(VMStatusInvalid), hash[0])
+
+	proof := state.EncodeThreadProof()
+	require.Len(t, proof, ThreadWitnessSize+32)
+	require.Equal(t, state.LeftThreadStack[1].EncodeThread(), proof[:ThreadWitnessSize])
+	require.Equal(t, ThreadStackRoot(state.LeftThreadStack[:1]).Bytes(), proof[ThreadWitnessSize:])
+	require.Equal(t, ThreadStackRoot(state.LeftThreadStack), pushThreadRoot(ThreadStackRoot(state.LeftThreadStack[:1]), state.LeftThreadStack[1]))
+	require.Equal(t, EmptyThreadStackRoot, ThreadStackRoot(state.RightThreadStack))
+}
+
+func TestMTClone(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		parent := state.CurrentThread()
+		parent.Registers[2] = sysClone
+		parent.Registers[4] = ValidCloneFlags
+		parent.Registers[5] = 0x7000
+		parent.Registers[16] = 0xabcd
+		step(t, us)
+
+		require.Equal(t, 2, state.ThreadCount())
+		require.Equal(t, uint32(2), state.NextThreadId)
+		require.Equal(t, uint32(1), parent.Registers[2], "parent receives child thread id")
+		require.Equal(t, uint32(testPC+4), parent.PC)
+
+		child := state.CurrentThread()
+		require.Equal(t, uint32(1), child.ThreadId, "child is scheduled next")
+		require.Equal(t, uint32(0), child.Registers[2], "child receives 0")
+		require.Equal(t, uint32(0x7000), child.Registers[29], "child uses new stack")
+		require.Equal(t, uint32(0xabcd), child.Registers[16], "child copies registers")
+		require.Equal(t, uint32(testPC+4), child.PC)
+		require.Equal(t, FutexEmptyAddr, child.FutexAddr)
+	})
+
+	t.Run("InvalidFlags", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		thread := state.CurrentThread()
+		thread.Registers[2] = sysClone
+		thread.Registers[4] = 0x11 // SIGCHLD, creating a process
+		step(t, us)
+		require.True(t, state.Exited)
+		require.Equal(t, uint8(VMStatusPanic), state.ExitCode)
+	})
+}
+
+func TestMTFutex(t *testing.T) {
+	t.Run("WaitValueMismatch", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		state.Memory.SetMemory(testAddr, 5)
+		thread := futexSyscall(state.CurrentThread(), futexWaitPrivate, 4, 0)
+		step(t, us)
+		require.Equal(t, FutexEmptyAddr, thread.FutexAddr)
+		require.Equal(t, sysErrorSignal, thread.Registers[2])
+		require.Equal(t, uint32(MipsEAGAIN), thread.Registers[7])
+		require.Equal(t, uint32(testPC+4), thread.PC)
+	})
+
+	t.Run("WaitUntilValueChanges", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		state.Memory.SetMemory(testAddr, 5)
+		thread := futexSyscall(state.CurrentThread(), futexWaitPrivate, 5, 0)
+		step(t, us)
+		require.Equal(t, uint32(testAddr), thread.FutexAddr)
+		require.Equal(t, FutexNoTimeout, thread.FutexTimeoutStep)
+		require.Equal(t, uint32(testPC), thread.PC, "syscall not complete while waiting")
+
+		step(t, us)
+		require.Equal(t, uint32(testAddr), thread.FutexAddr, "still waiting while value unchanged")
+		require.Same(t, thread, state.CurrentThread(), "only thread is rescheduled")
+
+		state.Memory.SetMemory(testAddr, 6)
+		step(t, us)
+		require.Equal(t, FutexEmptyAddr, thread.FutexAddr)
+		require.Equal(t, uint32(0), thread.Registers[2])
+		require.Equal(t, uint32(0), thread.Registers[7])
+		require.Equal(t, uint32(testPC+4), thread.PC)
+	})
+
+	t.Run("WaitTimeout", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		state.Memory.SetMemory(testAddr, 5)
+		thread := futexSyscall(state.CurrentThread(), futexWaitPrivate, 5, 0x7000)
+		step(t, us)
+		require.Equal(t, state.Step+FutexTimeoutSteps, thread.FutexTimeoutStep)
+
+		state.Step = thread.FutexTimeoutStep
+		step(t, us)
+		require.Equal(t, FutexEmptyAddr, thread.FutexAddr)
+		require.Equal(t, sysErrorSignal, thread.Registers[2])
+		require.Equal(t, uint32(MipsETIMEDOUT), thread.Registers[7])
+		require.Equal(t, uint32(testPC+4), thread.PC)
+	})
+
+	t.Run("Wake", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		state.Memory.SetMemory(testAddr, 5)
+		waiter := state.CurrentThread()
+		waiter.FutexAddr = testAddr
+		waiter.FutexVal = 5
+		waiter.FutexTimeoutStep = FutexNoTimeout
+		other := &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4}
+		state.RightThreadStack = append(state.RightThreadStack, &ThreadState{ThreadId: 2, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4})
+		state.LeftThreadStack = append(state.LeftThreadStack, other)
+		state.NextThreadId = 3
+		futexSyscall(other, futexWakePrivate, 1, 0)
+
+		step(t, us)
+		require.Equal(t, uint32(testAddr), state.Wakeup)
+		require.Equal(t, uint32(testPC+4), other.PC)
+		require.Same(t, waiter, state.CurrentThread(), "traversal starts from the left stack")
+
+		step(t, us)
+		require.Equal(t, FutexEmptyAddr, waiter.FutexAddr)
+		require.Equal(t, FutexEmptyAddr, state.Wakeup)
+		require.Equal(t, uint32(0), waiter.Registers[2])
+		require.Equal(t, uint32(testPC+4), waiter.PC)
+	})
+
+	t.Run("WakeWithoutWaiters", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		thread := state.CurrentThread()
+		state.LeftThreadStack = append(state.LeftThreadStack, &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4})
+		state.NextThreadId = 2
+		futexSyscall(state.CurrentThread(), futexWakePrivate, 1, 0)
+		step(t, us)
+		require.Equal(t, uint32(testAddr), state.Wakeup)
+		for i := 0; i < 4 && state.Wakeup != FutexEmptyAddr; i++ {
+			step(t, us)
+		}
+		require.Equal(t, FutexEmptyAddr, state.Wakeup, "wakeup completes after traversing all threads")
+		require.Equal(t, uint32(testPC), thread.PC, "other thread does not execute during wakeup")
+	})
+}
+
+func TestMTPreemption(t *testing.T) {
+	t.Run("SchedQuantum", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		first := state.CurrentThread()
+		second := &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4}
+		state.LeftThreadStack = []*ThreadState{second, first}
+		state.NextThreadId = 2
+		state.Memory.SetMemory(testPC, 0) // nop
+
+		state.StepsSinceLastContextSwitch = SchedQuantum - 1
+		step(t, us)
+		require.Equal(t, uint32(testPC+4), first.PC)
+		require.Same(t, first, state.CurrentThread())
+
+		step(t, us)
+		require.Equal(t, uint32(testPC+4), first.PC, "preempted without executing")
+		require.Same(t, second, state.CurrentThread())
+		require.Equal(t, []*ThreadState{first}, state.RightThreadStack)
+		require.Zero(t, state.StepsSinceLastContextSwitch)
+
+		step(t, us)
+		require.Equal(t, uint32(testPC+4), second.PC)
+	})
+
+	t.Run("Yield", func(t *testing.T) {
+		for _, syscallNum := range []uint32{sysSchedYield, sysNanosleep} {
+			state, us := setupMTTest(t)
+			first := state.CurrentThread()
+			second := &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4}
+			state.LeftThreadStack = []*ThreadState{second, first}
+			first.Registers[2] = syscallNum
+			step(t, us)
+			require.Equal(t, uint32(testPC+4), first.PC)
+			require.Same(t, second, state.CurrentThread())
+		}
+	})
+
+	t.Run("ReverseTraversal", func(t *testing.T) {
+		state, us := setupMTTest(t)
+		state.Memory.SetMemory(testPC, 0)
+		state.StepsSinceLastContextSwitch = SchedQuantum
+		step(t, us)
+		require.True(t, state.TraverseRight, "switch direction once left stack is empty")
+		require.Empty(t, state.LeftThreadStack)
+		require.Len(t, state.RightThreadStack, 1)
+	})
+}
+
+func TestMTExit(t *testing.T) {
+	state, us := setupMTTest(t)
+	first := state.CurrentThread()
+	second := &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC, NextPC: testPC + 4}
+	state.LeftThreadStack = []*ThreadState{second, first}
+	first.Registers[2] = sysExit
+	first.Registers[4] = 3
+	second.Registers[2] = sysExit
+	second.Registers[4] = 4
+
+	step(t, us)
+	require.True(t, first.Exited)
+	require.False(t, state.Exited, "other threads still running")
+
+	step(t, us)
+	require.Equal(t, []*ThreadState{second}, state.LeftThreadStack, "exited thread removed")
+
+	step(t, us)
+	require.True(t, state.Exited, "last thread exited")
+	require.Equal(t, uint8(4), state.ExitCode)
+}
+
+func TestMTLoadLinkedStoreConditional(t *testing.T) {
+	ll := uint32(0x30<<26 | 8<<21 | 9<<16)  // ll $t1, 0($t0)
+	sc := uint32(0x38<<26 | 8<<21 | 10<<16) // sc $t2, 0($t0)
+	sw := uint32(0x2b<<26 | 8<<21 | 11<<16) // sw $t3, 0($t0)
+
+	setup := func(t *testing.T) (*MTState, *MTInstrumentedState, *ThreadState, *ThreadState) {
+		state, us := setupMTTest(t)
+		first := state.CurrentThread()
+		second := &ThreadState{ThreadId: 1, FutexAddr: FutexEmptyAddr, PC: testPC + 8, NextPC: testPC + 12}
+		state.LeftThreadStack = []*ThreadState{second, first}
+		state.NextThreadId = 2
+		state.Memory.SetMemory(testPC, ll)
+		state.Memory.SetMemory(testPC+4, sc)
+		state.Memory.SetMemory(testPC+8, sw)
+		state.Memory.SetMemory(testAddr, 1)
+		first.Registers[8] = testAddr
+		first.Registers[10] = 2
+		second.Registers[8] = testAddr
+		second.Registers[11] = 3
+		return state, us, first, second
+	}
+
+	t.Run("Succeeds", func(t *testing.T) {
+		state, us, first, _ := setup(t)
+		step(t, us)
+		require.Equal(t, uint32(1), first.Registers[9])
+		require.True(t, state.LLReservationActive)
+		step(t, us)
+		require.Equal(t, uint32(1), first.Registers[10])
+		require.Equal(t, uint32(2), state.Memory.GetMemory(testAddr))
+		require.False(t, state.LLReservationActive)
+	})
+
+	t.Run("FailsAfterStoreByOtherThread", func(t *testing.T) {
+		state, us, first, second := setup(t)
+		step(t, us)
+		us.preemptThread(first)
+		require.Same(t, second, state.CurrentThread())
+		step(t, us)
+		require.Equal(t, uint32(3), state.Memory.GetMemory(testAddr))
+		require.False(t, state.LLReservationActive)
+
+		for state.CurrentThread() != first {
+			us.preemptThread(state.CurrentThread())
+		}
+		step(t, us)
+		require.Equal(t, uint32(0), first.Registers[10], "sc reports failure")
+		require.Equal(t, uint32(3), state.Memory.GetMemory(testAddr), "sc does not store")
+	})
+}
+
+func TestMTStepWitness(t *testing.T) {
+	state, us := setupMTTest(t)
+	state.Memory.SetMemory(testPC, 0)
+	expectedThreadProof := state.EncodeThreadProof()
+	expectedState := state.EncodeWitness()
+	wit, err := us.Step(true)
+	require.NoError(t, err)
+	require.Equal(t, []byte(expectedState), wit.State)
+	require.Equal(t, expectedThreadProof, wit.ThreadProof)
+	require.Equal(t, append(append([]byte{}, expectedThreadProof...), wit.MemProof...), wit.ProofData())
+}
+
+func TestMTHello(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+
+	st, err := LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	// The Go runtime does not need to be patched to disable the GC when running multi-threaded
+	require.NoError(t, PatchStack(st), "add initial stack")
+	state := NewMTState(st)
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewMTInstrumentedState(state, nil, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	for i := 0; i < 5_000_000; i++ {
+		if state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+	require.Greater(t, state.NextThreadId, uint32(1), "runtime should start threads")
+
+	require.Equal(t, "hello world!\n", stdOutBuf.String(), "stdout says hello")
+	require.Equal(t, "", stdErrBuf.String(), "stderr silent")
+}
+
+func setupMTTest(t *testing.T) (*MTState, *MTInstrumentedState) {
+	st := &State{Memory: NewMemory(), PC: testPC, NextPC: testPC + 4, Heap: 0x20000000}
+	st.Memory.SetMemory(testPC, syscallInsn)
+	state := NewMTState(st)
+	return state, NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+}
+
+func futexSyscall(thread *ThreadState, op uint32, val uint32, timeout uint32) *ThreadState {
+	thread.Registers[2] = sysFutex
+	thread.Registers[4] = testAddr
+	thread.Registers[5] = op
+	thread.Registers[6] = val
+	thread.Registers[7] = timeout
+	return thread
+}
+
+func step(t *testing.T, us *MTInstrumentedState) {
+	_, err := us.Step(true)
+	require.NoError(t, err)
+}
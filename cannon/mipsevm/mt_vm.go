@@ -0,0 +1,358 @@
+package mipsevm
+
+const (
+	sysExit       = 4001
+	sysSchedYield = 4162
+	sysNanosleep  = 4166
+	sysGetTID     = 4222
+	sysFutex      = 4238
+)
+
+const (
+	futexWaitPrivate = 128
+	futexWakePrivate = 129
+
+	// FutexNoTimeout is the timeout step of a futex wait without a timeout.
+	FutexNoTimeout = ^uint64(0)
+	// FutexTimeoutSteps is the number of steps after which a futex wait with a timeout completes.
+	// Steps are used rather than the requested duration so execution is deterministic.
+	FutexTimeoutSteps = 10_000
+	// SchedQuantum is the number of steps a thread executes before it is preempted.
+	SchedQuantum = 100_000
+
+	// ValidCloneFlags are the clone flags used by the Go runtime to create threads. Other uses of clone,
+	// such as creating processes, are not supported.
+	ValidCloneFlags = 0x100 | // CLONE_VM
+		0x200 | // CLONE_FS
+		0x400 | // CLONE_FILES
+		0x800 | // CLONE_SIGHAND
+		0x10000 | // CLONE_THREAD
+		0x40000 // CLONE_SYSVSEM
+
+	sysErrorSignal = ^uint32(0)
+)
+
+// storeMemory writes to memory, clearing any load-linked reservation on the address.
+func (m *MTInstrumentedState) storeMemory(effAddr uint32, v uint32) {
+	if m.state.LLReservationActive && m.state.LLAddress == effAddr {
+		m.clearLLReservation()
+	}
+	m.vmIO.storeMemory(effAddr, v)
+}
+
+func (m *MTInstrumentedState) clearLLReservation() {
+	m.state.LLReservationActive = false
+	m.state.LLAddress = 0
+	m.state.LLOwnerThread = 0
+}
+
+// pushThread adds a new thread to the top of the active stack so it is scheduled next.
+func (m *MTInstrumentedState) pushThread(thread *ThreadState) {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = append(m.state.RightThreadStack, thread)
+	} else {
+		m.state.LeftThreadStack = append(m.state.LeftThreadStack, thread)
+	}
+	m.state.StepsSinceLastContextSwitch = 0
+}
+
+// popThread removes the current thread. Returns true if the direction of traversal changed.
+func (m *MTInstrumentedState) popThread() bool {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = m.state.RightThreadStack[:len(m.state.RightThreadStack)-1]
+	} else {
+		m.state.LeftThreadStack = m.state.LeftThreadStack[:len(m.state.LeftThreadStack)-1]
+	}
+	return m.afterContextSwitch()
+}
+
+// preemptThread moves the current thread to the top of the other stack. Returns true if the direction of traversal
+// changed.
+func (m *MTInstrumentedState) preemptThread(thread *ThreadState) bool {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = m.state.RightThreadStack[:len(m.state.RightThreadStack)-1]
+		m.state.LeftThreadStack = append(m.state.LeftThreadStack, thread)
+	} else {
+		m.state.LeftThreadStack = m.state.LeftThreadStack[:len(m.state.LeftThreadStack)-1]
+		m.state.RightThreadStack = append(m.state.RightThreadStack, thread)
+	}
+	return m.afterContextSwitch()
+}
+
+func (m *MTInstrumentedState) afterContextSwitch() bool {
+	changedDirections := false
+	if len(m.state.activeThreadStack()) == 0 {
+		m.state.TraverseRight = !m.state.TraverseRight
+		changedDirections = true
+	}
+	m.state.StepsSinceLastContextSwitch = 0
+	return changedDirections
+}
+
+// onWaitComplete completes a futex wait syscall, which was left in progress when the thread started waiting.
+func (m *MTInstrumentedState) onWaitComplete(thread *ThreadState, isTimedOut bool) {
+	thread.FutexAddr = FutexEmptyAddr
+	thread.FutexVal = 0
+	thread.FutexTimeoutStep = 0
+	v0 := uint32(0)
+	v1 := uint32(0)
+	if isTimedOut {
+		v0 = sysErrorSignal
+		v1 = MipsETIMEDOUT
+	}
+	thread.completeSyscall(v0, v1)
+	m.state.Wakeup = FutexEmptyAddr
+}
+
+func (m *MTInstrumentedState) handleSyscall(thread *ThreadState) error {
+	syscallNum := thread.Registers[2] // v0
+	v0 := uint32(0)
+	v1 := uint32(0)
+
+	a0 := thread.Registers[4]
+	a1 := thread.Registers[5]
+	a2 := thread.Registers[6]
+	a3 := thread.Registers[7]
+
+	switch syscallNum {
+	case sysMmap:
+		v0 = handleMmap(m.sys(), a0, a1)
+	case sysBrk:
+		v0 = 0x40000000
+	case sysClone:
+		// args: a0 = flags, a1 = child stack pointer
+		if a0 != ValidCloneFlags {
+			m.state.Exited = true
+			m.state.ExitCode = VMStatusPanic
+			return nil
+		}
+		newThread := &ThreadState{
+			ThreadId:  m.state.NextThreadId,
+			FutexAddr: FutexEmptyAddr,
+			PC:        thread.NextPC,
+			NextPC:    thread.NextPC + 4,
+			LO:        thread.LO,
+			HI:        thread.HI,
+			Registers: thread.Registers,
+		}
+		newThread.Registers[29] = a1
+		// the child perceives a 0 return value and no error
+		newThread.Registers[2] = 0
+		newThread.Registers[7] = 0
+		m.state.NextThreadId++
+		thread.completeSyscall(newThread.ThreadId, 0)
+		m.pushThread(newThread)
+		return nil
+	case sysExitGroup:
+		m.state.Exited = true
+		m.state.ExitCode = uint8(a0)
+		return nil
+	case sysExit:
+		thread.Exited = true
+		thread.ExitCode = uint8(a0)
+		if m.state.ThreadCount() == 1 {
+			m.state.Exited = true
+			m.state.ExitCode = uint8(a0)
+		}
+		return nil
+	case sysFutex:
+		// args: a0 = addr, a1 = op, a2 = val, a3 = timeout
+		effAddr := a0 & 0xFFffFFfc
+		switch a1 {
+		case futexWaitPrivate:
+			mem := m.loadMemory(effAddr)
+			if mem != a2 {
+				v0 = sysErrorSignal
+				v1 = MipsEAGAIN
+			} else {
+				thread.FutexAddr = effAddr
+				thread.FutexVal = a2
+				if a3 == 0 {
+					thread.FutexTimeoutStep = FutexNoTimeout
+				} else {
+					thread.FutexTimeoutStep = m.state.Step + FutexTimeoutSteps
+				}
+				// The syscall is completed by onWaitComplete once the thread is woken or times out.
+				return nil
+			}
+		case futexWakePrivate:
+			// Traverse all threads, starting from the left stack, waking the first one waiting on the address.
+			// The woken thread is not reported as the guest must tolerate spurious wakeups anyway.
+			m.state.Wakeup = effAddr
+			thread.completeSyscall(0, 0)
+			m.preemptThread(thread)
+			m.state.TraverseRight = len(m.state.LeftThreadStack) == 0
+			return nil
+		default:
+			v0 = sysErrorSignal
+			v1 = MipsEINVAL
+		}
+	case sysSchedYield, sysNanosleep:
+		thread.completeSyscall(0, 0)
+		m.preemptThread(thread)
+		return nil
+	case sysGetTID:
+		v0 = thread.ThreadId
+	case sysRead:
+		v0, v1 = m.handleRead(m.sys(), a0, a1, a2)
+	case sysWrite:
+		v0, v1 = m.handleWrite(m.sys(), a0, a1, a2)
+	case sysFcntl:
+		v0, v1 = handleFcntl(a0, a1)
+	}
+	thread.completeSyscall(v0, v1)
+	return nil
+}
+
+// sys returns the state of the VM that syscalls operate on.
+func (m *MTInstrumentedState) sys() sysState[uint32] {
+	return sysState[uint32]{Memory: m, Heap: &m.state.Heap, PreimageKey: &m.state.PreimageKey, PreimageOffset: &m.state.PreimageOffset, LastHint: &m.state.LastHint}
+}
+
+func (m *MTInstrumentedState) mipsStep() error {
+	if m.state.Exited {
+		return nil
+	}
+	m.state.Step += 1
+	thread := m.state.CurrentThread()
+
+	// A wakeup is in progress: check each thread in turn until one waiting on the address is found,
+	// or all threads have been checked.
+	if m.state.Wakeup != FutexEmptyAddr {
+		if m.state.Wakeup == thread.FutexAddr {
+			m.onWaitComplete(thread, false)
+		} else {
+			traversingRight := m.state.TraverseRight
+			changedDirections := m.preemptThread(thread)
+			if traversingRight && changedDirections {
+				// Both stacks have been traversed, no thread was waiting.
+				m.state.Wakeup = FutexEmptyAddr
+			}
+		}
+		return nil
+	}
+
+	if thread.Exited {
+		m.popThread()
+		return nil
+	}
+
+	if thread.FutexAddr != FutexEmptyAddr {
+		if m.state.Step > thread.FutexTimeoutStep {
+			m.onWaitComplete(thread, true)
+			return nil
+		}
+		if m.loadMemory(thread.FutexAddr) == thread.FutexVal {
+			// Still waiting, try the next thread
+			m.preemptThread(thread)
+		} else {
+			// The value changed so stop waiting. The guest can wait again if this was spurious.
+			m.onWaitComplete(thread, false)
+		}
+		return nil
+	}
+
+	if m.state.StepsSinceLastContextSwitch >= SchedQuantum {
+		m.preemptThread(thread)
+		return nil
+	}
+	m.state.StepsSinceLastContextSwitch += 1
+
+	// instruction fetch
+	insn := m.state.Memory.GetMemory(thread.PC)
+	opcode := insn >> 26 // 6-bits
+
+	// j-type j/jal
+	if opcode == 2 || opcode == 3 {
+		linkReg := uint32(0)
+		if opcode == 3 {
+			linkReg = 31
+		}
+		// Take top 4 bits of the next PC (its 256 MB region), and concatenate with the 26-bit offset
+		target := (thread.NextPC & 0xF0000000) | ((insn & 0x03FFFFFF) << 2)
+		return handleJump(thread.cpu(), linkReg, target)
+	}
+
+	// register fetch
+	rs, rt, rtReg, rdReg := fetchOperands(&thread.Registers, insn)
+
+	if (opcode >= 4 && opcode < 8) || opcode == 1 {
+		return handleBranch(thread.cpu(), opcode, insn, rtReg, rs)
+	}
+
+	storeAddr := uint32(0xFF_FF_FF_FF)
+	// memory fetch (all I-type)
+	// we do the load for stores also
+	mem := uint32(0)
+	if opcode >= 0x20 {
+		// M[R[rs]+SignExtImm]
+		rs += SE(insn&0xFFFF, 16)
+		addr := rs & 0xFFFFFFFC
+		mem = m.loadMemory(addr)
+		if opcode >= 0x28 && opcode != 0x30 {
+			// store
+			storeAddr = addr
+			// store opcodes don't write back to a register
+			rdReg = 0
+		}
+	}
+
+	// ALU
+	val := execute(insn, rs, rt, mem)
+
+	fun := insn & 0x3f // 6-bits
+	if opcode == 0 && fun >= 8 && fun < 0x1c {
+		if fun == 8 || fun == 9 { // jr/jalr
+			linkReg := uint32(0)
+			if fun == 9 {
+				linkReg = rdReg
+			}
+			return handleJump(thread.cpu(), linkReg, rs)
+		}
+
+		if fun == 0xa { // movz
+			return handleRd(thread.cpu(), rdReg, rs, rt == 0)
+		}
+		if fun == 0xb { // movn
+			return handleRd(thread.cpu(), rdReg, rs, rt != 0)
+		}
+
+		// syscall (can read and write)
+		if fun == 0xC {
+			return m.handleSyscall(thread)
+		}
+
+		// lo and hi registers
+		// can write back
+		if fun >= 0x10 && fun < 0x1c {
+			return handleHiLo(thread.cpu(), fun, rs, rt, rdReg)
+		}
+	}
+
+	switch opcode {
+	case 0x30: // ll: reserve the address for this thread
+		m.state.LLReservationActive = true
+		m.state.LLAddress = rs & 0xFFFFFFFC
+		m.state.LLOwnerThread = thread.ThreadId
+	case 0x38: // sc: only store if the reservation is still held by this thread
+		success := m.state.LLReservationActive && m.state.LLAddress == storeAddr && m.state.LLOwnerThread == thread.ThreadId
+		if !success {
+			storeAddr = 0xFF_FF_FF_FF
+		}
+		if rtReg != 0 {
+			if success {
+				thread.Registers[rtReg] = 1
+			} else {
+				thread.Registers[rtReg] = 0
+			}
+		}
+	}
+
+	// write memory
+	if storeAddr != 0xFF_FF_FF_FF {
+		m.storeMemory(storeAddr, val)
+	}
+
+	// write back the value to destination register
+	return handleRd(thread.cpu(), rdReg, val, true)
+}
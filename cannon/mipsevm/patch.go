@@ -9,6 +9,9 @@ import (
 )
 
 func LoadELF(f *elf.File) (*State, error) {
+	if f.Class != elf.ELFCLASS32 {
+		return nil, fmt.Errorf("expected 32-bit ELF, got %s", f.Class)
+	}
 	s := &State{
 		PC:        uint32(f.Entry),
 		NextPC:    uint32(f.Entry + 4),
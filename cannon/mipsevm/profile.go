@@ -0,0 +1,228 @@
+package mipsevm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// maxProfileStackDepth bounds the tracked call stack, to not grow without limit
+// when returns are missed, e.g. because of goroutine stack switches.
+const maxProfileStackDepth = 256
+
+type profileFrame struct {
+	name string
+	// ret is the address that returns to the caller of this frame
+	ret uint64
+}
+
+// Profiler samples the guest program that is executed by the VM, to find where the executed instructions are spent.
+//
+// The call stack of the guest is tracked by observing every executed call and return instruction,
+// and is sampled every N steps. Functions are symbolized with the program metadata.
+// The tracked stack is a best-effort approximation: control flow that bypasses calls and returns,
+// like goroutine switches, is resynchronized on the next return that doesn't match the stack.
+type Profiler struct {
+	meta   *Metadata
+	period uint64
+
+	stack []profileFrame
+	// pending call or return, applied when the PC reaches its target after the delay slot
+	pendingStep uint64
+	pendingFrom uint64
+	pendingRet  uint64
+	pendingCall bool
+	pending     bool
+
+	samples map[string]uint64
+	// start addresses of the sampled functions, to add to pprof output
+	starts map[string]uint64
+}
+
+// NewProfiler creates a profiler that samples the guest every period steps.
+func NewProfiler(meta *Metadata, period uint64) *Profiler {
+	if period == 0 {
+		period = 1
+	}
+	return &Profiler{
+		meta:    meta,
+		period:  period,
+		samples: make(map[string]uint64),
+		starts:  make(map[string]uint64),
+	}
+}
+
+// Observe must be called with the state before every step of the VM.
+func (p *Profiler) Observe(state FPVMState) {
+	step := state.GetStep()
+	pc := state.GetPC()
+	if p.pending && step >= p.pendingStep {
+		p.pending = false
+		if p.pendingCall {
+			p.push(p.pendingFrom, pc, p.pendingRet)
+		} else {
+			p.ret(pc)
+		}
+	}
+
+	insn := state.GetInstruction()
+	if isCallInsn(insn) {
+		// the call takes effect after the delay slot
+		p.pending, p.pendingCall, p.pendingStep, p.pendingFrom, p.pendingRet = true, true, step+2, pc, pc+8
+	} else if isReturnInsn(insn) {
+		p.pending, p.pendingCall, p.pendingStep = true, false, step+2
+	}
+
+	if step%p.period == 0 {
+		p.sample(pc)
+	}
+}
+
+func (p *Profiler) push(from uint64, pc uint64, ret uint64) {
+	if len(p.stack) == 0 {
+		// the caller of the first tracked call is the root of the stack
+		p.stack = append(p.stack, profileFrame{name: p.lookup(from)})
+	}
+	if len(p.stack) >= maxProfileStackDepth {
+		copy(p.stack, p.stack[1:])
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+	p.stack = append(p.stack, profileFrame{name: p.lookup(pc), ret: ret})
+}
+
+func (p *Profiler) ret(pc uint64) {
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].ret == pc {
+			p.stack = p.stack[:i]
+			return
+		}
+	}
+	// The return doesn't match any tracked call, start over from the current function.
+	p.stack = p.stack[:0]
+}
+
+func (p *Profiler) sample(pc uint64) {
+	names := make([]string, 0, len(p.stack)+1)
+	for _, f := range p.stack {
+		names = append(names, f.name)
+	}
+	// the function that is currently executing may differ from the last called one, e.g. after a jump
+	leaf := p.lookup(pc)
+	if len(names) == 0 || names[len(names)-1] != leaf {
+		names = append(names, leaf)
+	}
+	p.samples[strings.Join(names, ";")]++
+}
+
+// lookup returns the name of the function at pc, and remembers the start address of the function.
+func (p *Profiler) lookup(pc uint64) string {
+	name := p.meta.LookupSymbol(pc)
+	if _, ok := p.starts[name]; !ok {
+		i := sort.Search(len(p.meta.Symbols), func(i int) bool {
+			return p.meta.Symbols[i].Start > pc
+		})
+		if i > 0 && p.meta.Symbols[i-1].Name == name {
+			p.starts[name] = p.meta.Symbols[i-1].Start
+		} else {
+			p.starts[name] = 0
+		}
+	}
+	return name
+}
+
+// WriteFolded writes the samples in the folded stack format used by flamegraph tools:
+// one line per call stack, with the frames from root to leaf separated by semicolons,
+// followed by the number of sampled instructions.
+func (p *Profiler) WriteFolded(w io.Writer) error {
+	stacks := make([]string, 0, len(p.samples))
+	for stack := range p.samples {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	out := bufio.NewWriter(w)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(out, "%s %d\n", stack, p.samples[stack]*p.period); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+// WritePprof writes the samples as a gzip-compressed pprof profile.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	prof := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "instructions", Unit: "count"},
+		},
+		PeriodType: &profile.ValueType{Type: "instructions", Unit: "count"},
+		Period:     int64(p.period),
+	}
+	locations := make(map[string]*profile.Location)
+	location := func(name string) *profile.Location {
+		if loc, ok := locations[name]; ok {
+			return loc
+		}
+		fn := &profile.Function{
+			ID:         uint64(len(prof.Function) + 1),
+			Name:       name,
+			SystemName: name,
+		}
+		prof.Function = append(prof.Function, fn)
+		loc := &profile.Location{
+			ID:      uint64(len(prof.Location) + 1),
+			Address: p.starts[name],
+			Line:    []profile.Line{{Function: fn}},
+		}
+		prof.Location = append(prof.Location, loc)
+		locations[name] = loc
+		return loc
+	}
+	stacks := make([]string, 0, len(p.samples))
+	for stack := range p.samples {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	for _, stack := range stacks {
+		names := strings.Split(stack, ";")
+		// pprof lists the locations of a sample from leaf to root
+		locs := make([]*profile.Location, len(names))
+		for i, name := range names {
+			locs[len(names)-1-i] = location(name)
+		}
+		count := int64(p.samples[stack])
+		prof.Sample = append(prof.Sample, &profile.Sample{
+			Location: locs,
+			Value:    []int64{count, count * int64(p.period)},
+		})
+	}
+	if err := prof.CheckValid(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	return prof.Write(w)
+}
+
+// isCallInsn returns true for the jump and branch instructions that link the return address:
+// jal, jalr, bltzal and bgezal.
+func isCallInsn(insn uint32) bool {
+	opcode := insn >> 26
+	switch opcode {
+	case 0x03: // jal
+		return true
+	case 0x00: // jalr
+		return insn&0x3F == 0x09
+	case 0x01: // bltzal, bgezal
+		rt := (insn >> 16) & 0x1F
+		return rt == 0x10 || rt == 0x11
+	}
+	return false
+}
+
+// isReturnInsn returns true for jr $ra.
+func isReturnInsn(insn uint32) bool {
+	return insn>>26 == 0 && insn&0x3F == 0x08 && (insn>>21)&0x1F == 31
+}
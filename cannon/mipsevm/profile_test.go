@@ -0,0 +1,81 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilerCallStack(t *testing.T) {
+	state := &State{PC: 0x1000, NextPC: 0x1004, Memory: NewMemory()}
+	state.Memory.SetMemory(0x1000, 0x0C000800) // jal 0x2000
+	state.Memory.SetMemory(0x2000, 0x25080001) // addiu $t0, $t0, 1
+	state.Memory.SetMemory(0x2004, 0x25080001) // addiu $t0, $t0, 1
+	state.Memory.SetMemory(0x2008, 0x03E00008) // jr $ra
+	meta := &Metadata{Symbols: []Symbol{
+		{Name: "main", Start: 0x1000, Size: 0x100},
+		{Name: "leaf", Start: 0x2000, Size: 0x100},
+	}}
+	profiler := NewProfiler(meta, 1)
+	us := NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	for i := 0; i < 8; i++ {
+		profiler.Observe(state)
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint32(0x1010), state.PC, "must return to the caller")
+
+	var folded bytes.Buffer
+	require.NoError(t, profiler.WriteFolded(&folded))
+	require.Equal(t, "main 4\nmain;leaf 4\n", folded.String())
+
+	var pprof bytes.Buffer
+	require.NoError(t, profiler.WritePprof(&pprof))
+	prof, err := profile.Parse(&pprof)
+	require.NoError(t, err)
+	require.Len(t, prof.Sample, 2)
+	require.Len(t, prof.Function, 2)
+	for _, sample := range prof.Sample {
+		require.Equal(t, "main", sample.Location[len(sample.Location)-1].Line[0].Function.Name, "main is the root")
+		require.Equal(t, []int64{4, 4}, sample.Value)
+	}
+	require.Equal(t, uint64(0x2000), prof.Location[1].Address)
+}
+
+func TestProfilerHello(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+	state, err := LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+	meta, err := MakeMetadata(elfProgram)
+	require.NoError(t, err)
+
+	profiler := NewProfiler(meta, 10)
+	us := NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	for !state.Exited {
+		profiler.Observe(state)
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	var folded bytes.Buffer
+	require.NoError(t, profiler.WriteFolded(&folded))
+	var total uint64
+	for _, line := range strings.Split(strings.TrimSpace(folded.String()), "\n") {
+		idx := strings.LastIndex(line, " ")
+		require.Positive(t, idx)
+		count, err := strconv.ParseUint(line[idx+1:], 10, 64)
+		require.NoError(t, err)
+		total += count
+	}
+	require.InDelta(t, state.Step, total, 10, "samples must account for all steps")
+	require.Contains(t, folded.String(), ";main.main;", "main function must be called by the runtime")
+}
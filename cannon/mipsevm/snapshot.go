@@ -0,0 +1,238 @@
+package mipsevm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SnapshotMagic identifies a binary state snapshot. It is followed by a version byte and the state hash,
+// which are left uncompressed so a snapshot can be identified and checked without decoding it.
+var SnapshotMagic = [4]byte{'C', 'N', 'S', 'N'}
+
+const SnapshotVersion = 1
+
+var (
+	ErrInvalidSnapshot      = errors.New("invalid snapshot")
+	ErrSnapshotHashMismatch = errors.New("snapshot state hash mismatch")
+)
+
+// maxSnapshotHintLen bounds the hint buffer of a snapshot, to not allocate arbitrary amounts of memory
+// when decoding a corrupt snapshot.
+const maxSnapshotHintLen = 1 << 24
+
+// IsSnapshot returns true if the data starts with the binary snapshot magic.
+func IsSnapshot(header []byte) bool {
+	return len(header) >= len(SnapshotMagic) && bytes.Equal(header[:len(SnapshotMagic)], SnapshotMagic[:])
+}
+
+// EncodeSnapshot writes the state in the binary snapshot format.
+//
+// The snapshot is much smaller and faster to load than the JSON encoding of a state:
+// the state is encoded in binary and gzip-compressed, and pages with identical contents,
+// such as the zeroed pages of reserved memory, are only stored once.
+// The state hash is included, so DecodeSnapshot can check the snapshot was not corrupted.
+func (s *State) EncodeSnapshot(w io.Writer) error {
+	stateHash, err := s.EncodeWitness().StateHash()
+	if err != nil {
+		return fmt.Errorf("failed to compute state hash: %w", err)
+	}
+	if _, err := w.Write(SnapshotMagic[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{SnapshotVersion}); err != nil {
+		return err
+	}
+	if _, err := w.Write(stateHash[:]); err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	out := bufio.NewWriter(zw)
+	var header []byte
+	header = append(header, s.PreimageKey[:]...)
+	header = binary.BigEndian.AppendUint32(header, s.PreimageOffset)
+	header = binary.BigEndian.AppendUint32(header, s.PC)
+	header = binary.BigEndian.AppendUint32(header, s.NextPC)
+	header = binary.BigEndian.AppendUint32(header, s.LO)
+	header = binary.BigEndian.AppendUint32(header, s.HI)
+	header = binary.BigEndian.AppendUint32(header, s.Heap)
+	header = append(header, s.ExitCode)
+	header = appendBool(header, s.Exited)
+	header = binary.BigEndian.AppendUint64(header, s.Step)
+	for _, r := range s.Registers {
+		header = binary.BigEndian.AppendUint32(header, r)
+	}
+	header = binary.BigEndian.AppendUint32(header, uint32(len(s.LastHint)))
+	header = append(header, s.LastHint...)
+	if _, err := out.Write(header); err != nil {
+		return err
+	}
+
+	// Deduplicate pages by their merkle root, which is cached as part of computing the state hash.
+	indices := make([]uint32, 0, len(s.Memory.pages))
+	for index := range s.Memory.pages {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	dataRefs := make(map[common.Hash]uint32)
+	refs := make([]uint32, len(indices))
+	var unique []*Page
+	for i, index := range indices {
+		p := s.Memory.pages[index]
+		root := common.Hash(p.MerkleRoot())
+		ref, ok := dataRefs[root]
+		if !ok {
+			ref = uint32(len(unique))
+			dataRefs[root] = ref
+			unique = append(unique, p.Data)
+		}
+		refs[i] = ref
+	}
+
+	var counts [8]byte
+	binary.BigEndian.PutUint32(counts[:4], uint32(len(indices)))
+	binary.BigEndian.PutUint32(counts[4:], uint32(len(unique)))
+	if _, err := out.Write(counts[:]); err != nil {
+		return err
+	}
+	for _, data := range unique {
+		if _, err := out.Write(data[:]); err != nil {
+			return err
+		}
+	}
+	var entry [8]byte
+	for i, index := range indices {
+		binary.BigEndian.PutUint32(entry[:4], index)
+		binary.BigEndian.PutUint32(entry[4:], refs[i])
+		if _, err := out.Write(entry[:]); err != nil {
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadSnapshotHash reads the state hash from the header of a binary snapshot, without decoding the state.
+func ReadSnapshotHash(r io.Reader) (common.Hash, error) {
+	var header [len(SnapshotMagic) + 1 + 32]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return common.Hash{}, fmt.Errorf("%w: failed to read header: %w", ErrInvalidSnapshot, err)
+	}
+	if !IsSnapshot(header[:]) {
+		return common.Hash{}, fmt.Errorf("%w: missing snapshot magic", ErrInvalidSnapshot)
+	}
+	if v := header[len(SnapshotMagic)]; v != SnapshotVersion {
+		return common.Hash{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, v)
+	}
+	return common.BytesToHash(header[len(SnapshotMagic)+1:]), nil
+}
+
+// DecodeSnapshot reads a state in the binary snapshot format,
+// and checks that it matches the state hash of the snapshot.
+func DecodeSnapshot(r io.Reader) (*State, error) {
+	expectedHash, err := ReadSnapshotHash(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decompress: %w", ErrInvalidSnapshot, err)
+	}
+	defer zr.Close()
+	in := bufio.NewReader(zr)
+
+	s := &State{Memory: NewMemory()}
+	readFull := func(dest []byte) error {
+		if _, err := io.ReadFull(in, dest); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+		return nil
+	}
+	var fixed [32 + 4*6 + 1 + 1 + 8 + 32*4 + 4]byte
+	if err := readFull(fixed[:]); err != nil {
+		return nil, err
+	}
+	dat := fixed[:]
+	next := func(n int) []byte {
+		v := dat[:n]
+		dat = dat[n:]
+		return v
+	}
+	copy(s.PreimageKey[:], next(32))
+	s.PreimageOffset = binary.BigEndian.Uint32(next(4))
+	s.PC = binary.BigEndian.Uint32(next(4))
+	s.NextPC = binary.BigEndian.Uint32(next(4))
+	s.LO = binary.BigEndian.Uint32(next(4))
+	s.HI = binary.BigEndian.Uint32(next(4))
+	s.Heap = binary.BigEndian.Uint32(next(4))
+	s.ExitCode = next(1)[0]
+	s.Exited = next(1)[0] != 0
+	s.Step = binary.BigEndian.Uint64(next(8))
+	for i := range s.Registers {
+		s.Registers[i] = binary.BigEndian.Uint32(next(4))
+	}
+	hintLen := binary.BigEndian.Uint32(next(4))
+	if hintLen > maxSnapshotHintLen {
+		return nil, fmt.Errorf("%w: hint of %d bytes is too large", ErrInvalidSnapshot, hintLen)
+	}
+	if hintLen > 0 {
+		s.LastHint = make([]byte, hintLen)
+		if err := readFull(s.LastHint); err != nil {
+			return nil, err
+		}
+	}
+
+	var counts [8]byte
+	if err := readFull(counts[:]); err != nil {
+		return nil, err
+	}
+	pageCount := binary.BigEndian.Uint32(counts[:4])
+	uniqueCount := binary.BigEndian.Uint32(counts[4:])
+	if pageCount > MaxPageCount || uniqueCount > pageCount {
+		return nil, fmt.Errorf("%w: invalid page counts %d and %d", ErrInvalidSnapshot, pageCount, uniqueCount)
+	}
+	unique := make([]*Page, 0, uniqueCount)
+	for i := uint32(0); i < uniqueCount; i++ {
+		p := new(Page)
+		if err := readFull(p[:]); err != nil {
+			return nil, err
+		}
+		unique = append(unique, p)
+	}
+	var entry [8]byte
+	for i := uint32(0); i < pageCount; i++ {
+		if err := readFull(entry[:]); err != nil {
+			return nil, err
+		}
+		index := binary.BigEndian.Uint32(entry[:4])
+		ref := binary.BigEndian.Uint32(entry[4:])
+		if index > PageKeyMask || ref >= uniqueCount {
+			return nil, fmt.Errorf("%w: invalid page entry %d", ErrInvalidSnapshot, i)
+		}
+		if _, ok := s.Memory.pages[index]; ok {
+			return nil, fmt.Errorf("%w: duplicate page index %d", ErrInvalidSnapshot, index)
+		}
+		// each page gets its own copy, as pages with equal contents may diverge after being loaded
+		data := *unique[ref]
+		s.Memory.AllocPage(index).Data = &data
+	}
+
+	stateHash, err := s.EncodeWitness().StateHash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute state hash: %w", err)
+	}
+	if stateHash != expectedHash {
+		return nil, fmt.Errorf("%w: expected %s but got %s", ErrSnapshotHashMismatch, expectedHash, stateHash)
+	}
+	return s, nil
+}
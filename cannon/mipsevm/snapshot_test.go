@@ -0,0 +1,91 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotTestState(t *testing.T) *State {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+	state, err := LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+
+	us := NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	for i := 0; i < 100_000; i++ {
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+	state.LastHint = []byte{0, 0, 0, 3, 'a'}
+	return state
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	state := snapshotTestState(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, state.EncodeSnapshot(&buf))
+	snapshot := buf.Bytes()
+	require.True(t, IsSnapshot(snapshot))
+
+	stateHash, err := state.EncodeWitness().StateHash()
+	require.NoError(t, err)
+	embeddedHash, err := ReadSnapshotHash(bytes.NewReader(snapshot))
+	require.NoError(t, err)
+	require.Equal(t, stateHash, embeddedHash)
+
+	decoded, err := DecodeSnapshot(bytes.NewReader(snapshot))
+	require.NoError(t, err)
+	require.Equal(t, state.EncodeWitness(), decoded.EncodeWitness())
+	require.Equal(t, state.LastHint, decoded.LastHint)
+	require.Equal(t, state.Memory.PageCount(), decoded.Memory.PageCount())
+
+	jsonState, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.Less(t, len(snapshot), len(jsonState), "snapshot should be smaller than JSON")
+
+	t.Run("PagesAreIndependent", func(t *testing.T) {
+		// the initial stack reservation contains several zeroed pages which are deduplicated
+		sp := state.Registers[29]
+		decoded, err := DecodeSnapshot(bytes.NewReader(snapshot))
+		require.NoError(t, err)
+		decoded.Memory.SetMemory(0x7f_ff_d0_00-4*PageSize, 0x1234)
+		require.Equal(t, uint32(0), decoded.Memory.GetMemory(0x7f_ff_d0_00-3*PageSize))
+		require.Equal(t, state.Memory.GetMemory(sp), decoded.Memory.GetMemory(sp))
+	})
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	state := snapshotTestState(t)
+	var buf bytes.Buffer
+	require.NoError(t, state.EncodeSnapshot(&buf))
+	snapshot := buf.Bytes()
+
+	t.Run("NotASnapshot", func(t *testing.T) {
+		_, err := DecodeSnapshot(bytes.NewReader([]byte(`{"memory":[]}`)))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		modified := bytes.Clone(snapshot)
+		modified[len(SnapshotMagic)] = SnapshotVersion + 1
+		_, err := DecodeSnapshot(bytes.NewReader(modified))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+	t.Run("Truncated", func(t *testing.T) {
+		_, err := DecodeSnapshot(bytes.NewReader(snapshot[:len(snapshot)/2]))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+	t.Run("HashMismatch", func(t *testing.T) {
+		modified := bytes.Clone(snapshot)
+		modified[len(SnapshotMagic)+1+5] ^= 0xFF
+		_, err := DecodeSnapshot(bytes.NewReader(modified))
+		require.ErrorIs(t, err, ErrSnapshotHashMismatch)
+	})
+}
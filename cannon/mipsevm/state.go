@@ -38,7 +38,7 @@ type State struct {
 	// to make sure pre-image requests can be served.
 	// The first 4 bytes are a uin32 length prefix.
 	// Warning: the hint MAY NOT BE COMPLETE. I.e. this is buffered,
-	// and should only be read when len(LastHint) > 4 && uint32(LastHint[:4]) >= len(LastHint[4:])
+	// and should only be read when len(LastHint) > 4 && uint32(LastHint[:4]) <= len(LastHint[4:])
 	LastHint hexutil.Bytes `json:"lastHint,omitempty"`
 }
 
@@ -79,13 +79,22 @@ const (
 	VMStatusUnfinished = 3
 )
 
+// StateHash computes the hash of a single-threaded, multi-threaded or 64-bit state witness,
+// with the first byte replaced by the VM status.
 func (sw StateWitness) StateHash() (common.Hash, error) {
-	if len(sw) != 226 {
-		return common.Hash{}, fmt.Errorf("Invalid witness length. Got %d, expected 226", len(sw))
+	var offset int
+	switch len(sw) {
+	case StateWitnessSize:
+		offset = 32*2 + 4*6
+	case MTStateWitnessSize:
+		offset = mtExitCodeWitnessOffset
+	case State64WitnessSize:
+		offset = state64ExitCodeWitnessOffset
+	default:
+		return common.Hash{}, fmt.Errorf("Invalid witness length. Got %d, expected one of %d, %d or %d", len(sw), StateWitnessSize, MTStateWitnessSize, State64WitnessSize)
 	}
 
 	hash := crypto.Keccak256Hash(sw)
-	offset := 32*2 + 4*6
 	exitCode := sw[offset]
 	exited := sw[offset+1]
 	status := vmStatus(exited == 1, exitCode)
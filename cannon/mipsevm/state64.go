@@ -0,0 +1,64 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// State64WitnessSize is the size of the 64-bit state witness encoding in bytes.
+const State64WitnessSize = 32*2 + 4 + 8*5 + 1 + 1 + 8 + 32*8
+
+// state64ExitCodeWitnessOffset is the offset of the exit code in the 64-bit state witness.
+const state64ExitCodeWitnessOffset = 32*2 + 4 + 8*5
+
+// State64 is the state of a MIPS64 VM.
+// It matches State, with 64-bit registers and a 64-bit address space.
+type State64 struct {
+	Memory *Memory64 `json:"memory"`
+
+	PreimageKey    common.Hash `json:"preimageKey"`
+	PreimageOffset uint32      `json:"preimageOffset"` // note that the offset includes the 8-byte length prefix
+
+	PC     uint64 `json:"pc"`
+	NextPC uint64 `json:"nextPC"`
+	LO     uint64 `json:"lo"`
+	HI     uint64 `json:"hi"`
+	Heap   uint64 `json:"heap"` // to handle mmap growth
+
+	ExitCode uint8 `json:"exit"`
+	Exited   bool  `json:"exited"`
+
+	Step uint64 `json:"step"`
+
+	Registers [32]uint64 `json:"registers"`
+
+	// LastHint is optional metadata, and not part of the VM state itself.
+	// See State.LastHint for details.
+	LastHint hexutil.Bytes `json:"lastHint,omitempty"`
+}
+
+func (s *State64) VMStatus() uint8 {
+	return vmStatus(s.Exited, s.ExitCode)
+}
+
+func (s *State64) EncodeWitness() StateWitness {
+	out := make([]byte, 0, State64WitnessSize)
+	memRoot := s.Memory.MerkleRoot()
+	out = append(out, memRoot[:]...)
+	out = append(out, s.PreimageKey[:]...)
+	out = binary.BigEndian.AppendUint32(out, s.PreimageOffset)
+	out = binary.BigEndian.AppendUint64(out, s.PC)
+	out = binary.BigEndian.AppendUint64(out, s.NextPC)
+	out = binary.BigEndian.AppendUint64(out, s.LO)
+	out = binary.BigEndian.AppendUint64(out, s.HI)
+	out = binary.BigEndian.AppendUint64(out, s.Heap)
+	out = append(out, s.ExitCode)
+	out = appendBool(out, s.Exited)
+	out = binary.BigEndian.AppendUint64(out, s.Step)
+	for _, r := range s.Registers {
+		out = binary.BigEndian.AppendUint64(out, r)
+	}
+	return out
+}
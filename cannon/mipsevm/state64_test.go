@@ -0,0 +1,243 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState64Hash(t *testing.T) {
+	cases := []struct {
+		exited   bool
+		exitCode uint8
+	}{
+		{exited: false, exitCode: 0},
+		{exited: false, exitCode: 1},
+		{exited: true, exitCode: 0},
+		{exited: true, exitCode: 1},
+		{exited: true, exitCode: 2},
+	}
+
+	for _, c := range cases {
+		state := &State64{
+			Memory:   NewMemory64(),
+			Exited:   c.exited,
+			ExitCode: c.exitCode,
+		}
+
+		actualWitness := state.EncodeWitness()
+		actualStateHash, err := actualWitness.StateHash()
+		require.NoError(t, err, "Error hashing witness")
+		require.Len(t, actualWitness, State64WitnessSize, "Incorrect witness size")
+
+		expectedWitness := make(StateWitness, State64WitnessSize)
+		memRoot := state.Memory.MerkleRoot()
+		copy(expectedWitness[:32], memRoot[:])
+		expectedWitness[state64ExitCodeWitnessOffset] = c.exitCode
+		if c.exited {
+			expectedWitness[state64ExitCodeWitnessOffset+1] = 1
+		}
+		require.Equal(t, expectedWitness, actualWitness, "Incorrect witness")
+
+		expectedStateHash := crypto.Keccak256Hash(actualWitness)
+		expectedStateHash[0] = vmStatus(c.exited, c.exitCode)
+		require.Equal(t, expectedStateHash, actualStateHash, "Incorrect state hash")
+	}
+}
+
+func TestState64Instructions(t *testing.T) {
+	rType := func(rs, rt, rd, shamt, fun uint32) uint32 {
+		return rs<<21 | rt<<16 | rd<<11 | shamt<<6 | fun
+	}
+	iType := func(opcode, rs, rt, imm uint32) uint32 {
+		return opcode<<26 | rs<<21 | rt<<16 | (imm & 0xFFFF)
+	}
+	const memAddr = 0x1_0000_2000
+
+	cases := []struct {
+		name   string
+		insn   uint32
+		regs   map[uint32]uint64
+		mem    uint64
+		expReg map[uint32]uint64
+		expMem uint64
+		expHi  uint64
+		expLo  uint64
+	}{
+		{name: "daddu", insn: rType(8, 9, 10, 0, 0x2d),
+			regs: map[uint32]uint64{8: 0xFFFF_FFFF, 9: 1}, expReg: map[uint32]uint64{10: 0x1_0000_0000}},
+		{name: "addu sign-extends", insn: rType(8, 9, 10, 0, 0x21),
+			regs: map[uint32]uint64{8: 0x7FFF_FFFF, 9: 1}, expReg: map[uint32]uint64{10: 0xFFFF_FFFF_8000_0000}},
+		{name: "daddiu", insn: iType(0x19, 8, 9, 0xFFFF),
+			regs: map[uint32]uint64{8: 0x1_0000_0000}, expReg: map[uint32]uint64{9: 0xFFFF_FFFF}},
+		{name: "lui sign-extends", insn: iType(0x0F, 0, 9, 0x8000),
+			expReg: map[uint32]uint64{9: 0xFFFF_FFFF_8000_0000}},
+		{name: "dsll32", insn: rType(0, 8, 9, 4, 0x3c),
+			regs: map[uint32]uint64{8: 0x3}, expReg: map[uint32]uint64{9: 0x30_0000_0000}},
+		{name: "dsra", insn: rType(0, 8, 9, 4, 0x3b),
+			regs: map[uint32]uint64{8: 0x8000_0000_0000_0000}, expReg: map[uint32]uint64{9: 0xF800_0000_0000_0000}},
+		{name: "dsrlv", insn: rType(10, 8, 9, 0, 0x16),
+			regs: map[uint32]uint64{8: 0x8000_0000_0000_0000, 10: 63}, expReg: map[uint32]uint64{9: 1}},
+		{name: "sll sign-extends", insn: rType(0, 8, 9, 1, 0x00),
+			regs: map[uint32]uint64{8: 0x4000_0000}, expReg: map[uint32]uint64{9: 0xFFFF_FFFF_8000_0000}},
+		{name: "slt compares 64 bits", insn: rType(8, 9, 10, 0, 0x2a),
+			regs: map[uint32]uint64{8: 0x8000_0000_0000_0000, 9: 1}, expReg: map[uint32]uint64{10: 1}},
+		{name: "ld", insn: iType(0x37, 8, 9, 8),
+			regs: map[uint32]uint64{8: memAddr - 8}, mem: 0x1122_3344_5566_7788,
+			expReg: map[uint32]uint64{9: 0x1122_3344_5566_7788}, expMem: 0x1122_3344_5566_7788},
+		{name: "lw sign-extends", insn: iType(0x23, 8, 9, 4),
+			regs: map[uint32]uint64{8: memAddr}, mem: 0x1122_3344_8000_0001,
+			expReg: map[uint32]uint64{9: 0xFFFF_FFFF_8000_0001}, expMem: 0x1122_3344_8000_0001},
+		{name: "lwu", insn: iType(0x27, 8, 9, 4),
+			regs: map[uint32]uint64{8: memAddr}, mem: 0x1122_3344_8000_0001,
+			expReg: map[uint32]uint64{9: 0x8000_0001}, expMem: 0x1122_3344_8000_0001},
+		{name: "lb", insn: iType(0x20, 8, 9, 7),
+			regs: map[uint32]uint64{8: memAddr}, mem: 0x1122_3344_5566_77F0,
+			expReg: map[uint32]uint64{9: 0xFFFF_FFFF_FFFF_FFF0}, expMem: 0x1122_3344_5566_77F0},
+		{name: "sd", insn: iType(0x3f, 8, 9, 0),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xAABB_CCDD_EEFF_0011}, mem: 0x1122_3344_5566_7788,
+			expMem: 0xAABB_CCDD_EEFF_0011},
+		{name: "sw", insn: iType(0x2b, 8, 9, 4),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xAABB_CCDD_EEFF_0011}, mem: 0x1122_3344_5566_7788,
+			expMem: 0x1122_3344_EEFF_0011},
+		{name: "sh", insn: iType(0x29, 8, 9, 2),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xAABB}, mem: 0x1122_3344_5566_7788,
+			expMem: 0x1122_AABB_5566_7788},
+		{name: "sc", insn: iType(0x38, 8, 9, 0),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xAABB_CCDD}, mem: 0x1122_3344_5566_7788,
+			expReg: map[uint32]uint64{9: 1}, expMem: 0xAABB_CCDD_5566_7788},
+		{name: "scd", insn: iType(0x3c, 8, 9, 0),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xAABB_CCDD}, mem: 0x1122_3344_5566_7788,
+			expReg: map[uint32]uint64{9: 1}, expMem: 0xAABB_CCDD},
+		{name: "ldl", insn: iType(0x1a, 8, 9, 2),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xFFFF_FFFF_FFFF_FFFF}, mem: 0x1122_3344_5566_7788,
+			expReg: map[uint32]uint64{9: 0x3344_5566_7788_FFFF}, expMem: 0x1122_3344_5566_7788},
+		{name: "ldr", insn: iType(0x1b, 8, 9, 5),
+			regs: map[uint32]uint64{8: memAddr, 9: 0xFFFF_FFFF_FFFF_FFFF}, mem: 0x1122_3344_5566_7788,
+			expReg: map[uint32]uint64{9: 0xFFFF_1122_3344_5566}, expMem: 0x1122_3344_5566_7788},
+		{name: "dmult", insn: rType(8, 9, 0, 0, 0x1c),
+			regs: map[uint32]uint64{8: 0xFFFF_FFFF_FFFF_FFFE, 9: 3}, expHi: 0xFFFF_FFFF_FFFF_FFFF, expLo: 0xFFFF_FFFF_FFFF_FFFA},
+		{name: "dmultu", insn: rType(8, 9, 0, 0, 0x1d),
+			regs: map[uint32]uint64{8: 0xFFFF_FFFF_FFFF_FFFF, 9: 2}, expHi: 1, expLo: 0xFFFF_FFFF_FFFF_FFFE},
+		{name: "ddiv", insn: rType(8, 9, 0, 0, 0x1e),
+			regs: map[uint32]uint64{8: 0xFFFF_FFFF_FFFF_FFF9, 9: 2}, expHi: 0xFFFF_FFFF_FFFF_FFFF, expLo: 0xFFFF_FFFF_FFFF_FFFD},
+		{name: "mult sign-extends", insn: rType(8, 9, 0, 0, 0x18),
+			regs: map[uint32]uint64{8: 0x8000_0000, 9: 2}, expHi: 0xFFFF_FFFF_FFFF_FFFF, expLo: 0},
+		{name: "dclz", insn: 0x1c<<26 | rType(8, 0, 9, 0, 0x24),
+			regs: map[uint32]uint64{8: 0x1_0000_0000}, expReg: map[uint32]uint64{9: 31}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			state := &State64{PC: 0x1_0000_1000, NextPC: 0x1_0000_1004, Memory: NewMemory64()}
+			state.Memory.SetDoubleword(state.PC, uint64(c.insn)<<32)
+			state.Memory.SetDoubleword(memAddr, c.mem)
+			for r, v := range c.regs {
+				state.Registers[r] = v
+			}
+			expected := state.Registers
+			for r, v := range c.expReg {
+				expected[r] = v
+			}
+
+			us := NewInstrumentedState64(state, nil, os.Stdout, os.Stderr)
+			wit, err := us.Step(true)
+			require.NoError(t, err)
+			require.Len(t, wit.MemProof, 2*MemProofSize64)
+			require.Equal(t, expected, state.Registers)
+			require.Equal(t, c.expMem, state.Memory.GetDoubleword(memAddr))
+			require.Equal(t, c.expHi, state.HI)
+			require.Equal(t, c.expLo, state.LO)
+			require.Equal(t, uint64(0x1_0000_1004), state.PC)
+			require.Equal(t, uint64(0x1_0000_1008), state.NextPC)
+		})
+	}
+}
+
+func TestState64Branch(t *testing.T) {
+	// beq $8, $9, 16: the registers only differ in their upper 32 bits, so the branch is not taken
+	state := &State64{PC: 0x1_0000_1000, NextPC: 0x1_0000_1004, Memory: NewMemory64()}
+	state.Memory.SetDoubleword(state.PC, uint64(0x4<<26|8<<21|9<<16|4)<<32)
+	state.Registers[8] = 0x1_0000_0001
+	state.Registers[9] = 0x1
+	us := NewInstrumentedState64(state, nil, os.Stdout, os.Stderr)
+	_, err := us.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1_0000_1004), state.PC)
+	require.Equal(t, uint64(0x1_0000_1008), state.NextPC)
+
+	state.PC, state.NextPC = 0x1_0000_1000, 0x1_0000_1004
+	state.Registers[9] = 0x1_0000_0001
+	_, err = us.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1_0000_1004), state.PC)
+	require.Equal(t, uint64(0x1_0000_1014), state.NextPC)
+}
+
+func TestHello64(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello64.elf")
+	require.NoError(t, err, "open ELF file")
+
+	state, err := LoadELF64(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+
+	err = PatchGo64(elfProgram, state)
+	require.NoError(t, err, "apply Go runtime patches")
+	require.NoError(t, PatchStack64(state), "add initial stack")
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewInstrumentedState64(state, nil, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	for i := 0; i < 400_000; i++ {
+		if us.state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+
+	require.Equal(t, "hello world!\n", stdOutBuf.String(), "stdout says hello")
+	require.Equal(t, "", stdErrBuf.String(), "stderr silent")
+}
+
+func TestClaim64(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/claim64.elf")
+	require.NoError(t, err, "open ELF file")
+
+	_, err = LoadELF(elfProgram)
+	require.Error(t, err, "64-bit program does not fit the 32-bit VM")
+
+	state, err := LoadELF64(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+
+	err = PatchGo64(elfProgram, state)
+	require.NoError(t, err, "apply Go runtime patches")
+	require.NoError(t, PatchStack64(state), "add initial stack")
+
+	oracle, expectedStdOut, expectedStdErr := claimTestOracle(t)
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewInstrumentedState64(state, oracle, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	for i := 0; i < 2000_000; i++ {
+		if us.state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+
+	require.Equal(t, expectedStdOut, stdOutBuf.String(), "stdout")
+	require.Equal(t, expectedStdErr, stdErrBuf.String(), "stderr")
+}
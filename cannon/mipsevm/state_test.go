@@ -269,3 +269,82 @@ func staticOracle(t *testing.T, preimageData []byte) *testOracle {
 		},
 	}
 }
+
+// TestHintWrite checks that a hint is only passed to the oracle once all its data has been written,
+// which may take several writes that each end in the middle of a hint or its length prefix.
+func TestHintWrite(t *testing.T) {
+	encodeHint := func(hint string) []byte {
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(hint))), hint...)
+	}
+	data := append(encodeHint("fetch-a 0x01"), encodeHint("fetch-b 0x0203")...)
+	writes := []struct {
+		count    uint32
+		expected []string
+	}{
+		{count: 7, expected: nil},                       // part of the first hint
+		{count: 11, expected: []string{"fetch-a 0x01"}}, // the rest of the first hint and part of the next prefix
+		{count: uint32(len(data)) - 18, expected: []string{"fetch-a 0x01", "fetch-b 0x0203"}}, // the rest of the second hint
+	}
+
+	// Each VM executes a single hint write syscall with the given arguments.
+	vms := []struct {
+		name  string
+		setup func(oracle PreimageOracle) func(addr uint32, count uint32)
+	}{
+		{"SingleThreaded", func(oracle PreimageOracle) func(addr uint32, count uint32) {
+			state := &State{Memory: NewMemory()}
+			state.Memory.SetMemory(testPC, syscallInsn)
+			require.NoError(t, state.Memory.SetMemoryRange(testAddr, bytes.NewReader(data)))
+			us := NewInstrumentedState(state, oracle, os.Stdout, os.Stderr)
+			return func(addr uint32, count uint32) {
+				state.PC, state.NextPC = testPC, testPC+4
+				state.Registers[2], state.Registers[4], state.Registers[5], state.Registers[6] = sysWrite, fdHintWrite, addr, count
+				_, err := us.Step(true)
+				require.NoError(t, err)
+				require.Equal(t, count, state.Registers[2])
+			}
+		}},
+		{"MultiThreaded", func(oracle PreimageOracle) func(addr uint32, count uint32) {
+			st := &State{Memory: NewMemory()}
+			st.Memory.SetMemory(testPC, syscallInsn)
+			require.NoError(t, st.Memory.SetMemoryRange(testAddr, bytes.NewReader(data)))
+			state := NewMTState(st)
+			us := NewMTInstrumentedState(state, oracle, os.Stdout, os.Stderr)
+			return func(addr uint32, count uint32) {
+				thread := state.CurrentThread()
+				thread.PC, thread.NextPC = testPC, testPC+4
+				thread.Registers[2], thread.Registers[4], thread.Registers[5], thread.Registers[6] = sysWrite, fdHintWrite, addr, count
+				_, err := us.Step(true)
+				require.NoError(t, err)
+				require.Equal(t, count, thread.Registers[2])
+			}
+		}},
+		{"64Bit", func(oracle PreimageOracle) func(addr uint32, count uint32) {
+			state := &State64{Memory: NewMemory64()}
+			state.Memory.SetDoubleword(testPC, uint64(syscallInsn)<<32)
+			require.NoError(t, state.Memory.SetMemoryRange(testAddr, bytes.NewReader(data)))
+			us := NewInstrumentedState64(state, oracle, os.Stdout, os.Stderr)
+			return func(addr uint32, count uint32) {
+				state.PC, state.NextPC = testPC, testPC+4
+				state.Registers[2], state.Registers[4], state.Registers[5], state.Registers[6] = sysWrite64, fdHintWrite, uint64(addr), uint64(count)
+				_, err := us.Step(true)
+				require.NoError(t, err)
+				require.Equal(t, uint64(count), state.Registers[2])
+			}
+		}},
+	}
+	for _, vm := range vms {
+		vm := vm
+		t.Run(vm.name, func(t *testing.T) {
+			var hints []string
+			oracle := &testOracle{hint: func(v []byte) { hints = append(hints, string(v)) }}
+			write := vm.setup(oracle)
+			addr := uint32(testAddr)
+			for _, w := range writes {
+				write(addr, w.count)
+				addr += w.count
+				require.Equal(t, w.expected, hints)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// wordMemory is the memory of a VM with words of type W, as accessed by the logic shared between the VMs.
+type wordMemory[W Word] interface {
+	readWord(addr W) W
+	writeWord(addr W, v W)
+	merkleProof(addr W) []byte
+	ReadMemoryRange(addr W, count W) io.Reader
+}
+
+// vmIO holds the output streams and pre-image oracle of a VM, and the memory and pre-image accesses of the current
+// step that are added to its witness. The single-threaded, multi-threaded and 64-bit VMs embed it, so all handle
+// syscalls, pre-images and hints with the same logic.
+type vmIO[W Word] struct {
+	stdOut io.Writer
+	stdErr io.Writer
+
+	memory          wordMemory[W]
+	lastMemAccess   W
+	memProofEnabled bool
+	memProof        []byte
+
+	preimageOracle PreimageOracle
+
+	// cached pre-image data, including 8 byte length prefix
+	lastPreimage []byte
+	// key for above preimage
+	lastPreimageKey [32]byte
+	// offset we last read from, or max uint32 if nothing is read this step
+	lastPreimageOffset uint32
+}
+
+func newVMIO[W Word](po PreimageOracle, stdOut, stdErr io.Writer) vmIO[W] {
+	memProofSize := 28 * 32
+	if wordBits[W]() == 64 {
+		memProofSize = MemProofSize64
+	}
+	return vmIO[W]{
+		stdOut:         stdOut,
+		stdErr:         stdErr,
+		memProof:       make([]byte, memProofSize),
+		preimageOracle: po,
+	}
+}
+
+// vmMemory is the memory of a VM as accessed by its syscalls.
+// The multi-threaded VM overrides storeMemory to also clear load-linked reservations.
+type vmMemory[W Word] interface {
+	loadMemory(effAddr W) W
+	storeMemory(effAddr W, v W)
+}
+
+// sysState points to the memory, heap and pre-image state of the VM, so all VMs execute syscalls with the same logic.
+type sysState[W Word] struct {
+	Memory         vmMemory[W]
+	Heap           *W
+	PreimageKey    *common.Hash
+	PreimageOffset *uint32
+	LastHint       *hexutil.Bytes
+}
+
+// startStep clears the accesses recorded for the witness of the previous step.
+func (m *vmIO[W]) startStep(memory wordMemory[W], proof bool) {
+	m.memory = memory
+	m.memProofEnabled = proof
+	m.lastMemAccess = ^W(0)
+	m.lastPreimageOffset = ^uint32(0)
+}
+
+// completeWitness adds the memory and pre-image accesses of the step to its witness.
+func (m *vmIO[W]) completeWitness(wit *StepWitness) {
+	wit.MemProof = append(wit.MemProof, m.memProof...)
+	if m.lastPreimageOffset != ^uint32(0) {
+		wit.PreimageOffset = m.lastPreimageOffset
+		wit.PreimageKey = m.lastPreimageKey
+		wit.PreimageValue = m.lastPreimage
+	}
+}
+
+func (m *vmIO[W]) LastPreimage() []byte {
+	return m.lastPreimage
+}
+
+func (m *vmIO[W]) readPreimage(key [32]byte, offset uint32) (dat [32]byte, datLen uint32) {
+	preimage := m.lastPreimage
+	if key != m.lastPreimageKey {
+		m.lastPreimageKey = key
+		data := m.preimageOracle.GetPreimage(key)
+		// add the length prefix
+		preimage = make([]byte, 0, 8+len(data))
+		preimage = binary.BigEndian.AppendUint64(preimage, uint64(len(data)))
+		preimage = append(preimage, data...)
+		m.lastPreimage = preimage
+	}
+	m.lastPreimageOffset = offset
+	datLen = uint32(copy(dat[:], preimage[offset:]))
+	return
+}
+
+func (m *vmIO[W]) trackMemAccess(effAddr W) {
+	if m.memProofEnabled && m.lastMemAccess != effAddr {
+		if m.lastMemAccess != ^W(0) {
+			panic(fmt.Errorf("unexpected different mem access at %0*x, already have access at %0*x buffered", wordBits[W]()/4, effAddr, wordBits[W]()/4, m.lastMemAccess))
+		}
+		m.lastMemAccess = effAddr
+		m.memProof = m.memory.merkleProof(effAddr)
+	}
+}
+
+func (m *vmIO[W]) loadMemory(effAddr W) W {
+	m.trackMemAccess(effAddr)
+	return m.memory.readWord(effAddr)
+}
+
+func (m *vmIO[W]) storeMemory(effAddr W, v W) {
+	m.trackMemAccess(effAddr)
+	m.memory.writeWord(effAddr, v)
+}
+
+// handleMmap allocates sz bytes, rounded up to whole pages, from the heap if no address is hinted.
+func handleMmap[W Word](st sysState[W], a0, a1 W) (v0 W) {
+	sz := a1
+	if sz&PageAddrMask != 0 { // adjust size to align with page size
+		sz += PageSize - (sz & PageAddrMask)
+	}
+	if a0 == 0 {
+		v0 = *st.Heap
+		*st.Heap += sz
+	} else {
+		v0 = a0
+	}
+	return v0
+}
+
+// handleRead reads from a file descriptor. Pre-image data is read into the word of memory containing the address,
+// up to the end of that word.
+func (m *vmIO[W]) handleRead(st sysState[W], a0, a1, a2 W) (v0, v1 W) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = read, v1 = err code
+	wordBytes := W(wordBits[W]() / 8)
+	switch a0 {
+	case fdStdin:
+		// leave v0 and v1 zero: read nothing, no error
+	case fdPreimageRead: // pre-image oracle
+		effAddr := a1 &^ (wordBytes - 1)
+		mem := st.Memory.loadMemory(effAddr)
+		dat, datLen := m.readPreimage(*st.PreimageKey, *st.PreimageOffset)
+		alignment := a1 & (wordBytes - 1)
+		space := wordBytes - alignment
+		if space < W(datLen) {
+			datLen = uint32(space)
+		}
+		if a2 < W(datLen) {
+			datLen = uint32(a2)
+		}
+		outMem := encodeWord(mem)
+		copy(outMem[alignment:], dat[:datLen])
+		st.Memory.storeMemory(effAddr, decodeWord[W](outMem))
+		*st.PreimageOffset += datLen
+		v0 = W(datLen)
+	case fdHintRead: // hint response
+		// don't actually read into memory, just say we read it all, we ignore the result anyway
+		v0 = a2
+	default:
+		v0 = ^W(0)
+		v1 = MipsEBADF
+	}
+	return v0, v1
+}
+
+// handleWrite writes to a file descriptor. The pre-image key is updated with the bytes from the address to the end
+// of the word of memory containing it.
+func (m *vmIO[W]) handleWrite(st sysState[W], a0, a1, a2 W) (v0, v1 W) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = written, v1 = err code
+	wordBytes := W(wordBits[W]() / 8)
+	switch a0 {
+	case fdStdout:
+		_, _ = io.Copy(m.stdOut, m.memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdStderr:
+		_, _ = io.Copy(m.stdErr, m.memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdHintWrite:
+		hintData, _ := io.ReadAll(m.memory.ReadMemoryRange(a1, a2))
+		m.writeHint(st.LastHint, hintData)
+		v0 = a2
+	case fdPreimageWrite:
+		effAddr := a1 &^ (wordBytes - 1)
+		mem := st.Memory.loadMemory(effAddr)
+		key := *st.PreimageKey
+		alignment := a1 & (wordBytes - 1)
+		space := wordBytes - alignment
+		if space < a2 {
+			a2 = space
+		}
+		copy(key[:], key[a2:])
+		copy(key[32-a2:], encodeWord(mem)[alignment:])
+		*st.PreimageKey = key
+		*st.PreimageOffset = 0
+		v0 = a2
+	default:
+		v0 = ^W(0)
+		v1 = MipsEBADF
+	}
+	return v0, v1
+}
+
+// writeHint buffers the hint data and passes each complete length-prefixed hint to the pre-image oracle.
+func (m *vmIO[W]) writeHint(lastHint *hexutil.Bytes, hintData []byte) {
+	*lastHint = append(*lastHint, hintData...)
+	for len(*lastHint) >= 4 { // process while there is enough data to check if there are any hints
+		hintLen := binary.BigEndian.Uint32((*lastHint)[:4])
+		if hintLen > uint32(len((*lastHint)[4:])) {
+			break // stop processing hints if there is incomplete data buffered
+		}
+		hint := (*lastHint)[4 : 4+hintLen] // without the length prefix
+		*lastHint = (*lastHint)[4+hintLen:]
+		m.preimageOracle.Hint(hint)
+	}
+}
+
+func handleFcntl[W Word](a0, a1 W) (v0, v1 W) {
+	// args: a0 = fd, a1 = cmd
+	if a1 == 3 { // F_GETFL: get file descriptor flags
+		switch a0 {
+		case fdStdin, fdPreimageRead, fdHintRead:
+			v0 = 0 // O_RDONLY
+		case fdStdout, fdStderr, fdPreimageWrite, fdHintWrite:
+			v0 = 1 // O_WRONLY
+		default:
+			v0 = ^W(0)
+			v1 = MipsEBADF
+		}
+	} else {
+		v0 = ^W(0)
+		v1 = MipsEINVAL // cmd not recognized by this kernel
+	}
+	return v0, v1
+}
+
+// encodeWord returns the big-endian encoding of a word.
+func encodeWord[W Word](w W) []byte {
+	out := make([]byte, wordBits[W]()/8)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = byte(w)
+		w >>= 8
+	}
+	return out
+}
+
+// decodeWord decodes a big-endian word.
+func decodeWord[W Word](b []byte) W {
+	var w W
+	for _, v := range b {
+		w = w<<8 | W(v)
+	}
+	return w
+}
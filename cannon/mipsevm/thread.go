@@ -0,0 +1,89 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ThreadWitnessSize is the size of the thread witness encoding in bytes.
+const ThreadWitnessSize = 166
+
+// FutexEmptyAddr is the futex address of a thread that is not waiting on a futex.
+const FutexEmptyAddr = ^uint32(0)
+
+// EmptyThreadStackRoot is the root of a thread stack that contains no threads.
+var EmptyThreadStackRoot = crypto.Keccak256Hash(make([]byte, 32))
+
+// ThreadState is the state of a single thread in a multi-threaded VM.
+type ThreadState struct {
+	ThreadId uint32 `json:"threadId"`
+	ExitCode uint8  `json:"exit"`
+	Exited   bool   `json:"exited"`
+
+	// FutexAddr is the address the thread is waiting on, or FutexEmptyAddr if it is not waiting.
+	FutexAddr uint32 `json:"futexAddr"`
+	// FutexVal is the value that was expected at FutexAddr when the thread started waiting.
+	FutexVal uint32 `json:"futexVal"`
+	// FutexTimeoutStep is the step after which the wait times out.
+	FutexTimeoutStep uint64 `json:"futexTimeoutStep"`
+
+	PC     uint32 `json:"pc"`
+	NextPC uint32 `json:"nextPC"`
+	LO     uint32 `json:"lo"`
+	HI     uint32 `json:"hi"`
+
+	Registers [32]uint32 `json:"registers"`
+}
+
+func (t *ThreadState) EncodeThread() []byte {
+	out := make([]byte, 0, ThreadWitnessSize)
+	out = binary.BigEndian.AppendUint32(out, t.ThreadId)
+	out = append(out, t.ExitCode)
+	if t.Exited {
+		out = append(out, 1)
+	} else {
+		out = append(out, 0)
+	}
+	out = binary.BigEndian.AppendUint32(out, t.FutexAddr)
+	out = binary.BigEndian.AppendUint32(out, t.FutexVal)
+	out = binary.BigEndian.AppendUint64(out, t.FutexTimeoutStep)
+	out = binary.BigEndian.AppendUint32(out, t.PC)
+	out = binary.BigEndian.AppendUint32(out, t.NextPC)
+	out = binary.BigEndian.AppendUint32(out, t.LO)
+	out = binary.BigEndian.AppendUint32(out, t.HI)
+	for _, r := range t.Registers {
+		out = binary.BigEndian.AppendUint32(out, r)
+	}
+	return out
+}
+
+// ThreadStackRoot computes the commitment to a thread stack, where the last thread is the top of the stack.
+// Each thread is hashed into the root of the threads below it, so the top thread can be proven with just the root
+// of the rest of the stack.
+func ThreadStackRoot(stack []*ThreadState) common.Hash {
+	root := EmptyThreadStackRoot
+	for _, thread := range stack {
+		root = pushThreadRoot(root, thread)
+	}
+	return root
+}
+
+func pushThreadRoot(prevRoot common.Hash, thread *ThreadState) common.Hash {
+	threadHash := crypto.Keccak256Hash(thread.EncodeThread())
+	return crypto.Keccak256Hash(prevRoot[:], threadHash[:])
+}
+
+// cpu returns the CPU state of the thread, to execute instructions on.
+func (t *ThreadState) cpu() cpuState[uint32] {
+	return cpuState[uint32]{PC: &t.PC, NextPC: &t.NextPC, LO: &t.LO, HI: &t.HI, Registers: &t.Registers}
+}
+
+// completeSyscall writes the syscall results to the thread's registers and moves to the next instruction.
+func (t *ThreadState) completeSyscall(v0 uint32, v1 uint32) {
+	t.Registers[2] = v0
+	t.Registers[7] = v1
+	t.PC = t.NextPC
+	t.NextPC = t.NextPC + 4
+}
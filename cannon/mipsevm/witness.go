@@ -8,6 +8,10 @@ type StepWitness struct {
 	// encoded state witness
 	State []byte
 
+	// ThreadProof is the encoded current thread and the root of the rest of its thread stack.
+	// Only set for multi-threaded states.
+	ThreadProof []byte
+
 	MemProof []byte
 
 	PreimageKey    [32]byte // zeroed when no pre-image is accessed
@@ -18,3 +22,10 @@ type StepWitness struct {
 func (wit *StepWitness) HasPreimage() bool {
 	return wit.PreimageKey != ([32]byte{})
 }
+
+// ProofData returns the proof data to pass alongside the state when executing the step on-chain.
+func (wit *StepWitness) ProofData() []byte {
+	out := make([]byte, 0, len(wit.ThreadProof)+len(wit.MemProof))
+	out = append(out, wit.ThreadProof...)
+	return append(out, wit.MemProof...)
+}
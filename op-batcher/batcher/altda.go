@@ -0,0 +1,173 @@
+package batcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// altDACommitment is an alt-DA commitment that the batcher posted to L1, with its input.
+type altDACommitment struct {
+	comm  altda.Keccak256Commitment
+	input []byte
+	// block is the number of the L1 block the commitment is included in
+	block uint64
+	// challenged is true if the availability of the input is challenged in the challenge window of the commitment
+	challenged bool
+	// resolving is true while, or once, the input is posted to L1 to resolve the challenge
+	resolving bool
+}
+
+// altDAChallenges keeps track of the alt-DA commitments that the batcher posted to L1, and finds the challenges of
+// the availability of their inputs on L1, so the batcher can resolve them before the end of the resolve window.
+// The challenges are found with the same rules as the derivation pipeline: successful transactions to the
+// challenge address, paying the challenge bond, in the challenge window of the commitment.
+type altDAChallenges struct {
+	log log.Logger
+	cfg *rollup.Config
+	l1  L1Client
+
+	mu sync.Mutex
+	// sent are the commitments of the batcher transactions that are not confirmed yet
+	sent map[txID]*altDACommitment
+	// posted are the commitments that are confirmed on L1, and can still be challenged or resolved
+	posted map[altda.Keccak256Commitment]*altDACommitment
+	// origin is the last L1 block that is searched for challenges
+	origin eth.L1BlockRef
+}
+
+func newAltDAChallenges(log log.Logger, cfg *rollup.Config, l1 L1Client) *altDAChallenges {
+	return &altDAChallenges{
+		log:    log,
+		cfg:    cfg,
+		l1:     l1,
+		sent:   make(map[txID]*altDACommitment),
+		posted: make(map[altda.Keccak256Commitment]*altDACommitment),
+	}
+}
+
+// Sent records the commitment in the batcher transaction with the given ID.
+func (c *altDAChallenges) Sent(id txID, comm altda.Keccak256Commitment, input []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent[id] = &altDACommitment{comm: comm, input: input}
+}
+
+// Confirmed records that the batcher transaction with the given ID is included in the L1 block.
+func (c *altDAChallenges) Confirmed(id txID, block eth.BlockID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sent, ok := c.sent[id]; ok {
+		delete(c.sent, id)
+		sent.block = block.Number
+		c.posted[sent.comm] = sent
+		// the receipt is only returned after some confirmations, so the blocks after it are searched again
+		if c.origin.Number > block.Number {
+			c.origin = eth.L1BlockRef{Number: block.Number}
+		}
+	}
+}
+
+// Failed forgets the commitment of the batcher transaction with the given ID, as it is not posted to L1.
+func (c *altDAChallenges) Failed(id txID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sent, id)
+}
+
+// ResolveFailed records that the input could not be posted to L1, so it is resolved again on the next update.
+func (c *altDAChallenges) ResolveFailed(comm altda.Keccak256Commitment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if posted, ok := c.posted[comm]; ok {
+		posted.resolving = false
+	}
+}
+
+// Update searches the L1 blocks up to the tip for challenges, and returns the commitments
+// of which the challenges must be resolved by posting their inputs to L1.
+func (c *altDAChallenges) Update(ctx context.Context, tip eth.L1BlockRef) ([]*altDACommitment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	window := c.cfg.AltDAWindow()
+	if c.origin == (eth.L1BlockRef{}) || tip.Number < c.origin.Number || tip.Number-c.origin.Number > window {
+		// challenges before the alt-DA window can't be resolved anymore, and are not searched for
+		if tip.Number > window {
+			c.origin = eth.L1BlockRef{Number: tip.Number - window - 1}
+		} else {
+			c.origin = eth.L1BlockRef{}
+		}
+	}
+	for c.origin.Number < tip.Number {
+		block, err := c.l1.BlockByNumber(ctx, new(big.Int).SetUint64(c.origin.Number+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L1 block %d: %w", c.origin.Number+1, err)
+		}
+		ref := eth.InfoToL1BlockRef(eth.HeaderBlockInfo(block.Header()))
+		if c.origin.Hash != (common.Hash{}) && ref.ParentHash != c.origin.Hash {
+			c.log.Warn("L1 reorg, searching the alt-DA window for challenges again", "block", ref, "origin", c.origin)
+			c.origin = eth.L1BlockRef{}
+			return c.resolvable(tip), nil
+		}
+		if err := c.searchChallenges(ctx, block); err != nil {
+			return nil, err
+		}
+		c.origin = ref
+	}
+	return c.resolvable(tip), nil
+}
+
+// searchChallenges marks the posted commitments that are challenged in the L1 block.
+func (c *altDAChallenges) searchChallenges(ctx context.Context, block *types.Block) error {
+	for _, tx := range block.Transactions() {
+		if to := tx.To(); to == nil || *to != c.cfg.AltDAChallengeAddress || tx.Value().Cmp(c.cfg.AltDAChallengeBond) < 0 {
+			continue
+		}
+		comm, input, err := altda.DecodeChallengeTxData(tx.Data())
+		if err != nil || input != nil {
+			continue
+		}
+		posted, ok := c.posted[comm]
+		if !ok || block.NumberU64() <= posted.block || block.NumberU64() > posted.block+c.cfg.AltDAChallengeWindow {
+			continue
+		}
+		receipt, err := c.l1.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to fetch receipt of challenge %s: %w", tx.Hash(), err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		c.log.Warn("alt-DA input is challenged", "commitment", comm, "l1", posted.block, "challenge", tx.Hash())
+		posted.challenged = true
+	}
+	return nil
+}
+
+// resolvable returns the challenged commitments that are not resolved yet, and forgets the commitments
+// that can't be challenged or resolved anymore.
+func (c *altDAChallenges) resolvable(tip eth.L1BlockRef) []*altDACommitment {
+	var out []*altDACommitment
+	for comm, posted := range c.posted {
+		challengeEnd := posted.block + c.cfg.AltDAChallengeWindow
+		resolveEnd := challengeEnd + c.cfg.AltDAResolveWindow
+		if tip.Number >= resolveEnd || (!posted.challenged && c.origin.Number >= challengeEnd) {
+			delete(c.posted, comm)
+			continue
+		}
+		if posted.challenged && !posted.resolving {
+			posted.resolving = true
+			out = append(out, posted)
+		}
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package batcher
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type testL1Client struct {
+	txs      map[uint64]types.Transactions
+	failed   map[common.Hash]bool
+	reorgAt  uint64
+	blocks   map[uint64]*types.Block
+	receipts int
+}
+
+func newTestL1Client() *testL1Client {
+	return &testL1Client{
+		txs:    make(map[uint64]types.Transactions),
+		failed: make(map[common.Hash]bool),
+		blocks: make(map[uint64]*types.Block),
+	}
+}
+
+func (l *testL1Client) addTx(num uint64, to common.Address, value int64, data []byte, success bool) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: uint64(len(l.failed)), To: &to, Value: big.NewInt(value), Data: data})
+	l.txs[num] = append(l.txs[num], tx)
+	l.failed[tx.Hash()] = !success
+}
+
+func (l *testL1Client) block(num uint64) *types.Block {
+	if block, ok := l.blocks[num]; ok {
+		return block
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(num), Extra: []byte{byte(l.reorgAt)}}
+	if num > 0 {
+		header.ParentHash = l.block(num - 1).Hash()
+	}
+	block := types.NewBlockWithHeader(header).WithBody(l.txs[num], nil)
+	l.blocks[num] = block
+	return block
+}
+
+// reorg replaces the L1 blocks from the given number.
+func (l *testL1Client) reorg(num uint64) {
+	l.reorgAt++
+	for n := range l.blocks {
+		if n >= num {
+			delete(l.blocks, n)
+		}
+	}
+}
+
+func (l *testL1Client) tip(num uint64) eth.L1BlockRef {
+	return eth.InfoToL1BlockRef(eth.HeaderBlockInfo(l.block(num).Header()))
+}
+
+func (l *testL1Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return l.block(number.Uint64()).Header(), nil
+}
+
+func (l *testL1Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return l.block(number.Uint64()), nil
+}
+
+func (l *testL1Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	l.receipts++
+	status := types.ReceiptStatusSuccessful
+	if l.failed[txHash] {
+		status = types.ReceiptStatusFailed
+	}
+	return &types.Receipt{TxHash: txHash, Status: status}, nil
+}
+
+func TestAltDAChallenges(t *testing.T) {
+	cfg := &rollup.Config{
+		UseAltDA:              true,
+		AltDAChallengeAddress: common.Address{0xda},
+		AltDAChallengeWindow:  3,
+		AltDAResolveWindow:    5,
+		AltDAChallengeBond:    big.NewInt(100),
+	}
+	input := []byte("batcher data")
+	comm := altda.NewKeccak256Commitment(input)
+	id := txID{frameNumber: 1}
+	setup := func(t *testing.T) (*altDAChallenges, *testL1Client) {
+		l1 := newTestL1Client()
+		c := newAltDAChallenges(testlog.Logger(t, log.LevelCrit), cfg, l1)
+		c.Sent(id, comm, input)
+		c.Confirmed(id, eth.BlockID{Number: 10})
+		return c, l1
+	}
+	update := func(t *testing.T, c *altDAChallenges, l1 *testL1Client, tip uint64) []*altDACommitment {
+		out, err := c.Update(context.Background(), l1.tip(tip))
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("Challenged", func(t *testing.T) {
+		c, l1 := setup(t)
+		l1.addTx(12, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), true)
+		require.Empty(t, update(t, c, l1, 11))
+
+		out := update(t, c, l1, 12)
+		require.Len(t, out, 1)
+		require.Equal(t, comm, out[0].comm)
+		require.Equal(t, input, out[0].input)
+		require.Empty(t, update(t, c, l1, 13), "challenge is being resolved")
+
+		c.ResolveFailed(comm)
+		require.Len(t, update(t, c, l1, 14), 1, "failed resolution is retried")
+		c.ResolveFailed(comm)
+		require.Empty(t, update(t, c, l1, 18), "resolve window is over")
+		require.Empty(t, c.posted)
+	})
+
+	t.Run("IgnoredChallenges", func(t *testing.T) {
+		c, l1 := setup(t)
+		other := altda.NewKeccak256Commitment([]byte("other"))
+		l1.addTx(10, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), true)
+		l1.addTx(11, cfg.AltDAChallengeAddress, 99, comm.ChallengeTxData(), true)
+		l1.addTx(11, common.Address{0xaa}, 100, comm.ChallengeTxData(), true)
+		l1.addTx(12, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), false)
+		l1.addTx(12, cfg.AltDAChallengeAddress, 100, other.ChallengeTxData(), true)
+		l1.addTx(13, cfg.AltDAChallengeAddress, 100, altda.ResolveTxData(input), true)
+		l1.addTx(14, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), true)
+		require.Empty(t, update(t, c, l1, 13))
+		require.Empty(t, c.posted, "commitment can't be challenged anymore")
+		require.Empty(t, update(t, c, l1, 14))
+		require.Equal(t, 1, l1.receipts, "only the receipt of the bonded challenge in the window is fetched")
+	})
+
+	t.Run("ConfirmedAfterSearch", func(t *testing.T) {
+		l1 := newTestL1Client()
+		c := newAltDAChallenges(testlog.Logger(t, log.LevelCrit), cfg, l1)
+		l1.addTx(11, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), true)
+		c.Sent(id, comm, input)
+		require.Empty(t, update(t, c, l1, 12))
+		// the receipt is returned after the challenge was searched for
+		c.Confirmed(id, eth.BlockID{Number: 10})
+		require.Len(t, update(t, c, l1, 12), 1)
+	})
+
+	t.Run("FailedTx", func(t *testing.T) {
+		c, l1 := setup(t)
+		c.Sent(txID{frameNumber: 2}, altda.NewKeccak256Commitment([]byte("other")), []byte("other"))
+		c.Failed(txID{frameNumber: 2})
+		require.Empty(t, c.sent)
+		require.Empty(t, update(t, c, l1, 11))
+	})
+
+	t.Run("Reorg", func(t *testing.T) {
+		c, l1 := setup(t)
+		require.Empty(t, update(t, c, l1, 11))
+		l1.reorg(11)
+		l1.addTx(12, cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), true)
+		require.Empty(t, update(t, c, l1, 12), "reorg is detected")
+		require.Len(t, update(t, c, l1, 12), 1, "challenge in the new chain is found")
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
@@ -70,6 +71,7 @@ type CLIConfig struct {
 	PprofConfig      oppprof.CLIConfig
 	CompressorConfig compressor.CLIConfig
 	RPC              oprpc.CLIConfig
+	AltDA            altda.CLIConfig
 }
 
 func (c *CLIConfig) Check() error {
@@ -97,6 +99,15 @@ func (c *CLIConfig) Check() error {
 	if !flags.ValidDataAvailabilityType(c.DataAvailabilityType) {
 		return fmt.Errorf("unknown data availability type: %q", c.DataAvailabilityType)
 	}
+	if c.AltDA.Enabled && c.DataAvailabilityType != flags.CalldataType {
+		return fmt.Errorf("alt-DA requires the %q data availability type", flags.CalldataType)
+	}
+	if c.AltDA.Enabled && c.MaxL1TxSize > altda.MaxInputSize {
+		return fmt.Errorf("max L1 tx size %d exceeds the maximum alt-DA input size %d", c.MaxL1TxSize, altda.MaxInputSize)
+	}
+	if err := c.AltDA.Check(); err != nil {
+		return err
+	}
 	if err := c.MetricsConfig.Check(); err != nil {
 		return err
 	}
@@ -136,5 +147,6 @@ func NewConfig(ctx *cli.Context) *CLIConfig {
 		PprofConfig:                  oppprof.ReadCLIConfig(ctx),
 		CompressorConfig:             compressor.ReadCLIConfig(ctx),
 		RPC:                          oprpc.ReadCLIConfig(ctx),
+		AltDA:                        altda.ReadCLIConfig(ctx),
 	}
 }
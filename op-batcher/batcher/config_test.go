@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/batcher"
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
@@ -87,6 +88,27 @@ func TestBatcherConfig(t *testing.T) {
 			override:  func(c *batcher.CLIConfig) { c.DataAvailabilityType = "foo" },
 			errString: "unknown data availability type: \"foo\"",
 		},
+		{
+			name: "alt-DA with blobs",
+			override: func(c *batcher.CLIConfig) {
+				c.AltDA = altda.CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}
+				c.DataAvailabilityType = flags.BlobsType
+			},
+			errString: "alt-DA requires the \"calldata\" data availability type",
+		},
+		{
+			name:      "alt-DA without DA server",
+			override:  func(c *batcher.CLIConfig) { c.AltDA = altda.CLIConfig{Enabled: true} },
+			errString: "no DA server is configured",
+		},
+		{
+			name: "alt-DA with too large max L1 tx size",
+			override: func(c *batcher.CLIConfig) {
+				c.AltDA = altda.CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}
+				c.MaxL1TxSize = altda.MaxInputSize + 1
+			},
+			errString: "exceeds the maximum alt-DA input size",
+		},
 	}
 
 	for _, test := range tests {
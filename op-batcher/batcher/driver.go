@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
@@ -26,12 +28,19 @@ var ErrBatcherNotRunning = errors.New("batcher is not running")
 
 type L1Client interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
 type L2Client interface {
 	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
 }
 
+// AltDAClient stores batcher data on an alternative data-availability server.
+type AltDAClient interface {
+	SetInput(ctx context.Context, input []byte) (altda.Keccak256Commitment, error)
+}
+
 type RollupClient interface {
 	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 }
@@ -46,6 +55,9 @@ type DriverSetup struct {
 	L1Client         L1Client
 	EndpointProvider dial.L2EndpointProvider
 	ChannelConfig    ChannelConfig
+	// AltDA is the DA server client to store batcher data on, in which case only the commitments
+	// to the data are posted to L1. It is nil if alt-DA is disabled.
+	AltDA AltDAClient
 }
 
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
@@ -68,14 +80,20 @@ type BatchSubmitter struct {
 	lastL1Tip       eth.L1BlockRef
 
 	state *channelManager
+	// altDAChallenges keeps track of the posted alt-DA commitments to resolve challenges of, if alt-DA is enabled
+	altDAChallenges *altDAChallenges
 }
 
 // NewBatchSubmitter initializes the BatchSubmitter driver from a preconfigured DriverSetup
 func NewBatchSubmitter(setup DriverSetup) *BatchSubmitter {
-	return &BatchSubmitter{
+	l := &BatchSubmitter{
 		DriverSetup: setup,
 		state:       NewChannelManager(setup.Log, setup.Metr, setup.ChannelConfig, setup.RollupConfig),
 	}
+	if setup.AltDA != nil {
+		l.altDAChallenges = newAltDAChallenges(setup.Log, setup.RollupConfig, setup.L1Client)
+	}
+	return l
 }
 
 func (l *BatchSubmitter) StartBatchSubmitting() error {
@@ -275,6 +293,9 @@ func (l *BatchSubmitter) loop() {
 				continue
 			}
 			l.publishStateToL1(queue, receiptsCh, false)
+			if l.altDAChallenges != nil {
+				l.resolveAltDAChallenges(l.shutdownCtx)
+			}
 		case r := <-receiptsCh:
 			l.handleReceipt(r)
 		case <-l.shutdownCtx.Done():
@@ -349,7 +370,7 @@ func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[t
 		return err
 	}
 
-	if err = l.sendTransaction(txdata, queue, receiptsCh); err != nil {
+	if err = l.sendTransaction(ctx, txdata, queue, receiptsCh); err != nil {
 		return fmt.Errorf("BatchSubmitter.sendTransaction failed: %w", err)
 	}
 	return nil
@@ -358,12 +379,22 @@ func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[t
 // sendTransaction creates & submits a transaction to the batch inbox address with the given `txData`.
 // It currently uses the underlying `txmgr` to handle transaction sending & price management.
 // This is a blocking method. It should not be called concurrently.
-func (l *BatchSubmitter) sendTransaction(txdata txData, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) error {
+func (l *BatchSubmitter) sendTransaction(ctx context.Context, txdata txData, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) error {
 	// Do the gas estimation offline. A value of 0 will cause the [txmgr] to estimate the gas limit.
 	data := txdata.Bytes()
 
 	var candidate *txmgr.TxCandidate
-	if l.Config.UseBlobs {
+	if l.AltDA != nil {
+		comm, err := l.AltDA.SetInput(ctx, data)
+		if err != nil {
+			// requeue the frames, so they are submitted again on the next attempt
+			l.recordFailedTx(txdata, err)
+			return fmt.Errorf("could not store data on DA server: %w", err)
+		}
+		l.Log.Info("stored batcher data on DA server", "commitment", comm, "size", len(data))
+		l.altDAChallenges.Sent(txdata.ID(), comm, data)
+		candidate = l.calldataTxCandidate(comm.TxData())
+	} else if l.Config.UseBlobs {
 		var err error
 		if candidate, err = l.blobTxCandidate(data); err != nil {
 			// We could potentially fall through and try a calldata tx instead, but this would
@@ -409,6 +440,44 @@ func (l *BatchSubmitter) calldataTxCandidate(data []byte) *txmgr.TxCandidate {
 	}
 }
 
+// resolveAltDAChallenges posts the inputs of the alt-DA commitments of the batcher that are challenged on L1,
+// to resolve the challenges before the end of the resolve window. Otherwise the commitments are skipped by derivation.
+func (l *BatchSubmitter) resolveAltDAChallenges(ctx context.Context) {
+	l1tip, err := l.l1Tip(ctx)
+	if err != nil {
+		l.Log.Error("Failed to query L1 tip", "err", err)
+		return
+	}
+	tctx, cancel := context.WithTimeout(ctx, l.Config.NetworkTimeout)
+	defer cancel()
+	challenged, err := l.altDAChallenges.Update(tctx, l1tip)
+	if err != nil {
+		l.Log.Error("Failed to search L1 for alt-DA challenges", "err", err)
+		return
+	}
+	for _, c := range challenged {
+		c := c
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			l.Log.Info("Resolving alt-DA challenge", "commitment", c.comm, "l1", c.block)
+			receipt, err := l.Txmgr.Send(l.killCtx, txmgr.TxCandidate{
+				To:     &l.RollupConfig.AltDAChallengeAddress,
+				TxData: altda.ResolveTxData(c.input),
+			})
+			if err == nil && receipt.Status != types.ReceiptStatusSuccessful {
+				err = fmt.Errorf("resolve transaction %s failed", receipt.TxHash)
+			}
+			if err != nil {
+				l.Log.Error("Failed to resolve alt-DA challenge", "commitment", c.comm, "err", err)
+				l.altDAChallenges.ResolveFailed(c.comm)
+				return
+			}
+			l.Log.Info("Resolved alt-DA challenge", "commitment", c.comm, "tx", receipt.TxHash, "block", eth.ReceiptBlockID(receipt))
+		}()
+	}
+}
+
 func (l *BatchSubmitter) handleReceipt(r txmgr.TxReceipt[txData]) {
 	// Record TX Status
 	if r.Err != nil {
@@ -429,12 +498,18 @@ func (l *BatchSubmitter) recordL1Tip(l1tip eth.L1BlockRef) {
 func (l *BatchSubmitter) recordFailedTx(txd txData, err error) {
 	l.Log.Warn("Transaction failed to send", logFields(txd, err)...)
 	l.state.TxFailed(txd.ID())
+	if l.altDAChallenges != nil {
+		l.altDAChallenges.Failed(txd.ID())
+	}
 }
 
 func (l *BatchSubmitter) recordConfirmedTx(txd txData, receipt *types.Receipt) {
 	l.Log.Info("Transaction confirmed", logFields(txd, receipt)...)
 	l1block := eth.ReceiptBlockID(receipt)
 	l.state.TxConfirmed(txd.ID(), l1block)
+	if l.altDAChallenges != nil {
+		l.altDAChallenges.Confirmed(txd.ID(), l1block)
+	}
 }
 
 // l1Tip gets the current L1 tip as a L1BlockRef. The passed context is assumed
@@ -55,6 +55,9 @@ type BatcherService struct {
 	// Channel builder parameters
 	ChannelConfig ChannelConfig
 
+	// AltDA is the DA server client, or nil if alt-DA is disabled
+	AltDA AltDAClient
+
 	driver *BatchSubmitter
 
 	Version string
@@ -185,6 +188,13 @@ func (bs *BatcherService) initChannelConfig(cfg *CLIConfig) error {
 		BatchType:          cfg.BatchType,
 	}
 
+	if cfg.AltDA.Enabled {
+		if !bs.RollupConfig.UseAltDA {
+			return errors.New("alt-DA is enabled, but the rollup does not use alt-DA")
+		}
+		bs.AltDA = cfg.AltDA.NewDAClient()
+	}
+
 	switch cfg.DataAvailabilityType {
 	case flags.BlobsType:
 		bs.ChannelConfig.MaxFrameSize = eth.MaxBlobDataSize
@@ -272,6 +282,7 @@ func (bs *BatcherService) initDriver() {
 		L1Client:         bs.L1Client,
 		EndpointProvider: bs.EndpointProvider,
 		ChannelConfig:    bs.ChannelConfig,
+		AltDA:            bs.AltDA,
 	})
 }
 
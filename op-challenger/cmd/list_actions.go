@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/journal"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ActionsGameAddressFlag = &cli.StringFlag{
+		Name:    "game-address",
+		Usage:   "Address of the fault game contract. If not set, actions for all games in the datadir are listed.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "GAME_ADDRESS"),
+	}
+)
+
+func ListActions(ctx *cli.Context) error {
+	datadir := ctx.String(flags.DatadirFlag.Name)
+	if datadir == "" {
+		return fmt.Errorf("missing %v", flags.DatadirFlag.Name)
+	}
+	games := make(map[common.Address]string)
+	if ctx.IsSet(ActionsGameAddressFlag.Name) {
+		gameAddr, err := opservice.ParseAddress(ctx.String(ActionsGameAddressFlag.Name))
+		if err != nil {
+			return err
+		}
+		games[gameAddr] = game.GameDir(datadir, gameAddr)
+	} else {
+		var err error
+		games, err = game.ListGameDirs(datadir)
+		if err != nil {
+			return err
+		}
+	}
+
+	addrs := make([]common.Address, 0, len(games))
+	for addr := range games {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Cmp(addrs[j]) < 0
+	})
+	for _, addr := range addrs {
+		entries, err := journal.Load(games[addr])
+		if err != nil {
+			return fmt.Errorf("failed to load actions for game %v: %w", addr, err)
+		}
+		listActions(addr, entries)
+	}
+	return nil
+}
+
+func listActions(addr common.Address, entries []journal.Entry) {
+	info := fmt.Sprintf("Action count: %v\n", len(entries))
+	for i, entry := range entries {
+		info = info + fmt.Sprintf("%v - Time: %v, Type: %v, Claim: %v, Attack: %v, Value: %v, Tx: %v, Status: %v",
+			i, entry.Time.UTC().Format("2006-01-02 15:04:05"), entry.Type, entry.ClaimIdx, entry.IsAttack,
+			optionalHash(entry.Value), optionalHash(entry.TxHash), entry.Status)
+		if entry.Error != "" {
+			info = info + fmt.Sprintf(", Error: %v", entry.Error)
+		}
+		info = info + "\n"
+	}
+	fmt.Printf("Game: %v\n%v\n", addr, info)
+}
+
+func optionalHash(hash *common.Hash) string {
+	if hash == nil {
+		return "-"
+	}
+	return hash.Hex()
+}
+
+var listActionsFlags = []cli.Flag{
+	flags.DatadirFlag,
+	ActionsGameAddressFlag,
+}
+
+var ListActionsCommand = &cli.Command{
+	Name:        "list-actions",
+	Usage:       "List the actions recorded by the challenger",
+	Description: "Lists the actions the challenger has taken in each game, as recorded in its data directory",
+	Action:      ListActions,
+	Flags:       listActionsFlags,
+	Hidden:      true,
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -160,6 +161,56 @@ func TestGameAllowlist(t *testing.T) {
 	})
 }
 
+func TestPolicyProposers(t *testing.T) {
+	t.Run("Optional", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.Empty(t, cfg.PolicyProposers)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		addr := common.Address{0xbb, 0xcc, 0xdd}
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--policy-proposers="+addr.Hex()))
+		require.Equal(t, []common.Address{addr}, cfg.PolicyProposers)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid address: foo", addRequiredArgs(config.TraceTypeAlphabet, "--policy-proposers=foo"))
+	})
+}
+
+func TestPolicyMinBond(t *testing.T) {
+	t.Run("Optional", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.Nil(t, cfg.PolicyMinBond)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--policy-min-bond=1000000000000000000000"))
+		expected, _ := new(big.Int).SetString("1000000000000000000000", 10)
+		require.Equal(t, expected, cfg.PolicyMinBond)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid policy-min-bond: abc", addRequiredArgs(config.TraceTypeAlphabet, "--policy-min-bond=abc"))
+	})
+}
+
+func TestPolicyDisagreeingRoots(t *testing.T) {
+	cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+	require.False(t, cfg.PolicyDisagreeingRoots)
+
+	cfg = configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--policy-disagreeing-roots"))
+	require.True(t, cfg.PolicyDisagreeingRoots)
+}
+
+func TestPolicyMaxGamesPerType(t *testing.T) {
+	cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+	require.Zero(t, cfg.PolicyMaxGamesPerType)
+
+	cfg = configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--policy-max-games-per-type=3"))
+	require.Equal(t, uint(3), cfg.PolicyMaxGamesPerType)
+}
+
 func TestTxManagerFlagsSupported(t *testing.T) {
 	// Not a comprehensive list of flags, just enough to sanity check the txmgr.CLIFlags were defined
 	cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--"+txmgr.NumConfirmationsFlagName, "7"))
@@ -188,6 +239,18 @@ func TestMaxConcurrency(t *testing.T) {
 	})
 }
 
+func TestDryRun(t *testing.T) {
+	t.Run("DefaultsToFalse", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.False(t, cfg.DryRun)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--dry-run"))
+		require.True(t, cfg.DryRun)
+	})
+}
+
 func TestMaxPendingTx(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		expected := uint64(345)
@@ -352,6 +415,31 @@ func TestCannonInfoFreq(t *testing.T) {
 	})
 }
 
+func TestCannonVMType(t *testing.T) {
+	t.Run("UsesDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon))
+		require.Equal(t, config.CannonVMTypeSingleThreaded, cfg.CannonVMType)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon, "--cannon-vm-type=cannon-mt", "--dry-run"))
+		require.Equal(t, config.CannonVMTypeMultiThreaded, cfg.CannonVMType)
+	})
+}
+
+func TestAltDA(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon))
+		require.False(t, cfg.AltDA.Enabled)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon, "--altda.enabled", "--altda.da-server=http://localhost:3100"))
+		require.True(t, cfg.AltDA.Enabled)
+		require.Equal(t, "http://localhost:3100", cfg.AltDA.DAServerURL)
+	})
+}
+
 func TestGameWindow(t *testing.T) {
 	t.Run("UsesDefault", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
@@ -430,6 +518,19 @@ func TestCannonL2Genesis(t *testing.T) {
 	})
 }
 
+func TestVisualizeGameFlags(t *testing.T) {
+	args := []string{"op-challenger", "visualize-game",
+		"--l1-eth-rpc", l1EthRpc,
+		"--game-address", "foo",
+		"--check-agreement",
+		"--trace-type", "cannon",
+		"--datadir", datadir,
+	}
+	// The flags are defined, so the command fails on the invalid game address rather than parsing the flags
+	err := run(context.Background(), args, nil)
+	require.ErrorContains(t, err, "invalid address: foo")
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := dryRunWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
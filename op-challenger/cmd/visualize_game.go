@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/visualize"
+	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
+	openum "github.com/ethereum-optimism/optimism/op-service/enum"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	VisualizeFormatFlag = &cli.GenericFlag{
+		Name:    "format",
+		Usage:   "Output format. Valid options: " + openum.EnumString(visualize.Formats),
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "VISUALIZE_FORMAT"),
+		Value: func() *visualize.Format {
+			out := visualize.FormatText
+			return &out
+		}(),
+	}
+	CheckAgreementFlag = &cli.BoolFlag{
+		Name: "check-agreement",
+		Usage: "Annotate each claim with whether the local trace agrees with it. " +
+			"Requires the same trace configuration as running the challenger.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "CHECK_AGREEMENT"),
+	}
+)
+
+func VisualizeGame(ctx *cli.Context) error {
+	logger, err := setupLogging(ctx)
+	if err != nil {
+		return err
+	}
+	rpcUrl := ctx.String(flags.L1EthRpcFlag.Name)
+	if rpcUrl == "" {
+		return fmt.Errorf("missing %v", flags.L1EthRpcFlag.Name)
+	}
+	gameAddr, err := opservice.ParseAddress(ctx.String(GameAddressFlag.Name))
+	if err != nil {
+		return err
+	}
+	format := *ctx.Generic(VisualizeFormatFlag.Name).(*visualize.Format)
+
+	l1Client, err := dial.DialEthClientWithTimeout(ctx.Context, dial.DefaultDialTimeout, logger, rpcUrl)
+	if err != nil {
+		return fmt.Errorf("failed to dial L1: %w", err)
+	}
+	defer l1Client.Close()
+
+	caller := batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize)
+	contract, err := contracts.NewFaultDisputeGameContract(gameAddr, caller)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute game bindings: %w", err)
+	}
+	maxDepth, err := contract.GetMaxGameDepth(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve max depth: %w", err)
+	}
+	claims, err := contract.GetAllClaims(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve claims: %w", err)
+	}
+
+	var agreement map[int]bool
+	if ctx.Bool(CheckAgreementFlag.Name) {
+		cfg, err := flags.NewConfigFromCLI(ctx)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Check(); err != nil {
+			return err
+		}
+		accessor, closeAccessor, err := newTraceAccessor(ctx.Context, logger, cfg, gameAddr, contract)
+		if err != nil {
+			return err
+		}
+		defer closeAccessor()
+		gameState := faultTypes.NewGameState(claims, maxDepth)
+		agreement = visualize.CheckAgreement(ctx.Context, accessor, gameState, func(claim faultTypes.Claim, err error) {
+			logger.Warn("Failed to check agreement with claim", "claimIdx", claim.ContractIndex, "err", err)
+		})
+	}
+
+	root, err := visualize.BuildTree(claims, maxDepth, agreement)
+	if err != nil {
+		return fmt.Errorf("failed to build claim tree: %w", err)
+	}
+	return visualize.Write(os.Stdout, format, root)
+}
+
+// newTraceAccessor creates the same TraceAccessor the challenger would use to play the game.
+func newTraceAccessor(ctx context.Context, logger log.Logger, cfg *config.Config, gameAddr common.Address, contract *contracts.FaultDisputeGameContract) (faultTypes.TraceAccessor, func(), error) {
+	gameType, err := contract.GetGameType(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	prestateBlock, poststateBlock, err := contract.GetBlockRange(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	splitDepth, err := contract.GetSplitDepth(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	rollupClient, err := dial.DialRollupClientWithTimeout(ctx, dial.DefaultDialTimeout, logger, cfg.RollupRpc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial rollup client: %w", err)
+	}
+	prestateProvider := outputs.NewPrestateProvider(ctx, logger, rollupClient, prestateBlock)
+	switch {
+	case gameType == faultTypes.AlphabetGameType && cfg.TraceTypeEnabled(config.TraceTypeAlphabet):
+		accessor, err := outputs.NewOutputAlphabetTraceAccessor(logger, metrics.NoopMetrics, prestateProvider, rollupClient, splitDepth, prestateBlock, poststateBlock)
+		if err != nil {
+			rollupClient.Close()
+			return nil, nil, err
+		}
+		return accessor, rollupClient.Close, nil
+	case gameType == faultTypes.CannonGameType && cfg.TraceTypeEnabled(config.TraceTypeCannon):
+		l2Client, err := ethclient.DialContext(ctx, cfg.CannonL2)
+		if err != nil {
+			rollupClient.Close()
+			return nil, nil, fmt.Errorf("dial l2 client %v: %w", cfg.CannonL2, err)
+		}
+		closeAll := func() {
+			l2Client.Close()
+			rollupClient.Close()
+		}
+		// Use the same directory as the challenger so any existing cannon snapshots and proofs are reused.
+		dir := game.GameDir(cfg.Datadir, gameAddr)
+		accessor, err := outputs.NewOutputCannonTraceAccessor(logger, metrics.NoopMetrics, cfg, l2Client, contract, prestateProvider, rollupClient, dir, splitDepth, prestateBlock, poststateBlock)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		return accessor, closeAll, nil
+	default:
+		rollupClient.Close()
+		return nil, nil, fmt.Errorf("no enabled trace type supports game type %v", gameType)
+	}
+}
+
+// visualizeGameFlags include the challenger flags, as checking agreement with the claims uses the challenger config.
+var visualizeGameFlags = append([]cli.Flag{
+	GameAddressFlag,
+	VisualizeFormatFlag,
+	CheckAgreementFlag,
+}, flags.Flags...)
+
+var VisualizeGameCommand = &cli.Command{
+	Name:        "visualize-game",
+	Usage:       "Render the claims in a dispute game as a tree",
+	Description: "Renders the claims in a dispute game as a tree in text, Graphviz DOT or JSON format",
+	Action:      VisualizeGame,
+	Flags:       visualizeGameFlags,
+	Hidden:      true,
+}
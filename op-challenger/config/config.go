@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"runtime"
 	"slices"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
@@ -32,7 +34,10 @@ var (
 	ErrCannonNetworkAndRollupConfig  = errors.New("only specify one of network or rollup config path")
 	ErrCannonNetworkAndL2Genesis     = errors.New("only specify one of network or l2 genesis path")
 	ErrCannonNetworkUnknown          = errors.New("unknown cannon network")
+	ErrCannonVMTypeUnknown           = errors.New("unknown cannon VM type")
+	ErrCannonVMTypeRequiresDryRun    = errors.New("multi-threaded cannon VM can only be used in dry run mode")
 	ErrMissingRollupRpc              = errors.New("missing rollup rpc url")
+	ErrNegativePolicyMinBond         = errors.New("policy min bond must not be negative")
 )
 
 type TraceType string
@@ -50,6 +55,16 @@ const (
 
 var TraceTypes = []TraceType{TraceTypeAlphabet, TraceTypeCannon}
 
+// Cannon VM types, matching the --type values of cannon.
+// The multi-threaded VM runs op-program with a normal multi-threaded Go runtime, but MIPS.sol cannot verify its
+// steps onchain yet.
+const (
+	CannonVMTypeSingleThreaded = "cannon"
+	CannonVMTypeMultiThreaded  = "cannon-mt"
+)
+
+var CannonVMTypes = []string{CannonVMTypeSingleThreaded, CannonVMTypeMultiThreaded}
+
 // GameIdToString maps game IDs to their string representation.
 var GameIdToString = map[uint8]string{
 	CannonFaultGameID:   "Cannon",
@@ -99,13 +114,17 @@ const (
 // This also contains config options for auxiliary services.
 // It is used to initialize the challenger.
 type Config struct {
-	L1EthRpc           string           // L1 RPC Url
-	GameFactoryAddress common.Address   // Address of the dispute game factory
-	GameAllowlist      []common.Address // Allowlist of fault game addresses
-	GameWindow         time.Duration    // Maximum time duration to look for games to progress
-	Datadir            string           // Data Directory
-	MaxConcurrency     uint             // Maximum number of threads to use when progressing games
-	PollInterval       time.Duration    // Polling interval for latest-block subscription when using an HTTP RPC provider
+	L1EthRpc               string           // L1 RPC Url
+	GameFactoryAddress     common.Address   // Address of the dispute game factory
+	GameAllowlist          []common.Address // Allowlist of fault game addresses
+	GameWindow             time.Duration    // Maximum time duration to look for games to progress
+	PolicyProposers        []common.Address // Only play games where the root claim was made by one of these proposers
+	PolicyMinBond          *big.Int         // Only play games where the total bonds posted is at least this value (in wei)
+	PolicyDisagreeingRoots bool             // Only play games where the root claim disagrees with the rollup node
+	PolicyMaxGamesPerType  uint             // Maximum number of in progress games to play for each game type (0 == no limit)
+	Datadir                string           // Data Directory
+	MaxConcurrency         uint             // Maximum number of threads to use when progressing games
+	PollInterval           time.Duration    // Polling interval for latest-block subscription when using an HTTP RPC provider
 
 	TraceTypes []TraceType // Type of traces supported
 
@@ -122,8 +141,12 @@ type Config struct {
 	CannonL2               string // L2 RPC Url
 	CannonSnapshotFreq     uint   // Frequency of snapshots to create when executing cannon (in VM instructions)
 	CannonInfoFreq         uint   // Frequency of cannon progress log messages (in VM instructions)
+	CannonVMType           string // Type of cannon VM to execute the absolute pre-state with
+	// DA server to fetch the inputs of alt-DA commitments from, required for chains that use alt-DA
+	AltDA altda.CLIConfig
 
 	MaxPendingTx uint64 // Maximum number of pending transactions (0 == no limit)
+	DryRun       bool   // Calculate and record actions without sending any transactions
 
 	TxMgrConfig   txmgr.CLIConfig
 	MetricsConfig opmetrics.CLIConfig
@@ -154,6 +177,7 @@ func NewConfig(
 
 		CannonSnapshotFreq: DefaultCannonSnapshotFreq,
 		CannonInfoFreq:     DefaultCannonInfoFreq,
+		CannonVMType:       CannonVMTypeSingleThreaded,
 		GameWindow:         DefaultGameWindow,
 	}
 }
@@ -181,6 +205,9 @@ func (c Config) Check() error {
 	if c.MaxConcurrency == 0 {
 		return ErrMaxConcurrencyZero
 	}
+	if c.PolicyMinBond != nil && c.PolicyMinBond.Sign() < 0 {
+		return ErrNegativePolicyMinBond
+	}
 	if c.TraceTypeEnabled(TraceTypeCannon) {
 		if c.CannonBin == "" {
 			return ErrMissingCannonBin
@@ -218,6 +245,16 @@ func (c Config) Check() error {
 		if c.CannonInfoFreq == 0 {
 			return ErrMissingCannonInfoFreq
 		}
+		if !slices.Contains(CannonVMTypes, c.CannonVMType) {
+			return fmt.Errorf("%w: %v", ErrCannonVMTypeUnknown, c.CannonVMType)
+		}
+		// MIPS.sol can't verify steps of the multi-threaded VM, so they would revert and the challenger would lose its bonds.
+		if c.CannonVMType == CannonVMTypeMultiThreaded && !c.DryRun {
+			return ErrCannonVMTypeRequiresDryRun
+		}
+		if err := c.AltDA.Check(); err != nil {
+			return err
+		}
 	}
 	if err := c.TxMgrConfig.Check(); err != nil {
 		return err
@@ -1,12 +1,14 @@
 package config
 
 import (
+	"math/big"
 	"runtime"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
@@ -96,6 +98,15 @@ func TestDatadirRequired(t *testing.T) {
 	require.ErrorIs(t, config.Check(), ErrMissingDatadir)
 }
 
+func TestPolicyMinBondNotNegative(t *testing.T) {
+	config := validConfig(TraceTypeAlphabet)
+	config.PolicyMinBond = big.NewInt(-1)
+	require.ErrorIs(t, config.Check(), ErrNegativePolicyMinBond)
+
+	config.PolicyMinBond = big.NewInt(0)
+	require.NoError(t, config.Check())
+}
+
 func TestMaxConcurrency(t *testing.T) {
 	t.Run("Required", func(t *testing.T) {
 		config := validConfig(TraceTypeAlphabet)
@@ -150,6 +161,41 @@ func TestCannonInfoFreq(t *testing.T) {
 	})
 }
 
+func TestCannonVMType(t *testing.T) {
+	t.Run("MultiThreaded", func(t *testing.T) {
+		cfg := validConfig(TraceTypeCannon)
+		cfg.CannonVMType = CannonVMTypeMultiThreaded
+		cfg.DryRun = true
+		require.NoError(t, cfg.Check())
+	})
+
+	t.Run("MultiThreadedRequiresDryRun", func(t *testing.T) {
+		cfg := validConfig(TraceTypeCannon)
+		cfg.CannonVMType = CannonVMTypeMultiThreaded
+		require.ErrorIs(t, cfg.Check(), ErrCannonVMTypeRequiresDryRun)
+	})
+
+	t.Run("MustBeKnown", func(t *testing.T) {
+		cfg := validConfig(TraceTypeCannon)
+		cfg.CannonVMType = "cannon64"
+		require.ErrorIs(t, cfg.Check(), ErrCannonVMTypeUnknown)
+	})
+}
+
+func TestAltDA(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		cfg := validConfig(TraceTypeCannon)
+		cfg.AltDA = altda.CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}
+		require.NoError(t, cfg.Check())
+	})
+
+	t.Run("DAServerRequired", func(t *testing.T) {
+		cfg := validConfig(TraceTypeCannon)
+		cfg.AltDA = altda.CLIConfig{Enabled: true}
+		require.ErrorIs(t, cfg.Check(), altda.ErrNoDAServer)
+	})
+}
+
 func TestCannonNetworkOrRollupConfigRequired(t *testing.T) {
 	cfg := validConfig(TraceTypeCannon)
 	cfg.CannonNetwork = ""
@@ -2,6 +2,7 @@ package flags
 
 import (
 	"fmt"
+	"math/big"
 	"runtime"
 	"slices"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -128,6 +130,14 @@ var (
 		EnvVars: prefixEnvVars("CANNON_INFO_FREQ"),
 		Value:   config.DefaultCannonInfoFreq,
 	}
+	CannonVMTypeFlag = &cli.StringFlag{
+		Name: "cannon-vm-type",
+		Usage: "Type of cannon VM to execute the absolute prestate with, which must have been loaded for the same VM type. " +
+			"Valid options: " + openum.EnumString(config.CannonVMTypes) + " (cannon trace type only). " +
+			config.CannonVMTypeMultiThreaded + " can only be used with --dry-run as its steps cannot be verified onchain.",
+		EnvVars: prefixEnvVars("CANNON_VM_TYPE"),
+		Value:   config.CannonVMTypeSingleThreaded,
+	}
 	GameWindowFlag = &cli.DurationFlag{
 		Name: "game-window",
 		Usage: "The time window which the challenger will look for games to progress and claim bonds. " +
@@ -135,6 +145,34 @@ var (
 		EnvVars: prefixEnvVars("GAME_WINDOW"),
 		Value:   config.DefaultGameWindow,
 	}
+	DryRunFlag = &cli.BoolFlag{
+		Name: "dry-run",
+		Usage: "Calculate the actions to take in each game and record them without sending any transactions. " +
+			"Intended for running a shadow challenger alongside a production instance.",
+		EnvVars: prefixEnvVars("DRY_RUN"),
+	}
+	PolicyProposersFlag = &cli.StringSliceFlag{
+		Name: "policy-proposers",
+		Usage: "List of proposer addresses. If set, the challenger only plays games where the root claim " +
+			"was made by one of these proposers.",
+		EnvVars: prefixEnvVars("POLICY_PROPOSERS"),
+	}
+	PolicyMinBondFlag = &cli.StringFlag{
+		Name:    "policy-min-bond",
+		Usage:   "If set, the challenger only plays games where the total bonds posted is at least this amount (in wei).",
+		EnvVars: prefixEnvVars("POLICY_MIN_BOND"),
+	}
+	PolicyDisagreeingRootsFlag = &cli.BoolFlag{
+		Name:    "policy-disagreeing-roots",
+		Usage:   "If set, the challenger only plays games where the root claim disagrees with the rollup node.",
+		EnvVars: prefixEnvVars("POLICY_DISAGREEING_ROOTS"),
+	}
+	PolicyMaxGamesPerTypeFlag = &cli.UintFlag{
+		Name: "policy-max-games-per-type",
+		Usage: "Maximum number of in progress games to play for each game type. " +
+			"Older games are preferred. 0 for no limit.",
+		EnvVars: prefixEnvVars("POLICY_MAX_GAMES_PER_TYPE"),
+	}
 )
 
 // requiredFlags are checked by [CheckRequired]
@@ -161,7 +199,13 @@ var optionalFlags = []cli.Flag{
 	CannonL2Flag,
 	CannonSnapshotFreqFlag,
 	CannonInfoFreqFlag,
+	CannonVMTypeFlag,
 	GameWindowFlag,
+	DryRunFlag,
+	PolicyProposersFlag,
+	PolicyMinBondFlag,
+	PolicyDisagreeingRootsFlag,
+	PolicyMaxGamesPerTypeFlag,
 }
 
 func init() {
@@ -169,6 +213,7 @@ func init() {
 	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, altda.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -265,6 +310,22 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 			allowedGames = append(allowedGames, gameAddress)
 		}
 	}
+	var proposers []common.Address
+	for _, addr := range ctx.StringSlice(PolicyProposersFlag.Name) {
+		proposer, err := opservice.ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		proposers = append(proposers, proposer)
+	}
+	var minBond *big.Int
+	if ctx.IsSet(PolicyMinBondFlag.Name) {
+		var ok bool
+		minBond, ok = new(big.Int).SetString(ctx.String(PolicyMinBondFlag.Name), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %v: %v", PolicyMinBondFlag.Name, ctx.String(PolicyMinBondFlag.Name))
+		}
+	}
 
 	txMgrConfig := txmgr.ReadCLIConfig(ctx)
 	metricsConfig := opmetrics.ReadCLIConfig(ctx)
@@ -281,8 +342,13 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		GameFactoryAddress:     gameFactoryAddress,
 		GameAllowlist:          allowedGames,
 		GameWindow:             ctx.Duration(GameWindowFlag.Name),
+		PolicyProposers:        proposers,
+		PolicyMinBond:          minBond,
+		PolicyDisagreeingRoots: ctx.Bool(PolicyDisagreeingRootsFlag.Name),
+		PolicyMaxGamesPerType:  ctx.Uint(PolicyMaxGamesPerTypeFlag.Name),
 		MaxConcurrency:         maxConcurrency,
 		MaxPendingTx:           ctx.Uint64(MaxPendingTransactionsFlag.Name),
+		DryRun:                 ctx.Bool(DryRunFlag.Name),
 		PollInterval:           ctx.Duration(HTTPPollInterval.Name),
 		RollupRpc:              ctx.String(RollupRpcFlag.Name),
 		CannonNetwork:          ctx.String(CannonNetworkFlag.Name),
@@ -295,6 +361,8 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		CannonL2:               ctx.String(CannonL2Flag.Name),
 		CannonSnapshotFreq:     ctx.Uint(CannonSnapshotFreqFlag.Name),
 		CannonInfoFreq:         ctx.Uint(CannonInfoFreqFlag.Name),
+		CannonVMType:           ctx.String(CannonVMTypeFlag.Name),
+		AltDA:                  altda.ReadCLIConfig(ctx),
 		TxMgrConfig:            txMgrConfig,
 		MetricsConfig:          metricsConfig,
 		PprofConfig:            pprofConfig,
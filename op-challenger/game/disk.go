@@ -23,20 +23,42 @@ func newDiskManager(dir string) *diskManager {
 }
 
 func (d *diskManager) DirForGame(addr common.Address) string {
-	return filepath.Join(d.datadir, gameDirPrefix+addr.Hex())
+	return GameDir(d.datadir, addr)
 }
 
 func (d *diskManager) RemoveAllExcept(keep []common.Address) error {
-	entries, err := os.ReadDir(d.datadir)
+	games, err := ListGameDirs(d.datadir)
 	if err != nil {
-		return fmt.Errorf("failed to list directory: %w", err)
+		return err
 	}
 	var errs []error
+	for addr, dir := range games {
+		if slices.Contains(keep, addr) {
+			// Preserve data for games we should keep.
+			continue
+		}
+		errs = append(errs, os.RemoveAll(dir))
+	}
+	return errors.Join(errs...)
+}
+
+// GameDir returns the directory used to store data for the game at addr within datadir.
+func GameDir(datadir string, addr common.Address) string {
+	return filepath.Join(datadir, gameDirPrefix+addr.Hex())
+}
+
+// ListGameDirs returns the data directory of each game that has data stored in datadir.
+func ListGameDirs(datadir string) (map[common.Address]string, error) {
+	entries, err := os.ReadDir(datadir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+	games := make(map[common.Address]string)
 	for _, entry := range entries {
 		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), gameDirPrefix) {
 			// Skip files and directories that don't have the game directory prefix.
 			// While random content shouldn't be in our datadir, we want to avoid
-			// deleting things like OS generated files.
+			// treating things like OS generated files as game data.
 			continue
 		}
 		name := entry.Name()[len(gameDirPrefix):]
@@ -45,11 +67,7 @@ func (d *diskManager) RemoveAllExcept(keep []common.Address) error {
 			// Ignore directories with non-address names.
 			continue
 		}
-		if slices.Contains(keep, addr) {
-			// Preserve data for games we should keep.
-			continue
-		}
-		errs = append(errs, os.RemoveAll(filepath.Join(d.datadir, entry.Name())))
+		games[addr] = filepath.Join(datadir, entry.Name())
 	}
-	return errors.Join(errs...)
+	return games, nil
 }
@@ -55,3 +55,20 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	require.DirExists(t, unexpectedDir, "should not delete unexpected dir")
 	require.DirExists(t, invalidHexDir, "should not delete dir with invalid address")
 }
+
+func TestListGameDirs(t *testing.T) {
+	baseDir := t.TempDir()
+	game1 := common.Address{0x53}
+	game2 := common.Address{0xaa}
+	require.NoError(t, os.MkdirAll(GameDir(baseDir, game1), 0777))
+	require.NoError(t, os.MkdirAll(GameDir(baseDir, game2), 0777))
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "notagame"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, gameDirPrefix+common.Address{0xbb}.Hex()), []byte("test"), 0644))
+
+	games, err := ListGameDirs(baseDir)
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{
+		game1: GameDir(baseDir, game1),
+		game2: GameDir(baseDir, game2),
+	}, games)
+}
@@ -9,11 +9,15 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+var ErrClaimReverted = errors.New("credit claim tx reverted")
+
 type BondClaimMetrics interface {
 	RecordBondClaimed(amount uint64)
+	RecordCreditUnclaimed(amount *big.Int)
 }
 
 type BondContract interface {
@@ -28,52 +32,87 @@ type Claimer struct {
 	metrics         BondClaimMetrics
 	contractCreator BondContractCreator
 	txSender        types.TxSender
+	dryRun          bool
+
+	// credits holds the last credit read from each game so credit that can't be read is still reported.
+	credits map[common.Address]*big.Int
 }
 
 var _ BondClaimer = (*Claimer)(nil)
 
-func NewBondClaimer(l log.Logger, m BondClaimMetrics, contractCreator BondContractCreator, txSender types.TxSender) *Claimer {
+// NewBondClaimer creates a Claimer. In dry run mode, the available credit is reported but not claimed.
+func NewBondClaimer(l log.Logger, m BondClaimMetrics, contractCreator BondContractCreator, txSender types.TxSender, dryRun bool) *Claimer {
 	return &Claimer{
 		logger:          l,
 		metrics:         m,
 		contractCreator: contractCreator,
 		txSender:        txSender,
+		dryRun:          dryRun,
+		credits:         make(map[common.Address]*big.Int),
 	}
 }
 
+// ClaimBonds claims any credit available to the tx sender from each of the games.
+// The total credit that remains unclaimed after all claims have been attempted is recorded as pending.
+// If the credit of a game can't be read, the last credit read from it is counted instead.
 func (c *Claimer) ClaimBonds(ctx context.Context, games []types.GameMetadata) (err error) {
+	unclaimed := big.NewInt(0)
+	credits := make(map[common.Address]*big.Int, len(games))
 	for _, game := range games {
-		err = errors.Join(err, c.claimBond(ctx, game))
+		remaining, claimErr := c.claimBond(ctx, game)
+		err = errors.Join(err, claimErr)
+		if remaining == nil {
+			remaining = c.credits[game.Proxy]
+		}
+		if remaining != nil {
+			credits[game.Proxy] = remaining
+			unclaimed.Add(unclaimed, remaining)
+		}
 	}
+	c.credits = credits
+	c.metrics.RecordCreditUnclaimed(unclaimed)
 	return err
 }
 
-func (c *Claimer) claimBond(ctx context.Context, game types.GameMetadata) error {
+// claimBond claims the credit available to the tx sender from the game.
+// Returns the amount of credit that remains unclaimed, or nil if the credit couldn't be read.
+func (c *Claimer) claimBond(ctx context.Context, game types.GameMetadata) (*big.Int, error) {
 	c.logger.Debug("Attempting to claim bonds for", "game", game.Proxy)
 
 	contract, err := c.contractCreator(game)
 	if err != nil {
-		return fmt.Errorf("failed to create bond contract bindings: %w", err)
+		return nil, fmt.Errorf("failed to create bond contract bindings: %w", err)
 	}
 	credit, err := contract.GetCredit(ctx, c.txSender.From())
 	if err != nil {
-		return fmt.Errorf("failed to get credit: %w", err)
+		return nil, fmt.Errorf("failed to get credit: %w", err)
 	}
 
 	if credit.Cmp(big.NewInt(0)) == 0 {
 		c.logger.Debug("No credit to claim", "game", game.Proxy)
-		return nil
+		return credit, nil
+	}
+	if c.dryRun {
+		c.logger.Debug("Dry run: not claiming credit", "game", game.Proxy, "amount", credit)
+		return credit, nil
 	}
 
 	candidate, err := contract.ClaimCredit(c.txSender.From())
 	if err != nil {
-		return fmt.Errorf("failed to create credit claim tx: %w", err)
+		return credit, fmt.Errorf("failed to create credit claim tx: %w", err)
 	}
 
-	if _, err = c.txSender.SendAndWait("claim credit", candidate); err != nil {
-		return fmt.Errorf("failed to claim credit: %w", err)
+	receipts, err := c.txSender.SendAndWait("claim credit", candidate)
+	if err != nil {
+		return credit, fmt.Errorf("failed to claim credit: %w", err)
+	}
+	for _, receipt := range receipts {
+		if receipt != nil && receipt.Status != ethtypes.ReceiptStatusSuccessful {
+			return credit, fmt.Errorf("%w: game %v", ErrClaimReverted, game.Proxy)
+		}
 	}
 
+	c.logger.Info("Claimed credit", "game", game.Proxy, "amount", credit)
 	c.metrics.RecordBondClaimed(credit.Uint64())
-	return nil
+	return big.NewInt(0), nil
 }
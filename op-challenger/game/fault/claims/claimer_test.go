@@ -17,6 +17,7 @@ import (
 
 var (
 	mockTxMgrSendError = errors.New("mock tx mgr send error")
+	mockGetCreditError = errors.New("mock get credit error")
 )
 
 func TestClaimer_ClaimBonds(t *testing.T) {
@@ -28,6 +29,7 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 3, txSender.sends)
 		require.Equal(t, 3, m.RecordBondClaimedCalls)
+		require.Equal(t, big.NewInt(0), m.unclaimed)
 	})
 
 	t.Run("BondClaimSucceeds", func(t *testing.T) {
@@ -49,6 +51,19 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		require.ErrorIs(t, err, mockTxMgrSendError)
 		require.Equal(t, 1, txSender.sends)
 		require.Equal(t, 0, m.RecordBondClaimedCalls)
+		require.Equal(t, big.NewInt(1), m.unclaimed)
+	})
+
+	t.Run("BondClaimReverts", func(t *testing.T) {
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, txSender := newTestClaimer(t, gameAddr)
+		txSender.statusFail = true
+		contract.credit = 5
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.ErrorIs(t, err, ErrClaimReverted)
+		require.Equal(t, 1, txSender.sends)
+		require.Equal(t, 0, m.RecordBondClaimedCalls)
+		require.Equal(t, big.NewInt(5), m.unclaimed)
 	})
 
 	t.Run("ZeroCreditReturnsNil", func(t *testing.T) {
@@ -61,6 +76,42 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		require.Equal(t, 0, m.RecordBondClaimedCalls)
 	})
 
+	t.Run("DryRunDoesNotClaim", func(t *testing.T) {
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, txSender := newTestClaimer(t, gameAddr)
+		c.dryRun = true
+		contract.credit = 5
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.NoError(t, err)
+		require.Equal(t, 0, txSender.sends)
+		require.Equal(t, 0, m.RecordBondClaimedCalls)
+		require.Equal(t, big.NewInt(5), m.unclaimed)
+	})
+
+	t.Run("GetCreditFailsCountsLastKnownCredit", func(t *testing.T) {
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, txSender := newTestClaimer(t, gameAddr)
+		c.dryRun = true
+		contract.credit = 5
+		require.NoError(t, c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}}))
+		require.Equal(t, big.NewInt(5), m.unclaimed)
+
+		contract.getCreditFails = true
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.ErrorIs(t, err, mockGetCreditError)
+		require.Equal(t, 0, txSender.sends)
+		require.Equal(t, big.NewInt(5), m.unclaimed)
+	})
+
+	t.Run("GetCreditFailsWithoutKnownCredit", func(t *testing.T) {
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, _ := newTestClaimer(t, gameAddr)
+		contract.getCreditFails = true
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.ErrorIs(t, err, mockGetCreditError)
+		require.Equal(t, big.NewInt(0), m.unclaimed)
+	})
+
 	t.Run("MultipleBondClaimFails", func(t *testing.T) {
 		gameAddr := common.HexToAddress("0x1234")
 		c, m, contract, txSender := newTestClaimer(t, gameAddr)
@@ -70,6 +121,7 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		require.ErrorIs(t, err, mockTxMgrSendError)
 		require.Equal(t, 3, txSender.sends)
 		require.Equal(t, 0, m.RecordBondClaimedCalls)
+		require.Equal(t, big.NewInt(3), m.unclaimed)
 	})
 }
 
@@ -81,18 +133,23 @@ func newTestClaimer(t *testing.T, gameAddr common.Address) (*Claimer, *mockClaim
 	contractCreator := func(game types.GameMetadata) (BondContract, error) {
 		return bondContract, nil
 	}
-	c := NewBondClaimer(logger, m, contractCreator, txSender)
+	c := NewBondClaimer(logger, m, contractCreator, txSender, false)
 	return c, m, bondContract, txSender
 }
 
 type mockClaimMetrics struct {
 	RecordBondClaimedCalls int
+	unclaimed              *big.Int
 }
 
 func (m *mockClaimMetrics) RecordBondClaimed(amount uint64) {
 	m.RecordBondClaimedCalls++
 }
 
+func (m *mockClaimMetrics) RecordCreditUnclaimed(amount *big.Int) {
+	m.unclaimed = amount
+}
+
 type mockTxSender struct {
 	sends      int
 	sendFails  bool
@@ -115,10 +172,14 @@ func (s *mockTxSender) SendAndWait(_ string, _ ...txmgr.TxCandidate) ([]*ethtype
 }
 
 type stubBondContract struct {
-	credit int64
+	credit         int64
+	getCreditFails bool
 }
 
 func (s *stubBondContract) GetCredit(_ context.Context, _ common.Address) (*big.Int, error) {
+	if s.getCreditFails {
+		return nil, mockGetCreditError
+	}
 	return big.NewInt(s.credit), nil
 }
 
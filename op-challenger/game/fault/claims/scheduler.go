@@ -47,6 +47,9 @@ func (s *BondClaimScheduler) Start(ctx context.Context) {
 }
 
 func (s *BondClaimScheduler) Close() error {
+	if s.cancel == nil {
+		return nil
+	}
 	s.cancel()
 	s.wg.Wait()
 	return nil
@@ -15,6 +15,7 @@ import (
 
 var (
 	methodGameDuration       = "gameDuration"
+	methodGameType           = "gameType"
 	methodMaxGameDepth       = "maxGameDepth"
 	methodAbsolutePrestate   = "absolutePrestate"
 	methodStatus             = "status"
@@ -32,6 +33,7 @@ var (
 	methodGenesisOutputRoot  = "genesisOutputRoot"
 	methodSplitDepth         = "splitDepth"
 	methodL2BlockNumber      = "l2BlockNumber"
+	methodRootClaim          = "rootClaim"
 	methodRequiredBond       = "getRequiredBond"
 	methodClaimCredit        = "claimCredit"
 	methodCredit             = "credit"
@@ -78,6 +80,29 @@ func (c *FaultDisputeGameContract) GetBlockRange(ctx context.Context) (prestateB
 	return
 }
 
+// GetGameMetadata returns the game's L2 block number, root claim, status and game duration.
+func (c *FaultDisputeGameContract) GetGameMetadata(ctx context.Context) (uint64, common.Hash, gameTypes.GameStatus, uint64, error) {
+	results, err := c.multiCaller.Call(ctx, batching.BlockLatest,
+		c.contract.Call(methodL2BlockNumber),
+		c.contract.Call(methodRootClaim),
+		c.contract.Call(methodStatus),
+		c.contract.Call(methodGameDuration))
+	if err != nil {
+		return 0, common.Hash{}, 0, 0, fmt.Errorf("failed to retrieve game metadata: %w", err)
+	}
+	if len(results) != 4 {
+		return 0, common.Hash{}, 0, 0, fmt.Errorf("expected 4 results but got %v", len(results))
+	}
+	l2BlockNumber := results[0].GetBigInt(0).Uint64()
+	rootClaim := results[1].GetHash(0)
+	status, err := gameTypes.GameStatusFromUint8(results[2].GetUint8(0))
+	if err != nil {
+		return 0, common.Hash{}, 0, 0, fmt.Errorf("failed to convert game status: %w", err)
+	}
+	duration := results[3].GetUint64(0)
+	return l2BlockNumber, rootClaim, status, duration, nil
+}
+
 func (c *FaultDisputeGameContract) GetGenesisOutputRoot(ctx context.Context) (common.Hash, error) {
 	genesisOutputRoot, err := c.multiCaller.SingleCall(ctx, batching.BlockLatest, c.contract.Call(methodGenesisOutputRoot))
 	if err != nil {
@@ -102,6 +127,23 @@ func (c *FaultDisputeGameContract) GetCredit(ctx context.Context, receipient com
 	return credit.GetBigInt(0), nil
 }
 
+// GetCredits returns the credit currently available to be claimed by each of the specified recipients.
+func (c *FaultDisputeGameContract) GetCredits(ctx context.Context, recipients ...common.Address) ([]*big.Int, error) {
+	calls := make([]*batching.ContractCall, 0, len(recipients))
+	for _, recipient := range recipients {
+		calls = append(calls, c.contract.Call(methodCredit, recipient))
+	}
+	results, err := c.multiCaller.Call(ctx, batching.BlockLatest, calls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credit: %w", err)
+	}
+	credits := make([]*big.Int, 0, len(recipients))
+	for _, result := range results {
+		credits = append(credits, result.GetBigInt(0))
+	}
+	return credits, nil
+}
+
 func (f *FaultDisputeGameContract) ClaimCredit(recipient common.Address) (txmgr.TxCandidate, error) {
 	call := f.contract.Call(methodClaimCredit, recipient)
 	return call.ToTxCandidate()
@@ -156,6 +198,14 @@ func (f *FaultDisputeGameContract) GetGameDuration(ctx context.Context) (uint64,
 	return result.GetUint64(0), nil
 }
 
+func (f *FaultDisputeGameContract) GetGameType(ctx context.Context) (uint32, error) {
+	result, err := f.multiCaller.SingleCall(ctx, batching.BlockLatest, f.contract.Call(methodGameType))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch game type: %w", err)
+	}
+	return result.GetUint32(0), nil
+}
+
 func (f *FaultDisputeGameContract) GetMaxGameDepth(ctx context.Context) (types.Depth, error) {
 	result, err := f.multiCaller.SingleCall(ctx, batching.BlockLatest, f.contract.Call(methodMaxGameDepth))
 	if err != nil {
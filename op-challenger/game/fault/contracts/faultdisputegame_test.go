@@ -46,6 +46,14 @@ func TestSimpleGetters(t *testing.T) {
 				return game.GetGameDuration(context.Background())
 			},
 		},
+		{
+			methodAlias: "gameType",
+			method:      methodGameType,
+			result:      uint32(255),
+			call: func(game *FaultDisputeGameContract) (any, error) {
+				return game.GetGameType(context.Background())
+			},
+		},
 		{
 			methodAlias: "maxGameDepth",
 			method:      methodMaxGameDepth,
@@ -269,6 +277,39 @@ func TestGetBlockRange(t *testing.T) {
 	require.Equal(t, expectedEnd, end)
 }
 
+func TestGetCredits(t *testing.T) {
+	stubRpc, game := setupFaultDisputeGameTest(t)
+	addrs := []common.Address{{0x01}, {0x02}, {0x03}}
+	expected := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(0)}
+	for i, addr := range addrs {
+		stubRpc.SetResponse(fdgAddr, methodCredit, batching.BlockLatest, []interface{}{addr}, []interface{}{expected[i]})
+	}
+	actual, err := game.GetCredits(context.Background(), addrs...)
+	require.NoError(t, err)
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		require.Zerof(t, expected[i].Cmp(actual[i]), "expectedCredit: %v, actual: %v", expected[i], actual[i])
+	}
+}
+
+func TestGetGameMetadata(t *testing.T) {
+	stubRpc, contract := setupFaultDisputeGameTest(t)
+	expectedL2BlockNumber := uint64(123)
+	expectedRootClaim := common.Hash{0x01, 0x02}
+	expectedStatus := types.GameStatusChallengerWon
+	expectedDuration := uint64(3600)
+	stubRpc.SetResponse(fdgAddr, methodL2BlockNumber, batching.BlockLatest, nil, []interface{}{new(big.Int).SetUint64(expectedL2BlockNumber)})
+	stubRpc.SetResponse(fdgAddr, methodRootClaim, batching.BlockLatest, nil, []interface{}{expectedRootClaim})
+	stubRpc.SetResponse(fdgAddr, methodStatus, batching.BlockLatest, nil, []interface{}{expectedStatus})
+	stubRpc.SetResponse(fdgAddr, methodGameDuration, batching.BlockLatest, nil, []interface{}{expectedDuration})
+	l2BlockNumber, rootClaim, status, duration, err := contract.GetGameMetadata(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, expectedL2BlockNumber, l2BlockNumber)
+	require.Equal(t, expectedRootClaim, rootClaim)
+	require.Equal(t, expectedStatus, status)
+	require.Equal(t, expectedDuration, duration)
+}
+
 func TestGetSplitDepth(t *testing.T) {
 	stubRpc, contract := setupFaultDisputeGameTest(t)
 	expectedSplitDepth := faultTypes.Depth(15)
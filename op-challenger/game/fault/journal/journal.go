@@ -0,0 +1,208 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const journalFile = "actions.jsonl"
+
+type EntryType string
+
+const (
+	EntryTypeMove         EntryType = "move"
+	EntryTypeStep         EntryType = "step"
+	EntryTypeResolve      EntryType = "resolve"
+	EntryTypeResolveClaim EntryType = "resolve-claim"
+)
+
+type Status string
+
+const (
+	// StatusSuccess indicates the transaction was included and succeeded.
+	StatusSuccess Status = "success"
+	// StatusReverted indicates the transaction was included but reverted.
+	StatusReverted Status = "reverted"
+	// StatusFailed indicates the action could not be submitted or no receipt was received.
+	StatusFailed Status = "failed"
+	// StatusDryRun indicates the action would have been submitted but the challenger is running in dry run mode.
+	StatusDryRun Status = "dry-run"
+)
+
+// Entry records a single action taken by the challenger and its outcome.
+type Entry struct {
+	Time           time.Time     `json:"time"`
+	Type           EntryType     `json:"type"`
+	ClaimIdx       uint64        `json:"claimIdx"`
+	ParentPosition *hexutil.Big  `json:"parentPosition,omitempty"`
+	IsAttack       bool          `json:"isAttack"`
+	Value          *common.Hash  `json:"value,omitempty"`
+	PreState       hexutil.Bytes `json:"preState,omitempty"`
+	ProofData      hexutil.Bytes `json:"proofData,omitempty"`
+	OracleKey      hexutil.Bytes `json:"oracleKey,omitempty"`
+	TxHash         *common.Hash  `json:"txHash,omitempty"`
+	Status         Status        `json:"status"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// NewActionEntry creates an Entry describing action.
+// The outcome of the action must be set by the caller.
+func NewActionEntry(action types.Action) Entry {
+	entry := Entry{
+		ClaimIdx:       uint64(action.ParentIdx),
+		ParentPosition: (*hexutil.Big)(action.ParentPosition.ToGIndex()),
+		IsAttack:       action.IsAttack,
+	}
+	switch action.Type {
+	case types.ActionTypeMove:
+		entry.Type = EntryTypeMove
+		value := action.Value
+		entry.Value = &value
+	case types.ActionTypeStep:
+		entry.Type = EntryTypeStep
+		entry.PreState = action.PreState
+		entry.ProofData = action.ProofData
+		if action.OracleData != nil {
+			entry.OracleKey = action.OracleData.OracleKey
+		}
+	}
+	return entry
+}
+
+// SameAction returns true if e and other describe the same action, regardless of outcome.
+func (e Entry) SameAction(other Entry) bool {
+	if e.Type != other.Type || e.ClaimIdx != other.ClaimIdx || e.IsAttack != other.IsAttack {
+		return false
+	}
+	if (e.Value == nil) != (other.Value == nil) {
+		return false
+	}
+	return e.Value == nil || *e.Value == *other.Value
+}
+
+// Journal is a durable, append-only record of the actions taken in a single game.
+// Entries are stored as JSON lines in the game's data directory.
+type Journal struct {
+	mu      sync.Mutex
+	clock   types.ClockReader
+	path    string
+	entries []Entry
+}
+
+// Open loads the journal from dir, creating it if it does not exist.
+// A partially written final entry is removed so that new entries are not appended to it.
+func Open(cl types.ClockReader, dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir %v: %w", dir, err)
+	}
+	path := filepath.Join(dir, journalFile)
+	entries, size, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > size {
+		if err := os.Truncate(path, size); err != nil {
+			return nil, fmt.Errorf("failed to remove partial journal entry: %w", err)
+		}
+	}
+	return &Journal{
+		clock:   cl,
+		path:    path,
+		entries: entries,
+	}, nil
+}
+
+// Load reads the entries recorded in dir without opening the journal for writing.
+// Returns no entries if the journal does not exist.
+func Load(dir string) ([]Entry, error) {
+	entries, _, err := load(filepath.Join(dir, journalFile))
+	return entries, err
+}
+
+// load reads the entries of the journal at path, and returns the size of the complete entries.
+// A partially written final line may be left behind if the process is killed mid-write. It is ignored rather than
+// refusing to load the rest of the journal. Entries are only written with a trailing newline, so any other line
+// that cannot be decoded means the journal is corrupt.
+func load(path string) ([]Entry, int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+	var entries []Entry
+	var size int64
+	// Step proofs can be large so read lines of any length
+	reader := bufio.NewReader(f)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Either the end of the journal or a partially written final line
+			return entries, size, nil
+		} else if err != nil {
+			return nil, 0, fmt.Errorf("failed to read journal: %w", err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, 0, fmt.Errorf("corrupt journal entry on line %d: %w", lineNum, err)
+		}
+		entries = append(entries, entry)
+		size += int64(len(line))
+	}
+}
+
+// Record appends entry to the journal, setting its time to the current time.
+func (j *Journal) Record(entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entry.Time = j.clock.Now()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+// LastOutcome returns the status of the most recently recorded entry for the same action as entry.
+// Returns false if the action has not been recorded.
+func (j *Journal) LastOutcome(entry Entry) (Status, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		if j.entries[i].SameAction(entry) {
+			return j.entries[i].Status, true
+		}
+	}
+	return "", false
+}
+
+// Entries returns a copy of the entries recorded in the journal.
+func (j *Journal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Entry(nil), j.entries...)
+}
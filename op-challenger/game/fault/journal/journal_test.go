@@ -0,0 +1,151 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "game")
+	cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+	journal, err := Open(cl, dir)
+	require.NoError(t, err)
+	require.Empty(t, journal.Entries())
+
+	txHash := common.Hash{0xbb}
+	move := NewActionEntry(types.Action{
+		Type:           types.ActionTypeMove,
+		ParentIdx:      3,
+		ParentPosition: types.NewPositionFromGIndex(common.Big2),
+		IsAttack:       true,
+		Value:          common.Hash{0xaa},
+	})
+	move.Status = StatusSuccess
+	move.TxHash = &txHash
+	require.NoError(t, journal.Record(move))
+
+	cl.AdvanceTime(time.Minute)
+	step := NewActionEntry(types.Action{
+		Type:           types.ActionTypeStep,
+		ParentIdx:      5,
+		ParentPosition: types.NewPositionFromGIndex(common.Big3),
+		PreState:       []byte{1, 2},
+		ProofData:      []byte{3, 4},
+		OracleData:     &types.PreimageOracleData{OracleKey: []byte{5, 6}},
+	})
+	step.Status = StatusFailed
+	step.Error = "boom"
+	require.NoError(t, journal.Record(step))
+
+	move.Time = time.Unix(1000, 0)
+	step.Time = time.Unix(1060, 0)
+	expected := []Entry{move, step}
+	require.Equal(t, expected, journal.Entries())
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	for i, entry := range loaded {
+		require.True(t, entry.Time.Equal(expected[i].Time))
+		entry.Time = expected[i].Time
+		require.Equal(t, expected[i], entry)
+	}
+
+	// Reopening the journal includes the existing entries
+	reopened, err := Open(cl, dir)
+	require.NoError(t, err)
+	require.Len(t, reopened.Entries(), 2)
+	status, ok := reopened.LastOutcome(move)
+	require.True(t, ok)
+	require.Equal(t, StatusSuccess, status)
+}
+
+func TestLoadMissingJournal(t *testing.T) {
+	entries, err := Load(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestLoadIgnoresPartialEntry(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := Open(clock.NewDeterministicClock(time.Unix(1000, 0)), dir)
+	require.NoError(t, err)
+	require.NoError(t, journal.Record(Entry{Type: EntryTypeResolve, Status: StatusSuccess}))
+
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"mo`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, EntryTypeResolve, entries[0].Type)
+
+	// Reopening the journal removes the partial entry so new entries can be appended
+	journal, err = Open(clock.NewDeterministicClock(time.Unix(1000, 0)), dir)
+	require.NoError(t, err)
+	require.NoError(t, journal.Record(Entry{Type: EntryTypeResolveClaim, Status: StatusSuccess}))
+	entries, err = Load(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, EntryTypeResolveClaim, entries[1].Type)
+}
+
+func TestLoadRejectsCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := Open(clock.NewDeterministicClock(time.Unix(1000, 0)), dir)
+	require.NoError(t, err)
+	require.NoError(t, journal.Record(Entry{Type: EntryTypeResolve, Status: StatusSuccess}))
+
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("{\"type\":\"mo\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, journal.Record(Entry{Type: EntryTypeResolveClaim, Status: StatusSuccess}))
+
+	_, err = Load(dir)
+	require.ErrorContains(t, err, "corrupt journal entry on line 2")
+	_, err = Open(clock.NewDeterministicClock(time.Unix(1000, 0)), dir)
+	require.ErrorContains(t, err, "corrupt journal entry on line 2")
+}
+
+func TestLastOutcome(t *testing.T) {
+	journal, err := Open(clock.NewDeterministicClock(time.Unix(1000, 0)), t.TempDir())
+	require.NoError(t, err)
+	action := types.Action{Type: types.ActionTypeMove, ParentIdx: 1, IsAttack: true, Value: common.Hash{0xaa}}
+	entry := NewActionEntry(action)
+	_, ok := journal.LastOutcome(entry)
+	require.False(t, ok)
+
+	entry.Status = StatusSuccess
+	require.NoError(t, journal.Record(entry))
+	status, ok := journal.LastOutcome(entry)
+	require.True(t, ok)
+	require.Equal(t, StatusSuccess, status)
+
+	entry.Status = StatusReverted
+	require.NoError(t, journal.Record(entry))
+	status, ok = journal.LastOutcome(entry)
+	require.True(t, ok)
+	require.Equal(t, StatusReverted, status, "most recent outcome is returned")
+
+	defend := action
+	defend.IsAttack = false
+	_, ok = journal.LastOutcome(NewActionEntry(defend))
+	require.False(t, ok)
+
+	differentValue := action
+	differentValue.Value = common.Hash{0xbb}
+	_, ok = journal.LastOutcome(NewActionEntry(differentValue))
+	require.False(t, ok)
+}
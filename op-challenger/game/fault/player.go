@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/journal"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/preimages"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/responder"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
@@ -52,6 +53,7 @@ func NewGamePlayer(
 	loader GameContract,
 	validators []Validator,
 	creator resourceCreator,
+	dryRun bool,
 ) (*GamePlayer, error) {
 	logger = logger.New("game", addr)
 
@@ -96,7 +98,11 @@ func NewGamePlayer(
 	direct := preimages.NewDirectPreimageUploader(logger, txSender, loader)
 	large := preimages.NewLargePreimageUploader(logger, cl, txSender, oracle)
 	uploader := preimages.NewSplitPreimageUploader(direct, large, minLargePreimageSize)
-	responder, err := responder.NewFaultResponder(logger, txSender, loader, uploader, oracle)
+	actionJournal, err := journal.Open(cl, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open action journal: %w", err)
+	}
+	responder, err := responder.NewFaultResponder(logger, txSender, loader, uploader, oracle, actionJournal, dryRun)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the responder: %w", err)
 	}
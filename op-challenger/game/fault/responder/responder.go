@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/journal"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/preimages"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -26,6 +29,12 @@ type GameContract interface {
 	GetRequiredBond(ctx context.Context, position types.Position) (*big.Int, error)
 	GetCredit(ctx context.Context, receipient common.Address) (*big.Int, error)
 	ClaimCredit(receipient common.Address) (txmgr.TxCandidate, error)
+	GetAllClaims(ctx context.Context) ([]types.Claim, error)
+}
+
+type Journal interface {
+	Record(entry journal.Entry) error
+	LastOutcome(entry journal.Entry) (journal.Status, bool)
 }
 
 type Oracle interface {
@@ -39,16 +48,21 @@ type FaultResponder struct {
 	contract GameContract
 	uploader preimages.PreimageUploader
 	oracle   Oracle
+	journal  Journal
+	dryRun   bool
 }
 
 // NewFaultResponder returns a new [FaultResponder].
-func NewFaultResponder(logger log.Logger, sender gameTypes.TxSender, contract GameContract, uploader preimages.PreimageUploader, oracle Oracle) (*FaultResponder, error) {
+// In dry run mode, preimages are not uploaded and each intended transaction is only recorded in the journal once.
+func NewFaultResponder(logger log.Logger, sender gameTypes.TxSender, contract GameContract, uploader preimages.PreimageUploader, oracle Oracle, journal Journal, dryRun bool) (*FaultResponder, error) {
 	return &FaultResponder{
 		log:      logger,
 		sender:   sender,
 		contract: contract,
 		uploader: uploader,
 		oracle:   oracle,
+		journal:  journal,
+		dryRun:   dryRun,
 	}, nil
 }
 
@@ -60,12 +74,17 @@ func (r *FaultResponder) CallResolve(ctx context.Context) (gameTypes.GameStatus,
 
 // Resolve executes a resolve transaction to resolve a fault dispute game.
 func (r *FaultResponder) Resolve() error {
+	entry := journal.Entry{Type: journal.EntryTypeResolve}
+	if r.recordedDryRun(entry) {
+		return nil
+	}
 	candidate, err := r.contract.ResolveTx()
 	if err != nil {
+		r.recordOutcome(entry, nil, err)
 		return err
 	}
 
-	return r.sendTxAndWait("resolve game", candidate)
+	return r.sendTxAndWait("resolve game", entry, candidate)
 }
 
 // CallResolveClaim determines if the resolveClaim function on the fault dispute game contract
@@ -76,21 +95,49 @@ func (r *FaultResponder) CallResolveClaim(ctx context.Context, claimIdx uint64)
 
 // ResolveClaim executes a resolveClaim transaction to resolve a fault dispute game.
 func (r *FaultResponder) ResolveClaim(claimIdx uint64) error {
+	entry := journal.Entry{Type: journal.EntryTypeResolveClaim, ClaimIdx: claimIdx}
+	if r.recordedDryRun(entry) {
+		return nil
+	}
 	candidate, err := r.contract.ResolveClaimTx(claimIdx)
 	if err != nil {
+		r.recordOutcome(entry, nil, err)
 		return err
 	}
-	return r.sendTxAndWait("resolve claim", candidate)
+	return r.sendTxAndWait("resolve claim", entry, candidate)
 }
 
 func (r *FaultResponder) PerformAction(ctx context.Context, action types.Action) error {
-	if action.OracleData != nil {
+	entry := journal.NewActionEntry(action)
+	if r.recordedDryRun(entry) {
+		return nil
+	}
+	if status, ok := r.journal.LastOutcome(entry); ok && status == journal.StatusSuccess {
+		// The action was performed before, possibly before a restart. Only skip it if it is still on chain, in
+		// which case the chain state used to calculate actions must be lagging and submitting it again would just
+		// revert and waste gas. The transaction may have been dropped by a reorg, so it is resent otherwise.
+		performed, err := r.performedOnChain(ctx, action)
+		if err != nil {
+			return fmt.Errorf("failed to check if action was performed: %w", err)
+		}
+		if performed {
+			r.log.Warn("Skipping action that was already performed", "type", action.Type, "parent", action.ParentIdx)
+			return nil
+		}
+		r.log.Warn("Resending action that is no longer on chain", "type", action.Type, "parent", action.ParentIdx)
+	}
+	if action.OracleData != nil && r.dryRun {
+		// Continue so the step that would follow the upload is recorded as well.
+		r.log.Debug("Dry run: preimage not uploaded")
+	} else if action.OracleData != nil {
 		var preimageExists bool
 		var err error
 		if !action.OracleData.IsLocal {
 			preimageExists, err = r.oracle.GlobalDataExists(ctx, action.OracleData)
 			if err != nil {
-				return fmt.Errorf("failed to check if preimage exists: %w", err)
+				err = fmt.Errorf("failed to check if preimage exists: %w", err)
+				r.recordOutcome(entry, nil, err)
+				return err
 			}
 		}
 		// Always upload local preimages
@@ -100,7 +147,9 @@ func (r *FaultResponder) PerformAction(ctx context.Context, action types.Action)
 				r.log.Debug("Large Preimage Squeeze failed, challenge period not over")
 				return nil
 			} else if err != nil {
-				return fmt.Errorf("failed to upload preimage: %w", err)
+				err = fmt.Errorf("failed to upload preimage: %w", err)
+				r.recordOutcome(entry, nil, err)
+				return err
 			}
 		}
 	}
@@ -119,6 +168,7 @@ func (r *FaultResponder) PerformAction(ctx context.Context, action types.Action)
 
 		bondValue, err := r.contract.GetRequiredBond(ctx, movePos)
 		if err != nil {
+			r.recordOutcome(entry, nil, err)
 			return err
 		}
 		candidate.Value = bondValue
@@ -126,14 +176,87 @@ func (r *FaultResponder) PerformAction(ctx context.Context, action types.Action)
 		candidate, err = r.contract.StepTx(uint64(action.ParentIdx), action.IsAttack, action.PreState, action.ProofData)
 	}
 	if err != nil {
+		r.recordOutcome(entry, nil, err)
 		return err
 	}
-	return r.sendTxAndWait("perform action", candidate)
+	return r.sendTxAndWait("perform action", entry, candidate)
+}
+
+// recordedDryRun returns true if the same action as entry was already recorded in dry run mode.
+// Each intended action is only recorded once, rather than on every poll.
+func (r *FaultResponder) recordedDryRun(entry journal.Entry) bool {
+	if !r.dryRun {
+		return false
+	}
+	status, ok := r.journal.LastOutcome(entry)
+	return ok && status == journal.StatusDryRun
+}
+
+// performedOnChain returns true if the game contract already includes the effect of action.
+// Moves are performed if a claim with the same value exists at the move position, and steps are performed if the
+// parent claim is countered.
+func (r *FaultResponder) performedOnChain(ctx context.Context, action types.Action) (bool, error) {
+	claims, err := r.contract.GetAllClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch action.Type {
+	case types.ActionTypeMove:
+		movePos := action.ParentPosition.Defend()
+		if action.IsAttack {
+			movePos = action.ParentPosition.Attack()
+		}
+		for _, claim := range claims {
+			if !claim.IsRoot() && claim.ParentContractIndex == action.ParentIdx && claim.Position.ToGIndex().Cmp(movePos.ToGIndex()) == 0 && claim.Value == action.Value {
+				return true, nil
+			}
+		}
+	case types.ActionTypeStep:
+		if action.ParentIdx < len(claims) {
+			return claims[action.ParentIdx].CounteredBy != (common.Address{}), nil
+		}
+	}
+	return false, nil
 }
 
 // sendTxAndWait sends a transaction through the [txmgr] and waits for a receipt.
 // This sets the tx GasLimit to 0, performing gas estimation online through the [txmgr].
-func (r *FaultResponder) sendTxAndWait(purpose string, candidate txmgr.TxCandidate) error {
-	_, err := r.sender.SendAndWait(purpose, candidate)
+// The outcome is recorded in the journal.
+func (r *FaultResponder) sendTxAndWait(purpose string, entry journal.Entry, candidate txmgr.TxCandidate) error {
+	receipts, err := r.sender.SendAndWait(purpose, candidate)
+	var receipt *ethTypes.Receipt
+	if len(receipts) > 0 {
+		receipt = receipts[0]
+	}
+	r.recordOutcome(entry, receipt, err)
+	if errors.Is(err, sender.ErrDryRun) {
+		// The action has been recorded, which is all that's expected in dry run mode.
+		return nil
+	}
 	return err
 }
+
+// recordOutcome records the result of attempting entry's action in the journal.
+// Failing to write to the journal is logged but does not prevent the challenger from acting.
+func (r *FaultResponder) recordOutcome(entry journal.Entry, receipt *ethTypes.Receipt, err error) {
+	switch {
+	case errors.Is(err, sender.ErrDryRun):
+		entry.Status = journal.StatusDryRun
+	case receipt == nil:
+		entry.Status = journal.StatusFailed
+	case receipt.Status == ethTypes.ReceiptStatusSuccessful:
+		entry.Status = journal.StatusSuccess
+	default:
+		entry.Status = journal.StatusReverted
+	}
+	if receipt != nil {
+		txHash := receipt.TxHash
+		entry.TxHash = &txHash
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := r.journal.Record(entry); err != nil {
+		r.log.Error("Failed to record action in journal", "type", entry.Type, "claimIdx", entry.ClaimIdx, "err", err)
+	}
+}
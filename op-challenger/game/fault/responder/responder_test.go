@@ -6,8 +6,10 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/journal"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 
@@ -280,17 +282,200 @@ func TestPerformAction(t *testing.T) {
 	})
 }
 
+func TestJournal(t *testing.T) {
+	action := types.Action{
+		Type:           types.ActionTypeMove,
+		ParentIdx:      123,
+		ParentPosition: types.NewPositionFromGIndex(big.NewInt(2)),
+		IsAttack:       true,
+		Value:          common.Hash{0xaa},
+	}
+
+	t.Run("RecordSuccess", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		actions := responder.journal.(*stubJournal)
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Len(t, actions.entries, 1)
+		entry := actions.entries[0]
+		require.Equal(t, journal.EntryTypeMove, entry.Type)
+		require.Equal(t, uint64(123), entry.ClaimIdx)
+		require.Equal(t, action.Value, *entry.Value)
+		require.Equal(t, journal.StatusSuccess, entry.Status)
+		require.Equal(t, mockTxMgr.txHash, *entry.TxHash)
+		require.Empty(t, entry.Error)
+	})
+
+	t.Run("RecordReverted", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		mockTxMgr.reverts = true
+		actions := responder.journal.(*stubJournal)
+		require.NoError(t, responder.ResolveClaim(4))
+		require.Len(t, actions.entries, 1)
+		require.Equal(t, journal.EntryTypeResolveClaim, actions.entries[0].Type)
+		require.Equal(t, uint64(4), actions.entries[0].ClaimIdx)
+		require.Equal(t, journal.StatusReverted, actions.entries[0].Status)
+	})
+
+	t.Run("RecordSendFailure", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		mockTxMgr.sendFails = true
+		actions := responder.journal.(*stubJournal)
+		require.ErrorIs(t, responder.Resolve(), mockSendError)
+		require.Len(t, actions.entries, 1)
+		require.Equal(t, journal.EntryTypeResolve, actions.entries[0].Type)
+		require.Equal(t, journal.StatusFailed, actions.entries[0].Status)
+		require.Nil(t, actions.entries[0].TxHash)
+		require.Equal(t, mockSendError.Error(), actions.entries[0].Error)
+	})
+
+	t.Run("RecordUploadFailure", func(t *testing.T) {
+		responder, _, _, uploader, _ := newTestFaultResponder(t)
+		uploader.uploadFails = true
+		actions := responder.journal.(*stubJournal)
+		err := responder.PerformAction(context.Background(), types.Action{
+			Type:       types.ActionTypeStep,
+			ParentIdx:  123,
+			PreState:   []byte{1, 2, 3},
+			ProofData:  []byte{4, 5, 6},
+			OracleData: &types.PreimageOracleData{IsLocal: true, OracleKey: []byte{7, 8}},
+		})
+		require.ErrorIs(t, err, mockPreimageUploadErr)
+		require.Len(t, actions.entries, 1)
+		require.Equal(t, journal.EntryTypeStep, actions.entries[0].Type)
+		require.EqualValues(t, []byte{7, 8}, actions.entries[0].OracleKey)
+		require.Equal(t, journal.StatusFailed, actions.entries[0].Status)
+	})
+
+	t.Run("SkipAlreadySucceeded", func(t *testing.T) {
+		responder, mockTxMgr, contract, _, _ := newTestFaultResponder(t)
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Equal(t, 1, mockTxMgr.sends)
+		contract.claims = []types.Claim{{
+			ClaimData:           types.ClaimData{Value: action.Value, Position: action.ParentPosition.Attack()},
+			ParentContractIndex: action.ParentIdx,
+		}}
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Equal(t, 1, mockTxMgr.sends)
+	})
+
+	t.Run("ResendSucceededMoveNotOnChain", func(t *testing.T) {
+		responder, mockTxMgr, contract, _, _ := newTestFaultResponder(t)
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Equal(t, 1, mockTxMgr.sends)
+		// The transaction was dropped by a reorg
+		contract.claims = []types.Claim{{
+			ClaimData:           types.ClaimData{Value: common.Hash{0xbb}, Position: action.ParentPosition.Attack()},
+			ParentContractIndex: action.ParentIdx,
+		}}
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Equal(t, 2, mockTxMgr.sends)
+	})
+
+	t.Run("SkipSucceededStepOnlyIfCountered", func(t *testing.T) {
+		responder, mockTxMgr, contract, _, _ := newTestFaultResponder(t)
+		step := types.Action{Type: types.ActionTypeStep, ParentIdx: 1, IsAttack: true}
+		contract.claims = []types.Claim{{}, {}}
+		require.NoError(t, responder.PerformAction(context.Background(), step))
+		require.NoError(t, responder.PerformAction(context.Background(), step))
+		require.Equal(t, 2, mockTxMgr.sends)
+		contract.claims[1].CounteredBy = common.Address{0xcc}
+		require.NoError(t, responder.PerformAction(context.Background(), step))
+		require.Equal(t, 2, mockTxMgr.sends)
+	})
+
+	t.Run("RecordDryRun", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		responder.dryRun = true
+		mockTxMgr.dryRun = true
+		actions := responder.journal.(*stubJournal)
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Len(t, actions.entries, 1)
+		require.Equal(t, journal.StatusDryRun, actions.entries[0].Status)
+		require.Nil(t, actions.entries[0].TxHash)
+
+		// Each intended action is only recorded once
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Len(t, actions.entries, 1)
+	})
+
+	t.Run("RecordDryRunResolveOnce", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		responder.dryRun = true
+		mockTxMgr.dryRun = true
+		actions := responder.journal.(*stubJournal)
+		require.NoError(t, responder.Resolve())
+		require.NoError(t, responder.Resolve())
+		require.NoError(t, responder.ResolveClaim(4))
+		require.NoError(t, responder.ResolveClaim(4))
+		require.NoError(t, responder.ResolveClaim(5))
+		require.Len(t, actions.entries, 3)
+		require.Equal(t, journal.EntryTypeResolve, actions.entries[0].Type)
+		require.Equal(t, journal.EntryTypeResolveClaim, actions.entries[1].Type)
+		require.Equal(t, uint64(4), actions.entries[1].ClaimIdx)
+		require.Equal(t, uint64(5), actions.entries[2].ClaimIdx)
+		for _, entry := range actions.entries {
+			require.Equal(t, journal.StatusDryRun, entry.Status)
+		}
+	})
+
+	t.Run("RecordDryRunStepWithoutPreimageUpload", func(t *testing.T) {
+		responder, mockTxMgr, _, uploader, oracle := newTestFaultResponder(t)
+		responder.dryRun = true
+		mockTxMgr.dryRun = true
+		actions := responder.journal.(*stubJournal)
+		err := responder.PerformAction(context.Background(), types.Action{
+			Type:       types.ActionTypeStep,
+			ParentIdx:  123,
+			PreState:   []byte{1, 2, 3},
+			ProofData:  []byte{4, 5, 6},
+			OracleData: &types.PreimageOracleData{IsLocal: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, actions.entries, 1)
+		require.Equal(t, journal.EntryTypeStep, actions.entries[0].Type)
+		require.Equal(t, journal.StatusDryRun, actions.entries[0].Status)
+		require.Zero(t, uploader.updates)
+		require.Zero(t, oracle.existCalls)
+	})
+
+	t.Run("RetryAfterFailure", func(t *testing.T) {
+		responder, mockTxMgr, _, _, _ := newTestFaultResponder(t)
+		mockTxMgr.reverts = true
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.NoError(t, responder.PerformAction(context.Background(), action))
+		require.Equal(t, 2, mockTxMgr.sends)
+	})
+}
+
 func newTestFaultResponder(t *testing.T) (*FaultResponder, *mockTxManager, *mockContract, *mockPreimageUploader, *mockOracle) {
 	log := testlog.Logger(t, log.LevelError)
-	mockTxMgr := &mockTxManager{}
+	mockTxMgr := &mockTxManager{txHash: common.Hash{0xab}}
 	contract := &mockContract{}
 	uploader := &mockPreimageUploader{}
 	oracle := &mockOracle{}
-	responder, err := NewFaultResponder(log, mockTxMgr, contract, uploader, oracle)
+	responder, err := NewFaultResponder(log, mockTxMgr, contract, uploader, oracle, &stubJournal{}, false)
 	require.NoError(t, err)
 	return responder, mockTxMgr, contract, uploader, oracle
 }
 
+type stubJournal struct {
+	entries []journal.Entry
+}
+
+func (s *stubJournal) Record(entry journal.Entry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *stubJournal) LastOutcome(entry journal.Entry) (journal.Status, bool) {
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if s.entries[i].SameAction(entry) {
+			return s.entries[i].Status, true
+		}
+	}
+	return "", false
+}
+
 type mockPreimageUploader struct {
 	updates     int
 	uploadFails bool
@@ -320,9 +505,12 @@ func (m *mockOracle) GlobalDataExists(ctx context.Context, data *types.PreimageO
 
 type mockTxManager struct {
 	from      common.Address
+	txHash    common.Hash
 	sends     int
 	sent      []txmgr.TxCandidate
 	sendFails bool
+	reverts   bool
+	dryRun    bool
 }
 
 func (m *mockTxManager) SendAndWait(_ string, txs ...txmgr.TxCandidate) ([]*ethtypes.Receipt, error) {
@@ -331,13 +519,18 @@ func (m *mockTxManager) SendAndWait(_ string, txs ...txmgr.TxCandidate) ([]*etht
 		if m.sendFails {
 			return nil, mockSendError
 		}
+		if m.dryRun {
+			return make([]*ethtypes.Receipt, len(txs)), sender.ErrDryRun
+		}
 		m.sends++
 		m.sent = append(m.sent, tx)
-		rcpts = append(rcpts, ethtypes.NewReceipt(
+		rcpt := ethtypes.NewReceipt(
 			[]byte{},
-			false,
+			m.reverts,
 			0,
-		))
+		)
+		rcpt.TxHash = m.txHash
+		rcpts = append(rcpts, rcpt)
 	}
 	return rcpts, nil
 }
@@ -361,6 +554,7 @@ type mockContract struct {
 	stepArgs             []interface{}
 	updateOracleClaimIdx uint64
 	updateOracleArgs     *types.PreimageOracleData
+	claims               []types.Claim
 }
 
 func (m *mockContract) CallResolve(_ context.Context) (gameTypes.GameStatus, error) {
@@ -416,6 +610,10 @@ func (m *mockContract) GetCredit(_ context.Context, _ common.Address) (*big.Int,
 	return big.NewInt(5), nil
 }
 
+func (m *mockContract) GetAllClaims(_ context.Context) ([]types.Claim, error) {
+	return m.claims, nil
+}
+
 func (m *mockContract) ClaimCredit(_ common.Address) (txmgr.TxCandidate, error) {
 	return txmgr.TxCandidate{TxData: ([]byte)("claimCredit")}, nil
 }
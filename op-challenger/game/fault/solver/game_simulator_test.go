@@ -0,0 +1,188 @@
+package solver
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	faulttest "github.com/ethereum-optimism/optimism/op-challenger/game/fault/test"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	honestName    = "honest"
+	adversaryName = "adversary"
+	roundDuration = time.Minute
+	maxRounds     = 100
+)
+
+var (
+	honestAddr    = common.Address{0xaa}
+	adversaryAddr = common.Address{0xbb}
+)
+
+func TestSimulateGames(t *testing.T) {
+	maxDepth := types.Depth(4)
+	claimBuilder := faulttest.NewAlphabetClaimBuilder(t, big.NewInt(0), maxDepth)
+	honest := faulttest.Player{
+		Name:  honestName,
+		Addr:  honestAddr,
+		Actor: honestActor(maxDepth, claimBuilder),
+	}
+
+	t.Run("NoChallengeToCorrectRoot", func(t *testing.T) {
+		sim := claimBuilder.Simulator(true, adversaryAddr)
+		status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, honest)
+		require.NoError(t, err)
+		require.Equal(t, gameTypes.GameStatusDefenderWon, status)
+		require.Len(t, sim.History(), 0)
+	})
+
+	t.Run("UnchallengedIncorrectRoot", func(t *testing.T) {
+		sim := claimBuilder.Simulator(false, adversaryAddr)
+		status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, honest)
+		require.NoError(t, err)
+		require.Equal(t, gameTypes.GameStatusChallengerWon, status)
+		require.Empty(t, sim.Errors(honestName))
+		require.Equal(t, sim.Bonded(honestAddr).Add(sim.Bonded(honestAddr), faulttest.RequiredBond(types.NewPositionFromGIndex(big.NewInt(1)))), sim.Credit(honestAddr))
+	})
+
+	t.Run("AlwaysAttack", func(t *testing.T) {
+		for _, rootCorrect := range []bool{true, false} {
+			rootCorrect := rootCorrect
+			t.Run(rootName(rootCorrect), func(t *testing.T) {
+				sim := claimBuilder.Simulator(rootCorrect, proposer(rootCorrect))
+				adversary := faulttest.Player{
+					Name:  adversaryName,
+					Addr:  adversaryAddr,
+					Actor: claimBuilder.AlwaysAttackActor(adversaryAddr),
+				}
+				status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, honest, adversary)
+				require.NoError(t, err)
+				requireHonestWin(t, sim, rootCorrect, status)
+				require.Empty(t, sim.Errors(adversaryName))
+			})
+		}
+	})
+
+	t.Run("WrongStep", func(t *testing.T) {
+		// With an even max depth, the honest actor only makes claims at max depth when defending the root claim
+		sim := claimBuilder.Simulator(true, honestAddr)
+		adversary := faulttest.Player{
+			Name:  adversaryName,
+			Addr:  adversaryAddr,
+			Actor: claimBuilder.WrongStepActor(adversaryAddr),
+		}
+		status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, honest, adversary)
+		require.NoError(t, err)
+		requireHonestWin(t, sim, true, status)
+		errs := sim.Errors(adversaryName)
+		require.NotEmpty(t, errs, "adversary should have attempted invalid steps")
+		for _, err := range errs {
+			require.ErrorIs(t, err, faulttest.ErrValidStep)
+		}
+	})
+
+	t.Run("LateMove", func(t *testing.T) {
+		sim := claimBuilder.Simulator(false, adversaryAddr)
+		adversary := faulttest.Player{
+			Name:  adversaryName,
+			Addr:  adversaryAddr,
+			Actor: claimBuilder.AlwaysAttackActor(adversaryAddr),
+			Delay: faulttest.DefaultSimulatorGameDuration/2 + time.Second,
+		}
+		status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, honest, adversary)
+		require.NoError(t, err)
+		requireHonestWin(t, sim, false, status)
+		errs := sim.Errors(adversaryName)
+		require.NotEmpty(t, errs)
+		for _, err := range errs {
+			require.ErrorIs(t, err, faulttest.ErrClockTimeExceeded)
+		}
+		require.Len(t, sim.Game().Claims(), 2, "late moves should not be added to the game")
+	})
+
+	t.Run("Freeloader", func(t *testing.T) {
+		freeloaderAddr := common.Address{0xcc}
+		sim := claimBuilder.Simulator(false, adversaryAddr)
+		freeloader := faulttest.Player{
+			Name:  "freeloader",
+			Addr:  freeloaderAddr,
+			Actor: faulttest.FreeloaderActor(honestActor(maxDepth, claimBuilder)),
+		}
+		adversary := faulttest.Player{
+			Name:  adversaryName,
+			Addr:  adversaryAddr,
+			Actor: claimBuilder.AlwaysAttackActor(adversaryAddr),
+		}
+		status, err := sim.RunAndResolve(context.Background(), roundDuration, maxRounds, freeloader, honest, adversary)
+		require.NoError(t, err)
+		require.Equal(t, gameTypes.GameStatusChallengerWon, status)
+		require.Empty(t, sim.Errors(honestName))
+		require.Empty(t, sim.Errors("freeloader"))
+		// The honest actor does not duplicate moves made by the freeloader but still performs the steps
+		require.Positive(t, sim.Credit(freeloaderAddr).Sign())
+		require.GreaterOrEqual(t, sim.Credit(honestAddr).Cmp(sim.Bonded(honestAddr)), 0, "honest actor should not lose bonds")
+		require.Zero(t, sim.Credit(adversaryAddr).Sign(), "adversary should lose all bonds")
+	})
+}
+
+// TestSimulatorResolveClaimClock checks that a subgame can only be resolved once the chess clock of the claim at its
+// root has expired, even when the clock of its parent claim expires sooner.
+func TestSimulatorResolveClaimClock(t *testing.T) {
+	claimBuilder := faulttest.NewAlphabetClaimBuilder(t, big.NewInt(0), types.Depth(4))
+	sim := claimBuilder.Simulator(false, adversaryAddr)
+	halfDuration := faulttest.DefaultSimulatorGameDuration / 2
+
+	// The first claim uses most of the challenger's clock, the second very little of the defender's.
+	sim.AdvanceTime(halfDuration - 10*time.Minute)
+	require.NoError(t, sim.Move(honestAddr, 0, true, common.Hash{0x01}))
+	sim.AdvanceTime(time.Minute)
+	require.NoError(t, sim.Move(adversaryAddr, 1, true, common.Hash{0x02}))
+
+	// The parent's clock would have expired, but the claim's own clock has not.
+	sim.AdvanceTime(11 * time.Minute)
+	require.ErrorIs(t, sim.ResolveClaim(2), faulttest.ErrClockNotExpired)
+
+	sim.AdvanceTime(halfDuration - 12*time.Minute)
+	require.ErrorIs(t, sim.ResolveClaim(2), faulttest.ErrClockNotExpired)
+	sim.AdvanceTime(time.Second)
+	require.NoError(t, sim.ResolveClaim(2))
+}
+
+// requireHonestWin asserts that the game resolved in favour of the honest actor and that it recovered all its bonds.
+func requireHonestWin(t *testing.T, sim *faulttest.Simulator, rootCorrect bool, status gameTypes.GameStatus) {
+	if rootCorrect {
+		require.Equal(t, gameTypes.GameStatusDefenderWon, status)
+	} else {
+		require.Equal(t, gameTypes.GameStatusChallengerWon, status)
+	}
+	require.Empty(t, sim.Errors(honestName), "honest actor should only perform valid actions")
+	require.Positive(t, sim.Bonded(honestAddr).Sign(), "honest actor should have participated")
+	require.GreaterOrEqual(t, sim.Credit(honestAddr).Cmp(sim.Bonded(honestAddr)), 0, "honest actor should not lose bonds")
+}
+
+func honestActor(maxDepth types.Depth, claimBuilder *faulttest.ClaimBuilder) faulttest.Actor {
+	solver := NewGameSolver(maxDepth, trace.NewSimpleTraceAccessor(claimBuilder.CorrectTraceProvider()))
+	return faulttest.ActorFunc(solver.CalculateNextActions)
+}
+
+// proposer returns the address that posts the root claim, which is the honest actor only if the root is correct.
+func proposer(rootCorrect bool) common.Address {
+	if rootCorrect {
+		return honestAddr
+	}
+	return adversaryAddr
+}
+
+func rootName(rootCorrect bool) string {
+	if rootCorrect {
+		return "CorrectRoot"
+	}
+	return "IncorrectRoot"
+}
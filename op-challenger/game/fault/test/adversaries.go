@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AlwaysAttackActor attacks every claim made by other players with an incorrect claim until max depth is reached.
+func (c *ClaimBuilder) AlwaysAttackActor(self common.Address) Actor {
+	return ActorFunc(func(ctx context.Context, game types.Game) ([]types.Action, error) {
+		var actions []types.Action
+		for _, claim := range game.Claims() {
+			if claim.Claimant == self || claim.Depth() >= game.MaxDepth() {
+				continue
+			}
+			counter := c.AttackClaim(claim, false)
+			if game.IsDuplicate(counter) {
+				continue
+			}
+			actions = append(actions, types.Action{
+				Type:           types.ActionTypeMove,
+				ParentIdx:      claim.ContractIndex,
+				ParentPosition: claim.Position,
+				IsAttack:       true,
+				Value:          counter.Value,
+			})
+		}
+		return actions, nil
+	})
+}
+
+// WrongStepActor behaves like [AlwaysAttackActor] but also attempts to step against every uncountered max depth claim
+// made by other players, even when the claim is correct and the step must fail.
+func (c *ClaimBuilder) WrongStepActor(self common.Address) Actor {
+	attacker := c.AlwaysAttackActor(self)
+	return ActorFunc(func(ctx context.Context, game types.Game) ([]types.Action, error) {
+		actions, err := attacker.Act(ctx, game)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range game.Claims() {
+			if claim.Claimant == self || claim.Depth() != game.MaxDepth() || claim.CounteredBy != (common.Address{}) {
+				continue
+			}
+			traceIdx := claim.TraceIndex(game.MaxDepth())
+			actions = append(actions, types.Action{
+				Type:           types.ActionTypeStep,
+				ParentIdx:      claim.ContractIndex,
+				ParentPosition: claim.Position,
+				IsAttack:       true,
+				PreState:       c.CorrectPreState(traceIdx),
+				ProofData:      c.CorrectProofData(traceIdx),
+				OracleData:     c.CorrectOracleData(traceIdx),
+			})
+		}
+		return actions, nil
+	})
+}
+
+// FreeloaderActor copies the moves actor would make so that, when played before actor, it claims the bonds of the
+// claims it counters without calculating any trace itself. Steps are left for actor to perform.
+func FreeloaderActor(actor Actor) Actor {
+	return ActorFunc(func(ctx context.Context, game types.Game) ([]types.Action, error) {
+		actions, err := actor.Act(ctx, game)
+		if err != nil {
+			return nil, err
+		}
+		var moves []types.Action
+		for _, action := range actions {
+			if action.Type == types.ActionTypeMove {
+				moves = append(moves, action)
+			}
+		}
+		return moves, nil
+	})
+}
@@ -0,0 +1,407 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrGameNotInProgress    = errors.New("game not in progress")
+	ErrParentDoesNotExist   = errors.New("parent claim does not exist")
+	ErrGameDepthExceeded    = errors.New("game depth exceeded")
+	ErrCannotDefendRoot     = errors.New("cannot defend root claim")
+	ErrClaimAlreadyExists   = errors.New("claim already exists")
+	ErrClockTimeExceeded    = errors.New("clock time exceeded")
+	ErrNotAtMaxDepth        = errors.New("step parent not at max depth")
+	ErrDuplicateStep        = errors.New("claim already countered by step")
+	ErrInvalidPrestate      = errors.New("invalid prestate")
+	ErrValidStep            = errors.New("step is valid for the claimed post state")
+	ErrClockNotExpired      = errors.New("clock not expired")
+	ErrOutOfOrderResolution = errors.New("out of order resolution")
+	ErrClaimAlreadyResolved = errors.New("claim already resolved")
+)
+
+// DefaultSimulatorGameDuration is the game duration used by a [Simulator] unless otherwise specified.
+// Each team has half of the duration on their chess clock.
+const DefaultSimulatorGameDuration = 7 * 24 * time.Hour
+
+// Actor decides which actions to take in a game.
+// The returned actions are submitted to the [Simulator] in order.
+type Actor interface {
+	Act(ctx context.Context, game types.Game) ([]types.Action, error)
+}
+
+// ActorFunc adapts a function into an [Actor].
+type ActorFunc func(ctx context.Context, game types.Game) ([]types.Action, error)
+
+func (f ActorFunc) Act(ctx context.Context, game types.Game) ([]types.Action, error) {
+	return f(ctx, game)
+}
+
+// Player is an [Actor] participating in a simulated game from a specific address.
+type Player struct {
+	Name  string
+	Addr  common.Address
+	Actor Actor
+	// Delay is the time that passes between the player deciding on its actions and submitting them each round.
+	Delay time.Duration
+}
+
+// SimulatedAction records the outcome of an action submitted by a player.
+type SimulatedAction struct {
+	Player string
+	Action types.Action
+	Err    error
+}
+
+type simClaim struct {
+	types.Claim
+	duration time.Duration
+	resolved bool
+}
+
+// Simulator is an in-memory model of the FaultDisputeGame contract.
+// It enforces the move, step, chess clock and resolution rules of the contract so that solver strategies can be
+// played against each other without deploying contracts. The trace from the ClaimBuilder is used as the VM, so a
+// step is valid when it produces the value in the correct trace.
+// Split depth games are not modelled and all claims are treated as being in a single execution trace game.
+type Simulator struct {
+	builder      *ClaimBuilder
+	gameDuration time.Duration
+	now          time.Time
+	claims       []*simClaim
+	status       gameTypes.GameStatus
+	credit       map[common.Address]*big.Int
+	bonded       map[common.Address]*big.Int
+	history      []SimulatedAction
+}
+
+// Simulator creates a new [Simulator] with a root claim posted by proposer.
+func (c *ClaimBuilder) Simulator(rootCorrect bool, proposer common.Address) *Simulator {
+	return c.SimulatorWithDuration(rootCorrect, proposer, DefaultSimulatorGameDuration)
+}
+
+// SimulatorWithDuration creates a new [Simulator] with a root claim posted by proposer and the specified game duration.
+func (c *ClaimBuilder) SimulatorWithDuration(rootCorrect bool, proposer common.Address, gameDuration time.Duration) *Simulator {
+	s := &Simulator{
+		builder:      c,
+		gameDuration: gameDuration,
+		now:          time.Unix(1_000_000, 0),
+		status:       gameTypes.GameStatusInProgress,
+		credit:       make(map[common.Address]*big.Int),
+		bonded:       make(map[common.Address]*big.Int),
+	}
+	root := c.CreateRootClaim(rootCorrect)
+	root.ParentContractIndex = math.MaxUint32
+	s.addClaim(proposer, root, 0)
+	return s
+}
+
+// RequiredBond returns the bond that must be posted to make a claim at the specified position.
+func RequiredBond(pos types.Position) *big.Int {
+	return big.NewInt(int64(pos.Depth()) + 1)
+}
+
+// Game returns a snapshot of the current game state.
+func (s *Simulator) Game() types.Game {
+	claims := make([]types.Claim, 0, len(s.claims))
+	for _, claim := range s.claims {
+		claims = append(claims, claim.Claim)
+	}
+	return types.NewGameState(claims, s.builder.maxDepth)
+}
+
+// Status returns the status of the game.
+func (s *Simulator) Status() gameTypes.GameStatus {
+	return s.status
+}
+
+// Now returns the current simulated time.
+func (s *Simulator) Now() time.Time {
+	return s.now
+}
+
+// AdvanceTime moves the simulated time forward by d.
+func (s *Simulator) AdvanceTime(d time.Duration) {
+	s.now = s.now.Add(d)
+}
+
+// Credit returns the credit paid out to addr when claims were resolved.
+func (s *Simulator) Credit(addr common.Address) *big.Int {
+	return valueOrZero(s.credit[addr])
+}
+
+// Bonded returns the total bonds posted by addr.
+func (s *Simulator) Bonded(addr common.Address) *big.Int {
+	return valueOrZero(s.bonded[addr])
+}
+
+// History returns the outcome of every action submitted via Run.
+func (s *Simulator) History() []SimulatedAction {
+	return s.history
+}
+
+// Errors returns the errors from actions submitted via Run by the specified player.
+func (s *Simulator) Errors(player string) []error {
+	var errs []error
+	for _, action := range s.history {
+		if action.Player == player && action.Err != nil {
+			errs = append(errs, action.Err)
+		}
+	}
+	return errs
+}
+
+// Perform submits an action to the game on behalf of sender.
+func (s *Simulator) Perform(sender common.Address, action types.Action) error {
+	switch action.Type {
+	case types.ActionTypeMove:
+		return s.Move(sender, action.ParentIdx, action.IsAttack, action.Value)
+	case types.ActionTypeStep:
+		return s.Step(sender, action.ParentIdx, action.IsAttack, action.PreState)
+	default:
+		return fmt.Errorf("unsupported action type: %v", action.Type)
+	}
+}
+
+// Move attacks or defends the claim at parentIdx with a new claim.
+func (s *Simulator) Move(sender common.Address, parentIdx int, isAttack bool, value common.Hash) error {
+	if s.status != gameTypes.GameStatusInProgress {
+		return ErrGameNotInProgress
+	}
+	if parentIdx < 0 || parentIdx >= len(s.claims) {
+		return fmt.Errorf("%w: %v", ErrParentDoesNotExist, parentIdx)
+	}
+	parent := s.claims[parentIdx]
+	if parent.IsRoot() && !isAttack {
+		return ErrCannotDefendRoot
+	}
+	var pos types.Position
+	if isAttack {
+		pos = parent.Position.Attack()
+	} else {
+		pos = parent.Position.Defend()
+	}
+	if pos.Depth() > s.builder.maxDepth {
+		return fmt.Errorf("%w: %v", ErrGameDepthExceeded, pos.Depth())
+	}
+	claim := types.Claim{
+		ClaimData: types.ClaimData{
+			Value:    value,
+			Position: pos,
+		},
+		ParentContractIndex: parentIdx,
+	}
+	if s.Game().IsDuplicate(claim) {
+		return fmt.Errorf("%w: %v at %v", ErrClaimAlreadyExists, value, pos.ToGIndex())
+	}
+	// The time spent by the mover's team is the time taken to respond to the parent plus the time already used
+	// by the team when making the grandparent claim.
+	var grandparentDuration time.Duration
+	if !parent.IsRoot() {
+		grandparentDuration = s.claims[parent.ParentContractIndex].duration
+	}
+	duration := s.now.Sub(time.Unix(int64(parent.Clock), 0)) + grandparentDuration
+	if duration > s.gameDuration/2 {
+		return fmt.Errorf("%w: %v", ErrClockTimeExceeded, duration)
+	}
+	s.addClaim(sender, claim, duration)
+	return nil
+}
+
+// Step counters the max depth claim at claimIdx by executing a single instruction of the VM.
+// The correct trace is used as the VM so preState must match the correct pre-state for the step.
+func (s *Simulator) Step(sender common.Address, claimIdx int, isAttack bool, preState []byte) error {
+	if s.status != gameTypes.GameStatusInProgress {
+		return ErrGameNotInProgress
+	}
+	if claimIdx < 0 || claimIdx >= len(s.claims) {
+		return fmt.Errorf("%w: %v", ErrParentDoesNotExist, claimIdx)
+	}
+	parent := s.claims[claimIdx]
+	if parent.Depth() != s.builder.maxDepth {
+		return fmt.Errorf("%w: %v", ErrNotAtMaxDepth, parent.Depth())
+	}
+	if parent.CounteredBy != (common.Address{}) {
+		return ErrDuplicateStep
+	}
+	traceIdx := parent.TraceIndex(s.builder.maxDepth)
+	var preClaim, postClaim *simClaim
+	var stepIdx *big.Int
+	if isAttack {
+		// Executes the instruction that produces the state claimed by the parent.
+		stepIdx = traceIdx
+		if traceIdx.Sign() != 0 {
+			preClaim = s.findTraceAncestor(claimIdx, new(big.Int).Sub(traceIdx, common.Big1))
+		}
+		postClaim = parent
+	} else {
+		// Executes the instruction after the state claimed by the parent.
+		stepIdx = new(big.Int).Add(traceIdx, common.Big1)
+		preClaim = parent
+		postClaim = s.findTraceAncestor(claimIdx, stepIdx)
+	}
+	if preClaim != nil && preClaim.Value != s.builder.CorrectClaimAtPosition(preClaim.Position) {
+		// No pre-image exists for an incorrect claim so the pre-state can never match.
+		return fmt.Errorf("%w: claim %v is not a valid state", ErrInvalidPrestate, preClaim.ContractIndex)
+	}
+	if !slices.Equal(preState, s.builder.CorrectPreState(stepIdx)) {
+		return fmt.Errorf("%w: pre-state does not match claim", ErrInvalidPrestate)
+	}
+	validStep := postClaim.Value == s.builder.CorrectClaimAtPosition(types.NewPosition(s.builder.maxDepth, stepIdx))
+	parentPostAgree := (parent.Depth()-postClaim.Depth())%2 == 0
+	if parentPostAgree == validStep {
+		return ErrValidStep
+	}
+	parent.CounteredBy = sender
+	return nil
+}
+
+// ResolveClaim resolves the subgame rooted at claimIdx, paying its bond to the leftmost uncountered child or to the
+// claimant if there is no such child.
+func (s *Simulator) ResolveClaim(claimIdx int) error {
+	if s.status != gameTypes.GameStatusInProgress {
+		return ErrGameNotInProgress
+	}
+	if claimIdx < 0 || claimIdx >= len(s.claims) {
+		return fmt.Errorf("%w: %v", ErrParentDoesNotExist, claimIdx)
+	}
+	claim := s.claims[claimIdx]
+	if claim.resolved {
+		return ErrClaimAlreadyResolved
+	}
+	if !s.clockExpired(claim) {
+		return ErrClockNotExpired
+	}
+	var countered common.Address
+	var leftmost *big.Int
+	for _, child := range s.claims {
+		if child.ParentContractIndex != claimIdx {
+			continue
+		}
+		if !child.resolved {
+			return fmt.Errorf("%w: child %v is unresolved", ErrOutOfOrderResolution, child.ContractIndex)
+		}
+		if child.CounteredBy != (common.Address{}) {
+			continue
+		}
+		if leftmost == nil || child.ToGIndex().Cmp(leftmost) < 0 {
+			countered = child.Claimant
+			leftmost = child.ToGIndex()
+		}
+	}
+	if countered == (common.Address{}) {
+		// A max depth claim may have been countered by a step
+		countered = claim.CounteredBy
+	}
+	recipient := claim.Claimant
+	if countered != (common.Address{}) {
+		recipient = countered
+	}
+	s.credit[recipient] = new(big.Int).Add(s.Credit(recipient), claim.Bond)
+	claim.CounteredBy = countered
+	claim.resolved = true
+	return nil
+}
+
+// Resolve resolves every claim, deepest first, and then the game itself.
+func (s *Simulator) Resolve() (gameTypes.GameStatus, error) {
+	for i := len(s.claims) - 1; i >= 0; i-- {
+		if s.claims[i].resolved {
+			continue
+		}
+		if err := s.ResolveClaim(i); err != nil {
+			return s.status, fmt.Errorf("failed to resolve claim %v: %w", i, err)
+		}
+	}
+	if s.claims[0].CounteredBy != (common.Address{}) {
+		s.status = gameTypes.GameStatusChallengerWon
+	} else {
+		s.status = gameTypes.GameStatusDefenderWon
+	}
+	return s.status, nil
+}
+
+// Run plays the game in rounds until no player successfully performs an action or maxRounds is reached.
+// Each round, every player in turn is given the current game state and its actions are submitted after its Delay.
+// The simulated time advances by roundDuration at the end of each round.
+// Failed actions are recorded in the history rather than stopping the game, since adversaries are expected to make
+// invalid actions. An error is only returned if a player is unable to decide on its actions.
+func (s *Simulator) Run(ctx context.Context, roundDuration time.Duration, maxRounds int, players ...Player) error {
+	for round := 0; round < maxRounds; round++ {
+		progressed := false
+		for _, player := range players {
+			actions, err := player.Actor.Act(ctx, s.Game())
+			if err != nil {
+				return fmt.Errorf("player %v failed to act in round %v: %w", player.Name, round, err)
+			}
+			if len(actions) == 0 {
+				continue
+			}
+			s.AdvanceTime(player.Delay)
+			for _, action := range actions {
+				err := s.Perform(player.Addr, action)
+				s.history = append(s.history, SimulatedAction{Player: player.Name, Action: action, Err: err})
+				if err == nil {
+					progressed = true
+				}
+			}
+		}
+		if !progressed {
+			return nil
+		}
+		s.AdvanceTime(roundDuration)
+	}
+	return fmt.Errorf("game still progressing after %v rounds", maxRounds)
+}
+
+// RunAndResolve plays the game with Run then waits for all clocks to expire and resolves it.
+func (s *Simulator) RunAndResolve(ctx context.Context, roundDuration time.Duration, maxRounds int, players ...Player) (gameTypes.GameStatus, error) {
+	if err := s.Run(ctx, roundDuration, maxRounds, players...); err != nil {
+		return s.status, err
+	}
+	s.AdvanceTime(s.gameDuration)
+	return s.Resolve()
+}
+
+func (s *Simulator) addClaim(sender common.Address, claim types.Claim, duration time.Duration) {
+	claim.ContractIndex = len(s.claims)
+	claim.Claimant = sender
+	claim.Bond = RequiredBond(claim.Position)
+	claim.Clock = uint64(s.now.Unix())
+	s.bonded[sender] = new(big.Int).Add(s.Bonded(sender), claim.Bond)
+	s.claims = append(s.claims, &simClaim{Claim: claim, duration: duration})
+}
+
+// clockExpired returns true if the claim's chess clock, which includes the time elapsed since the claim was made,
+// has run out, matching the check in FaultDisputeGame.resolveClaim.
+func (s *Simulator) clockExpired(claim *simClaim) bool {
+	elapsed := claim.duration + s.now.Sub(time.Unix(int64(claim.Clock), 0))
+	return elapsed > s.gameDuration/2
+}
+
+// findTraceAncestor returns the first claim, starting from claimIdx and following parents, that commits to the state
+// at traceIdx. Returns the root claim if no other ancestor commits to that state.
+func (s *Simulator) findTraceAncestor(claimIdx int, traceIdx *big.Int) *simClaim {
+	claim := s.claims[claimIdx]
+	for !claim.IsRoot() && claim.TraceIndex(s.builder.maxDepth).Cmp(traceIdx) != 0 {
+		claim = s.claims[claim.ParentContractIndex]
+	}
+	return claim
+}
+
+func valueOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(v)
+}
@@ -0,0 +1,60 @@
+package cannon
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+)
+
+const (
+	// bisectionProofDepth is the number of depths below a requested position that proofs are generated for
+	// in the same execution of cannon. Each additional depth doubles the number of proofs generated.
+	bisectionProofDepth = 4
+	// bisectionSnapshotDepth is the number of depths below a requested position that snapshots are written at.
+	// Snapshots are much larger than proofs so are only written at the nearest bisection midpoints.
+	bisectionSnapshotDepth = 2
+)
+
+// bisectionTraceIndices returns the trace indices that may be requested in the moves following a request for pos,
+// in ascending order, and the subset of them that snapshots should be written at.
+//
+// Both the attack and defend positions of pos are in the subtree of its parent, so the trace indices of the
+// positions in that subtree are returned, down to bisectionProofDepth below pos.
+// Trace indices that don't fit in a uint64 are omitted.
+func bisectionTraceIndices(pos types.Position, maxDepth types.Depth) (proofsAt []uint64, snapshotsAt []uint64) {
+	root := pos.Depth()
+	rootIndex := pos.IndexAtDepth()
+	if root > 0 {
+		root--
+		rootIndex = new(big.Int).Rsh(rootIndex, 1)
+	}
+	lastDepth := min(pos.Depth()+bisectionProofDepth, maxDepth)
+	proofs := make(map[uint64]bool)
+	snapshots := make(map[uint64]bool)
+	for depth := pos.Depth() + 1; depth <= lastDepth; depth++ {
+		count := uint64(1) << (depth - root)
+		first := new(big.Int).Lsh(rootIndex, uint(depth-root))
+		for i := uint64(0); i < count; i++ {
+			index := new(big.Int).Add(first, new(big.Int).SetUint64(i))
+			traceIndex := types.NewPosition(depth, index).TraceIndex(maxDepth)
+			if !traceIndex.IsUint64() {
+				continue
+			}
+			proofs[traceIndex.Uint64()] = true
+			if depth <= pos.Depth()+bisectionSnapshotDepth {
+				snapshots[traceIndex.Uint64()] = true
+			}
+		}
+	}
+	return sortedKeys(proofs), sortedKeys(snapshots)
+}
+
+func sortedKeys(m map[uint64]bool) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,52 @@
+package cannon
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectionTraceIndices(t *testing.T) {
+	rangeOf := func(first uint64, last uint64, stride uint64) []uint64 {
+		var out []uint64
+		for i := first; i <= last; i += stride {
+			out = append(out, i)
+		}
+		return out
+	}
+
+	t.Run("Root", func(t *testing.T) {
+		proofsAt, snapshotsAt := bisectionTraceIndices(types.NewPosition(0, big.NewInt(0)), 4)
+		require.Equal(t, rangeOf(0, 15, 1), proofsAt)
+		require.Equal(t, []uint64{3, 7, 11, 15}, snapshotsAt)
+	})
+
+	t.Run("IncludeDefendPositions", func(t *testing.T) {
+		pos := types.NewPosition(1, big.NewInt(0))
+		proofsAt, snapshotsAt := bisectionTraceIndices(pos, 4)
+		require.Equal(t, rangeOf(0, 15, 1), proofsAt)
+		require.Equal(t, rangeOf(1, 15, 2), snapshotsAt)
+		require.Contains(t, proofsAt, pos.Attack().TraceIndex(4).Uint64())
+		require.Contains(t, proofsAt, pos.Defend().TraceIndex(4).Uint64())
+	})
+
+	t.Run("LimitedToLookaheadDepth", func(t *testing.T) {
+		proofsAt, snapshotsAt := bisectionTraceIndices(types.NewPosition(2, big.NewInt(1)), 10)
+		require.Equal(t, rangeOf(15, 511, 16), proofsAt)
+		require.Equal(t, rangeOf(63, 511, 64), snapshotsAt)
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		proofsAt, snapshotsAt := bisectionTraceIndices(types.NewPosition(4, big.NewInt(3)), 4)
+		require.Empty(t, proofsAt)
+		require.Empty(t, snapshotsAt)
+	})
+
+	t.Run("OmitTraceIndicesBeyondUint64", func(t *testing.T) {
+		proofsAt, _ := bisectionTraceIndices(types.NewPosition(0, big.NewInt(0)), 67)
+		require.Equal(t, []uint64{1<<63 - 1, math.MaxUint64}, proofsAt)
+	})
+}
@@ -1,23 +1,42 @@
 package cannon
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
 )
 
-func parseState(path string) (*mipsevm.State, error) {
+// parseState loads a JSON state of the specified cannon VM type, optionally gzipped, or a binary state snapshot.
+func parseState(path string, vmType string) (mipsevm.FPVMState, error) {
 	file, err := ioutil.OpenDecompressed(path)
 	if err != nil {
 		return nil, fmt.Errorf("cannot open state file (%v): %w", path, err)
 	}
 	defer file.Close()
-	var state mipsevm.State
-	err = json.NewDecoder(file).Decode(&state)
+	in := bufio.NewReader(file)
+	if header, _ := in.Peek(len(mipsevm.SnapshotMagic)); mipsevm.IsSnapshot(header) {
+		state, err := mipsevm.DecodeSnapshot(in)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mipsevm snapshot (%v): %w", path, err)
+		}
+		return state, nil
+	}
+	var state mipsevm.FPVMState
+	switch vmType {
+	case config.CannonVMTypeSingleThreaded:
+		state = new(mipsevm.State)
+	case config.CannonVMTypeMultiThreaded:
+		state = new(mipsevm.MTState)
+	default:
+		return nil, fmt.Errorf("%w: %v", config.ErrCannonVMTypeUnknown, vmType)
+	}
+	err = json.NewDecoder(in).Decode(state)
 	if err != nil {
 		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
 	}
-	return &state, nil
+	return state, nil
 }
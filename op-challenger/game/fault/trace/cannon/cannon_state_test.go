@@ -1,6 +1,7 @@
 package cannon
 
 import (
+	"bytes"
 	"compress/gzip"
 	_ "embed"
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,7 +23,7 @@ func TestLoadState(t *testing.T) {
 		path := filepath.Join(dir, "state.json")
 		require.NoError(t, os.WriteFile(path, testState, 0644))
 
-		state, err := parseState(path)
+		state, err := parseState(path, config.CannonVMTypeSingleThreaded)
 		require.NoError(t, err)
 
 		var expected mipsevm.State
@@ -29,6 +31,21 @@ func TestLoadState(t *testing.T) {
 		require.Equal(t, &expected, state)
 	})
 
+	t.Run("BinarySnapshot", func(t *testing.T) {
+		var expected mipsevm.State
+		require.NoError(t, json.Unmarshal(testState, &expected))
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.bin")
+		var buf bytes.Buffer
+		require.NoError(t, expected.EncodeSnapshot(&buf))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		state, err := parseState(path, config.CannonVMTypeSingleThreaded)
+		require.NoError(t, err)
+		require.Equal(t, expected.EncodeWitness(), state.EncodeWitness())
+	})
+
 	t.Run("Gzipped", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "state.json.gz")
@@ -40,11 +57,32 @@ func TestLoadState(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, writer.Close())
 
-		state, err := parseState(path)
+		state, err := parseState(path, config.CannonVMTypeSingleThreaded)
 		require.NoError(t, err)
 
 		var expected mipsevm.State
 		require.NoError(t, json.Unmarshal(testState, &expected))
 		require.Equal(t, &expected, state)
 	})
+	t.Run("MultiThreaded", func(t *testing.T) {
+		var st mipsevm.State
+		require.NoError(t, json.Unmarshal(testState, &st))
+		expected := mipsevm.NewMTState(&st)
+		data, err := json.Marshal(expected)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, data, 0644))
+
+		state, err := parseState(path, config.CannonVMTypeMultiThreaded)
+		require.NoError(t, err)
+		require.IsType(t, &mipsevm.MTState{}, state)
+		require.Equal(t, expected.EncodeWitness(), state.EncodeWitness())
+	})
+
+	t.Run("UnknownVMType", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, testState, 0644))
+		_, err := parseState(path, "unknown")
+		require.ErrorIs(t, err, config.ErrCannonVMTypeUnknown)
+	})
 }
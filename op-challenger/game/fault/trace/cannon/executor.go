@@ -9,11 +9,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -24,7 +26,15 @@ const (
 	finalState   = "final.json.gz"
 )
 
-var snapshotNameRegexp = regexp.MustCompile(`^[0-9]+\.json.gz$`)
+// Snapshots are written in cannon's binary snapshot format, which is much faster to resume from than JSON.
+// JSON snapshots written by earlier versions are still used when resuming.
+// The multi-threaded VM does not support binary snapshots, so JSON snapshots are written for it.
+const (
+	snapshotExt     = ".bin"
+	jsonSnapshotExt = ".json.gz"
+)
+
+var snapshotNameRegexp = regexp.MustCompile(`^([0-9]+)(\.json\.gz|\.bin)$`)
 
 type snapshotSelect func(logger log.Logger, dir string, absolutePreState string, i uint64) (string, error)
 type cmdExecutor func(ctx context.Context, l log.Logger, binary string, args ...string) error
@@ -41,8 +51,10 @@ type Executor struct {
 	rollupConfig     string
 	l2Genesis        string
 	absolutePreState string
+	vmType           string
 	snapshotFreq     uint
 	infoFreq         uint
+	altDA            altda.CLIConfig
 	selectSnapshot   snapshotSelect
 	cmdExecutor      cmdExecutor
 }
@@ -60,23 +72,31 @@ func NewExecutor(logger log.Logger, m CannonMetricer, cfg *config.Config, inputs
 		rollupConfig:     cfg.CannonRollupConfigPath,
 		l2Genesis:        cfg.CannonL2GenesisPath,
 		absolutePreState: cfg.CannonAbsolutePreState,
+		vmType:           cfg.CannonVMType,
 		snapshotFreq:     cfg.CannonSnapshotFreq,
 		infoFreq:         cfg.CannonInfoFreq,
+		altDA:            cfg.AltDA,
 		selectSnapshot:   findStartingSnapshot,
 		cmdExecutor:      runCmd,
 	}
 }
 
-// GenerateProof executes cannon to generate a proof at the specified trace index.
-// The proof is stored at the specified directory.
-func (e *Executor) GenerateProof(ctx context.Context, dir string, i uint64) error {
-	return e.generateProof(ctx, dir, i, i)
+// GenerateProofs executes cannon once to generate proofs at all the specified trace indices.
+// Snapshots are written at the snapshotsAt trace indices, in addition to the regular snapshot frequency,
+// so later executions can start close to them.
+// The proofs and snapshots are stored at the specified directory.
+func (e *Executor) GenerateProofs(ctx context.Context, dir string, proofsAt []uint64, snapshotsAt []uint64) error {
+	if len(proofsAt) == 0 {
+		return nil
+	}
+	return e.generateProof(ctx, dir, slices.Min(proofsAt), proofsAt, snapshotsAt)
 }
 
-// generateProofOrUntilPreimageRead executes cannon to generate a proof at the specified trace index,
-// or until a non-local preimage read is encountered if untilPreimageRead is true.
-// The proof is stored at the specified directory.
-func (e *Executor) generateProof(ctx context.Context, dir string, begin uint64, end uint64, extraCannonArgs ...string) error {
+// generateProof executes cannon from the closest snapshot before begin to generate proofs at the specified
+// trace indices, stopping after the last of them unless extraCannonArgs stop execution earlier.
+// The proofs are stored at the specified directory.
+func (e *Executor) generateProof(ctx context.Context, dir string, begin uint64, proofsAt []uint64, snapshotsAt []uint64, extraCannonArgs ...string) error {
+	end := slices.Max(proofsAt)
 	snapshotDir := filepath.Join(dir, snapsDir)
 	start, err := e.selectSnapshot(e.logger, snapshotDir, e.absolutePreState, begin)
 	if err != nil {
@@ -85,16 +105,21 @@ func (e *Executor) generateProof(ctx context.Context, dir string, begin uint64,
 	proofDir := filepath.Join(dir, proofsDir)
 	dataDir := filepath.Join(dir, preimagesDir)
 	lastGeneratedState := filepath.Join(dir, finalState)
+	snapshotFmt := "%d" + snapshotExt
+	if e.vmType != config.CannonVMTypeSingleThreaded {
+		snapshotFmt = "%d" + jsonSnapshotExt
+	}
 	args := []string{
 		"run",
+		"--type", e.vmType,
 		"--input", start,
 		"--output", lastGeneratedState,
 		"--meta", "",
 		"--info-at", "%" + strconv.FormatUint(uint64(e.infoFreq), 10),
-		"--proof-at", "=" + strconv.FormatUint(end, 10),
+		"--proof-at", exactStepsPattern(proofsAt),
 		"--proof-fmt", filepath.Join(proofDir, "%d.json.gz"),
-		"--snapshot-at", "%" + strconv.FormatUint(uint64(e.snapshotFreq), 10),
-		"--snapshot-fmt", filepath.Join(snapshotDir, "%d.json.gz"),
+		"--snapshot-at", strings.Join(append([]string{"%" + strconv.FormatUint(uint64(e.snapshotFreq), 10)}, exactSteps(snapshotsAt)...), ","),
+		"--snapshot-fmt", filepath.Join(snapshotDir, snapshotFmt),
 	}
 	if end < math.MaxUint64 {
 		args = append(args, "--stop-at", "="+strconv.FormatUint(end+1, 10))
@@ -121,6 +146,9 @@ func (e *Executor) generateProof(ctx context.Context, dir string, begin uint64,
 	if e.l2Genesis != "" {
 		args = append(args, "--l2.genesis", e.l2Genesis)
 	}
+	if e.altDA.Enabled {
+		args = append(args, "--"+altda.EnabledFlagName+"=true", "--"+altda.DAServerFlagName, e.altDA.DAServerURL)
+	}
 
 	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
 		return fmt.Errorf("could not create snapshot directory %v: %w", snapshotDir, err)
@@ -131,13 +159,26 @@ func (e *Executor) generateProof(ctx context.Context, dir string, begin uint64,
 	if err := os.MkdirAll(proofDir, 0755); err != nil {
 		return fmt.Errorf("could not create proofs directory %v: %w", proofDir, err)
 	}
-	e.logger.Info("Generating trace", "proof", end, "cmd", e.cannon, "args", strings.Join(args, ", "))
+	e.logger.Info("Generating trace", "proof", end, "proofs", len(proofsAt), "cmd", e.cannon, "args", strings.Join(args, ", "))
 	execStart := time.Now()
 	err = e.cmdExecutor(ctx, e.logger.New("proof", end), e.cannon, args...)
 	e.metrics.RecordCannonExecutionTime(time.Since(execStart).Seconds())
 	return err
 }
 
+// exactStepsPattern returns the cannon step pattern matching exactly the specified steps.
+func exactStepsPattern(steps []uint64) string {
+	return strings.Join(exactSteps(steps), ",")
+}
+
+func exactSteps(steps []uint64) []string {
+	patterns := make([]string, 0, len(steps))
+	for _, step := range steps {
+		patterns = append(patterns, "="+strconv.FormatUint(step, 10))
+	}
+	return patterns
+}
+
 func runCmd(ctx context.Context, l log.Logger, binary string, args ...string) error {
 	cmd := exec.CommandContext(ctx, binary, args...)
 	stdOut := oplog.NewWriter(l, log.LevelInfo)
@@ -162,29 +203,31 @@ func findStartingSnapshot(logger log.Logger, snapDir string, absolutePreState st
 		return "", fmt.Errorf("list snapshots in %v: %w", snapDir, err)
 	}
 	bestSnap := uint64(0)
+	bestName := ""
 	for _, entry := range entries {
 		if entry.IsDir() {
 			logger.Warn("Unexpected directory in snapshots dir", "parent", snapDir, "child", entry.Name())
 			continue
 		}
 		name := entry.Name()
-		if !snapshotNameRegexp.MatchString(name) {
+		match := snapshotNameRegexp.FindStringSubmatch(name)
+		if match == nil {
 			logger.Warn("Unexpected file in snapshots dir", "parent", snapDir, "child", entry.Name())
 			continue
 		}
-		index, err := strconv.ParseUint(name[0:len(name)-len(".json.gz")], 10, 64)
+		index, err := strconv.ParseUint(match[1], 10, 64)
 		if err != nil {
 			logger.Error("Unable to parse trace index of snapshot file", "parent", snapDir, "child", entry.Name())
 			continue
 		}
-		if index > bestSnap && index < traceIndex {
+		// Prefer the binary snapshot if both formats are available for the same index
+		if index < traceIndex && (index > bestSnap || (index == bestSnap && match[2] == snapshotExt)) {
 			bestSnap = index
+			bestName = name
 		}
 	}
 	if bestSnap == 0 {
 		return absolutePreState, nil
 	}
-	startFrom := fmt.Sprintf("%v/%v.json.gz", snapDir, bestSnap)
-
-	return startFrom, nil
+	return filepath.Join(snapDir, bestName), nil
 }
@@ -7,11 +7,13 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -39,7 +41,7 @@ func TestGenerateProof(t *testing.T) {
 		L2Claim:       common.Hash{0x44},
 		L2BlockNumber: big.NewInt(3333),
 	}
-	captureExec := func(t *testing.T, cfg config.Config, proofAt uint64) (string, string, map[string]string) {
+	captureExecWith := func(t *testing.T, cfg config.Config, generate func(executor *Executor) error) (string, string, map[string]string) {
 		m := &cannonDurationMetrics{}
 		executor := NewExecutor(testlog.Logger(t, log.LevelInfo), m, &cfg, inputs)
 		executor.selectSnapshot = func(logger log.Logger, dir string, absolutePreState string, i uint64) (string, error) {
@@ -57,16 +59,27 @@ func TestGenerateProof(t *testing.T) {
 					i += 1
 					continue
 				}
+				if name, value, ok := strings.Cut(a[i], "="); ok && strings.HasPrefix(name, "--") {
+					// Boolean flags are passed with their value in the same arg
+					args[name] = value
+					i += 1
+					continue
+				}
 				args[a[i]] = a[i+1]
 				i += 2
 			}
 			return nil
 		}
-		err := executor.GenerateProof(context.Background(), dir, proofAt)
+		err := generate(executor)
 		require.NoError(t, err)
 		require.Equal(t, 1, m.executionTimeRecordCount, "Should record cannon execution time")
 		return binary, subcommand, args
 	}
+	captureExec := func(t *testing.T, cfg config.Config, proofAt uint64) (string, string, map[string]string) {
+		return captureExecWith(t, cfg, func(executor *Executor) error {
+			return executor.GenerateProofs(context.Background(), dir, []uint64{proofAt}, nil)
+		})
+	}
 
 	t.Run("Network", func(t *testing.T) {
 		cfg.CannonNetwork = "mainnet"
@@ -78,6 +91,7 @@ func TestGenerateProof(t *testing.T) {
 		require.DirExists(t, filepath.Join(dir, snapsDir))
 		require.Equal(t, cfg.CannonBin, binary)
 		require.Equal(t, "run", subcommand)
+		require.Equal(t, config.CannonVMTypeSingleThreaded, args["--type"])
 		require.Equal(t, input, args["--input"])
 		require.Contains(t, args, "--meta")
 		require.Equal(t, "", args["--meta"])
@@ -94,7 +108,7 @@ func TestGenerateProof(t *testing.T) {
 		require.Equal(t, cfg.CannonL2, args["--l2"])
 		require.Equal(t, filepath.Join(dir, preimagesDir), args["--datadir"])
 		require.Equal(t, filepath.Join(dir, proofsDir, "%d.json.gz"), args["--proof-fmt"])
-		require.Equal(t, filepath.Join(dir, snapsDir, "%d.json.gz"), args["--snapshot-fmt"])
+		require.Equal(t, filepath.Join(dir, snapsDir, "%d.bin"), args["--snapshot-fmt"])
 		require.Equal(t, cfg.CannonNetwork, args["--network"])
 		require.NotContains(t, args, "--rollup.config")
 		require.NotContains(t, args, "--l2.genesis")
@@ -117,6 +131,20 @@ func TestGenerateProof(t *testing.T) {
 		require.Equal(t, cfg.CannonL2GenesisPath, args["--l2.genesis"])
 	})
 
+	t.Run("AltDA", func(t *testing.T) {
+		cfg := cfg
+		cfg.AltDA = altda.CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}
+		_, _, args := captureExec(t, cfg, 150_000_000)
+		require.Equal(t, "true", args["--altda.enabled"])
+		require.Equal(t, cfg.AltDA.DAServerURL, args["--altda.da-server"])
+	})
+
+	t.Run("AltDADisabled", func(t *testing.T) {
+		_, _, args := captureExec(t, cfg, 150_000_000)
+		require.NotContains(t, args, "--altda.enabled")
+		require.NotContains(t, args, "--altda.da-server")
+	})
+
 	t.Run("NoStopAtWhenProofIsMaxUInt", func(t *testing.T) {
 		cfg.CannonNetwork = "mainnet"
 		cfg.CannonRollupConfigPath = "rollup.json"
@@ -126,6 +154,23 @@ func TestGenerateProof(t *testing.T) {
 		// so expect that it will be omitted. We'll ultimately want cannon to execute until the program exits.
 		require.NotContains(t, args, "--stop-at")
 	})
+
+	t.Run("MultiThreaded", func(t *testing.T) {
+		cfg := cfg
+		cfg.CannonVMType = config.CannonVMTypeMultiThreaded
+		_, _, args := captureExec(t, cfg, 150_000_000)
+		require.Equal(t, config.CannonVMTypeMultiThreaded, args["--type"])
+		require.Equal(t, filepath.Join(dir, snapsDir, "%d.json.gz"), args["--snapshot-fmt"])
+	})
+
+	t.Run("MultipleProofs", func(t *testing.T) {
+		_, _, args := captureExecWith(t, cfg, func(executor *Executor) error {
+			return executor.GenerateProofs(context.Background(), dir, []uint64{150, 170, 160}, []uint64{160})
+		})
+		require.Equal(t, "=150,=170,=160", args["--proof-at"])
+		require.Equal(t, "=171", args["--stop-at"])
+		require.Equal(t, "%500,=160", args["--snapshot-at"])
+	})
 }
 
 func TestRunCmdLogsOutput(t *testing.T) {
@@ -197,6 +242,18 @@ func TestFindStartingSnapshot(t *testing.T) {
 		require.Equal(t, filepath.Join(dir, "250.json.gz"), snapshot)
 	})
 
+	t.Run("UseBinarySnapshots", func(t *testing.T) {
+		dir := withSnapshots(t, "100.json.gz", "123.bin", "200.json.gz", "200.bin")
+
+		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 150)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "123.bin"), snapshot)
+
+		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 201)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "200.bin"), snapshot)
+	})
+
 	t.Run("IgnoreDirectories", func(t *testing.T) {
 		dir := withSnapshots(t, "100.json.gz")
 		require.NoError(t, os.Mkdir(filepath.Join(dir, "120.json.gz"), 0o777))
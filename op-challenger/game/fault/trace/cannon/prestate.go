@@ -14,14 +14,15 @@ var _ types.PrestateProvider = (*CannonPrestateProvider)(nil)
 
 type CannonPrestateProvider struct {
 	prestate string
+	vmType   string
 }
 
-func NewPrestateProvider(prestate string) *CannonPrestateProvider {
-	return &CannonPrestateProvider{prestate}
+func NewPrestateProvider(prestate string, vmType string) *CannonPrestateProvider {
+	return &CannonPrestateProvider{prestate: prestate, vmType: vmType}
 }
 
 func (p *CannonPrestateProvider) absolutePreState() ([]byte, error) {
-	state, err := parseState(p.prestate)
+	state, err := parseState(p.prestate, p.vmType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot load absolute pre-state: %w", err)
 	}
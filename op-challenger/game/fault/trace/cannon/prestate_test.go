@@ -7,14 +7,13 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
 func newCannonPrestateProvider(dataDir string, prestate string) *CannonPrestateProvider {
-	return &CannonPrestateProvider{
-		prestate: filepath.Join(dataDir, prestate),
-	}
+	return NewPrestateProvider(filepath.Join(dataDir, prestate), config.CannonVMTypeSingleThreaded)
 }
 
 func TestAbsolutePreStateCommitment(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
@@ -40,14 +41,16 @@ type CannonMetricer interface {
 }
 
 type ProofGenerator interface {
-	// GenerateProof executes cannon to generate a proof at the specified trace index in dataDir.
-	GenerateProof(ctx context.Context, dataDir string, proofAt uint64) error
+	// GenerateProofs executes cannon once to generate proofs at all the specified trace indices in dataDir,
+	// and snapshots at the snapshotsAt trace indices so later executions can start close to them.
+	GenerateProofs(ctx context.Context, dataDir string, proofsAt []uint64, snapshotsAt []uint64) error
 }
 
 type CannonTraceProvider struct {
 	logger    log.Logger
 	dir       string
 	prestate  string
+	vmType    string
 	generator ProofGenerator
 	gameDepth types.Depth
 
@@ -61,6 +64,7 @@ func NewTraceProvider(logger log.Logger, m CannonMetricer, cfg *config.Config, l
 		logger:    logger,
 		dir:       dir,
 		prestate:  cfg.CannonAbsolutePreState,
+		vmType:    cfg.CannonVMType,
 		generator: NewExecutor(logger, m, cfg, localInputs),
 		gameDepth: gameDepth,
 	}
@@ -75,7 +79,10 @@ func (p *CannonTraceProvider) Get(ctx context.Context, pos types.Position) (comm
 	if !traceIndex.IsUint64() {
 		return common.Hash{}, errors.New("trace index out of bounds")
 	}
-	proof, err := p.loadProof(ctx, traceIndex.Uint64())
+	// Generate proofs for the moves that may follow this one while executing cannon,
+	// so responding to them doesn't require executing cannon again.
+	lookahead, snapshotsAt := bisectionTraceIndices(pos, p.gameDepth)
+	proof, err := p.loadProof(ctx, traceIndex.Uint64(), lookahead, snapshotsAt)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -92,7 +99,7 @@ func (p *CannonTraceProvider) GetStepData(ctx context.Context, pos types.Positio
 	if !traceIndex.IsUint64() {
 		return nil, nil, nil, errors.New("trace index out of bounds")
 	}
-	proof, err := p.loadProof(ctx, traceIndex.Uint64())
+	proof, err := p.loadProof(ctx, traceIndex.Uint64(), nil, nil)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -112,7 +119,7 @@ func (p *CannonTraceProvider) GetStepData(ctx context.Context, pos types.Positio
 }
 
 func (p *CannonTraceProvider) absolutePreState() ([]byte, error) {
-	state, err := parseState(p.prestate)
+	state, err := parseState(p.prestate, p.vmType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot load absolute pre-state: %w", err)
 	}
@@ -133,7 +140,9 @@ func (p *CannonTraceProvider) AbsolutePreStateCommitment(_ context.Context) (com
 
 // loadProof will attempt to load or generate the proof data at the specified index
 // If the requested index is beyond the end of the actual trace it is extended with no-op instructions.
-func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofData, error) {
+// If the proof has to be generated, any missing proofs at the lookahead indices are generated by the same execution,
+// and snapshots are written at the snapshotsAt indices.
+func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64, lookahead []uint64, snapshotsAt []uint64) (*proofData, error) {
 	// Attempt to read the last step from disk cache
 	if p.lastStep == 0 {
 		step, err := readLastStep(p.dir)
@@ -147,10 +156,11 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 	if p.lastStep != 0 && i > p.lastStep {
 		i = p.lastStep
 	}
-	path := filepath.Join(p.dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
+	path := p.proofPath(i)
 	file, err := ioutil.OpenDecompressed(path)
 	if errors.Is(err, os.ErrNotExist) {
-		if err := p.generator.GenerateProof(ctx, p.dir, i); err != nil {
+		proofsAt, snapshotsAt := p.missingProofs(i, lookahead, snapshotsAt)
+		if err := p.generator.GenerateProofs(ctx, p.dir, proofsAt, snapshotsAt); err != nil {
 			return nil, fmt.Errorf("generate cannon trace with proof at %v: %w", i, err)
 		}
 		// Try opening the file again now and it should exist.
@@ -161,11 +171,11 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 			if err != nil {
 				return nil, err
 			}
-			if state.Exited && state.Step <= i {
-				p.logger.Warn("Requested proof was after the program exited", "proof", i, "last", state.Step)
+			if state.GetExited() && state.GetStep() <= i {
+				p.logger.Warn("Requested proof was after the program exited", "proof", i, "last", state.GetStep())
 				// The final instruction has already been applied to this state, so the last step we can execute
 				// is one before its Step value.
-				p.lastStep = state.Step - 1
+				p.lastStep = state.GetStep() - 1
 				// Extend the trace out to the full length using a no-op instruction that doesn't change any state
 				// No execution is done, so no proof-data or oracle values are required.
 				witness := state.EncodeWitness()
@@ -186,7 +196,7 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 				}
 				return proof, nil
 			} else {
-				return nil, fmt.Errorf("expected proof not generated but final state was not exited, requested step %v, final state at step %v", i, state.Step)
+				return nil, fmt.Errorf("expected proof not generated but final state was not exited, requested step %v, final state at step %v", i, state.GetStep())
 			}
 		}
 	}
@@ -202,8 +212,36 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 	return &proof, nil
 }
 
-func (c *CannonTraceProvider) finalState() (*mipsevm.State, error) {
-	state, err := parseState(filepath.Join(c.dir, finalState))
+func (p *CannonTraceProvider) proofPath(i uint64) string {
+	return filepath.Join(p.dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
+}
+
+// missingProofs returns the trace indices to generate proofs at to load the proof at i, in ascending order.
+// Lookahead indices are included unless their proof is already available or they are after the end of the trace.
+// Snapshots are only written at indices that a proof is generated for, as otherwise they were written previously.
+func (p *CannonTraceProvider) missingProofs(i uint64, lookahead []uint64, snapshotsAt []uint64) ([]uint64, []uint64) {
+	proofsAt := []uint64{i}
+	for _, j := range lookahead {
+		if j == i || (p.lastStep != 0 && j > p.lastStep) {
+			continue
+		}
+		if _, err := os.Stat(p.proofPath(j)); err == nil {
+			continue
+		}
+		proofsAt = append(proofsAt, j)
+	}
+	slices.Sort(proofsAt)
+	var snapshots []uint64
+	for _, j := range snapshotsAt {
+		if _, ok := slices.BinarySearch(proofsAt, j); ok {
+			snapshots = append(snapshots, j)
+		}
+	}
+	return proofsAt, snapshots
+}
+
+func (c *CannonTraceProvider) finalState() (mipsevm.FPVMState, error) {
+	state, err := parseState(filepath.Join(c.dir, finalState), c.vmType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read final state: %w", err)
 	}
@@ -269,6 +307,7 @@ func NewTraceProviderForTest(logger log.Logger, m CannonMetricer, cfg *config.Co
 		logger:    logger,
 		dir:       dir,
 		prestate:  cfg.CannonAbsolutePreState,
+		vmType:    cfg.CannonVMType,
 		generator: NewExecutor(logger, m, cfg, localInputs),
 		gameDepth: gameDepth,
 	}
@@ -277,26 +316,26 @@ func NewTraceProviderForTest(logger log.Logger, m CannonMetricer, cfg *config.Co
 
 func (p *CannonTraceProviderForTest) FindStep(ctx context.Context, start uint64, preimage PreimageOpt) (uint64, common.Hash, error) {
 	// First generate a snapshot of the starting state, so we can snap to it later for the full trace search
-	prestateProof, err := p.loadProof(ctx, start)
+	prestateProof, err := p.loadProof(ctx, start, nil, nil)
 	if err != nil {
 		return 0, common.Hash{}, err
 	}
 	start += 1
 	for {
-		if err := p.generator.(*Executor).generateProof(ctx, p.dir, start, math.MaxUint64, preimage()...); err != nil {
+		if err := p.generator.(*Executor).generateProof(ctx, p.dir, start, []uint64{math.MaxUint64}, nil, preimage()...); err != nil {
 			return 0, common.Hash{}, fmt.Errorf("generate cannon trace (until preimage read) with proof at %d: %w", start, err)
 		}
 		state, err := p.finalState()
 		if err != nil {
 			return 0, common.Hash{}, err
 		}
-		if state.Exited {
+		if state.GetExited() {
 			break
 		}
-		if state.PreimageOffset != 0 && state.PreimageOffset != prestateProof.OracleOffset {
-			return state.Step - 1, state.PreimageKey, nil
+		if state.GetPreimageOffset() != 0 && state.GetPreimageOffset() != prestateProof.OracleOffset {
+			return state.GetStep() - 1, state.GetPreimageKey(), nil
 		}
-		start = state.Step
+		start = state.GetStep()
 	}
 	return 0, common.Hash{}, io.EOF
 }
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
@@ -61,6 +62,34 @@ func TestGet(t *testing.T) {
 		require.Equal(t, stateHash, value)
 	})
 
+	t.Run("GenerateBisectionProofsInSingleExecution", func(t *testing.T) {
+		dataDir, prestate := setupTestData(t)
+		provider, generator := setupWithTestData(t, dataDir, prestate)
+		provider.gameDepth = 4
+		generator.proof = &proofData{
+			ClaimValue: common.Hash{0xaa},
+			StateData:  []byte{0xbb},
+			ProofData:  []byte{0xcc},
+		}
+		pos := types.NewPosition(1, big.NewInt(0))
+		value, err := provider.Get(context.Background(), pos)
+		require.NoError(t, err)
+		require.Equal(t, generator.proof.ClaimValue, value)
+		require.Equal(t, 1, generator.executions)
+		// Proofs 0, 1 and 2 are already available so are not generated again
+		require.Equal(t, []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, generator.generated)
+		require.Equal(t, []uint64{3, 5, 7, 9, 11, 13, 15}, generator.snapshotsAt)
+
+		// Following the bisection doesn't need to execute cannon again
+		for _, next := range []types.Position{pos.Attack(), pos.Defend(), pos.Attack().Defend(), pos.Defend().Defend()} {
+			_, err := provider.Get(context.Background(), next)
+			require.NoError(t, err)
+		}
+		_, _, _, err = provider.GetStepData(context.Background(), pos.Defend().Attack().Attack())
+		require.NoError(t, err)
+		require.Equal(t, 1, generator.executions)
+	})
+
 	t.Run("MissingPostHash", func(t *testing.T) {
 		provider, generator := setupWithTestData(t, dataDir, prestate)
 		_, err := provider.Get(context.Background(), PositionFromTraceIndex(provider, big.NewInt(1)))
@@ -239,33 +268,42 @@ func setupWithTestData(t *testing.T, dataDir string, prestate string) (*CannonTr
 		dir:       dataDir,
 		generator: generator,
 		prestate:  filepath.Join(dataDir, prestate),
+		vmType:    config.CannonVMTypeSingleThreaded,
 		gameDepth: 63,
 	}, generator
 }
 
 type stubGenerator struct {
-	generated  []int // Using int makes assertions easier
-	finalState *mipsevm.State
-	proof      *proofData
+	executions  int
+	generated   []int // Using int makes assertions easier
+	snapshotsAt []uint64
+	finalState  *mipsevm.State
+	proof       *proofData
 }
 
-func (e *stubGenerator) GenerateProof(ctx context.Context, dir string, i uint64) error {
-	e.generated = append(e.generated, int(i))
-	if e.finalState != nil && e.finalState.Step <= i {
-		// Requesting a trace index past the end of the trace
-		data, err := json.Marshal(e.finalState)
-		if err != nil {
-			return err
+func (e *stubGenerator) GenerateProofs(ctx context.Context, dir string, proofsAt []uint64, snapshotsAt []uint64) error {
+	e.executions++
+	e.snapshotsAt = append(e.snapshotsAt, snapshotsAt...)
+	for _, i := range proofsAt {
+		e.generated = append(e.generated, int(i))
+		if e.finalState != nil && e.finalState.Step <= i {
+			// Requesting a trace index past the end of the trace
+			data, err := json.Marshal(e.finalState)
+			if err != nil {
+				return err
+			}
+			return writeGzip(filepath.Join(dir, finalState), data)
 		}
-		return writeGzip(filepath.Join(dir, finalState), data)
-	}
-	if e.proof != nil {
-		proofFile := filepath.Join(dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
-		data, err := json.Marshal(e.proof)
-		if err != nil {
-			return err
+		if e.proof != nil {
+			proofFile := filepath.Join(dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
+			data, err := json.Marshal(e.proof)
+			if err != nil {
+				return err
+			}
+			if err := writeGzip(proofFile, data); err != nil {
+				return err
+			}
 		}
-		return writeGzip(proofFile, data)
 	}
 	return nil
 }
@@ -10,6 +10,11 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+const (
+	CannonGameType   uint32 = 0
+	AlphabetGameType uint32 = 255
+)
+
 var (
 	ErrGameDepthReached = errors.New("game depth reached")
 
@@ -0,0 +1,139 @@
+package visualize
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatDot  Format = "dot"
+	FormatJSON Format = "json"
+)
+
+var Formats = []Format{FormatText, FormatDot, FormatJSON}
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// Set implements the Set method required by the [cli.Generic] interface.
+func (f *Format) Set(value string) error {
+	switch Format(value) {
+	case FormatText, FormatDot, FormatJSON:
+		*f = Format(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown format: %q", value)
+	}
+}
+
+func (f *Format) Clone() any {
+	cpy := *f
+	return &cpy
+}
+
+// Write renders the tree rooted at root to w in the specified format.
+func Write(w io.Writer, format Format, root *Node) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, root)
+	case FormatDot:
+		return WriteDot(w, root)
+	case FormatJSON:
+		return WriteJSON(w, root)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
+}
+
+// WriteText renders the tree as indented text, one claim per line.
+func WriteText(w io.Writer, root *Node) error {
+	var b strings.Builder
+	writeTextNode(&b, root, "", "")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTextNode(b *strings.Builder, node *Node, prefix string, childPrefix string) {
+	b.WriteString(prefix)
+	b.WriteString(describe(node, ", "))
+	b.WriteString("\n")
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			writeTextNode(b, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			writeTextNode(b, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// WriteDot renders the tree as a Graphviz DOT digraph.
+// Countered claims are drawn with a dashed border and claims the local trace disagrees with are drawn in red.
+func WriteDot(w io.Writer, root *Node) error {
+	var b strings.Builder
+	b.WriteString("digraph game {\n")
+	b.WriteString("  node [shape=box, fontname=monospace];\n")
+	writeDotNode(&b, root)
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDotNode(b *strings.Builder, node *Node) {
+	var attrs []string
+	attrs = append(attrs, fmt.Sprintf("label=%q", describe(node, "\n")))
+	if node.Countered() {
+		attrs = append(attrs, "style=dashed")
+	}
+	if node.Agree != nil {
+		if *node.Agree {
+			attrs = append(attrs, "color=darkgreen")
+		} else {
+			attrs = append(attrs, "color=red")
+		}
+	}
+	fmt.Fprintf(b, "  c%d [%s];\n", node.Index, strings.Join(attrs, ", "))
+	for _, child := range node.Children {
+		fmt.Fprintf(b, "  c%d -> c%d [label=%q];\n", node.Index, child.Index, child.Move)
+		writeDotNode(b, child)
+	}
+}
+
+// WriteJSON renders the tree as nested JSON objects.
+func WriteJSON(w io.Writer, root *Node) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+func describe(node *Node, sep string) string {
+	fields := []string{
+		fmt.Sprintf("#%d (%v)", node.Index, node.Move),
+		fmt.Sprintf("Depth: %v", node.Depth),
+		fmt.Sprintf("IndexAtDepth: %v", node.IndexAtDepth),
+		fmt.Sprintf("TraceIndex: %v", node.TraceIndex),
+		fmt.Sprintf("Value: %v", node.Value.Hex()),
+		fmt.Sprintf("Claimant: %v", node.Claimant.Hex()),
+		fmt.Sprintf("Bond: %v", node.Bond),
+		fmt.Sprintf("Clock: %v", node.Clock),
+		fmt.Sprintf("Countered: %v", counteredBy(node.CounteredBy)),
+	}
+	if node.Agree != nil {
+		fields = append(fields, fmt.Sprintf("Agree: %v", *node.Agree))
+	}
+	return strings.Join(fields, sep)
+}
+
+func counteredBy(addr common.Address) string {
+	if addr == (common.Address{}) {
+		return "no"
+	}
+	return addr.Hex()
+}
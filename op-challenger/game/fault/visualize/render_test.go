@@ -0,0 +1,71 @@
+package visualize
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	root, err := BuildTree(testClaims(), maxDepth, map[int]bool{0: false})
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, WriteText(&out, root))
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 4)
+	require.Contains(t, string(lines[0]), "#0 (root), Depth: 0")
+	require.Contains(t, string(lines[0]), "Countered: "+common.Address{0xbb}.Hex())
+	require.Contains(t, string(lines[0]), "Agree: false")
+	require.Contains(t, string(lines[1]), "└── #1 (attack), Depth: 1")
+	require.Contains(t, string(lines[2]), "    ├── #2 (defend), Depth: 2")
+	require.Contains(t, string(lines[2]), "Countered: no")
+	require.NotContains(t, string(lines[2]), "Agree")
+	require.Contains(t, string(lines[3]), "    └── #3 (attack), Depth: 2")
+}
+
+func TestWriteDot(t *testing.T) {
+	root, err := BuildTree(testClaims(), maxDepth, map[int]bool{0: false, 1: true})
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, WriteDot(&out, root))
+	dot := out.String()
+	require.Contains(t, dot, "digraph game {")
+	require.Regexp(t, `c0 \[label="#0 \(root\)\\nDepth: 0.*", style=dashed, color=red\];`, dot)
+	require.Regexp(t, `c1 \[label=".*", color=darkgreen\];`, dot)
+	require.Contains(t, dot, `c0 -> c1 [label="attack"];`)
+	require.Contains(t, dot, `c1 -> c2 [label="defend"];`)
+	require.Contains(t, dot, `c1 -> c3 [label="attack"];`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	root, err := BuildTree(testClaims(), maxDepth, nil)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, WriteJSON(&out, root))
+
+	var decoded Node
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, root.Value, decoded.Value)
+	require.Len(t, decoded.Children, 1)
+	require.Len(t, decoded.Children[0].Children, 2)
+	require.Equal(t, MoveTypeDefend, decoded.Children[0].Children[0].Move)
+	require.Zero(t, decoded.Children[0].Children[0].TraceIndex.Cmp(root.Children[0].Children[0].TraceIndex))
+
+	// Re-encoding the decoded tree produces the same output
+	var reencoded bytes.Buffer
+	require.NoError(t, WriteJSON(&reencoded, &decoded))
+	require.Equal(t, out.String(), reencoded.String())
+}
+
+func TestFormat(t *testing.T) {
+	for _, format := range Formats {
+		var f Format
+		require.NoError(t, f.Set(format.String()))
+		require.Equal(t, format, f)
+	}
+	var f Format
+	require.ErrorContains(t, f.Set("svg"), "unknown format")
+}
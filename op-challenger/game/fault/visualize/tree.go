@@ -0,0 +1,99 @@
+package visualize
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type MoveType string
+
+const (
+	MoveTypeRoot   MoveType = "root"
+	MoveTypeAttack MoveType = "attack"
+	MoveTypeDefend MoveType = "defend"
+)
+
+// Node is a single claim in the game tree, annotated with its position and whether the local trace agrees with it.
+type Node struct {
+	Index        int            `json:"index"`
+	Move         MoveType       `json:"move"`
+	Depth        types.Depth    `json:"depth"`
+	IndexAtDepth *big.Int       `json:"indexAtDepth"`
+	TraceIndex   *big.Int       `json:"traceIndex"`
+	Value        common.Hash    `json:"value"`
+	Claimant     common.Address `json:"claimant"`
+	Bond         *big.Int       `json:"bond"`
+	Clock        uint64         `json:"clock"`
+	CounteredBy  common.Address `json:"counteredBy"`
+	// Agree is nil if agreement with the local trace was not checked
+	Agree    *bool   `json:"agree,omitempty"`
+	Children []*Node `json:"children"`
+}
+
+func (n *Node) Countered() bool {
+	return n.CounteredBy != (common.Address{})
+}
+
+// BuildTree arranges claims into a tree rooted at the root claim.
+// agreement maps claim indices to whether the local trace agrees with the claim and may be nil.
+func BuildTree(claims []types.Claim, maxDepth types.Depth, agreement map[int]bool) (*Node, error) {
+	if len(claims) == 0 {
+		return nil, fmt.Errorf("no claims")
+	}
+	nodes := make([]*Node, len(claims))
+	for i, claim := range claims {
+		node := &Node{
+			Index:        i,
+			Move:         MoveTypeRoot,
+			Depth:        claim.Depth(),
+			IndexAtDepth: claim.IndexAtDepth(),
+			TraceIndex:   claim.TraceIndex(maxDepth),
+			Value:        claim.Value,
+			Claimant:     claim.Claimant,
+			Bond:         claim.Bond,
+			Clock:        claim.Clock,
+			CounteredBy:  claim.CounteredBy,
+			Children:     []*Node{},
+		}
+		if agree, ok := agreement[i]; ok {
+			node.Agree = &agree
+		}
+		nodes[i] = node
+	}
+	for i, claim := range claims {
+		if claim.IsRoot() {
+			continue
+		}
+		if claim.ParentContractIndex < 0 || claim.ParentContractIndex >= i {
+			return nil, fmt.Errorf("claim %v has invalid parent %v", i, claim.ParentContractIndex)
+		}
+		parent := claims[claim.ParentContractIndex]
+		if parent.Attack().ToGIndex().Cmp(claim.ToGIndex()) == 0 {
+			nodes[i].Move = MoveTypeAttack
+		} else {
+			nodes[i].Move = MoveTypeDefend
+		}
+		parentNode := nodes[claim.ParentContractIndex]
+		parentNode.Children = append(parentNode.Children, nodes[i])
+	}
+	return nodes[0], nil
+}
+
+// CheckAgreement uses accessor to determine whether the local trace agrees with each claim in game.
+// Claims that can't be checked are reported via onError and omitted from the result.
+func CheckAgreement(ctx context.Context, accessor types.TraceAccessor, game types.Game, onError func(claim types.Claim, err error)) map[int]bool {
+	agreement := make(map[int]bool)
+	for i, claim := range game.Claims() {
+		expected, err := accessor.Get(ctx, game, claim, claim.Position)
+		if err != nil {
+			onError(claim, err)
+			continue
+		}
+		agreement[i] = expected == claim.Value
+	}
+	return agreement
+}
@@ -0,0 +1,122 @@
+package visualize
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const maxDepth = types.Depth(4)
+
+func testClaims() []types.Claim {
+	root := types.NewPositionFromGIndex(big.NewInt(1))
+	attack := root.Attack()
+	defend := attack.Defend()
+	counterAttack := attack.Attack()
+	return []types.Claim{
+		{
+			ClaimData:           types.ClaimData{Value: common.Hash{0x01}, Position: root, Bond: big.NewInt(10)},
+			Claimant:            common.Address{0xaa},
+			CounteredBy:         common.Address{0xbb},
+			ParentContractIndex: math.MaxUint32,
+		},
+		{
+			ClaimData:           types.ClaimData{Value: common.Hash{0x02}, Position: attack, Bond: big.NewInt(20)},
+			Claimant:            common.Address{0xbb},
+			ContractIndex:       1,
+			ParentContractIndex: 0,
+		},
+		{
+			ClaimData:           types.ClaimData{Value: common.Hash{0x03}, Position: defend, Bond: big.NewInt(30)},
+			Claimant:            common.Address{0xaa},
+			ContractIndex:       2,
+			ParentContractIndex: 1,
+		},
+		{
+			ClaimData:           types.ClaimData{Value: common.Hash{0x04}, Position: counterAttack, Bond: big.NewInt(30)},
+			Claimant:            common.Address{0xaa},
+			ContractIndex:       3,
+			ParentContractIndex: 1,
+		},
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	claims := testClaims()
+	root, err := BuildTree(claims, maxDepth, map[int]bool{0: false, 1: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 0, root.Index)
+	require.Equal(t, MoveTypeRoot, root.Move)
+	require.True(t, root.Countered())
+	require.False(t, *root.Agree)
+	require.Len(t, root.Children, 1)
+
+	attack := root.Children[0]
+	require.Equal(t, 1, attack.Index)
+	require.Equal(t, MoveTypeAttack, attack.Move)
+	require.Equal(t, types.Depth(1), attack.Depth)
+	require.Equal(t, claims[1].TraceIndex(maxDepth), attack.TraceIndex)
+	require.True(t, *attack.Agree)
+	require.Len(t, attack.Children, 2)
+
+	require.Equal(t, 2, attack.Children[0].Index)
+	require.Equal(t, MoveTypeDefend, attack.Children[0].Move)
+	require.Nil(t, attack.Children[0].Agree)
+	require.Equal(t, 3, attack.Children[1].Index)
+	require.Equal(t, MoveTypeAttack, attack.Children[1].Move)
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	t.Run("NoClaims", func(t *testing.T) {
+		_, err := BuildTree(nil, maxDepth, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidParent", func(t *testing.T) {
+		claims := testClaims()
+		claims[1].ParentContractIndex = 2
+		_, err := BuildTree(claims, maxDepth, nil)
+		require.ErrorContains(t, err, "claim 1 has invalid parent 2")
+	})
+}
+
+func TestCheckAgreement(t *testing.T) {
+	claims := testClaims()
+	game := types.NewGameState(claims, maxDepth)
+	accessor := &stubAccessor{
+		values: map[uint64]common.Hash{
+			claims[0].ToGIndex().Uint64(): {0x01},
+			claims[1].ToGIndex().Uint64(): {0xff},
+			claims[2].ToGIndex().Uint64(): {0x03},
+		},
+	}
+	var failed []int
+	agreement := CheckAgreement(context.Background(), accessor, game, func(claim types.Claim, err error) {
+		failed = append(failed, claim.ContractIndex)
+	})
+	require.Equal(t, map[int]bool{0: true, 1: false, 2: true}, agreement)
+	require.Equal(t, []int{3}, failed)
+}
+
+type stubAccessor struct {
+	values map[uint64]common.Hash
+}
+
+func (s *stubAccessor) Get(_ context.Context, _ types.Game, _ types.Claim, pos types.Position) (common.Hash, error) {
+	value, ok := s.values[pos.ToGIndex().Uint64()]
+	if !ok {
+		return common.Hash{}, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (s *stubAccessor) GetStepData(_ context.Context, _ types.Game, _ types.Claim, _ types.Position) ([]byte, []byte, *types.PreimageOracleData, error) {
+	panic("not supported")
+}
@@ -42,6 +42,10 @@ type claimer interface {
 	Schedule(blockNumber uint64, games []types.GameMetadata) error
 }
 
+type gamePolicy interface {
+	Filter(ctx context.Context, games []types.GameMetadata) []types.GameMetadata
+}
+
 type gameMonitor struct {
 	logger           log.Logger
 	clock            RWClock
@@ -52,6 +56,7 @@ type gameMonitor struct {
 	claimer          claimer
 	fetchBlockNumber blockNumberFetcher
 	allowedGames     []common.Address
+	policy           gamePolicy
 	l1HeadsSub       ethereum.Subscription
 	l1Source         *headSource
 	runState         sync.Mutex
@@ -79,6 +84,7 @@ func newGameMonitor(
 	claimer claimer,
 	fetchBlockNumber blockNumberFetcher,
 	allowedGames []common.Address,
+	policy gamePolicy,
 	l1Source MinimalSubscriber,
 ) *gameMonitor {
 	return &gameMonitor{
@@ -91,6 +97,7 @@ func newGameMonitor(
 		claimer:          claimer,
 		fetchBlockNumber: fetchBlockNumber,
 		allowedGames:     allowedGames,
+		policy:           policy,
 		l1Source:         &headSource{inner: l1Source},
 	}
 }
@@ -135,6 +142,7 @@ func (m *gameMonitor) progressGames(ctx context.Context, blockHash common.Hash,
 		}
 		gamesToPlay = append(gamesToPlay, game)
 	}
+	gamesToPlay = m.policy.Filter(ctx, gamesToPlay)
 	if err := m.scheduler.Schedule(gamesToPlay, blockNumber); errors.Is(err, scheduler.ErrBusy) {
 		m.logger.Info("Scheduler still busy with previous update")
 	} else if err != nil {
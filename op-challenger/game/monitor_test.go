@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum"
@@ -170,6 +171,23 @@ func TestMonitorOnlyScheduleSpecifiedGame(t *testing.T) {
 	require.Equal(t, []common.Address{addr2}, sched.Scheduled()[0])
 }
 
+func TestMonitorOnlyScheduleGamesSelectedByPolicy(t *testing.T) {
+	addr1 := common.Address{0xaa}
+	addr2 := common.Address{0xbb}
+	addr3 := common.Address{0xcc}
+	monitor, source, sched, _, _ := setupMonitorTest(t, []common.Address{addr1, addr2})
+	gamePolicy := &stubPolicy{excluded: addr1}
+	monitor.policy = gamePolicy
+	source.games = []types.GameMetadata{newFDG(addr1, 9999), newFDG(addr2, 9999), newFDG(addr3, 9999)}
+
+	require.NoError(t, monitor.progressGames(context.Background(), common.Hash{0x01}, 0))
+
+	require.Len(t, sched.Scheduled(), 1)
+	require.Equal(t, []common.Address{addr2}, sched.Scheduled()[0])
+	// Policy is only applied to games on the allowlist
+	require.Equal(t, []types.GameMetadata{newFDG(addr1, 9999), newFDG(addr2, 9999)}, gamePolicy.received)
+}
+
 func newFDG(proxy common.Address, timestamp uint64) types.GameMetadata {
 	return types.GameMetadata{
 		Proxy:     proxy,
@@ -202,6 +220,7 @@ func setupMonitorTest(
 		mockScheduler,
 		fetchBlockNum,
 		allowedGames,
+		policy.Policies{},
 		mockHeadSource,
 	)
 	return monitor, source, sched, mockHeadSource, preimages
@@ -283,6 +302,22 @@ func (s *stubGameSource) GetGamesAtOrAfter(
 	return s.games, nil
 }
 
+type stubPolicy struct {
+	excluded common.Address
+	received []types.GameMetadata
+}
+
+func (s *stubPolicy) Filter(_ context.Context, games []types.GameMetadata) []types.GameMetadata {
+	s.received = games
+	var selected []types.GameMetadata
+	for _, game := range games {
+		if game.Proxy != s.excluded {
+			selected = append(selected, game)
+		}
+	}
+	return selected
+}
+
 type stubScheduler struct {
 	sync.Mutex
 	scheduled [][]common.Address
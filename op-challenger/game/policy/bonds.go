@@ -0,0 +1,29 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// NewMinBondPolicy creates a Policy that only selects games where the total value of bonds posted is at least
+// minBond. Once a game meets the threshold it remains selected.
+func NewMinBondPolicy(logger log.Logger, creator CreateGameContract, minBond *big.Int) Policy {
+	return newPredicatePolicy(logger, "min-bond", creator, func(ctx context.Context, game GameContract) (bool, bool, error) {
+		claims, err := game.GetAllClaims(ctx)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to load claims: %w", err)
+		}
+		total := big.NewInt(0)
+		for _, claim := range claims {
+			if claim.Bond != nil {
+				total.Add(total, claim.Bond)
+			}
+		}
+		// Bonds are only ever added while a game is in progress so only a positive decision is final.
+		allow := total.Cmp(minBond) >= 0
+		return allow, allow, nil
+	})
+}
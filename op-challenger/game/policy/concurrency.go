@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxConcurrentPolicy limits the number of in progress games played for each game type.
+// Once selected, a game remains selected until it is no longer listed, but stops counting towards the limit
+// once it is resolved.
+type maxConcurrentPolicy struct {
+	logger   log.Logger
+	creator  CreateGameContract
+	max      uint
+	selected map[common.Address]bool // Selected games, mapped to whether the game is known to be resolved
+}
+
+// NewMaxConcurrentPolicy creates a Policy that plays at most max in progress games of each game type.
+// Older games are preferred as they are closer to their deadline.
+func NewMaxConcurrentPolicy(logger log.Logger, creator CreateGameContract, max uint) Policy {
+	return &maxConcurrentPolicy{
+		logger:   logger.New("policy", "max-concurrent"),
+		creator:  creator,
+		max:      max,
+		selected: make(map[common.Address]bool),
+	}
+}
+
+func (p *maxConcurrentPolicy) Filter(ctx context.Context, games []types.GameMetadata) []types.GameMetadata {
+	selected := make(map[common.Address]bool, len(games))
+	active := make(map[uint32]uint)
+	for _, game := range games {
+		resolved, ok := p.selected[game.Proxy]
+		if !ok {
+			continue
+		}
+		if !resolved {
+			resolved = p.isResolved(ctx, game)
+		}
+		selected[game.Proxy] = resolved
+		if !resolved {
+			active[game.GameType]++
+		}
+	}
+
+	candidates := slices.Clone(games)
+	slices.SortStableFunc(candidates, func(a, b types.GameMetadata) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+	for _, game := range candidates {
+		if _, ok := selected[game.Proxy]; ok {
+			continue
+		}
+		if active[game.GameType] >= p.max {
+			continue
+		}
+		status, err := p.status(ctx, game)
+		if err != nil {
+			p.logger.Warn("Failed to load game status", "game", game.Proxy, "err", err)
+			continue
+		}
+		if status != types.GameStatusInProgress {
+			continue
+		}
+		selected[game.Proxy] = false
+		active[game.GameType]++
+	}
+	p.selected = selected
+
+	var allowed []types.GameMetadata
+	for _, game := range games {
+		if _, ok := selected[game.Proxy]; !ok {
+			p.logger.Debug("Skipping game, maximum concurrent games reached", "game", game.Proxy, "gameType", game.GameType)
+			continue
+		}
+		allowed = append(allowed, game)
+	}
+	return allowed
+}
+
+// isResolved reports whether a selected game has been resolved.
+// Games are assumed to still be in progress if their status can't be loaded so the limit isn't exceeded.
+func (p *maxConcurrentPolicy) isResolved(ctx context.Context, game types.GameMetadata) bool {
+	status, err := p.status(ctx, game)
+	if err != nil {
+		p.logger.Warn("Failed to load game status", "game", game.Proxy, "err", err)
+		return false
+	}
+	return status != types.GameStatusInProgress
+}
+
+func (p *maxConcurrentPolicy) status(ctx context.Context, game types.GameMetadata) (types.GameStatus, error) {
+	contract, err := p.creator(game)
+	if err != nil {
+		return 0, err
+	}
+	return contract.GetStatus(ctx)
+}
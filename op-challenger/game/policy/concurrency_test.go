@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrentPolicy(t *testing.T) {
+	newest := types.GameMetadata{GameType: 0, Timestamp: 300, Proxy: common.Address{0x03}}
+	middle := types.GameMetadata{GameType: 0, Timestamp: 200, Proxy: common.Address{0x02}}
+	oldest := types.GameMetadata{GameType: 0, Timestamp: 100, Proxy: common.Address{0x01}}
+	otherType := types.GameMetadata{GameType: 1, Timestamp: 400, Proxy: common.Address{0x04}}
+	// Games are listed newest first, matching the order returned by the factory
+	games := []types.GameMetadata{otherType, newest, middle, oldest}
+
+	setup := func(t *testing.T, max uint) (Policy, *stubContracts) {
+		contracts := newStubContracts()
+		for _, game := range games {
+			contracts.games[game.Proxy] = &stubGameContract{status: types.GameStatusInProgress}
+		}
+		return NewMaxConcurrentPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, max), contracts
+	}
+
+	t.Run("PrefersOldestGamesPerGameType", func(t *testing.T) {
+		policy, _ := setup(t, 2)
+		selected := policy.Filter(context.Background(), games)
+		require.Equal(t, []types.GameMetadata{otherType, middle, oldest}, selected)
+	})
+
+	t.Run("KeepsSelectedGames", func(t *testing.T) {
+		policy, _ := setup(t, 1)
+		require.Equal(t, []types.GameMetadata{otherType, newest}, policy.Filter(context.Background(), []types.GameMetadata{otherType, newest}))
+		// An older game appearing later doesn't displace the game already being played
+		require.Equal(t, []types.GameMetadata{otherType, newest}, policy.Filter(context.Background(), games))
+	})
+
+	t.Run("ResolvedGamesFreeSlot", func(t *testing.T) {
+		policy, contracts := setup(t, 1)
+		require.Equal(t, []types.GameMetadata{otherType, oldest}, policy.Filter(context.Background(), games))
+
+		contracts.games[oldest.Proxy].status = types.GameStatusDefenderWon
+		require.Equal(t, []types.GameMetadata{otherType, middle, oldest}, policy.Filter(context.Background(), games))
+
+		// Status of resolved games isn't reloaded
+		calls := contracts.games[oldest.Proxy].statusCalls
+		policy.Filter(context.Background(), games)
+		require.Equal(t, calls, contracts.games[oldest.Proxy].statusCalls)
+	})
+
+	t.Run("SkipResolvedCandidates", func(t *testing.T) {
+		policy, contracts := setup(t, 1)
+		contracts.games[oldest.Proxy].status = types.GameStatusChallengerWon
+		require.Equal(t, []types.GameMetadata{otherType, middle}, policy.Filter(context.Background(), games))
+	})
+
+	t.Run("AssumeInProgressWhenStatusUnavailable", func(t *testing.T) {
+		policy, contracts := setup(t, 1)
+		require.Equal(t, []types.GameMetadata{otherType, oldest}, policy.Filter(context.Background(), games))
+
+		contracts.games[oldest.Proxy].err = errLoad
+		require.Equal(t, []types.GameMetadata{otherType, oldest}, policy.Filter(context.Background(), games))
+	})
+}
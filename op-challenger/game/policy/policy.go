@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"context"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Policy selects the games the challenger participates in.
+type Policy interface {
+	// Filter returns the subset of games that should be played.
+	// Games that have been selected previously should continue to be selected while they remain in the
+	// supplied list, otherwise the scheduler will discard any progress made on them.
+	Filter(ctx context.Context, games []types.GameMetadata) []types.GameMetadata
+}
+
+type GameContract interface {
+	GetGameMetadata(ctx context.Context) (uint64, common.Hash, types.GameStatus, uint64, error)
+	GetClaim(ctx context.Context, idx uint64) (faultTypes.Claim, error)
+	GetAllClaims(ctx context.Context) ([]faultTypes.Claim, error)
+	GetStatus(ctx context.Context) (types.GameStatus, error)
+}
+
+type CreateGameContract func(game types.GameMetadata) (GameContract, error)
+
+// Policies applies each policy in order, with each policy only considering the games selected by the previous one.
+type Policies []Policy
+
+func (p Policies) Filter(ctx context.Context, games []types.GameMetadata) []types.GameMetadata {
+	for _, policy := range p {
+		games = policy.Filter(ctx, games)
+	}
+	return games
+}
+
+// predicate decides whether a single game should be played.
+// When final is true the decision is cached and not re-evaluated while the game remains in the list.
+// Decisions to play a game are always cached, as the game is joined once it is selected and must stay selected.
+// Decisions not to play a game that are not final are re-evaluated, so the game may be selected later.
+type predicate func(ctx context.Context, game GameContract) (allow bool, final bool, err error)
+
+// predicatePolicy is a Policy that considers each game independently.
+type predicatePolicy struct {
+	logger    log.Logger
+	name      string
+	creator   CreateGameContract
+	check     predicate
+	decisions map[common.Address]bool
+}
+
+func newPredicatePolicy(logger log.Logger, name string, creator CreateGameContract, check predicate) *predicatePolicy {
+	return &predicatePolicy{
+		logger:    logger.New("policy", name),
+		name:      name,
+		creator:   creator,
+		check:     check,
+		decisions: make(map[common.Address]bool),
+	}
+}
+
+func (p *predicatePolicy) Filter(ctx context.Context, games []types.GameMetadata) []types.GameMetadata {
+	// Rebuild the decision cache on each call so games that are no longer listed are dropped.
+	decisions := make(map[common.Address]bool, len(games))
+	var allowed []types.GameMetadata
+	for _, game := range games {
+		allow, ok := p.decisions[game.Proxy]
+		if ok {
+			decisions[game.Proxy] = allow
+		} else {
+			var final bool
+			var err error
+			allow, final, err = p.evaluate(ctx, game)
+			if err != nil {
+				p.logger.Warn("Failed to evaluate game policy", "game", game.Proxy, "err", err)
+				continue
+			}
+			if final || allow {
+				decisions[game.Proxy] = allow
+			}
+		}
+		if !allow {
+			p.logger.Debug("Skipping game excluded by policy", "game", game.Proxy)
+			continue
+		}
+		allowed = append(allowed, game)
+	}
+	p.decisions = decisions
+	return allowed
+}
+
+func (p *predicatePolicy) evaluate(ctx context.Context, game types.GameMetadata) (bool, bool, error) {
+	contract, err := p.creator(game)
+	if err != nil {
+		return false, false, err
+	}
+	return p.check(ctx, contract)
+}
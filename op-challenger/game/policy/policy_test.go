@@ -0,0 +1,253 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var errLoad = errors.New("boom")
+
+func TestPolicies_AppliesInOrder(t *testing.T) {
+	games := []types.GameMetadata{{Proxy: common.Address{0x01}}, {Proxy: common.Address{0x02}}, {Proxy: common.Address{0x03}}}
+	first := &stubPolicy{exclude: common.Address{0x01}}
+	second := &stubPolicy{exclude: common.Address{0x02}}
+	selected := Policies{first, second}.Filter(context.Background(), games)
+	require.Equal(t, []types.GameMetadata{games[2]}, selected)
+	require.Equal(t, games[1:], second.received)
+}
+
+func TestPredicatePolicy(t *testing.T) {
+	game := types.GameMetadata{Proxy: common.Address{0xaa}}
+
+	t.Run("CachesFinalDecision", func(t *testing.T) {
+		contracts := newStubContracts()
+		calls := 0
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			calls++
+			return true, true, nil
+		})
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("ReevaluatesNonFinalDecision", func(t *testing.T) {
+		contracts := newStubContracts()
+		calls := 0
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			calls++
+			return false, false, nil
+		})
+		require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{game}))
+		require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{game}))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("KeepsSelectedGame", func(t *testing.T) {
+		contracts := newStubContracts()
+		calls := 0
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			calls++
+			// Allow the game at first, but not once re-evaluated
+			return calls == 1, false, nil
+		})
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("SelectsGameExcludedBefore", func(t *testing.T) {
+		contracts := newStubContracts()
+		calls := 0
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			calls++
+			// Exclude the game at first, but not once re-evaluated
+			return calls > 1, false, nil
+		})
+		require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{game}))
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Len(t, policy.Filter(context.Background(), []types.GameMetadata{game}), 1)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("DropsDecisionsForUnlistedGames", func(t *testing.T) {
+		contracts := newStubContracts()
+		calls := 0
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			calls++
+			return true, true, nil
+		})
+		policy.Filter(context.Background(), []types.GameMetadata{game})
+		policy.Filter(context.Background(), nil)
+		require.Empty(t, policy.decisions)
+		policy.Filter(context.Background(), []types.GameMetadata{game})
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("ExcludeGameOnError", func(t *testing.T) {
+		contracts := newStubContracts()
+		policy := newPredicatePolicy(testlog.Logger(t, log.LvlDebug), "test", contracts.Create, func(ctx context.Context, _ GameContract) (bool, bool, error) {
+			return true, true, errLoad
+		})
+		require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{game}))
+		require.Empty(t, policy.decisions)
+	})
+}
+
+func TestProposerPolicy(t *testing.T) {
+	proposer := common.Address{0xaa}
+	allowedGame := types.GameMetadata{Proxy: common.Address{0x01}}
+	otherGame := types.GameMetadata{Proxy: common.Address{0x02}}
+	failingGame := types.GameMetadata{Proxy: common.Address{0x03}}
+	contracts := newStubContracts()
+	contracts.games[allowedGame.Proxy] = &stubGameContract{claims: []faultTypes.Claim{{Claimant: proposer}}}
+	contracts.games[otherGame.Proxy] = &stubGameContract{claims: []faultTypes.Claim{{Claimant: common.Address{0xbb}}}}
+	contracts.games[failingGame.Proxy] = &stubGameContract{err: errLoad}
+
+	policy := NewProposerPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, []common.Address{proposer})
+	selected := policy.Filter(context.Background(), []types.GameMetadata{allowedGame, otherGame, failingGame})
+	require.Equal(t, []types.GameMetadata{allowedGame}, selected)
+}
+
+func TestMinBondPolicy(t *testing.T) {
+	game := types.GameMetadata{Proxy: common.Address{0x01}}
+	contract := &stubGameContract{claims: []faultTypes.Claim{{ClaimData: faultTypes.ClaimData{Bond: big.NewInt(5)}}, {ClaimData: faultTypes.ClaimData{Bond: big.NewInt(4)}}}}
+	contracts := newStubContracts()
+	contracts.games[game.Proxy] = contract
+	policy := NewMinBondPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, big.NewInt(10))
+
+	require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{game}))
+
+	contract.claims = append(contract.claims, faultTypes.Claim{ClaimData: faultTypes.ClaimData{Bond: big.NewInt(1)}})
+	require.Equal(t, []types.GameMetadata{game}, policy.Filter(context.Background(), []types.GameMetadata{game}))
+
+	// Remains selected once the threshold is reached
+	contract.err = errLoad
+	require.Equal(t, []types.GameMetadata{game}, policy.Filter(context.Background(), []types.GameMetadata{game}))
+}
+
+func TestDisagreeingRootPolicy(t *testing.T) {
+	validRoot := common.Hash{0x01}
+	agreeGame := types.GameMetadata{Proxy: common.Address{0x01}}
+	disagreeGame := types.GameMetadata{Proxy: common.Address{0x02}}
+	unsafeBlockGame := types.GameMetadata{Proxy: common.Address{0x03}}
+	futureBlockGame := types.GameMetadata{Proxy: common.Address{0x04}}
+	games := []types.GameMetadata{agreeGame, disagreeGame, unsafeBlockGame, futureBlockGame}
+	contracts := newStubContracts()
+	contracts.games[agreeGame.Proxy] = &stubGameContract{l2BlockNum: 10, rootClaim: validRoot}
+	contracts.games[disagreeGame.Proxy] = &stubGameContract{l2BlockNum: 10, rootClaim: common.Hash{0xba, 0xd0}}
+	contracts.games[unsafeBlockGame.Proxy] = &stubGameContract{l2BlockNum: 15, rootClaim: validRoot}
+	contracts.games[futureBlockGame.Proxy] = &stubGameContract{l2BlockNum: 1000, rootClaim: validRoot}
+	rollup := &stubRollupClient{outputs: map[uint64]common.Hash{10: validRoot, 15: validRoot}, safeHead: 12}
+
+	policy := NewDisagreeingRootPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, rollup)
+	selected := policy.Filter(context.Background(), games)
+	require.Equal(t, []types.GameMetadata{disagreeGame, unsafeBlockGame, futureBlockGame}, selected)
+
+	// Games that were selected stay selected once their block is safe, even if the root claim agrees
+	rollup.safeHead = 20
+	selected = policy.Filter(context.Background(), games)
+	require.Equal(t, []types.GameMetadata{disagreeGame, unsafeBlockGame, futureBlockGame}, selected)
+
+	// Games seen for the first time once the block is safe are compared to its output
+	policy = NewDisagreeingRootPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, rollup)
+	selected = policy.Filter(context.Background(), games)
+	require.Equal(t, []types.GameMetadata{disagreeGame, futureBlockGame}, selected)
+
+	// Games whose root claim cannot be checked are not selected
+	rollup.err = errLoad
+	policy = NewDisagreeingRootPolicy(testlog.Logger(t, log.LvlDebug), contracts.Create, rollup)
+	require.Empty(t, policy.Filter(context.Background(), []types.GameMetadata{unsafeBlockGame, futureBlockGame}))
+}
+
+type stubPolicy struct {
+	exclude  common.Address
+	received []types.GameMetadata
+}
+
+func (s *stubPolicy) Filter(_ context.Context, games []types.GameMetadata) []types.GameMetadata {
+	s.received = games
+	var selected []types.GameMetadata
+	for _, game := range games {
+		if game.Proxy != s.exclude {
+			selected = append(selected, game)
+		}
+	}
+	return selected
+}
+
+type stubContracts struct {
+	games map[common.Address]*stubGameContract
+}
+
+func newStubContracts() *stubContracts {
+	return &stubContracts{games: make(map[common.Address]*stubGameContract)}
+}
+
+func (s *stubContracts) Create(game types.GameMetadata) (GameContract, error) {
+	contract, ok := s.games[game.Proxy]
+	if !ok {
+		return &stubGameContract{}, nil
+	}
+	return contract, nil
+}
+
+type stubGameContract struct {
+	l2BlockNum  uint64
+	rootClaim   common.Hash
+	status      types.GameStatus
+	claims      []faultTypes.Claim
+	err         error
+	statusCalls int
+}
+
+func (s *stubGameContract) GetGameMetadata(_ context.Context) (uint64, common.Hash, types.GameStatus, uint64, error) {
+	return s.l2BlockNum, s.rootClaim, s.status, 0, s.err
+}
+
+func (s *stubGameContract) GetClaim(_ context.Context, idx uint64) (faultTypes.Claim, error) {
+	if s.err != nil {
+		return faultTypes.Claim{}, s.err
+	}
+	return s.claims[idx], nil
+}
+
+func (s *stubGameContract) GetAllClaims(_ context.Context) ([]faultTypes.Claim, error) {
+	return s.claims, s.err
+}
+
+func (s *stubGameContract) GetStatus(_ context.Context) (types.GameStatus, error) {
+	s.statusCalls++
+	return s.status, s.err
+}
+
+type stubRollupClient struct {
+	outputs  map[uint64]common.Hash
+	safeHead uint64
+	err      error
+}
+
+func (s *stubRollupClient) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	output, ok := s.outputs[blockNum]
+	if !ok {
+		return nil, errLoad
+	}
+	return &eth.OutputResponse{OutputRoot: eth.Bytes32(output)}, nil
+}
+
+func (s *stubRollupClient) SyncStatus(_ context.Context) (*eth.SyncStatus, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &eth.SyncStatus{SafeL2: eth.L2BlockRef{Number: s.safeHead}}, nil
+}
@@ -0,0 +1,23 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// NewProposerPolicy creates a Policy that only selects games where the root claim was made by one of the
+// specified proposers.
+func NewProposerPolicy(logger log.Logger, creator CreateGameContract, proposers []common.Address) Policy {
+	return newPredicatePolicy(logger, "proposer", creator, func(ctx context.Context, game GameContract) (bool, bool, error) {
+		root, err := game.GetClaim(ctx, 0)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to load root claim: %w", err)
+		}
+		// The root claimant never changes so the decision is final.
+		return slices.Contains(proposers, root.Claimant), true, nil
+	})
+}
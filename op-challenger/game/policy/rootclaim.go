@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type OutputRollupClient interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+}
+
+// NewDisagreeingRootPolicy creates a Policy that only selects games where the root claim does not match the
+// output root reported by the trusted rollup node.
+// Root claims for blocks beyond the safe head of the rollup node cannot yet be derived from L1 data, so are
+// considered to disagree, like the dispute monitor does. Those games stay selected once the block is safe,
+// even if the root claim turns out to agree, as the challenger has joined them.
+func NewDisagreeingRootPolicy(logger log.Logger, creator CreateGameContract, rollupClient OutputRollupClient) Policy {
+	return newPredicatePolicy(logger, "disagreeing-root", creator, func(ctx context.Context, game GameContract) (bool, bool, error) {
+		l2BlockNum, rootClaim, _, _, err := game.GetGameMetadata(ctx)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to load game metadata: %w", err)
+		}
+		status, err := rollupClient.SyncStatus(ctx)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to get sync status: %w", err)
+		}
+		if l2BlockNum > status.SafeL2.Number {
+			logger.Debug("Root claim is for a block beyond the safe head", "blockNum", l2BlockNum, "safeHead", status.SafeL2.Number)
+			return true, false, nil
+		}
+		output, err := rollupClient.OutputAtBlock(ctx, l2BlockNum)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to get output at block %v: %w", l2BlockNum, err)
+		}
+		// The root claim never changes and the output of a safe block is derived from L1, so the decision is final.
+		return common.Hash(output.OutputRoot) != rootClaim, true, nil
+	})
+}
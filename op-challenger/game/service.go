@@ -18,8 +18,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
 	"github.com/ethereum-optimism/optimism/op-challenger/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -43,7 +45,7 @@ type Service struct {
 	preimages *keccak.LargePreimageScheduler
 
 	txMgr    *txmgr.SimpleTxManager
-	txSender *sender.TxSender
+	txSender types.TxSender
 
 	cl *clock.SimpleClock
 
@@ -51,6 +53,7 @@ type Service struct {
 
 	factoryContract *contracts.DisputeGameFactoryContract
 	registry        *registry.GameTypeRegistry
+	policy          policy.Policies
 	rollupClient    *sources.RollupClient
 
 	l1Client   *ethclient.Client
@@ -105,7 +108,7 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.registerGameTypes(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to register game types: %w", err)
 	}
-	if err := s.initBondClaims(); err != nil {
+	if err := s.initBondClaims(cfg); err != nil {
 		return fmt.Errorf("failed to init bond claiming: %w", err)
 	}
 	if err := s.initScheduler(cfg); err != nil {
@@ -115,6 +118,7 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 		return fmt.Errorf("failed to init large preimage scheduler: %w", err)
 	}
 
+	s.initPolicy(cfg)
 	s.initMonitor(cfg)
 
 	s.metrics.RecordInfo(version.SimpleWithMeta)
@@ -128,6 +132,11 @@ func (s *Service) initTxManager(ctx context.Context, cfg *config.Config) error {
 		return fmt.Errorf("failed to create the transaction manager: %w", err)
 	}
 	s.txMgr = txMgr
+	if cfg.DryRun {
+		s.logger.Warn("Running in dry run mode, no transactions will be sent")
+		s.txSender = sender.NewDryRunTxSender(s.logger, s.metrics, txMgr.From())
+		return nil
+	}
 	s.txSender = sender.NewTxSender(ctx, s.logger, txMgr, cfg.MaxPendingTx)
 	return nil
 }
@@ -196,8 +205,8 @@ func (s *Service) initFactoryContract(cfg *config.Config) error {
 	return nil
 }
 
-func (s *Service) initBondClaims() error {
-	claimer := claims.NewBondClaimer(s.logger, s.metrics, s.registry.CreateBondContract, s.txSender)
+func (s *Service) initBondClaims(cfg *config.Config) error {
+	claimer := claims.NewBondClaimer(s.logger, s.metrics, s.registry.CreateBondContract, s.txSender, cfg.DryRun)
 	s.claimer = claims.NewBondClaimScheduler(s.logger, s.metrics, claimer)
 	return nil
 }
@@ -240,14 +249,36 @@ func (s *Service) initLargePreimages() error {
 	return nil
 }
 
+func (s *Service) initPolicy(cfg *config.Config) {
+	caller := batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize)
+	createContract := func(game types.GameMetadata) (policy.GameContract, error) {
+		return contracts.NewFaultDisputeGameContract(game.Proxy, caller)
+	}
+	// Cheaper policies are applied first so later ones consider fewer games.
+	// The concurrency limit is applied last so it only counts games selected by the other policies.
+	if len(cfg.PolicyProposers) > 0 {
+		s.policy = append(s.policy, policy.NewProposerPolicy(s.logger, createContract, cfg.PolicyProposers))
+	}
+	if cfg.PolicyDisagreeingRoots {
+		s.policy = append(s.policy, policy.NewDisagreeingRootPolicy(s.logger, createContract, s.rollupClient))
+	}
+	if cfg.PolicyMinBond != nil {
+		s.policy = append(s.policy, policy.NewMinBondPolicy(s.logger, createContract, cfg.PolicyMinBond))
+	}
+	if cfg.PolicyMaxGamesPerType > 0 {
+		s.policy = append(s.policy, policy.NewMaxConcurrentPolicy(s.logger, createContract, cfg.PolicyMaxGamesPerType))
+	}
+}
+
 func (s *Service) initMonitor(cfg *config.Config) {
-	s.monitor = newGameMonitor(s.logger, s.cl, s.factoryContract, s.sched, s.preimages, cfg.GameWindow, s.claimer, s.l1Client.BlockNumber, cfg.GameAllowlist, s.pollClient)
+	s.monitor = newGameMonitor(s.logger, s.cl, s.factoryContract, s.sched, s.preimages, cfg.GameWindow, s.claimer, s.l1Client.BlockNumber, cfg.GameAllowlist, s.policy, s.pollClient)
 }
 
 func (s *Service) Start(ctx context.Context) error {
 	s.logger.Info("starting scheduler")
 	s.sched.Start(ctx)
 	s.preimages.Start(ctx)
+	s.claimer.Start(ctx)
 	s.logger.Info("starting monitoring")
 	s.monitor.StartMonitoring()
 	s.logger.Info("challenger game service start completed")
@@ -270,6 +301,11 @@ func (s *Service) Stop(ctx context.Context) error {
 	if s.monitor != nil {
 		s.monitor.StopMonitoring()
 	}
+	if s.claimer != nil {
+		if err := s.claimer.Close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close claimer: %w", err))
+		}
+	}
 	if s.faultGamesCloser != nil {
 		s.faultGamesCloser()
 	}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum/go-ethereum/common"
@@ -39,6 +40,9 @@ type Metricer interface {
 
 	RecordBondClaimFailed()
 	RecordBondClaimed(amount uint64)
+	RecordCreditUnclaimed(amount *big.Int)
+
+	RecordDryRunTx(purpose string)
 
 	RecordGamesStatus(inProgress, defenderWon, challengerWon int)
 
@@ -70,6 +74,9 @@ type Metrics struct {
 
 	bondClaimFailures prometheus.Counter
 	bondsClaimed      prometheus.Counter
+	creditUnclaimed   prometheus.Gauge
+
+	dryRunTxs prometheus.CounterVec
 
 	preimageChallenged      prometheus.Counter
 	preimageChallengeFailed prometheus.Counter
@@ -151,6 +158,18 @@ func NewMetrics() *Metrics {
 			Name:      "bonds",
 			Help:      "Number of bonds claimed by the challenge agent",
 		}),
+		creditUnclaimed: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "credit_unclaimed",
+			Help:      "Total credit (in ether) available to the challenge agent that has not yet been claimed",
+		}),
+		dryRunTxs: *factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "dry_run_txs",
+			Help:      "Number of transactions that would have been sent if not running in dry run mode",
+		}, []string{
+			"purpose",
+		}),
 		preimageChallenged: factory.NewCounter(prometheus.CounterOpts{
 			Namespace: Namespace,
 			Name:      "preimage_challenged",
@@ -233,6 +252,14 @@ func (m *Metrics) RecordBondClaimed(amount uint64) {
 	m.bondsClaimed.Add(float64(amount))
 }
 
+func (m *Metrics) RecordCreditUnclaimed(amount *big.Int) {
+	m.creditUnclaimed.Set(opmetrics.WeiToEther(amount))
+}
+
+func (m *Metrics) RecordDryRunTx(purpose string) {
+	m.dryRunTxs.WithLabelValues(purpose).Inc()
+}
+
 func (m *Metrics) RecordCannonExecutionTime(t float64) {
 	m.cannonExecutionTime.Observe(t)
 }
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -31,8 +32,11 @@ func (*NoopMetricsImpl) RecordActedL1Block(_ uint64) {}
 func (*NoopMetricsImpl) RecordPreimageChallenged()      {}
 func (*NoopMetricsImpl) RecordPreimageChallengeFailed() {}
 
-func (*NoopMetricsImpl) RecordBondClaimFailed()   {}
-func (*NoopMetricsImpl) RecordBondClaimed(uint64) {}
+func (*NoopMetricsImpl) RecordBondClaimFailed()         {}
+func (*NoopMetricsImpl) RecordBondClaimed(uint64)       {}
+func (*NoopMetricsImpl) RecordCreditUnclaimed(*big.Int) {}
+
+func (*NoopMetricsImpl) RecordDryRunTx(string) {}
 
 func (*NoopMetricsImpl) RecordCannonExecutionTime(t float64) {}
 
@@ -0,0 +1,49 @@
+package sender
+
+import (
+	"errors"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrDryRun is returned for every transaction submitted to a DryRunTxSender.
+var ErrDryRun = errors.New("dry run: transaction not sent")
+
+type DryRunMetrics interface {
+	RecordDryRunTx(purpose string)
+}
+
+// DryRunTxSender records the transactions the challenger would send instead of sending them.
+// Each transaction is logged and counted in metrics, then reported as not sent with ErrDryRun.
+type DryRunTxSender struct {
+	log     log.Logger
+	metrics DryRunMetrics
+	from    common.Address
+}
+
+func NewDryRunTxSender(logger log.Logger, m DryRunMetrics, from common.Address) *DryRunTxSender {
+	return &DryRunTxSender{
+		log:     logger,
+		metrics: m,
+		from:    from,
+	}
+}
+
+func (s *DryRunTxSender) From() common.Address {
+	return s.from
+}
+
+// SendAndWait records txs without sending them. No receipts are available so the returned slice contains only nil
+// receipts and the error is always ErrDryRun.
+func (s *DryRunTxSender) SendAndWait(txPurpose string, txs ...txmgr.TxCandidate) ([]*types.Receipt, error) {
+	for _, tx := range txs {
+		s.log.Info("Dry run: not sending transaction",
+			"purpose", txPurpose, "to", tx.To, "value", tx.Value, "data", hexutil.Bytes(tx.TxData))
+		s.metrics.RecordDryRunTx(txPurpose)
+	}
+	return make([]*types.Receipt, len(txs)), ErrDryRun
+}
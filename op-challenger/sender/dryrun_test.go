@@ -0,0 +1,37 @@
+package sender
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunSendAndWait(t *testing.T) {
+	from := common.Address{0xaa}
+	m := &stubDryRunMetrics{}
+	sender := NewDryRunTxSender(testlog.Logger(t, log.LevelInfo), m, from)
+	require.Equal(t, from, sender.From())
+
+	to := common.Address{0xbb}
+	rcpts, err := sender.SendAndWait("testing",
+		txmgr.TxCandidate{To: &to, TxData: []byte{1}, Value: big.NewInt(5)},
+		txmgr.TxCandidate{To: &to, TxData: []byte{2}})
+	require.ErrorIs(t, err, ErrDryRun)
+	require.Len(t, rcpts, 2)
+	require.Nil(t, rcpts[0])
+	require.Nil(t, rcpts[1])
+	require.Equal(t, []string{"testing", "testing"}, m.purposes)
+}
+
+type stubDryRunMetrics struct {
+	purposes []string
+}
+
+func (s *stubDryRunMetrics) RecordDryRunTx(purpose string) {
+	s.purposes = append(s.purposes, purpose)
+}
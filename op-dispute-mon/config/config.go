@@ -3,27 +3,58 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 )
 
 var (
-	ErrMissingL1EthRPC = errors.New("missing l1 eth rpc url")
+	ErrMissingL1EthRPC           = errors.New("missing l1 eth rpc url")
+	ErrMissingGameFactoryAddress = errors.New("missing game factory address")
+	ErrMissingRollupRpc          = errors.New("missing rollup rpc url")
+	ErrInvalidMonitorInterval    = errors.New("monitor interval must be greater than zero")
+)
+
+const (
+	// DefaultGameWindow is the default maximum time duration in the past
+	// to look for games to monitor. The default value is 11 days, which
+	// is a 4 day resolution buffer plus the 7 day game finalization window.
+	DefaultGameWindow = time.Duration(11 * 24 * time.Hour)
+	// DefaultMonitorInterval is the default interval at which games are reloaded and metrics updated.
+	DefaultMonitorInterval = time.Second * 30
+	// DefaultNearDeadlineWindow is the default time before a game's resolution deadline
+	// at which it is reported as nearing its deadline.
+	DefaultNearDeadlineWindow = time.Duration(6 * time.Hour)
 )
 
 // Config is a well typed config that is parsed from the CLI params.
 // It also contains config options for auxiliary services.
 type Config struct {
-	L1EthRpc string // L1 RPC Url
+	L1EthRpc           string         // L1 RPC Url
+	GameFactoryAddress common.Address // Address of the dispute game factory
+	RollupRpc          string         // The rollup node RPC URL used to validate root claims
+
+	MonitorInterval    time.Duration // Frequency to check for new games to monitor.
+	GameWindow         time.Duration // Maximum window to look for games to monitor.
+	NearDeadlineWindow time.Duration // Time before a game's deadline at which it is considered near the deadline.
 
 	MetricsConfig opmetrics.CLIConfig
 	PprofConfig   oppprof.CLIConfig
 }
 
-func NewConfig(l1EthRpc string) Config {
+func NewConfig(gameFactoryAddress common.Address, l1EthRpc string, rollupRpc string) Config {
 	return Config{
-		L1EthRpc:      l1EthRpc,
+		L1EthRpc:           l1EthRpc,
+		GameFactoryAddress: gameFactoryAddress,
+		RollupRpc:          rollupRpc,
+
+		MonitorInterval:    DefaultMonitorInterval,
+		GameWindow:         DefaultGameWindow,
+		NearDeadlineWindow: DefaultNearDeadlineWindow,
+
 		MetricsConfig: opmetrics.DefaultCLIConfig(),
 		PprofConfig:   oppprof.DefaultCLIConfig(),
 	}
@@ -33,6 +64,15 @@ func (c Config) Check() error {
 	if c.L1EthRpc == "" {
 		return ErrMissingL1EthRPC
 	}
+	if c.GameFactoryAddress == (common.Address{}) {
+		return ErrMissingGameFactoryAddress
+	}
+	if c.RollupRpc == "" {
+		return ErrMissingRollupRpc
+	}
+	if c.MonitorInterval <= 0 {
+		return ErrInvalidMonitorInterval
+	}
 	if err := c.MetricsConfig.Check(); err != nil {
 		return fmt.Errorf("metrics config: %w", err)
 	}
@@ -3,15 +3,22 @@ package config
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
 var (
-	validL1EthRpc = "http://localhost:8545"
+	validL1EthRpc           = "http://localhost:8545"
+	validGameFactoryAddress = common.Address{0x23}
+	validRollupRpc          = "http://localhost:8555"
 )
 
 func validConfig() Config {
-	return NewConfig(validL1EthRpc)
+	return NewConfig(validGameFactoryAddress, validL1EthRpc, validRollupRpc)
+}
+
+func TestValidConfigIsValid(t *testing.T) {
+	require.NoError(t, validConfig().Check())
 }
 
 func TestL1EthRpcRequired(t *testing.T) {
@@ -19,3 +26,21 @@ func TestL1EthRpcRequired(t *testing.T) {
 	config.L1EthRpc = ""
 	require.ErrorIs(t, config.Check(), ErrMissingL1EthRPC)
 }
+
+func TestGameFactoryAddressRequired(t *testing.T) {
+	config := validConfig()
+	config.GameFactoryAddress = common.Address{}
+	require.ErrorIs(t, config.Check(), ErrMissingGameFactoryAddress)
+}
+
+func TestRollupRpcRequired(t *testing.T) {
+	config := validConfig()
+	config.RollupRpc = ""
+	require.ErrorIs(t, config.Check(), ErrMissingRollupRpc)
+}
+
+func TestMonitorIntervalRequired(t *testing.T) {
+	config := validConfig()
+	config.MonitorInterval = 0
+	require.ErrorIs(t, config.Check(), ErrInvalidMonitorInterval)
+}
@@ -27,16 +27,51 @@ var (
 		Usage:   "HTTP provider URL for L1.",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
+	FactoryAddressFlag = &cli.StringFlag{
+		Name:    "game-factory-address",
+		Usage:   "Address of the fault game factory contract.",
+		EnvVars: prefixEnvVars("GAME_FACTORY_ADDRESS"),
+	}
+	RollupRpcFlag = &cli.StringFlag{
+		Name:    "rollup-rpc",
+		Usage:   "HTTP provider URL for the rollup node",
+		EnvVars: prefixEnvVars("ROLLUP_RPC"),
+	}
 	// Optional Flags
+	MonitorIntervalFlag = &cli.DurationFlag{
+		Name:    "monitor-interval",
+		Usage:   "The interval at which the dispute monitor will check for new games to monitor.",
+		EnvVars: prefixEnvVars("MONITOR_INTERVAL"),
+		Value:   config.DefaultMonitorInterval,
+	}
+	GameWindowFlag = &cli.DurationFlag{
+		Name: "game-window",
+		Usage: "The time window which the monitor will consider games to report on. " +
+			"This should include a bond claiming buffer for games outside the maximum game duration.",
+		EnvVars: prefixEnvVars("GAME_WINDOW"),
+		Value:   config.DefaultGameWindow,
+	}
+	NearDeadlineWindowFlag = &cli.DurationFlag{
+		Name:    "near-deadline-window",
+		Usage:   "The time before an in progress game's resolution deadline at which it is reported as nearing the deadline.",
+		EnvVars: prefixEnvVars("NEAR_DEADLINE_WINDOW"),
+		Value:   config.DefaultNearDeadlineWindow,
+	}
 )
 
 // requiredFlags are checked by [CheckRequired]
 var requiredFlags = []cli.Flag{
 	L1EthRpcFlag,
+	FactoryAddressFlag,
+	RollupRpcFlag,
 }
 
 // optionalFlags is a list of unchecked cli flags
-var optionalFlags = []cli.Flag{}
+var optionalFlags = []cli.Flag{
+	MonitorIntervalFlag,
+	GameWindowFlag,
+	NearDeadlineWindowFlag,
+}
 
 func init() {
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
@@ -64,11 +99,23 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		return nil, err
 	}
 
+	gameFactoryAddress, err := opservice.ParseAddress(ctx.String(FactoryAddressFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+
 	metricsConfig := opmetrics.ReadCLIConfig(ctx)
 	pprofConfig := oppprof.ReadCLIConfig(ctx)
 
 	return &config.Config{
-		L1EthRpc:      ctx.String(L1EthRpcFlag.Name),
+		L1EthRpc:           ctx.String(L1EthRpcFlag.Name),
+		GameFactoryAddress: gameFactoryAddress,
+		RollupRpc:          ctx.String(RollupRpcFlag.Name),
+
+		MonitorInterval:    ctx.Duration(MonitorIntervalFlag.Name),
+		GameWindow:         ctx.Duration(GameWindowFlag.Name),
+		NearDeadlineWindow: ctx.Duration(NearDeadlineWindowFlag.Name),
+
 		MetricsConfig: metricsConfig,
 		PprofConfig:   pprofConfig,
 	}, nil
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,10 +16,72 @@ import (
 
 const Namespace = "op_dispute_mon"
 
+type GameAgreementStatus uint8
+
+const (
+	// In progress, forecasting the result if no further moves are made
+	AgreeDefenderAhead GameAgreementStatus = iota
+	DisagreeDefenderAhead
+	AgreeChallengerAhead
+	DisagreeChallengerAhead
+
+	// Completed
+	AgreeDefenderWins
+	DisagreeDefenderWins
+	AgreeChallengerWins
+	DisagreeChallengerWins
+)
+
+// GameAgreementStatuses lists all agreement statuses so that each can be reported, including when the count is zero.
+var GameAgreementStatuses = []GameAgreementStatus{
+	AgreeDefenderAhead,
+	DisagreeDefenderAhead,
+	AgreeChallengerAhead,
+	DisagreeChallengerAhead,
+	AgreeDefenderWins,
+	DisagreeDefenderWins,
+	AgreeChallengerWins,
+	DisagreeChallengerWins,
+}
+
+func (s GameAgreementStatus) String() string {
+	switch s {
+	case AgreeDefenderAhead:
+		return "agree_defender_ahead"
+	case DisagreeDefenderAhead:
+		return "disagree_defender_ahead"
+	case AgreeChallengerAhead:
+		return "agree_challenger_ahead"
+	case DisagreeChallengerAhead:
+		return "disagree_challenger_ahead"
+	case AgreeDefenderWins:
+		return "agree_defender_wins"
+	case DisagreeDefenderWins:
+		return "disagree_defender_wins"
+	case AgreeChallengerWins:
+		return "agree_challenger_wins"
+	case DisagreeChallengerWins:
+		return "disagree_challenger_wins"
+	default:
+		return "unknown"
+	}
+}
+
 type Metricer interface {
 	RecordInfo(version string)
 	RecordUp()
 
+	RecordGamesStatus(inProgress, defenderWon, challengerWon int)
+	RecordGamesNearDeadline(count int)
+
+	RecordGameAgreement(status GameAgreementStatus, count int)
+	RecordGameForecasts(forecasts map[common.Address]GameAgreementStatus)
+	RecordGamesUnverified(count int)
+
+	RecordFailedEnrichments(data string, count int)
+
+	RecordBonds(posted, atStake, credit map[common.Address]*big.Int)
+
 	caching.Metrics
 }
 
@@ -34,6 +97,18 @@ type Metrics struct {
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
+
+	trackedGames      prometheus.GaugeVec
+	gamesNearDeadline prometheus.Gauge
+	gamesAgreement    prometheus.GaugeVec
+	gameForecasts     prometheus.GaugeVec
+	gamesUnverified   prometheus.Gauge
+
+	failedEnrichments prometheus.GaugeVec
+
+	bondsPosted     prometheus.GaugeVec
+	bondsAtStake    prometheus.GaugeVec
+	unclaimedCredit prometheus.GaugeVec
 }
 
 func (m *Metrics) Registry() *prometheus.Registry {
@@ -65,6 +140,69 @@ func NewMetrics() *Metrics {
 			Name:      "up",
 			Help:      "1 if the op-challenger has finished starting up",
 		}),
+		trackedGames: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "tracked_games",
+			Help:      "Number of games being tracked by the monitor",
+		}, []string{
+			"status",
+		}),
+		gamesNearDeadline: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_near_deadline",
+			Help:      "Number of in progress games that are at or near their resolution deadline",
+		}),
+		gamesAgreement: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_agreement",
+			Help:      "Number of games broken down by whether the result is the expected outcome",
+		}, []string{
+			"result_correctness",
+			"completion",
+			"status",
+		}),
+		gameForecasts: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "game_forecast",
+			Help:      "Set to 1 for each in progress game, labelled with the outcome forecast if no further moves are made and whether that is the expected outcome",
+		}, []string{
+			"game",
+			"forecast",
+			"result_correctness",
+		}),
+		gamesUnverified: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_unverified",
+			Help:      "Number of games whose agreement with the trusted rollup node could not be determined",
+		}),
+		failedEnrichments: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "failed_enrichments",
+			Help:      "Number of games for which loading data from the game contract failed, by the data that failed to load",
+		}, []string{
+			"data",
+		}),
+		bondsPosted: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bonds_posted",
+			Help:      "Total value of bonds (in ether) posted by each address across monitored games",
+		}, []string{
+			"address",
+		}),
+		bondsAtStake: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bonds_at_stake",
+			Help:      "Total value of bonds (in ether) posted by each address in games that are still in progress",
+		}, []string{
+			"address",
+		}),
+		unclaimedCredit: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "unclaimed_credit",
+			Help:      "Total credit (in ether) available to each address that has not yet been claimed",
+		}, []string{
+			"address",
+		}),
 	}
 }
 
@@ -92,6 +230,86 @@ func (m *Metrics) RecordUp() {
 	m.up.Set(1)
 }
 
+func (m *Metrics) RecordGamesStatus(inProgress, defenderWon, challengerWon int) {
+	m.trackedGames.WithLabelValues("in_progress").Set(float64(inProgress))
+	m.trackedGames.WithLabelValues("defender_won").Set(float64(defenderWon))
+	m.trackedGames.WithLabelValues("challenger_won").Set(float64(challengerWon))
+}
+
+func (m *Metrics) RecordGamesNearDeadline(count int) {
+	m.gamesNearDeadline.Set(float64(count))
+}
+
+func (m *Metrics) RecordGameAgreement(status GameAgreementStatus, count int) {
+	m.gamesAgreement.WithLabelValues(resultCorrectness(status), completion(status), status.String()).Set(float64(count))
+}
+
+// RecordGameForecasts records the forecast outcome of each in progress game.
+// Previously recorded games are removed so that games that have since resolved are not reported.
+func (m *Metrics) RecordGameForecasts(forecasts map[common.Address]GameAgreementStatus) {
+	m.gameForecasts.Reset()
+	for game, status := range forecasts {
+		m.gameForecasts.WithLabelValues(game.Hex(), forecast(status), resultCorrectness(status)).Set(1)
+	}
+}
+
+func (m *Metrics) RecordGamesUnverified(count int) {
+	m.gamesUnverified.Set(float64(count))
+}
+
+func (m *Metrics) RecordFailedEnrichments(data string, count int) {
+	m.failedEnrichments.WithLabelValues(data).Set(float64(count))
+}
+
+// resultCorrectness reports whether the game outcome matches the outcome expected from the trusted rollup node.
+// For in progress games this is based on the forecast result if no further moves are made.
+func resultCorrectness(status GameAgreementStatus) string {
+	switch status {
+	case AgreeDefenderWins, DisagreeChallengerWins, AgreeDefenderAhead, DisagreeChallengerAhead:
+		return "correct"
+	case DisagreeDefenderWins, AgreeChallengerWins, DisagreeDefenderAhead, AgreeChallengerAhead:
+		return "incorrect"
+	default:
+		return "unknown"
+	}
+}
+
+// forecast reports the outcome an in progress game is forecast to have if no further moves are made.
+func forecast(status GameAgreementStatus) string {
+	switch status {
+	case AgreeDefenderAhead, DisagreeDefenderAhead:
+		return "defender_wins"
+	case AgreeChallengerAhead, DisagreeChallengerAhead:
+		return "challenger_wins"
+	default:
+		return "unknown"
+	}
+}
+
+func completion(status GameAgreementStatus) string {
+	switch status {
+	case AgreeDefenderAhead, DisagreeDefenderAhead, AgreeChallengerAhead, DisagreeChallengerAhead:
+		return "in_progress"
+	default:
+		return "complete"
+	}
+}
+
+// RecordBonds records the bond accounting for each address.
+// Previously recorded addresses are removed so that addresses from games outside the game window are not reported.
+func (m *Metrics) RecordBonds(posted, atStake, credit map[common.Address]*big.Int) {
+	recordByAddress(&m.bondsPosted, posted)
+	recordByAddress(&m.bondsAtStake, atStake)
+	recordByAddress(&m.unclaimedCredit, credit)
+}
+
+func recordByAddress(gauge *prometheus.GaugeVec, values map[common.Address]*big.Int) {
+	gauge.Reset()
+	for addr, value := range values {
+		gauge.WithLabelValues(addr.Hex()).Set(opmetrics.WeiToEther(value))
+	}
+}
+
 func (m *Metrics) Document() []opmetrics.DocumentedMetric {
 	return m.factory.Document()
 }
@@ -1,5 +1,11 @@
 package metrics
 
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
 type NoopMetricsImpl struct{}
 
 var NoopMetrics Metricer = new(NoopMetricsImpl)
@@ -7,5 +13,16 @@ var NoopMetrics Metricer = new(NoopMetricsImpl)
 func (*NoopMetricsImpl) RecordInfo(version string) {}
 func (*NoopMetricsImpl) RecordUp()                 {}
 
+func (*NoopMetricsImpl) RecordGamesStatus(inProgress, defenderWon, challengerWon int) {}
+func (*NoopMetricsImpl) RecordGamesNearDeadline(count int)                            {}
+
+func (*NoopMetricsImpl) RecordGameAgreement(status GameAgreementStatus, count int)            {}
+func (*NoopMetricsImpl) RecordGameForecasts(forecasts map[common.Address]GameAgreementStatus) {}
+func (*NoopMetricsImpl) RecordGamesUnverified(count int)                                      {}
+
+func (*NoopMetricsImpl) RecordFailedEnrichments(data string, count int) {}
+
+func (*NoopMetricsImpl) RecordBonds(posted, atStake, credit map[common.Address]*big.Int) {}
+
 func (*NoopMetricsImpl) CacheAdd(_ string, _ int, _ bool) {}
 func (*NoopMetricsImpl) CacheGet(_ string, _ bool)        {}
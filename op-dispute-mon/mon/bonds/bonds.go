@@ -0,0 +1,91 @@
+package bonds
+
+import (
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Accounting tracks the bonds and credit of a single address across all monitored games.
+type Accounting struct {
+	// Posted is the total value of bonds posted in claims, across all monitored games.
+	Posted *big.Int
+	// AtStake is the total value of bonds posted in claims of games that are still in progress.
+	AtStake *big.Int
+	// Credit is the total credit that is available to claim but has not yet been claimed.
+	Credit *big.Int
+}
+
+func newAccounting() *Accounting {
+	return &Accounting{
+		Posted:  big.NewInt(0),
+		AtStake: big.NewInt(0),
+		Credit:  big.NewInt(0),
+	}
+}
+
+type BondMetrics interface {
+	RecordBonds(posted, atStake, credit map[common.Address]*big.Int)
+}
+
+type Bonds struct {
+	logger  log.Logger
+	metrics BondMetrics
+}
+
+func NewBonds(logger log.Logger, metrics BondMetrics) *Bonds {
+	return &Bonds{
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// CheckBonds calculates the bond and credit accounting for every address in the supplied games and reports it.
+func (b *Bonds) CheckBonds(games []*monTypes.EnrichedGameData) {
+	accounts := make(map[common.Address]*Accounting)
+	account := func(addr common.Address) *Accounting {
+		acc, ok := accounts[addr]
+		if !ok {
+			acc = newAccounting()
+			accounts[addr] = acc
+		}
+		return acc
+	}
+	for _, game := range games {
+		gamePosted := big.NewInt(0)
+		gameCredit := big.NewInt(0)
+		for _, claim := range game.Claims {
+			if claim.Bond == nil {
+				continue
+			}
+			acc := account(claim.Claimant)
+			acc.Posted.Add(acc.Posted, claim.Bond)
+			if game.Status == types.GameStatusInProgress {
+				acc.AtStake.Add(acc.AtStake, claim.Bond)
+			}
+			gamePosted.Add(gamePosted, claim.Bond)
+		}
+		for addr, credit := range game.Credits {
+			acc := account(addr)
+			acc.Credit.Add(acc.Credit, credit)
+			gameCredit.Add(gameCredit, credit)
+		}
+		b.logger.Debug("Game bonds", "game", game.Proxy, "status", game.Status, "posted", gamePosted, "unclaimedCredit", gameCredit)
+	}
+	b.record(accounts)
+}
+
+func (b *Bonds) record(accounts map[common.Address]*Accounting) {
+	posted := make(map[common.Address]*big.Int, len(accounts))
+	atStake := make(map[common.Address]*big.Int, len(accounts))
+	credit := make(map[common.Address]*big.Int, len(accounts))
+	for addr, acc := range accounts {
+		posted[addr] = acc.Posted
+		atStake[addr] = acc.AtStake
+		credit[addr] = acc.Credit
+	}
+	b.metrics.RecordBonds(posted, atStake, credit)
+}
@@ -0,0 +1,99 @@
+package bonds
+
+import (
+	"math/big"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	honest     = common.Address{0xaa}
+	dishonest  = common.Address{0xbb}
+	bystander  = common.Address{0xcc}
+	proxyGame1 = common.Address{0x01}
+	proxyGame2 = common.Address{0x02}
+)
+
+func TestCheckBonds(t *testing.T) {
+	t.Run("NoGames", func(t *testing.T) {
+		bonds, metrics := setupBondsTest(t)
+		bonds.CheckBonds(nil)
+		require.NotNil(t, metrics.posted)
+		require.Empty(t, metrics.posted)
+	})
+
+	t.Run("AccumulatesAcrossGames", func(t *testing.T) {
+		bonds, metrics := setupBondsTest(t)
+		games := []*monTypes.EnrichedGameData{
+			{
+				GameMetadata: types.GameMetadata{Proxy: proxyGame1},
+				Status:       types.GameStatusInProgress,
+				Claims: []faultTypes.Claim{
+					claimWithBond(dishonest, 10),
+					claimWithBond(honest, 20),
+					claimWithBond(dishonest, 40),
+				},
+				Credits: map[common.Address]*big.Int{
+					honest:    big.NewInt(0),
+					dishonest: big.NewInt(0),
+				},
+			},
+			{
+				GameMetadata: types.GameMetadata{Proxy: proxyGame2},
+				Status:       types.GameStatusChallengerWon,
+				Claims: []faultTypes.Claim{
+					claimWithBond(dishonest, 100),
+					claimWithBond(honest, 200),
+				},
+				Credits: map[common.Address]*big.Int{
+					honest:    big.NewInt(300),
+					dishonest: big.NewInt(0),
+					bystander: big.NewInt(5),
+				},
+			},
+		}
+		bonds.CheckBonds(games)
+		require.Len(t, metrics.posted, 3)
+		metrics.requireAccounting(t, honest, 220, 20, 300)
+		metrics.requireAccounting(t, dishonest, 150, 50, 0)
+		metrics.requireAccounting(t, bystander, 0, 0, 5)
+	})
+}
+
+func claimWithBond(claimant common.Address, bond int64) faultTypes.Claim {
+	return faultTypes.Claim{
+		ClaimData: faultTypes.ClaimData{Bond: big.NewInt(bond)},
+		Claimant:  claimant,
+	}
+}
+
+func setupBondsTest(t *testing.T) (*Bonds, *stubBondMetrics) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	metrics := &stubBondMetrics{}
+	return NewBonds(logger, metrics), metrics
+}
+
+type stubBondMetrics struct {
+	posted  map[common.Address]*big.Int
+	atStake map[common.Address]*big.Int
+	credit  map[common.Address]*big.Int
+}
+
+func (s *stubBondMetrics) RecordBonds(posted, atStake, credit map[common.Address]*big.Int) {
+	s.posted = posted
+	s.atStake = atStake
+	s.credit = credit
+}
+
+func (s *stubBondMetrics) requireAccounting(t *testing.T, addr common.Address, posted, atStake, credit int64) {
+	require.Equal(t, big.NewInt(posted), s.posted[addr], "posted")
+	require.Equal(t, big.NewInt(atStake), s.atStake[addr], "at stake")
+	require.Equal(t, big.NewInt(credit), s.credit[addr], "credit")
+}
@@ -0,0 +1,155 @@
+package mon
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/resolution"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type OutputValidator interface {
+	CheckRootAgreement(ctx context.Context, blockNum uint64, root common.Hash) (bool, common.Hash, error)
+}
+
+type DetectorMetrics interface {
+	RecordGamesStatus(inProgress, defenderWon, challengerWon int)
+	RecordGamesNearDeadline(count int)
+	RecordGameAgreement(status metrics.GameAgreementStatus, count int)
+	RecordGameForecasts(forecasts map[common.Address]metrics.GameAgreementStatus)
+	RecordGamesUnverified(count int)
+}
+
+type detector struct {
+	logger       log.Logger
+	metrics      DetectorMetrics
+	clock        clock.Clock
+	validator    OutputValidator
+	nearDeadline time.Duration
+}
+
+func newDetector(logger log.Logger, metrics DetectorMetrics, cl clock.Clock, validator OutputValidator, nearDeadline time.Duration) *detector {
+	return &detector{
+		logger:       logger,
+		metrics:      metrics,
+		clock:        cl,
+		validator:    validator,
+		nearDeadline: nearDeadline,
+	}
+}
+
+func (d *detector) Detect(ctx context.Context, games []*monTypes.EnrichedGameData) {
+	var inProgress, defenderWon, challengerWon, nearDeadline, unverified int
+	agreements := make(map[metrics.GameAgreementStatus]int)
+	forecasts := make(map[common.Address]metrics.GameAgreementStatus)
+	now := d.clock.Now()
+	for _, game := range games {
+		d.logger.Debug("Game status",
+			"game", game.Proxy,
+			"status", game.Status,
+			"rootClaim", game.RootClaim,
+			"l2BlockNum", game.L2BlockNumber,
+			"createdAt", game.CreatedAt(),
+			"deadline", game.Deadline())
+		switch game.Status {
+		case types.GameStatusInProgress:
+			inProgress++
+			if d.isNearDeadline(now, game) {
+				d.logger.Warn("Game approaching resolution deadline", "game", game.Proxy, "deadline", game.Deadline())
+				nearDeadline++
+			}
+		case types.GameStatusDefenderWon:
+			defenderWon++
+		case types.GameStatusChallengerWon:
+			challengerWon++
+		}
+		if status, ok := d.checkAgreement(ctx, game); ok {
+			agreements[status]++
+			if game.Status == types.GameStatusInProgress {
+				forecasts[game.Proxy] = status
+			}
+		} else {
+			unverified++
+		}
+	}
+	d.metrics.RecordGamesStatus(inProgress, defenderWon, challengerWon)
+	d.metrics.RecordGamesNearDeadline(nearDeadline)
+	for _, status := range metrics.GameAgreementStatuses {
+		d.metrics.RecordGameAgreement(status, agreements[status])
+	}
+	d.metrics.RecordGameForecasts(forecasts)
+	d.metrics.RecordGamesUnverified(unverified)
+}
+
+// checkAgreement classifies the game by whether its root claim agrees with the trusted output.
+// It returns false if the root claim cannot be checked, or the outcome of an in progress game cannot be forecast,
+// in which case the game is reported as unverified rather than as agreeing or disagreeing.
+func (d *detector) checkAgreement(ctx context.Context, game *monTypes.EnrichedGameData) (metrics.GameAgreementStatus, bool) {
+	agree, expected, err := d.validator.CheckRootAgreement(ctx, game.L2BlockNumber, game.RootClaim)
+	if err != nil {
+		d.logger.Error("Failed to check root claim agreement", "game", game.Proxy, "err", err)
+		return 0, false
+	}
+	inProgress := game.Status == types.GameStatusInProgress
+	outcome := game.Status
+	if inProgress {
+		outcome = resolution.Resolve(game.Claims)
+		if outcome == types.GameStatusInProgress {
+			// Games always have a root claim, so there are no claims only if they failed to load.
+			d.logger.Warn("Unable to forecast game outcome without claims", "game", game.Proxy)
+			return 0, false
+		}
+		d.logger.Debug("Forecast game outcome", "game", game.Proxy, "forecast", outcome, "rootAgreement", agree)
+	}
+	status := agreementStatus(inProgress, outcome, agree)
+	switch status {
+	case metrics.DisagreeDefenderWins:
+		d.logger.Error("Invalid root claim won the game", "game", game.Proxy, "rootClaim", game.RootClaim, "expected", expected)
+	case metrics.AgreeChallengerWins:
+		d.logger.Error("Valid root claim lost the game", "game", game.Proxy, "rootClaim", game.RootClaim, "expected", expected)
+	case metrics.DisagreeDefenderAhead:
+		d.logger.Warn("Invalid root claim is currently winning", "game", game.Proxy, "rootClaim", game.RootClaim, "expected", expected, "deadline", game.Deadline())
+	case metrics.AgreeChallengerAhead:
+		d.logger.Warn("Valid root claim is currently losing", "game", game.Proxy, "rootClaim", game.RootClaim, "expected", expected, "deadline", game.Deadline())
+	}
+	return status, true
+}
+
+// isNearDeadline returns true if the game's deadline is within the configured window of now.
+// In progress games whose deadline has already passed are awaiting resolution and are also included.
+func (d *detector) isNearDeadline(now time.Time, game *monTypes.EnrichedGameData) bool {
+	return game.Deadline().Sub(now) <= d.nearDeadline
+}
+
+// agreementStatus classifies a game based on its actual or forecast outcome and whether the root claim
+// agrees with the trusted output. Forecasts are only used for games that are still in progress.
+func agreementStatus(inProgress bool, outcome types.GameStatus, agree bool) metrics.GameAgreementStatus {
+	defenderWins := outcome != types.GameStatusChallengerWon
+	switch {
+	case inProgress && defenderWins:
+		if agree {
+			return metrics.AgreeDefenderAhead
+		}
+		return metrics.DisagreeDefenderAhead
+	case inProgress:
+		if agree {
+			return metrics.AgreeChallengerAhead
+		}
+		return metrics.DisagreeChallengerAhead
+	case defenderWins:
+		if agree {
+			return metrics.AgreeDefenderWins
+		}
+		return metrics.DisagreeDefenderWins
+	default:
+		if agree {
+			return metrics.AgreeChallengerWins
+		}
+		return metrics.DisagreeChallengerWins
+	}
+}
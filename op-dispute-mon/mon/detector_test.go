@@ -0,0 +1,226 @@
+package mon
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var frozenTime = time.Unix(int64(time.Hour.Seconds()*10), 0)
+
+func TestDetector_Detect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoGames", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		detector.Detect(context.Background(), nil)
+		m.Equals(t, 0, 0, 0, 0)
+	})
+
+	t.Run("CountsStatuses", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		games := []*monTypes.EnrichedGameData{
+			inProgressGame(common.Address{0x01}, frozenTime.Add(24*time.Hour)),
+			inProgressGame(common.Address{0x02}, frozenTime.Add(24*time.Hour)),
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x03}}, Status: types.GameStatusDefenderWon},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x04}}, Status: types.GameStatusChallengerWon},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x05}}, Status: types.GameStatusChallengerWon},
+		}
+		detector.Detect(context.Background(), games)
+		m.Equals(t, 2, 1, 2, 0)
+	})
+
+	t.Run("CountsGamesNearDeadline", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		games := []*monTypes.EnrichedGameData{
+			inProgressGame(common.Address{0x01}, frozenTime.Add(24*time.Hour)),
+			inProgressGame(common.Address{0x02}, frozenTime.Add(time.Hour)),
+			inProgressGame(common.Address{0x03}, frozenTime.Add(-time.Hour)),
+			{
+				GameMetadata: types.GameMetadata{Proxy: common.Address{0x04}, Timestamp: uint64(frozenTime.Unix())},
+				Status:       types.GameStatusDefenderWon,
+			},
+		}
+		detector.Detect(context.Background(), games)
+		m.Equals(t, 3, 1, 0, 2)
+	})
+
+	t.Run("CheckAgreementFails", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		validator := detector.validator.(*stubValidator)
+		validator.err = errors.New("boom")
+		games := []*monTypes.EnrichedGameData{
+			{Status: types.GameStatusDefenderWon},
+			{Status: types.GameStatusInProgress, Duration: 1_000_000, Claims: []faultTypes.Claim{rootClaim()}},
+		}
+		detector.Detect(context.Background(), games)
+		m.Equals(t, 1, 1, 0, 0)
+		require.Equal(t, 2, m.unverified)
+		for _, status := range metrics.GameAgreementStatuses {
+			require.Zerof(t, m.agreements[status], "status %v", status)
+		}
+	})
+
+	t.Run("MissingClaims", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		games := []*monTypes.EnrichedGameData{
+			{Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000},
+			{Status: types.GameStatusDefenderWon, RootClaim: mockRootClaim},
+		}
+		detector.Detect(context.Background(), games)
+		require.Equal(t, 1, m.unverified)
+		require.Equal(t, 1, m.agreements[metrics.AgreeDefenderWins])
+		require.Zero(t, m.agreements[metrics.AgreeDefenderAhead])
+	})
+
+	t.Run("RecordsForecastPerGame", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		badRoot := common.Hash{0xba, 0xd0}
+		uncontested := []faultTypes.Claim{rootClaim()}
+		challenged := []faultTypes.Claim{rootClaim(), attackClaim()}
+		games := []*monTypes.EnrichedGameData{
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x01}}, Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000, Claims: uncontested},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x02}}, Status: types.GameStatusInProgress, RootClaim: badRoot, Duration: 1_000_000, Claims: uncontested},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x03}}, Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000, Claims: challenged},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x04}}, Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000},
+			{GameMetadata: types.GameMetadata{Proxy: common.Address{0x05}}, Status: types.GameStatusDefenderWon, RootClaim: mockRootClaim},
+		}
+		detector.Detect(context.Background(), games)
+		require.Equal(t, map[common.Address]metrics.GameAgreementStatus{
+			{0x01}: metrics.AgreeDefenderAhead,
+			{0x02}: metrics.DisagreeDefenderAhead,
+			{0x03}: metrics.AgreeChallengerAhead,
+		}, m.forecasts)
+	})
+
+	t.Run("ClassifiesAgreement", func(t *testing.T) {
+		detector, m := setupDetectorTest(t)
+		badRoot := common.Hash{0xba, 0xd0}
+		uncontested := []faultTypes.Claim{rootClaim()}
+		challenged := []faultTypes.Claim{rootClaim(), attackClaim()}
+		games := []*monTypes.EnrichedGameData{
+			{Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000, Claims: uncontested},
+			{Status: types.GameStatusInProgress, RootClaim: badRoot, Duration: 1_000_000, Claims: uncontested},
+			{Status: types.GameStatusInProgress, RootClaim: badRoot, Duration: 1_000_000, Claims: uncontested},
+			{Status: types.GameStatusInProgress, RootClaim: mockRootClaim, Duration: 1_000_000, Claims: challenged},
+			{Status: types.GameStatusInProgress, RootClaim: badRoot, Duration: 1_000_000, Claims: challenged},
+			{Status: types.GameStatusDefenderWon, RootClaim: mockRootClaim},
+			{Status: types.GameStatusDefenderWon, RootClaim: badRoot},
+			{Status: types.GameStatusChallengerWon, RootClaim: mockRootClaim},
+			{Status: types.GameStatusChallengerWon, RootClaim: badRoot},
+			{Status: types.GameStatusChallengerWon, RootClaim: badRoot},
+		}
+		detector.Detect(context.Background(), games)
+		require.Equal(t, 1, m.agreements[metrics.AgreeDefenderAhead])
+		require.Equal(t, 2, m.agreements[metrics.DisagreeDefenderAhead])
+		require.Equal(t, 1, m.agreements[metrics.AgreeChallengerAhead])
+		require.Equal(t, 1, m.agreements[metrics.DisagreeChallengerAhead])
+		require.Equal(t, 1, m.agreements[metrics.AgreeDefenderWins])
+		require.Equal(t, 1, m.agreements[metrics.DisagreeDefenderWins])
+		require.Equal(t, 1, m.agreements[metrics.AgreeChallengerWins])
+		require.Equal(t, 2, m.agreements[metrics.DisagreeChallengerWins])
+		require.Zero(t, m.unverified)
+	})
+}
+
+func rootClaim() faultTypes.Claim {
+	return faultTypes.Claim{
+		ClaimData:           faultTypes.ClaimData{Position: faultTypes.NewPositionFromGIndex(big.NewInt(1))},
+		ContractIndex:       0,
+		ParentContractIndex: math.MaxUint32,
+	}
+}
+
+func attackClaim() faultTypes.Claim {
+	return faultTypes.Claim{
+		ClaimData:           faultTypes.ClaimData{Position: faultTypes.NewPositionFromGIndex(big.NewInt(2))},
+		Claimant:            common.Address{0xcc},
+		ContractIndex:       1,
+		ParentContractIndex: 0,
+	}
+}
+
+func inProgressGame(addr common.Address, deadline time.Time) *monTypes.EnrichedGameData {
+	duration := uint64(time.Hour.Seconds() * 48)
+	return &monTypes.EnrichedGameData{
+		GameMetadata: types.GameMetadata{
+			Proxy:     addr,
+			Timestamp: uint64(deadline.Unix()) - duration,
+		},
+		Status:   types.GameStatusInProgress,
+		Duration: duration,
+	}
+}
+
+func setupDetectorTest(t *testing.T) (*detector, *mockDetectorMetrics) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	m := &mockDetectorMetrics{}
+	cl := clock.NewDeterministicClock(frozenTime)
+	return newDetector(logger, m, cl, &stubValidator{}, 2*time.Hour), m
+}
+
+type stubValidator struct {
+	err error
+}
+
+func (s *stubValidator) CheckRootAgreement(_ context.Context, _ uint64, rootClaim common.Hash) (bool, common.Hash, error) {
+	if s.err != nil {
+		return false, common.Hash{}, s.err
+	}
+	return rootClaim == mockRootClaim, mockRootClaim, nil
+}
+
+type mockDetectorMetrics struct {
+	agreements    map[metrics.GameAgreementStatus]int
+	forecasts     map[common.Address]metrics.GameAgreementStatus
+	inProgress    int
+	defenderWon   int
+	challengerWon int
+	nearDeadline  int
+	unverified    int
+}
+
+func (m *mockDetectorMetrics) Equals(t *testing.T, inProgress, defenderWon, challengerWon, nearDeadline int) {
+	require.Equal(t, inProgress, m.inProgress)
+	require.Equal(t, defenderWon, m.defenderWon)
+	require.Equal(t, challengerWon, m.challengerWon)
+	require.Equal(t, nearDeadline, m.nearDeadline)
+}
+
+func (m *mockDetectorMetrics) RecordGamesStatus(inProgress, defenderWon, challengerWon int) {
+	m.inProgress = inProgress
+	m.defenderWon = defenderWon
+	m.challengerWon = challengerWon
+}
+
+func (m *mockDetectorMetrics) RecordGamesNearDeadline(count int) {
+	m.nearDeadline = count
+}
+
+func (m *mockDetectorMetrics) RecordGameAgreement(status metrics.GameAgreementStatus, count int) {
+	if m.agreements == nil {
+		m.agreements = make(map[metrics.GameAgreementStatus]int)
+	}
+	m.agreements[status] = count
+}
+
+func (m *mockDetectorMetrics) RecordGameForecasts(forecasts map[common.Address]metrics.GameAgreementStatus) {
+	m.forecasts = forecasts
+}
+
+func (m *mockDetectorMetrics) RecordGamesUnverified(count int) {
+	m.unverified = count
+}
@@ -0,0 +1,69 @@
+package extract
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var fdgAddr = common.HexToAddress("0x24112842371dFC380576ebb09Ae16Cb6B6caD7CB")
+
+func TestMetadataCreator_CreateContract(t *testing.T) {
+	tests := []struct {
+		name        string
+		game        gameTypes.GameMetadata
+		expectedErr error
+	}{
+		{
+			name: "validCannonGameType",
+			game: gameTypes.GameMetadata{GameType: faultTypes.CannonGameType, Proxy: fdgAddr},
+		},
+		{
+			name: "validAlphabetGameType",
+			game: gameTypes.GameMetadata{GameType: faultTypes.AlphabetGameType, Proxy: fdgAddr},
+		},
+		{
+			name:        "InvalidGameType",
+			game:        gameTypes.GameMetadata{GameType: 2, Proxy: fdgAddr},
+			expectedErr: fmt.Errorf("unsupported game type: 2"),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			caller := batching.NewMultiCaller(nil, batching.DefaultBatchSize)
+			metrics := &mockCacheMetrics{}
+			creator := NewGameCallerCreator(metrics, caller)
+			_, err := creator.CreateContract(test.game)
+			require.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				require.Equal(t, 1, metrics.cacheAddCalls)
+				require.Equal(t, 1, metrics.cacheGetCalls)
+				fdg, ok := creator.cache.Get(fdgAddr)
+				require.True(t, ok)
+				expectedFdg, err := contracts.NewFaultDisputeGameContract(fdgAddr, caller)
+				require.NoError(t, err)
+				require.Equal(t, expectedFdg, fdg)
+			}
+		})
+	}
+}
+
+type mockCacheMetrics struct {
+	cacheAddCalls int
+	cacheGetCalls int
+}
+
+func (m *mockCacheMetrics) CacheAdd(_ string, _ int, _ bool) {
+	m.cacheAddCalls++
+}
+func (m *mockCacheMetrics) CacheGet(_ string, _ bool) {
+	m.cacheGetCalls++
+}
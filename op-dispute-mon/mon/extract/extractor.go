@@ -0,0 +1,123 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type CreateGameCaller func(game gameTypes.GameMetadata) (GameCaller, error)
+type FactoryGameFetcher func(ctx context.Context, blockHash common.Hash, earliestTimestamp uint64) ([]gameTypes.GameMetadata, error)
+
+// Data loaded from the game contract, by which failures to enrich games are reported.
+const (
+	EnrichMetadata = "metadata"
+	EnrichClaims   = "claims"
+	EnrichCredits  = "credits"
+)
+
+type ExtractorMetrics interface {
+	RecordFailedEnrichments(data string, count int)
+}
+
+type Extractor struct {
+	logger         log.Logger
+	metrics        ExtractorMetrics
+	createContract CreateGameCaller
+	fetchGames     FactoryGameFetcher
+}
+
+func NewExtractor(logger log.Logger, metrics ExtractorMetrics, creator CreateGameCaller, fetchGames FactoryGameFetcher) *Extractor {
+	return &Extractor{
+		logger:         logger,
+		metrics:        metrics,
+		createContract: creator,
+		fetchGames:     fetchGames,
+	}
+}
+
+// Extract loads all games created at or after minTimestamp as of the specified block and enriches them with
+// the current state of each game contract. Games whose metadata fails to load are logged and skipped.
+// Games whose claims or credits fail to load are logged and kept without them.
+func (e *Extractor) Extract(ctx context.Context, blockHash common.Hash, minTimestamp uint64) ([]*monTypes.EnrichedGameData, error) {
+	games, err := e.fetchGames(ctx, blockHash, minTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load games: %w", err)
+	}
+	return e.enrichGames(ctx, games), nil
+}
+
+func (e *Extractor) enrichGames(ctx context.Context, games []gameTypes.GameMetadata) []*monTypes.EnrichedGameData {
+	var enrichedGames []*monTypes.EnrichedGameData
+	failures := make(map[string]int)
+	for _, game := range games {
+		caller, err := e.createContract(game)
+		if err != nil {
+			e.logger.Error("Failed to create game caller", "game", game.Proxy, "err", err)
+			failures[EnrichMetadata]++
+			continue
+		}
+		l2BlockNum, rootClaim, status, duration, err := caller.GetGameMetadata(ctx)
+		if err != nil {
+			e.logger.Error("Failed to fetch game metadata", "game", game.Proxy, "err", err)
+			failures[EnrichMetadata]++
+			continue
+		}
+		enrichedGame := &monTypes.EnrichedGameData{
+			GameMetadata:  game,
+			L2BlockNumber: l2BlockNum,
+			RootClaim:     rootClaim,
+			Status:        status,
+			Duration:      duration,
+		}
+		enrichedGames = append(enrichedGames, enrichedGame)
+		claims, err := caller.GetAllClaims(ctx)
+		if err != nil {
+			e.logger.Error("Failed to fetch game claims", "game", game.Proxy, "err", err)
+			failures[EnrichClaims]++
+			continue
+		}
+		enrichedGame.Claims = claims
+		recipients := creditRecipients(claims)
+		credits, err := caller.GetCredits(ctx, recipients...)
+		if err != nil {
+			e.logger.Error("Failed to fetch credits", "game", game.Proxy, "err", err)
+			failures[EnrichCredits]++
+			continue
+		}
+		creditsByAddr := make(map[common.Address]*big.Int, len(recipients))
+		for i, recipient := range recipients {
+			creditsByAddr[recipient] = credits[i]
+		}
+		enrichedGame.Credits = creditsByAddr
+	}
+	for _, data := range []string{EnrichMetadata, EnrichClaims, EnrichCredits} {
+		e.metrics.RecordFailedEnrichments(data, failures[data])
+	}
+	return enrichedGames
+}
+
+// creditRecipients returns the unique addresses that may be paid bonds from the game.
+// Bonds are paid to either the claimant or the address that countered the claim.
+func creditRecipients(claims []faultTypes.Claim) []common.Address {
+	seen := make(map[common.Address]bool)
+	var recipients []common.Address
+	add := func(addr common.Address) {
+		if addr == (common.Address{}) || seen[addr] {
+			return
+		}
+		seen[addr] = true
+		recipients = append(recipients, addr)
+	}
+	for _, claim := range claims {
+		add(claim.Claimant)
+		add(claim.CounteredBy)
+	}
+	return recipients
+}
@@ -0,0 +1,205 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockRootClaim = common.HexToHash("0x10")
+
+	claimant = common.Address{0x01}
+	counter  = common.Address{0x02}
+	stepper  = common.Address{0x03}
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Run("FetchGamesError", func(t *testing.T) {
+		extractor, _, games, _ := setupExtractorTest(t)
+		games.err = errors.New("boom")
+		_, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.ErrorIs(t, err, games.err)
+		require.Equal(t, 1, games.calls)
+	})
+
+	t.Run("CreateGameErrorLog", func(t *testing.T) {
+		extractor, creator, games, m := setupExtractorTest(t)
+		games.games = []gameTypes.GameMetadata{{}}
+		creator.err = errors.New("boom")
+		enriched, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.NoError(t, err)
+		require.Len(t, enriched, 0)
+		require.Equal(t, 1, games.calls)
+		require.Equal(t, 1, creator.calls)
+		require.Equal(t, 0, creator.caller.calls)
+		m.Equals(t, 1, 0, 0)
+	})
+
+	t.Run("MetadataFetchErrorLog", func(t *testing.T) {
+		extractor, creator, games, m := setupExtractorTest(t)
+		games.games = []gameTypes.GameMetadata{{}}
+		creator.caller.err = errors.New("boom")
+		enriched, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.NoError(t, err)
+		require.Len(t, enriched, 0)
+		require.Equal(t, 1, creator.caller.calls)
+		require.Equal(t, 0, creator.caller.claimsCalls)
+		m.Equals(t, 1, 0, 0)
+	})
+
+	t.Run("ClaimsFetchErrorKeepsGame", func(t *testing.T) {
+		extractor, creator, games, m := setupExtractorTest(t)
+		games.games = []gameTypes.GameMetadata{{}}
+		creator.caller.claimsErr = errors.New("boom")
+		enriched, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.NoError(t, err)
+		require.Len(t, enriched, 1)
+		require.Equal(t, mockRootClaim, enriched[0].RootClaim)
+		require.Nil(t, enriched[0].Claims)
+		require.Nil(t, enriched[0].Credits)
+		require.Equal(t, 1, creator.caller.calls)
+		require.Equal(t, 1, creator.caller.claimsCalls)
+		require.Equal(t, 0, creator.caller.creditsCalls)
+		m.Equals(t, 0, 1, 0)
+	})
+
+	t.Run("CreditsFetchErrorKeepsGame", func(t *testing.T) {
+		extractor, creator, games, m := setupExtractorTest(t)
+		games.games = []gameTypes.GameMetadata{{}}
+		creator.caller.creditsErr = errors.New("boom")
+		enriched, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.NoError(t, err)
+		require.Len(t, enriched, 1)
+		require.Len(t, enriched[0].Claims, 2)
+		require.Nil(t, enriched[0].Credits)
+		require.Equal(t, 1, creator.caller.creditsCalls)
+		m.Equals(t, 0, 0, 1)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		extractor, creator, games, m := setupExtractorTest(t)
+		games.games = []gameTypes.GameMetadata{{Proxy: common.Address{0xaa}, Timestamp: 50}}
+		enriched, err := extractor.Extract(context.Background(), common.Hash{}, 0)
+		require.NoError(t, err)
+		require.Len(t, enriched, 1)
+		require.Equal(t, 1, creator.caller.calls)
+		require.Equal(t, games.games[0], enriched[0].GameMetadata)
+		require.Equal(t, uint64(10), enriched[0].L2BlockNumber)
+		require.Equal(t, mockRootClaim, enriched[0].RootClaim)
+		require.Equal(t, gameTypes.GameStatusInProgress, enriched[0].Status)
+		require.Equal(t, uint64(100), enriched[0].Duration)
+		require.Len(t, enriched[0].Claims, 2)
+		require.Len(t, enriched[0].Credits, 3)
+		require.Equal(t, big.NewInt(1), enriched[0].Credits[claimant])
+		require.Equal(t, big.NewInt(2), enriched[0].Credits[counter])
+		require.Equal(t, big.NewInt(3), enriched[0].Credits[stepper])
+		m.Equals(t, 0, 0, 0)
+	})
+}
+
+func setupExtractorTest(t *testing.T) (*Extractor, *mockGameCallerCreator, *mockGameFetcher, *mockExtractorMetrics) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	games := &mockGameFetcher{}
+	caller := &mockGameCaller{rootClaim: mockRootClaim}
+	creator := &mockGameCallerCreator{caller: caller}
+	m := &mockExtractorMetrics{failures: make(map[string]int)}
+	extractor := NewExtractor(
+		logger,
+		m,
+		creator.CreateGameCaller,
+		games.FetchGames,
+	)
+	return extractor, creator, games, m
+}
+
+type mockExtractorMetrics struct {
+	failures map[string]int
+}
+
+func (m *mockExtractorMetrics) Equals(t *testing.T, metadata, claims, credits int) {
+	require.Equal(t, metadata, m.failures[EnrichMetadata])
+	require.Equal(t, claims, m.failures[EnrichClaims])
+	require.Equal(t, credits, m.failures[EnrichCredits])
+}
+
+func (m *mockExtractorMetrics) RecordFailedEnrichments(data string, count int) {
+	m.failures[data] = count
+}
+
+type mockGameFetcher struct {
+	calls int
+	err   error
+	games []gameTypes.GameMetadata
+}
+
+func (m *mockGameFetcher) FetchGames(_ context.Context, _ common.Hash, _ uint64) ([]gameTypes.GameMetadata, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.games, nil
+}
+
+type mockGameCallerCreator struct {
+	calls  int
+	err    error
+	caller *mockGameCaller
+}
+
+func (m *mockGameCallerCreator) CreateGameCaller(_ gameTypes.GameMetadata) (GameCaller, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.caller, nil
+}
+
+type mockGameCaller struct {
+	calls        int
+	claimsCalls  int
+	creditsCalls int
+	err          error
+	claimsErr    error
+	creditsErr   error
+	rootClaim    common.Hash
+}
+
+func (m *mockGameCaller) GetGameMetadata(_ context.Context) (uint64, common.Hash, gameTypes.GameStatus, uint64, error) {
+	m.calls++
+	if m.err != nil {
+		return 0, common.Hash{}, 0, 0, m.err
+	}
+	return 10, m.rootClaim, gameTypes.GameStatusInProgress, 100, nil
+}
+
+func (m *mockGameCaller) GetAllClaims(_ context.Context) ([]faultTypes.Claim, error) {
+	m.claimsCalls++
+	if m.claimsErr != nil {
+		return nil, m.claimsErr
+	}
+	return []faultTypes.Claim{
+		{Claimant: claimant, CounteredBy: counter},
+		{Claimant: counter, CounteredBy: stepper},
+	}, nil
+}
+
+func (m *mockGameCaller) GetCredits(_ context.Context, recipients ...common.Address) ([]*big.Int, error) {
+	m.creditsCalls++
+	if m.creditsErr != nil {
+		return nil, m.creditsErr
+	}
+	credits := make([]*big.Int, len(recipients))
+	for i := range recipients {
+		credits[i] = big.NewInt(int64(i + 1))
+	}
+	return credits, nil
+}
@@ -0,0 +1,184 @@
+package mon
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockErr = errors.New("mock error")
+)
+
+func TestMonitor_MinGameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ZeroGameWindow", func(t *testing.T) {
+		monitor, _, _ := setupMonitorTest(t)
+		monitor.gameWindow = time.Duration(0)
+		require.Equal(t, monitor.minGameTimestamp(), uint64(0))
+	})
+
+	t.Run("ZeroClock", func(t *testing.T) {
+		monitor, _, _ := setupMonitorTest(t)
+		monitor.gameWindow = time.Minute
+		monitor.clock = clock.NewDeterministicClock(time.Unix(0, 0))
+		require.Equal(t, uint64(0), monitor.minGameTimestamp())
+	})
+
+	t.Run("ValidArithmetic", func(t *testing.T) {
+		monitor, _, _ := setupMonitorTest(t)
+		monitor.gameWindow = time.Minute
+		frozen := time.Unix(int64(time.Hour.Seconds()), 0)
+		monitor.clock = clock.NewDeterministicClock(frozen)
+		expected := uint64(frozen.Add(-time.Minute).Unix())
+		require.Equal(t, monitor.minGameTimestamp(), expected)
+	})
+}
+
+func TestMonitor_MonitorGames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FailedFetchBlocknumber", func(t *testing.T) {
+		monitor, _, _ := setupMonitorTest(t)
+		boom := errors.New("boom")
+		monitor.fetchBlockNumber = func(ctx context.Context) (uint64, error) {
+			return 0, boom
+		}
+		err := monitor.monitorGames()
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("FailedFetchBlockHash", func(t *testing.T) {
+		monitor, _, _ := setupMonitorTest(t)
+		boom := errors.New("boom")
+		monitor.fetchBlockHash = func(ctx context.Context, number *big.Int) (common.Hash, error) {
+			return common.Hash{}, boom
+		}
+		err := monitor.monitorGames()
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("FailedExtract", func(t *testing.T) {
+		monitor, extractor, detector := setupMonitorTest(t)
+		extractor.err = mockErr
+		err := monitor.monitorGames()
+		require.ErrorIs(t, err, mockErr)
+		require.Equal(t, 0, detector.calls)
+	})
+
+	t.Run("ChecksBonds", func(t *testing.T) {
+		monitor, extractor, _ := setupMonitorTest(t)
+		extractor.games = []*monTypes.EnrichedGameData{{}, {}, {}}
+		var bondGames []*monTypes.EnrichedGameData
+		monitor.bonds = func(games []*monTypes.EnrichedGameData) {
+			bondGames = games
+		}
+		err := monitor.monitorGames()
+		require.NoError(t, err)
+		require.Len(t, bondGames, 3)
+	})
+
+	t.Run("DetectsGames", func(t *testing.T) {
+		monitor, extractor, detector := setupMonitorTest(t)
+		extractor.games = []*monTypes.EnrichedGameData{{}, {}}
+		err := monitor.monitorGames()
+		require.NoError(t, err)
+		require.Equal(t, 1, extractor.calls)
+		require.Equal(t, 1, detector.calls)
+		require.Len(t, detector.games, 2)
+	})
+}
+
+func TestMonitor_StartMonitoring(t *testing.T) {
+	t.Run("MonitorsGames", func(t *testing.T) {
+		monitor, extractor, detector := setupMonitorTest(t)
+		extractor.games = []*monTypes.EnrichedGameData{{}, {}}
+		cl := monitor.clock.(*clock.DeterministicClock)
+		monitor.StartMonitoring()
+		defer monitor.StopMonitoring()
+		require.True(t, cl.WaitForNewPendingTaskWithTimeout(10*time.Second))
+		cl.AdvanceTime(monitor.monitorInterval)
+		require.Eventually(t, func() bool {
+			return detector.Calls() >= 1
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestMonitor_StopMonitoringTwice(t *testing.T) {
+	monitor, _, _ := setupMonitorTest(t)
+	monitor.StartMonitoring()
+	monitor.StopMonitoring()
+	require.NotPanics(t, monitor.StopMonitoring)
+}
+
+func setupMonitorTest(t *testing.T) (*gameMonitor, *mockExtractor, *mockDetector) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	fetchBlockNum := func(ctx context.Context) (uint64, error) {
+		return 1, nil
+	}
+	fetchBlockHash := func(ctx context.Context, number *big.Int) (common.Hash, error) {
+		return common.Hash{}, nil
+	}
+	monitorInterval := time.Duration(100 * time.Millisecond)
+	cl := clock.NewDeterministicClock(time.Unix(int64(time.Hour.Seconds()), 0))
+	extractor := &mockExtractor{}
+	detector := &mockDetector{}
+	monitor := newGameMonitor(
+		context.Background(),
+		logger,
+		cl,
+		monitorInterval,
+		10*time.Second,
+		detector.Detect,
+		func(games []*monTypes.EnrichedGameData) {},
+		extractor.Extract,
+		fetchBlockNum,
+		fetchBlockHash,
+	)
+	return monitor, extractor, detector
+}
+
+type mockDetector struct {
+	calls int
+	games []*monTypes.EnrichedGameData
+	mu    sync.Mutex
+}
+
+func (m *mockDetector) Detect(_ context.Context, games []*monTypes.EnrichedGameData) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	m.games = games
+}
+
+func (m *mockDetector) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+type mockExtractor struct {
+	calls int
+	games []*monTypes.EnrichedGameData
+	err   error
+}
+
+func (m *mockExtractor) Extract(
+	_ context.Context,
+	_ common.Hash,
+	_ uint64,
+) ([]*monTypes.EnrichedGameData, error) {
+	m.calls++
+	return m.games, m.err
+}
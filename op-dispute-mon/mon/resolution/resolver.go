@@ -0,0 +1,62 @@
+package resolution
+
+import (
+	"math/big"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Resolve forecasts the outcome of a game by applying the same subgame resolution rules as the
+// FaultDisputeGame contract to the supplied claims, assuming no further moves are made.
+// Returns GameStatusInProgress if there are no claims to resolve.
+func Resolve(claims []faultTypes.Claim) gameTypes.GameStatus {
+	if len(claims) == 0 {
+		return gameTypes.GameStatusInProgress
+	}
+	counteredBy := resolveSubgames(claims)
+	if counteredBy[0] == (common.Address{}) {
+		return gameTypes.GameStatusDefenderWon
+	}
+	return gameTypes.GameStatusChallengerWon
+}
+
+// resolveSubgames resolves every subgame in the claim tree and returns the address that countered each claim,
+// indexed by contract index. The zero address indicates the claim was not countered.
+func resolveSubgames(claims []faultTypes.Claim) []common.Address {
+	children := make([][]int, len(claims))
+	for i, claim := range claims {
+		if claim.IsRoot() {
+			continue
+		}
+		children[claim.ParentContractIndex] = append(children[claim.ParentContractIndex], i)
+	}
+
+	counteredBy := make([]common.Address, len(claims))
+	// Children are always added to the game after their parent so resolving in reverse order
+	// guarantees every subgame is resolved before its parent, matching the required on-chain ordering.
+	for i := len(claims) - 1; i >= 0; i-- {
+		claim := claims[i]
+		if len(children[i]) == 0 {
+			// Uncontested claims are only countered if they were successfully stepped against.
+			counteredBy[i] = claim.CounteredBy
+			continue
+		}
+		var countered common.Address
+		var leftmostCounter *big.Int
+		for _, childIdx := range children[i] {
+			if counteredBy[childIdx] != (common.Address{}) {
+				continue
+			}
+			// The left-most uncountered child is the counter to the parent.
+			childPos := claims[childIdx].Position.ToGIndex()
+			if leftmostCounter == nil || childPos.Cmp(leftmostCounter) < 0 {
+				countered = claims[childIdx].Claimant
+				leftmostCounter = childPos
+			}
+		}
+		counteredBy[i] = countered
+	}
+	return counteredBy
+}
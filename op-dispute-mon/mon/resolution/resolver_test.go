@@ -0,0 +1,100 @@
+package resolution
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	honest     = common.Address{0xaa}
+	dishonest  = common.Address{0xbb}
+	challenger = common.Address{0xcc}
+)
+
+func TestResolve(t *testing.T) {
+	t.Run("NoClaims", func(t *testing.T) {
+		require.Equal(t, gameTypes.GameStatusInProgress, Resolve(nil))
+	})
+
+	t.Run("UncontestedRoot", func(t *testing.T) {
+		claims := []faultTypes.Claim{rootClaim(dishonest)}
+		require.Equal(t, gameTypes.GameStatusDefenderWon, Resolve(claims))
+	})
+
+	t.Run("UncounteredAttack", func(t *testing.T) {
+		claims := []faultTypes.Claim{
+			rootClaim(dishonest),
+			newClaim(1, 0, 1, 0, honest),
+		}
+		require.Equal(t, gameTypes.GameStatusChallengerWon, Resolve(claims))
+	})
+
+	t.Run("CounteredAttack", func(t *testing.T) {
+		claims := []faultTypes.Claim{
+			rootClaim(honest),
+			newClaim(1, 0, 1, 0, dishonest),
+			newClaim(2, 1, 2, 0, honest),
+		}
+		require.Equal(t, gameTypes.GameStatusDefenderWon, Resolve(claims))
+	})
+
+	t.Run("MultipleAttacksOneUncountered", func(t *testing.T) {
+		claims := []faultTypes.Claim{
+			rootClaim(dishonest),
+			newClaim(1, 0, 1, 0, dishonest),
+			newClaim(2, 1, 2, 0, honest),
+			newClaim(3, 0, 1, 0, honest),
+		}
+		require.Equal(t, gameTypes.GameStatusChallengerWon, Resolve(claims))
+	})
+
+	t.Run("SteppedLeafIsCountered", func(t *testing.T) {
+		leaf := newClaim(2, 1, 2, 0, dishonest)
+		leaf.CounteredBy = honest
+		claims := []faultTypes.Claim{
+			rootClaim(honest),
+			newClaim(1, 0, 1, 0, dishonest),
+			leaf,
+		}
+		require.Equal(t, gameTypes.GameStatusChallengerWon, Resolve(claims))
+	})
+
+	t.Run("LeftmostCounterChosen", func(t *testing.T) {
+		claims := []faultTypes.Claim{
+			rootClaim(dishonest),
+			newClaim(1, 0, 1, 1, honest),
+			newClaim(2, 0, 1, 0, challenger),
+		}
+		require.Equal(t, gameTypes.GameStatusChallengerWon, Resolve(claims))
+		counteredBy := resolveSubgames(claims)
+		require.Equal(t, challenger, counteredBy[0])
+	})
+}
+
+func rootClaim(claimant common.Address) faultTypes.Claim {
+	return faultTypes.Claim{
+		ClaimData: faultTypes.ClaimData{
+			Position: faultTypes.NewPositionFromGIndex(big.NewInt(1)),
+		},
+		Claimant:            claimant,
+		ContractIndex:       0,
+		ParentContractIndex: math.MaxUint32,
+	}
+}
+
+func newClaim(idx int, parentIdx int, depth int, indexAtDepth int64, claimant common.Address) faultTypes.Claim {
+	return faultTypes.Claim{
+		ClaimData: faultTypes.ClaimData{
+			Position: faultTypes.NewPosition(faultTypes.Depth(depth), big.NewInt(indexAtDepth)),
+		},
+		Claimant:            claimant,
+		ContractIndex:       idx,
+		ParentContractIndex: parentIdx,
+	}
+}
@@ -4,25 +4,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"sync/atomic"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/config"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/bonds"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/extract"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/version"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 )
 
 type Service struct {
 	logger  log.Logger
 	metrics metrics.Metricer
+	monitor *gameMonitor
 
-	l1Client *ethclient.Client
+	cl clock.Clock
+
+	extractor *extract.Extractor
+	validator *outputValidator
+	detector  *detector
+	bonds     *bonds.Bonds
+
+	game            *extract.GameCallerCreator
+	factoryContract *contracts.DisputeGameFactoryContract
+
+	l1Client     *ethclient.Client
+	rollupClient *sources.RollupClient
 
 	pprofService *oppprof.Service
 	metricsSrv   *httputil.HTTPServer
@@ -33,6 +53,7 @@ type Service struct {
 // NewService creates a new Service.
 func NewService(ctx context.Context, logger log.Logger, cfg *config.Config) (*Service, error) {
 	s := &Service{
+		cl:      clock.SystemClock,
 		logger:  logger,
 		metrics: metrics.NewMetrics(),
 	}
@@ -48,18 +69,52 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.initL1Client(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to init l1 client: %w", err)
 	}
+	if err := s.initRollupClient(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to init rollup client: %w", err)
+	}
 	if err := s.initPProf(&cfg.PprofConfig); err != nil {
 		return fmt.Errorf("failed to init profiling: %w", err)
 	}
 	if err := s.initMetricsServer(&cfg.MetricsConfig); err != nil {
 		return fmt.Errorf("failed to init metrics server: %w", err)
 	}
+	if err := s.initFactoryContract(cfg); err != nil {
+		return fmt.Errorf("failed to create factory contract bindings: %w", err)
+	}
+
+	s.initGameCallerCreator()
+	s.initExtractor()
+	s.initOutputValidator()
+	s.initDetector(cfg)
+	s.initBonds()
+
+	s.initMonitor(ctx, cfg)
 
 	s.metrics.RecordInfo(version.SimpleWithMeta)
 	s.metrics.RecordUp()
 	return nil
 }
 
+func (s *Service) initGameCallerCreator() {
+	s.game = extract.NewGameCallerCreator(s.metrics, batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize))
+}
+
+func (s *Service) initExtractor() {
+	s.extractor = extract.NewExtractor(s.logger, s.metrics, s.game.CreateContract, s.factoryContract.GetGamesAtOrAfter)
+}
+
+func (s *Service) initOutputValidator() {
+	s.validator = newOutputValidator(s.logger, s.rollupClient)
+}
+
+func (s *Service) initDetector(cfg *config.Config) {
+	s.detector = newDetector(s.logger, s.metrics, s.cl, s.validator, cfg.NearDeadlineWindow)
+}
+
+func (s *Service) initBonds() {
+	s.bonds = bonds.NewBonds(s.logger, s.metrics)
+}
+
 func (s *Service) initL1Client(ctx context.Context, cfg *config.Config) error {
 	l1Client, err := dial.DialEthClientWithTimeout(ctx, dial.DefaultDialTimeout, s.logger, cfg.L1EthRpc)
 	if err != nil {
@@ -69,6 +124,15 @@ func (s *Service) initL1Client(ctx context.Context, cfg *config.Config) error {
 	return nil
 }
 
+func (s *Service) initRollupClient(ctx context.Context, cfg *config.Config) error {
+	rollupClient, err := dial.DialRollupClientWithTimeout(ctx, dial.DefaultDialTimeout, s.logger, cfg.RollupRpc)
+	if err != nil {
+		return fmt.Errorf("failed to dial rollup client: %w", err)
+	}
+	s.rollupClient = rollupClient
+	return nil
+}
+
 func (s *Service) initPProf(cfg *oppprof.CLIConfig) error {
 	s.pprofService = oppprof.New(
 		cfg.ListenEnabled,
@@ -104,9 +168,41 @@ func (s *Service) initMetricsServer(cfg *opmetrics.CLIConfig) error {
 	return nil
 }
 
+func (s *Service) initFactoryContract(cfg *config.Config) error {
+	factoryContract, err := contracts.NewDisputeGameFactoryContract(cfg.GameFactoryAddress,
+		batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize))
+	if err != nil {
+		return fmt.Errorf("failed to bind the fault dispute game factory contract: %w", err)
+	}
+	s.factoryContract = factoryContract
+	return nil
+}
+
+func (s *Service) initMonitor(ctx context.Context, cfg *config.Config) {
+	blockHashFetcher := func(ctx context.Context, blockNumber *big.Int) (common.Hash, error) {
+		header, err := s.l1Client.HeaderByNumber(ctx, blockNumber)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to fetch block header: %w", err)
+		}
+		return header.Hash(), nil
+	}
+	s.monitor = newGameMonitor(
+		ctx,
+		s.logger,
+		s.cl,
+		cfg.MonitorInterval,
+		cfg.GameWindow,
+		s.detector.Detect,
+		s.bonds.CheckBonds,
+		s.extractor.Extract,
+		s.l1Client.BlockNumber,
+		blockHashFetcher,
+	)
+}
+
 func (s *Service) Start(ctx context.Context) error {
-	s.logger.Info("starting scheduler")
 	s.logger.Info("starting monitoring")
+	s.monitor.StartMonitoring()
 	s.logger.Info("dispute monitor game service start completed")
 	return nil
 }
@@ -119,11 +215,20 @@ func (s *Service) Stop(ctx context.Context) error {
 	s.logger.Info("stopping dispute mon service")
 
 	var result error
+	if s.monitor != nil {
+		s.monitor.StopMonitoring()
+	}
 	if s.pprofService != nil {
 		if err := s.pprofService.Stop(ctx); err != nil {
 			result = errors.Join(result, fmt.Errorf("failed to close pprof server: %w", err))
 		}
 	}
+	if s.rollupClient != nil {
+		s.rollupClient.Close()
+	}
+	if s.l1Client != nil {
+		s.l1Client.Close()
+	}
 	if s.metricsSrv != nil {
 		if err := s.metricsSrv.Stop(ctx); err != nil {
 			result = errors.Join(result, fmt.Errorf("failed to close metrics server: %w", err))
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EnrichedGameData is the game metadata loaded from the factory, enriched with
+// additional state read from the game contract itself.
+type EnrichedGameData struct {
+	types.GameMetadata
+	L2BlockNumber uint64
+	RootClaim     common.Hash
+	Status        types.GameStatus
+	Duration      uint64
+	Claims        []faultTypes.Claim
+
+	// Credits is the credit currently available to claim from the game for each address that
+	// has made a claim or countered a claim in the game.
+	Credits map[common.Address]*big.Int
+}
+
+// CreatedAt returns the time the game was created.
+func (g *EnrichedGameData) CreatedAt() time.Time {
+	return time.Unix(int64(g.Timestamp), 0)
+}
+
+// Deadline returns the time after which the game can be resolved if no further moves are made.
+func (g *EnrichedGameData) Deadline() time.Time {
+	return time.Unix(int64(g.Timestamp+g.Duration), 0)
+}
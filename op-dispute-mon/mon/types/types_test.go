@@ -0,0 +1,18 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichedGameData_Times(t *testing.T) {
+	game := EnrichedGameData{
+		GameMetadata: types.GameMetadata{Timestamp: 1000},
+		Duration:     500,
+	}
+	require.Equal(t, time.Unix(1000, 0), game.CreatedAt())
+	require.Equal(t, time.Unix(1500, 0), game.Deadline())
+}
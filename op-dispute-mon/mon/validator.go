@@ -0,0 +1,42 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type OutputRollupClient interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
+type outputValidator struct {
+	log    log.Logger
+	client OutputRollupClient
+}
+
+func newOutputValidator(logger log.Logger, client OutputRollupClient) *outputValidator {
+	return &outputValidator{
+		log:    logger,
+		client: client,
+	}
+}
+
+// CheckRootAgreement validates the specified root claim against the output at the given block number.
+// Claims for blocks beyond the trusted node's safe head are considered invalid as they cannot yet be
+// derived from L1 data.
+func (o *outputValidator) CheckRootAgreement(ctx context.Context, blockNum uint64, rootClaim common.Hash) (bool, common.Hash, error) {
+	output, err := o.client.OutputAtBlock(ctx, blockNum)
+	if err != nil {
+		return false, common.Hash{}, fmt.Errorf("failed to get output at block: %w", err)
+	}
+	expected := common.Hash(output.OutputRoot)
+	if output.Status != nil && output.Status.SafeL2.Number < blockNum {
+		o.log.Debug("Root claim is for a block beyond the safe head", "blockNum", blockNum, "safeHead", output.Status.SafeL2.Number)
+		return false, expected, nil
+	}
+	return expected == rootClaim, expected, nil
+}
@@ -0,0 +1,79 @@
+package mon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockRootClaim = common.HexToHash("0x11")
+)
+
+func TestOutputValidator_CheckRootAgreement(t *testing.T) {
+	t.Run("OutputFetchFails", func(t *testing.T) {
+		validator, rollup := setupOutputValidatorTest(t)
+		rollup.err = errors.New("boom")
+		agree, fetched, err := validator.CheckRootAgreement(context.Background(), 0, mockRootClaim)
+		require.ErrorIs(t, err, rollup.err)
+		require.Equal(t, common.Hash{}, fetched)
+		require.False(t, agree)
+	})
+
+	t.Run("OutputMismatch", func(t *testing.T) {
+		validator, _ := setupOutputValidatorTest(t)
+		agree, fetched, err := validator.CheckRootAgreement(context.Background(), 0, common.Hash{})
+		require.NoError(t, err)
+		require.Equal(t, mockRootClaim, fetched)
+		require.False(t, agree)
+	})
+
+	t.Run("OutputMatches", func(t *testing.T) {
+		validator, _ := setupOutputValidatorTest(t)
+		agree, fetched, err := validator.CheckRootAgreement(context.Background(), 0, mockRootClaim)
+		require.NoError(t, err)
+		require.Equal(t, mockRootClaim, fetched)
+		require.True(t, agree)
+	})
+
+	t.Run("BlockBeyondSafeHead", func(t *testing.T) {
+		validator, rollup := setupOutputValidatorTest(t)
+		rollup.safeHeadNum = 99
+		agree, fetched, err := validator.CheckRootAgreement(context.Background(), 100, mockRootClaim)
+		require.NoError(t, err)
+		require.Equal(t, mockRootClaim, fetched)
+		require.False(t, agree)
+	})
+}
+
+func setupOutputValidatorTest(t *testing.T) (*outputValidator, *stubRollupClient) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	client := &stubRollupClient{safeHeadNum: 1000}
+	validator := newOutputValidator(logger, client)
+	return validator, client
+}
+
+type stubRollupClient struct {
+	blockNum    uint64
+	err         error
+	safeHeadNum uint64
+}
+
+func (s *stubRollupClient) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	s.blockNum = blockNum
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &eth.OutputResponse{
+		OutputRoot: eth.Bytes32(mockRootClaim),
+		Status: &eth.SyncStatus{
+			SafeL2: eth.L2BlockRef{Number: s.safeHeadNum},
+		},
+	}, nil
+}
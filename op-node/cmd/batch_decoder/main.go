@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"os"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/fetch"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/reassemble"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/replay"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	gethlog "github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -205,9 +214,248 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "record",
+			Usage: "Records the L1 and L2 data that is needed to replay derivation offline",
+			Flags: append(append(append([]cli.Flag{}, rollupConfigFlags...), recordFlags...),
+				&cli.StringFlag{
+					Name:     "out",
+					Required: true,
+					Usage:    "File to write the recorded data to",
+				},
+			),
+			Action: func(cliCtx *cli.Context) error {
+				logger := oplog.NewLogger(os.Stderr, oplog.DefaultCLIConfig())
+				rollupCfg, err := loadRollupConfig(cliCtx)
+				if err != nil {
+					log.Fatal(err)
+				}
+				archive, err := recordArchive(cliCtx, logger, rollupCfg)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if err := replay.WriteArchive(cliCtx.String("out"), archive); err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("Recorded %v L1 blocks, %v L2 blocks to start from and %v L2 headers to %v\n",
+					len(archive.L1Blocks), len(archive.L2Blocks), len(archive.L2Headers), cliCtx.String("out"))
+				return nil
+			},
+		},
+		{
+			Name: "derive",
+			Usage: "Derives the L2 payload attributes and safe head progression from recorded L1 data, or from the RPCs directly, " +
+				"without an L2 execution client",
+			Flags: append(append(append([]cli.Flag{}, rollupConfigFlags...), recordFlags...),
+				&cli.StringFlag{
+					Name:  "in",
+					Usage: "File with the data recorded by the record command. If not set, the data is fetched from the RPCs.",
+				},
+				&cli.StringFlag{
+					Name:  "out",
+					Usage: "File to write the derivation output to, as JSON lines. Defaults to stdout.",
+				},
+			),
+			Action: func(cliCtx *cli.Context) error {
+				logger := oplog.NewLogger(os.Stderr, oplog.DefaultCLIConfig())
+				rollupCfg, err := loadRollupConfig(cliCtx)
+				if err != nil {
+					log.Fatal(err)
+				}
+				var archive *replay.Archive
+				if cliCtx.IsSet("in") {
+					archive, err = replay.LoadArchive(cliCtx.String("in"))
+				} else {
+					archive, err = recordArchive(cliCtx, logger, rollupCfg)
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+				out := os.Stdout
+				if cliCtx.IsSet("out") {
+					out, err = os.Create(cliCtx.String("out"))
+					if err != nil {
+						log.Fatal(err)
+					}
+					defer out.Close()
+				}
+				result, err := replay.Derive(context.Background(), logger, rollupCfg, archive, out)
+				if err != nil {
+					log.Fatal(err)
+				}
+				fmt.Fprintf(os.Stderr, "Derived %v L2 blocks, %v of which do not match the recorded L2 blocks. Safe head: %v\n",
+					result.Blocks, result.Mismatches, result.SafeHead)
+				return nil
+			},
+		},
+		{
+			Name:  "pipeline-state",
+			Usage: "Prints the live state of the derivation pipeline stages of a rollup node",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "rollup-rpc",
+					Required: true,
+					Usage:    "Rollup node RPC URL, with the admin API enabled",
+					EnvVars:  []string{"ROLLUP_RPC"},
+				},
+			},
+			Action: func(cliCtx *cli.Context) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				rpcClient, err := client.NewRPC(ctx, oplog.NewLogger(os.Stderr, oplog.DefaultCLIConfig()), cliCtx.String("rollup-rpc"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				defer rpcClient.Close()
+				state, err := sources.NewRollupClient(rpcClient).DerivationPipelineState(ctx)
+				if err != nil {
+					log.Fatal(err)
+				}
+				return printJSON(state)
+			},
+		},
+		{
+			Name:  "replay-trace",
+			Usage: "Reconstructs the state of the derivation pipeline stages at a step of a recorded derivation trace",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "in",
+					Required: true,
+					Usage:    "Derivation trace file, as recorded by the rollup node with --derivation.trace-path",
+				},
+				&cli.Uint64Flag{
+					Name:  "step",
+					Value: math.MaxUint64,
+					Usage: "Pipeline step to reconstruct the state at. Defaults to the last step of the trace.",
+				},
+			},
+			Action: func(cliCtx *cli.Context) error {
+				f, err := os.Open(cliCtx.String("in"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				defer f.Close()
+				state, stepErrs, err := derive.ReplayPipelineTrace(f, cliCtx.Uint64("step"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				for _, entry := range stepErrs {
+					fmt.Fprintf(os.Stderr, "Step %d failed: %s\n", entry.Step, entry.Error)
+				}
+				return printJSON(state)
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
+
+var rollupConfigFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "network",
+		Usage: "Predefined network to load the rollup config of",
+	},
+	&cli.StringFlag{
+		Name:  "rollup-config",
+		Usage: "Rollup config file, used if no network is set",
+	},
+}
+
+// recordFlags are the flags of the data to record, which are only required if the data is recorded.
+var recordFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "l1",
+		Usage:   "L1 RPC URL",
+		EnvVars: []string{"L1_RPC"},
+	},
+	&cli.StringFlag{
+		Name:    "l1-beacon",
+		Usage:   "L1 Beacon API URL, required if the batcher posted blobs in the recorded L1 blocks",
+		EnvVars: []string{"L1_BEACON"},
+	},
+	&cli.StringFlag{
+		Name:    "l2",
+		Usage:   "L2 RPC URL, to record the L2 blocks to start derivation from and the L2 headers to compare the derived blocks to",
+		EnvVars: []string{"L2_RPC"},
+	},
+	&cli.Uint64Flag{
+		Name: "l2-start",
+		Usage: "Safe L2 block to start derivation from. Defaults to the L2 genesis block. " +
+			"The L1 blocks are recorded from up to a channel timeout before the L1 origin of this block.",
+	},
+	&cli.Uint64Flag{
+		Name:  "l1-end",
+		Usage: "Last L1 block (exclusive) to derive from",
+	},
+	&cli.IntFlag{
+		Name:  "concurrent-requests",
+		Value: 10,
+		Usage: "Concurrency level when fetching L1 and L2 data",
+	},
+}
+
+func loadRollupConfig(cliCtx *cli.Context) (*rollup.Config, error) {
+	var rollupCfg *rollup.Config
+	if network := cliCtx.String("network"); network != "" {
+		cfg, err := chaincfg.GetRollupConfig(network)
+		if err != nil {
+			return nil, err
+		}
+		rollupCfg = cfg
+	} else if path := cliCtx.String("rollup-config"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rollup config: %w", err)
+		}
+		defer f.Close()
+		rollupCfg = new(rollup.Config)
+		if err := json.NewDecoder(f).Decode(rollupCfg); err != nil {
+			return nil, fmt.Errorf("failed to decode rollup config: %w", err)
+		}
+	} else {
+		return nil, errors.New("either a network or a rollup config file is required")
+	}
+	if err := rollupCfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid rollup config: %w", err)
+	}
+	return rollupCfg, nil
+}
+
+func recordArchive(cliCtx *cli.Context, logger gethlog.Logger, rollupCfg *rollup.Config) (*replay.Archive, error) {
+	for _, name := range []string{"l1", "l2", "l1-end"} {
+		if !cliCtx.IsSet(name) {
+			return nil, fmt.Errorf("flag %q is required to record the data", name)
+		}
+	}
+	l1Client, err := ethclient.Dial(cliCtx.String("l1"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial L1 RPC: %w", err)
+	}
+	defer l1Client.Close()
+	l2Client, err := ethclient.Dial(cliCtx.String("l2"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial L2 RPC: %w", err)
+	}
+	defer l2Client.Close()
+	var blobs derive.L1BlobsFetcher
+	if addr := cliCtx.String("l1-beacon"); addr != "" {
+		blobs = sources.NewL1BeaconClient(client.NewBasicHTTPClient(addr, logger), sources.L1BeaconClientConfig{})
+	}
+	l2Start := rollupCfg.Genesis.L2.Number
+	if cliCtx.IsSet("l2-start") {
+		l2Start = cliCtx.Uint64("l2-start")
+	}
+	return replay.Record(context.Background(), logger, rollupCfg, replay.RecordConfig{
+		L2Start:            l2Start,
+		L1End:              cliCtx.Uint64("l1-end"),
+		ConcurrentRequests: uint64(cliCtx.Int("concurrent-requests")),
+	}, l1Client, blobs, l2Client)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Archive is a recording of the L1 and L2 data that is needed to derive the L2 chain from a range of L1 blocks,
+// without access to an L1 or L2 node.
+type Archive struct {
+	// L1Blocks are the consecutive L1 blocks that the L2 chain is derived from.
+	L1Blocks []L1Block `json:"l1_blocks"`
+	// Blobs are the blobs of the batcher transactions in the L1 blocks.
+	Blobs []BlobWithHash `json:"blobs"`
+	// L2Blocks are the RLP encoded L2 blocks to start derivation from: the first block is the safe head to start from,
+	// followed by its ancestors up to the block of which the L1 origin is old enough to start reading channels from.
+	L2Blocks []hexutil.Bytes `json:"l2_blocks"`
+	// L2Headers are the RLP encoded headers of the L2 blocks after the safe head to start from,
+	// as they were created by the L2 execution client. They are used to give the derived L2 blocks their canonical hashes.
+	L2Headers []hexutil.Bytes `json:"l2_headers"`
+}
+
+// L1Block is the recorded data of an L1 block.
+// Only the data that the L2 chain is derived from is recorded, to keep the archive small.
+type L1Block struct {
+	// Header is the RLP encoded block header.
+	Header hexutil.Bytes `json:"header"`
+	// Transactions are the binary encoded transactions that were sent to the batch inbox address.
+	Transactions []hexutil.Bytes `json:"transactions"`
+	// Receipts are the receipts with logs of the deposit contract or system config contract.
+	Receipts []*types.Receipt `json:"receipts"`
+}
+
+type BlobWithHash struct {
+	Hash common.Hash `json:"hash"`
+	Blob *eth.Blob   `json:"blob"`
+}
+
+// LoadArchive reads an archive from the given JSON file.
+func LoadArchive(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	var archive Archive
+	if err := json.NewDecoder(f).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	return &archive, nil
+}
+
+// WriteArchive writes the archive to the given JSON file.
+func WriteArchive(path string, archive *Archive) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(archive); err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+	return nil
+}
+
+func encodeL2Block(block *types.Block) (hexutil.Bytes, error) {
+	return rlp.EncodeToBytes(block)
+}
+
+func decodeL2Block(data hexutil.Bytes) (*types.Block, error) {
+	var block types.Block
+	if err := rlp.DecodeBytes(data, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func decodeHeader(data hexutil.Bytes) (*types.Header, error) {
+	var header types.Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
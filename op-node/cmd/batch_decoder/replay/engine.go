@@ -0,0 +1,304 @@
+package replay
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// BuildFn is called with every block that the engine builds from payload attributes.
+// The mismatch describes how the block differs from the recorded L2 block at the same height, and is empty if it does not.
+type BuildFn func(attrs *eth.PayloadAttributes, block eth.L2BlockRef, mismatch string)
+
+// engine is a mocked L2 execution engine. It does not execute transactions, but builds blocks from payload attributes
+// with the transactions in the attributes. The hashes of the blocks that match the recorded L2 headers are the canonical
+// hashes, so the batches that build on them are valid. Other blocks get a hash of a header with the derived fields only.
+type engine struct {
+	cfg     *rollup.Config
+	onBuild BuildFn
+
+	blocks    map[common.Hash]*types.Block
+	canonical map[uint64]common.Hash
+	recorded  map[uint64]*types.Header
+
+	unsafe, safe, finalized common.Hash
+
+	// built are the blocks built from payload attributes, by payload ID, which are not retrieved yet
+	built  map[eth.PayloadID]*types.Block
+	nextID uint64
+}
+
+var _ derive.Engine = (*engine)(nil)
+
+func newEngine(cfg *rollup.Config, archive *Archive, onBuild BuildFn) (*engine, error) {
+	e := &engine{
+		cfg:       cfg,
+		onBuild:   onBuild,
+		blocks:    make(map[common.Hash]*types.Block),
+		canonical: make(map[uint64]common.Hash),
+		recorded:  make(map[uint64]*types.Header),
+		built:     make(map[eth.PayloadID]*types.Block),
+	}
+	if len(archive.L2Blocks) == 0 {
+		return nil, fmt.Errorf("%w: no L2 block to start from", ErrMissingData)
+	}
+	var start, child *types.Block
+	for i, data := range archive.L2Blocks {
+		block, err := decodeL2Block(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode L2 block %d of archive: %w", i, err)
+		}
+		if child != nil && child.ParentHash() != block.Hash() {
+			return nil, fmt.Errorf("L2 block %s of archive is not the parent of L2 block %s", eth.ToBlockID(block), eth.ToBlockID(child))
+		}
+		e.blocks[block.Hash()] = block
+		e.canonical[block.NumberU64()] = block.Hash()
+		if start == nil {
+			start = block
+		}
+		child = block
+	}
+	// the first block is the head of the L2 chain, and derivation restarts from it
+	e.unsafe, e.safe, e.finalized = start.Hash(), start.Hash(), start.Hash()
+	for i, data := range archive.L2Headers {
+		header, err := decodeHeader(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode L2 header %d of archive: %w", i, err)
+		}
+		e.recorded[header.Number.Uint64()] = header
+	}
+	return e, nil
+}
+
+func (e *engine) block(hash common.Hash) (*types.Block, error) {
+	block, ok := e.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("L2 block %s: %w", hash, ethereum.NotFound)
+	}
+	return block, nil
+}
+
+func (e *engine) GetPayload(ctx context.Context, payloadInfo eth.PayloadInfo) (*eth.ExecutionPayloadEnvelope, error) {
+	block, ok := e.built[payloadInfo.ID]
+	if !ok {
+		return nil, eth.InputError{Inner: errors.New("unknown payload"), Code: eth.UnknownPayload}
+	}
+	delete(e.built, payloadInfo.ID)
+	return e.envelope(block)
+}
+
+func (e *engine) ForkchoiceUpdate(ctx context.Context, state *eth.ForkchoiceState, attr *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error) {
+	head, ok := e.blocks[state.HeadBlockHash]
+	if !ok {
+		return nil, eth.InputError{Inner: fmt.Errorf("unknown head block %s", state.HeadBlockHash), Code: eth.InvalidForkchoiceState}
+	}
+	for _, h := range []common.Hash{state.SafeBlockHash, state.FinalizedBlockHash} {
+		if _, ok := e.blocks[h]; h != (common.Hash{}) && !ok {
+			return nil, eth.InputError{Inner: fmt.Errorf("unknown block %s", h), Code: eth.InvalidForkchoiceState}
+		}
+	}
+	e.setHead(head)
+	if state.SafeBlockHash != (common.Hash{}) {
+		e.safe = state.SafeBlockHash
+	}
+	if state.FinalizedBlockHash != (common.Hash{}) {
+		e.finalized = state.FinalizedBlockHash
+	}
+	res := &eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid, LatestValidHash: &state.HeadBlockHash}}
+	if attr == nil {
+		return res, nil
+	}
+	block, err := e.build(head, attr)
+	if err != nil {
+		return nil, eth.InputError{Inner: err, Code: eth.InvalidPayloadAttributes}
+	}
+	var id eth.PayloadID
+	e.nextID++
+	binary.BigEndian.PutUint64(id[:], e.nextID)
+	e.built[id] = block
+	res.PayloadID = &id
+	return res, nil
+}
+
+// setHead makes the given block the head of the canonical chain.
+func (e *engine) setHead(head *types.Block) {
+	for num := head.NumberU64() + 1; e.canonical[num] != (common.Hash{}); num++ {
+		delete(e.canonical, num)
+	}
+	for block := head; block != nil && e.canonical[block.NumberU64()] != block.Hash(); block = e.blocks[block.ParentHash()] {
+		e.canonical[block.NumberU64()] = block.Hash()
+	}
+	e.unsafe = head.Hash()
+}
+
+func (e *engine) NewPayload(ctx context.Context, payload *eth.ExecutionPayload, parentBeaconBlockRoot *common.Hash) (*eth.PayloadStatusV1, error) {
+	if _, ok := e.blocks[payload.BlockHash]; !ok {
+		msg := "only blocks built by the engine can be inserted"
+		return &eth.PayloadStatusV1{Status: eth.ExecutionInvalid, ValidationError: &msg}, nil
+	}
+	return &eth.PayloadStatusV1{Status: eth.ExecutionValid, LatestValidHash: &payload.BlockHash}, nil
+}
+
+// build creates a block with the transactions of the payload attributes on top of the given parent.
+func (e *engine) build(parent *types.Block, attrs *eth.PayloadAttributes) (*types.Block, error) {
+	if attrs.GasLimit == nil {
+		return nil, errors.New("missing gas limit in payload attributes")
+	}
+	txs := make(types.Transactions, len(attrs.Transactions))
+	for i, data := range attrs.Transactions {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d of payload attributes: %w", i, err)
+		}
+	}
+	header := &types.Header{
+		ParentHash:       parent.Hash(),
+		UncleHash:        types.EmptyUncleHash,
+		Coinbase:         attrs.SuggestedFeeRecipient,
+		TxHash:           types.DeriveSha(txs, trie.NewStackTrie(nil)),
+		ReceiptHash:      types.EmptyReceiptsHash,
+		Difficulty:       common.Big0,
+		Number:           new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:         uint64(*attrs.GasLimit),
+		Time:             uint64(attrs.Timestamp),
+		MixDigest:        common.Hash(attrs.PrevRandao),
+		BaseFee:          parent.BaseFee(),
+		ParentBeaconRoot: attrs.ParentBeaconBlockRoot,
+	}
+	if header.BaseFee == nil {
+		header.BaseFee = new(big.Int)
+	}
+	var withdrawals types.Withdrawals
+	if attrs.Withdrawals != nil {
+		withdrawals = *attrs.Withdrawals
+		withdrawalsHash := types.DeriveSha(withdrawals, trie.NewStackTrie(nil))
+		header.WithdrawalsHash = &withdrawalsHash
+	}
+	if e.cfg.IsEcotone(header.Time) {
+		header.BlobGasUsed = new(uint64)
+		header.ExcessBlobGas = new(uint64)
+	}
+
+	mismatch := "no recorded L2 block"
+	if recorded, ok := e.recorded[header.Number.Uint64()]; ok {
+		mismatch = compareHeaders(header, recorded)
+		if mismatch == "" {
+			// the recorded header has the execution results, and the canonical hash
+			header = recorded
+		} else {
+			header.BaseFee = recorded.BaseFee
+		}
+	}
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil).WithWithdrawals(withdrawals)
+	ref, err := derive.L2BlockToBlockRef(e.cfg, block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid L2 block: %w", err)
+	}
+	e.blocks[block.Hash()] = block
+	e.onBuild(attrs, ref, mismatch)
+	return block, nil
+}
+
+// compareHeaders describes the differences between the derived fields of the headers, or returns an empty string if there are none.
+func compareHeaders(derived, recorded *types.Header) string {
+	var diffs []string
+	diff := func(field string, a, b any) {
+		diffs = append(diffs, fmt.Sprintf("%s: derived %v, recorded %v", field, a, b))
+	}
+	if derived.ParentHash != recorded.ParentHash {
+		diff("parent hash", derived.ParentHash, recorded.ParentHash)
+	}
+	if derived.TxHash != recorded.TxHash {
+		diff("transactions root", derived.TxHash, recorded.TxHash)
+	}
+	if derived.Time != recorded.Time {
+		diff("timestamp", derived.Time, recorded.Time)
+	}
+	if derived.Coinbase != recorded.Coinbase {
+		diff("fee recipient", derived.Coinbase, recorded.Coinbase)
+	}
+	if derived.GasLimit != recorded.GasLimit {
+		diff("gas limit", derived.GasLimit, recorded.GasLimit)
+	}
+	if derived.MixDigest != recorded.MixDigest {
+		diff("prev randao", derived.MixDigest, recorded.MixDigest)
+	}
+	if !equalHashPtrs(derived.WithdrawalsHash, recorded.WithdrawalsHash) {
+		diff("withdrawals root", derived.WithdrawalsHash, recorded.WithdrawalsHash)
+	}
+	if !equalHashPtrs(derived.ParentBeaconRoot, recorded.ParentBeaconRoot) {
+		diff("parent beacon block root", derived.ParentBeaconRoot, recorded.ParentBeaconRoot)
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func equalHashPtrs(a, b *common.Hash) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (e *engine) envelope(block *types.Block) (*eth.ExecutionPayloadEnvelope, error) {
+	payload, err := eth.BlockAsPayload(block, e.cfg.CanyonTime)
+	if err != nil {
+		return nil, err
+	}
+	return &eth.ExecutionPayloadEnvelope{ParentBeaconBlockRoot: block.BeaconRoot(), ExecutionPayload: payload}, nil
+}
+
+func (e *engine) PayloadByHash(ctx context.Context, hash common.Hash) (*eth.ExecutionPayloadEnvelope, error) {
+	block, err := e.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	return e.envelope(block)
+}
+
+func (e *engine) PayloadByNumber(ctx context.Context, num uint64) (*eth.ExecutionPayloadEnvelope, error) {
+	return e.PayloadByHash(ctx, e.canonical[num])
+}
+
+func (e *engine) L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L2BlockRef, error) {
+	switch label {
+	case eth.Unsafe:
+		return e.L2BlockRefByHash(ctx, e.unsafe)
+	case eth.Safe:
+		return e.L2BlockRefByHash(ctx, e.safe)
+	case eth.Finalized:
+		return e.L2BlockRefByHash(ctx, e.finalized)
+	default:
+		return eth.L2BlockRef{}, fmt.Errorf("unknown label: %v", label)
+	}
+}
+
+func (e *engine) L2BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L2BlockRef, error) {
+	block, err := e.block(hash)
+	if err != nil {
+		return eth.L2BlockRef{}, err
+	}
+	return derive.L2BlockToBlockRef(e.cfg, block)
+}
+
+func (e *engine) L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error) {
+	return e.L2BlockRefByHash(ctx, e.canonical[num])
+}
+
+func (e *engine) SystemConfigByL2Hash(ctx context.Context, hash common.Hash) (eth.SystemConfig, error) {
+	envelope, err := e.PayloadByHash(ctx, hash)
+	if err != nil {
+		return eth.SystemConfig{}, err
+	}
+	return derive.PayloadToSystemConfig(e.cfg, envelope.ExecutionPayload)
+}
@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrMissingData is returned when data that is needed for derivation is not in the archive.
+var ErrMissingData = errors.New("missing data in archive")
+
+type l1Block struct {
+	info     eth.BlockInfo
+	txs      types.Transactions
+	receipts types.Receipts
+}
+
+// l1Source serves the L1 data of an archive to the derivation pipeline.
+type l1Source struct {
+	blocks   map[common.Hash]*l1Block
+	byNumber map[uint64]common.Hash
+	head     eth.L1BlockRef
+	blobs    map[common.Hash]*eth.Blob
+}
+
+func newL1Source(archive *Archive) (*l1Source, error) {
+	src := &l1Source{
+		blocks:   make(map[common.Hash]*l1Block, len(archive.L1Blocks)),
+		byNumber: make(map[uint64]common.Hash, len(archive.L1Blocks)),
+		blobs:    make(map[common.Hash]*eth.Blob, len(archive.Blobs)),
+	}
+	for i, block := range archive.L1Blocks {
+		header, err := decodeHeader(block.Header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode header of L1 block %d of archive: %w", i, err)
+		}
+		info := eth.HeaderBlockInfo(header)
+		if prev, ok := src.byNumber[info.NumberU64()-1]; i > 0 && (!ok || prev != info.ParentHash()) {
+			return nil, fmt.Errorf("L1 block %s of archive does not build on the previous L1 block", eth.ToBlockID(info))
+		}
+		txs := make(types.Transactions, len(block.Transactions))
+		for j, data := range block.Transactions {
+			txs[j] = new(types.Transaction)
+			if err := txs[j].UnmarshalBinary(data); err != nil {
+				return nil, fmt.Errorf("failed to decode transaction %d of L1 block %s: %w", j, eth.ToBlockID(info), err)
+			}
+		}
+		src.blocks[info.Hash()] = &l1Block{info: info, txs: txs, receipts: block.Receipts}
+		src.byNumber[info.NumberU64()] = info.Hash()
+		src.head = eth.InfoToL1BlockRef(info)
+	}
+	for _, blob := range archive.Blobs {
+		src.blobs[blob.Hash] = blob.Blob
+	}
+	return src, nil
+}
+
+func (s *l1Source) block(hash common.Hash) (*l1Block, error) {
+	block, ok := s.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: L1 block %s", ErrMissingData, hash)
+	}
+	return block, nil
+}
+
+func (s *l1Source) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	// all recorded blocks are considered to be final
+	return s.head, nil
+}
+
+// L1BlockRefByNumber returns ethereum.NotFound after the last block of the archive,
+// which signals the derivation pipeline that it reached the L1 head.
+func (s *l1Source) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	hash, ok := s.byNumber[num]
+	if !ok {
+		if num > s.head.Number {
+			return eth.L1BlockRef{}, ethereum.NotFound
+		}
+		return eth.L1BlockRef{}, fmt.Errorf("%w: L1 block %d", ErrMissingData, num)
+	}
+	return s.L1BlockRefByHash(ctx, hash)
+}
+
+func (s *l1Source) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	block, err := s.block(hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return eth.InfoToL1BlockRef(block.info), nil
+}
+
+func (s *l1Source) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	block, err := s.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	return block.info, nil
+}
+
+func (s *l1Source) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	block, err := s.block(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block.info, block.txs, nil
+}
+
+func (s *l1Source) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	block, err := s.block(blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block.info, block.receipts, nil
+}
+
+// GetBlobs returns the recorded blobs by versioned hash. The blob indices are not needed to identify the blobs.
+func (s *l1Source) GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error) {
+	blobs := make([]*eth.Blob, len(hashes))
+	for i, h := range hashes {
+		blob, ok := s.blobs[h.Hash]
+		if !ok {
+			return nil, fmt.Errorf("%w: blob %s of L1 block %s", ErrMissingData, h.Hash, ref)
+		}
+		blobs[i] = blob
+	}
+	return blobs, nil
+}
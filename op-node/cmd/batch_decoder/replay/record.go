@@ -0,0 +1,210 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/sync/errgroup"
+)
+
+// L1Client fetches the L1 blocks and receipts to record.
+type L1Client interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error)
+}
+
+// L2Client fetches the L2 blocks to start derivation from, and the L2 headers of the blocks to derive.
+type L2Client interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+type RecordConfig struct {
+	// L2Start is the number of the L2 block to start derivation from. It must be a safe block.
+	L2Start uint64
+	// L1End is the last L1 block (exclusive) to derive from.
+	L1End              uint64
+	ConcurrentRequests uint64
+}
+
+// Record fetches the data that is needed to derive the L2 chain from the given L2 block up to the given L1 block.
+// The L1 blocks are recorded from the L1 origin that the derivation pipeline starts reading channels from,
+// which is up to a channel timeout before the L1 origin of the L2 block to start from.
+// The L2 headers after the L2 block to start from are recorded up to the latest timestamp that can be derived from the L1 blocks.
+// The blobs fetcher is only needed if the batcher posted blobs in the recorded L1 blocks, and may be nil otherwise.
+func Record(ctx context.Context, logger log.Logger, cfg *rollup.Config, rcfg RecordConfig, l1 L1Client, blobs derive.L1BlobsFetcher, l2 L2Client) (*Archive, error) {
+	var archive Archive
+
+	// Record the L2 blocks that the derivation pipeline resets to, like the engine queue does on reset.
+	start, err := l2.BlockByNumber(ctx, new(big.Int).SetUint64(rcfg.L2Start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch L2 block %d to start from: %w", rcfg.L2Start, err)
+	}
+	startRef, err := derive.L2BlockToBlockRef(cfg, start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid L2 block to start from: %w", err)
+	}
+	block, pipelineL2 := start, startRef
+	for {
+		data, err := encodeL2Block(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode L2 block %s: %w", pipelineL2, err)
+		}
+		archive.L2Blocks = append(archive.L2Blocks, data)
+		afterL2Genesis := pipelineL2.Number > cfg.Genesis.L2.Number
+		afterL1Genesis := pipelineL2.L1Origin.Number > cfg.Genesis.L1.Number
+		afterChannelTimeout := pipelineL2.L1Origin.Number+cfg.ChannelTimeout > startRef.L1Origin.Number
+		if !afterL2Genesis || !afterL1Genesis || !afterChannelTimeout {
+			break
+		}
+		block, err = l2.BlockByHash(ctx, pipelineL2.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L2 block %s: %w", pipelineL2.ParentID(), err)
+		}
+		pipelineL2, err = derive.L2BlockToBlockRef(cfg, block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid L2 block %s: %w", eth.ToBlockID(block), err)
+		}
+	}
+	l1Start := pipelineL2.L1Origin.Number
+	if rcfg.L1End <= startRef.L1Origin.Number {
+		return nil, fmt.Errorf("L1 end %d is not after the L1 origin %s of the L2 block to start from", rcfg.L1End, startRef.L1Origin)
+	}
+	logger.Info("Recorded L2 blocks to start from", "start", startRef, "pipeline_l2", pipelineL2, "count", len(archive.L2Blocks))
+
+	// Record the L1 blocks
+	archive.L1Blocks = make([]L1Block, rcfg.L1End-l1Start)
+	blobsPerBlock := make([][]BlobWithHash, len(archive.L1Blocks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(int(rcfg.ConcurrentRequests))
+	for i := range archive.L1Blocks {
+		i := i
+		g.Go(func() error {
+			block, blockBlobs, err := recordL1Block(gctx, cfg, l1, blobs, l1Start+uint64(i))
+			if err != nil {
+				return err
+			}
+			archive.L1Blocks[i], blobsPerBlock[i] = *block, blockBlobs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	for _, blockBlobs := range blobsPerBlock {
+		archive.Blobs = append(archive.Blobs, blockBlobs...)
+	}
+	lastL1, err := decodeHeader(archive.L1Blocks[len(archive.L1Blocks)-1].Header)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Recorded L1 blocks", "start", l1Start, "end", rcfg.L1End, "blobs", len(archive.Blobs))
+
+	// Record the L2 headers of the L2 blocks that can be derived, to build the derived blocks with their canonical hashes.
+	// The timestamp of an L2 block is at most the max sequencer drift after the timestamp of its L1 origin.
+	maxTime := lastL1.Time + cfg.MaxSequencerDrift
+	if maxTime <= startRef.Time {
+		return &archive, nil
+	}
+	headers := make([]hexutil.Bytes, (maxTime-startRef.Time)/cfg.BlockTime)
+	g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(int(rcfg.ConcurrentRequests))
+	for i := range headers {
+		i := i
+		g.Go(func() error {
+			num := rcfg.L2Start + 1 + uint64(i)
+			header, err := l2.HeaderByNumber(gctx, new(big.Int).SetUint64(num))
+			if errors.Is(err, ethereum.NotFound) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to fetch L2 header %d: %w", num, err)
+			}
+			headers[i], err = rlp.EncodeToBytes(header)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// the headers after the L2 head are not found
+	for _, header := range headers {
+		if header == nil {
+			break
+		}
+		archive.L2Headers = append(archive.L2Headers, header)
+	}
+	logger.Info("Recorded L2 headers", "count", len(archive.L2Headers))
+	return &archive, nil
+}
+
+// recordL1Block fetches the L1 block with the given number, and returns the data of the block that derivation uses.
+func recordL1Block(ctx context.Context, cfg *rollup.Config, l1 L1Client, blobs derive.L1BlobsFetcher, num uint64) (*L1Block, []BlobWithHash, error) {
+	block, err := l1.BlockByNumber(ctx, new(big.Int).SetUint64(num))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch L1 block %d: %w", num, err)
+	}
+	receipts, err := l1.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch receipts of L1 block %s: %w", eth.ToBlockID(block), err)
+	}
+	var out L1Block
+	if out.Header, err = rlp.EncodeToBytes(block.Header()); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode header of L1 block %s: %w", eth.ToBlockID(block), err)
+	}
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address == cfg.DepositContractAddress || l.Address == cfg.L1SystemConfigAddress {
+				out.Receipts = append(out.Receipts, receipt)
+				break
+			}
+		}
+	}
+	// The index of a blob is its position among the blobs of all transactions in the block.
+	var blobHashes []eth.IndexedBlobHash
+	blobIndex := uint64(0)
+	for _, tx := range block.Transactions() {
+		isBatcherTx := tx.To() != nil && *tx.To() == cfg.BatchInboxAddress
+		if isBatcherTx {
+			data, err := tx.MarshalBinary()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to encode transaction %s: %w", tx.Hash(), err)
+			}
+			out.Transactions = append(out.Transactions, data)
+		}
+		for _, h := range tx.BlobHashes() {
+			if isBatcherTx {
+				blobHashes = append(blobHashes, eth.IndexedBlobHash{Index: blobIndex, Hash: h})
+			}
+			blobIndex++
+		}
+	}
+	if len(blobHashes) == 0 {
+		return &out, nil, nil
+	}
+	if blobs == nil {
+		return nil, nil, fmt.Errorf("L1 block %s has batcher blobs, but no beacon endpoint is configured", eth.ToBlockID(block))
+	}
+	ref := eth.InfoToL1BlockRef(eth.BlockToInfo(block))
+	blockBlobs, err := blobs.GetBlobs(ctx, ref, blobHashes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch blobs of L1 block %s: %w", ref, err)
+	}
+	recorded := make([]BlobWithHash, len(blobHashes))
+	for i, h := range blobHashes {
+		recorded[i] = BlobWithHash{Hash: h.Hash, Blob: blockBlobs[i]}
+	}
+	return &out, recorded, nil
+}
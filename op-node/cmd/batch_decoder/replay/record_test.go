@@ -0,0 +1,128 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type stubL1Client struct {
+	blocks   []*types.Block
+	receipts map[common.Hash][]*types.Receipt
+}
+
+func (c *stubL1Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if number.Uint64() >= uint64(len(c.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return c.blocks[number.Uint64()], nil
+}
+
+func (c *stubL1Client) BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	hash, ok := blockNrOrHash.Hash()
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return c.receipts[hash], nil
+}
+
+type stubL2Client struct {
+	blocks  []*types.Block
+	headers []*types.Header
+}
+
+func (c *stubL2Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if number.Uint64() >= uint64(len(c.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return c.blocks[number.Uint64()], nil
+}
+
+func (c *stubL2Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	for _, block := range c.blocks {
+		if block.Hash() == hash {
+			return block, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *stubL2Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	for _, header := range c.headers {
+		if header.Number.Cmp(number) == 0 {
+			return header, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func TestRecord(t *testing.T) {
+	cfg, archive := testArchive(t, 6)
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LevelError)
+
+	l1 := &stubL1Client{receipts: make(map[common.Hash][]*types.Receipt)}
+	for _, recorded := range archive.L1Blocks {
+		header, err := decodeHeader(recorded.Header)
+		require.NoError(t, err)
+		l1.blocks = append(l1.blocks, types.NewBlockWithHeader(header))
+	}
+	// L1 block 1 has a batcher transaction and a deposit, along with data that derivation does not use
+	batcherTxData := channelTx(t, cfg, testBatcherKey, &derive.SingularBatch{Timestamp: cfg.Genesis.L2Time + cfg.BlockTime})
+	var batcherTx types.Transaction
+	require.NoError(t, batcherTx.UnmarshalBinary(batcherTxData))
+	otherTx := types.NewTx(&types.LegacyTx{To: &common.Address{0x01}, Gas: 21_000, GasPrice: big.NewInt(10)})
+	l1.blocks[1] = l1.blocks[1].WithBody([]*types.Transaction{otherTx, &batcherTx}, nil)
+	depositReceipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs:   []*types.Log{{Address: cfg.DepositContractAddress, Topics: []common.Hash{derive.DepositEventABIHash}}},
+	}
+	otherReceipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Address: common.Address{0x01}}}}
+	l1.receipts[l1.blocks[1].Hash()] = []*types.Receipt{otherReceipt, depositReceipt}
+
+	l2Genesis, err := decodeL2Block(archive.L2Blocks[0])
+	require.NoError(t, err)
+	l2 := &stubL2Client{blocks: []*types.Block{l2Genesis}}
+	for i := int64(1); i <= 2; i++ {
+		l2.headers = append(l2.headers, &types.Header{Number: big.NewInt(i), Time: l2Genesis.Time() + uint64(i)*cfg.BlockTime})
+	}
+
+	recorded, err := Record(ctx, logger, cfg, RecordConfig{L2Start: 0, L1End: 6, ConcurrentRequests: 2}, l1, nil, l2)
+	require.NoError(t, err)
+	require.Equal(t, archive.L2Blocks, recorded.L2Blocks)
+	require.Len(t, recorded.L1Blocks, len(archive.L1Blocks))
+	for i, block := range recorded.L1Blocks {
+		require.Equal(t, archive.L1Blocks[i].Header, block.Header)
+	}
+	require.Equal(t, []hexutil.Bytes{batcherTxData}, recorded.L1Blocks[1].Transactions)
+	require.Equal(t, []*types.Receipt{depositReceipt}, recorded.L1Blocks[1].Receipts)
+	require.Empty(t, recorded.Blobs)
+	require.Len(t, recorded.L2Headers, len(l2.headers))
+	for i, header := range l2.headers {
+		data, err := rlp.EncodeToBytes(header)
+		require.NoError(t, err)
+		require.Equal(t, hexutil.Bytes(data), recorded.L2Headers[i])
+	}
+
+	t.Run("L1 end before start", func(t *testing.T) {
+		_, err := Record(ctx, logger, cfg, RecordConfig{L2Start: 0, L1End: 0, ConcurrentRequests: 2}, l1, nil, l2)
+		require.ErrorContains(t, err, "is not after the L1 origin")
+	})
+
+	t.Run("missing L1 block", func(t *testing.T) {
+		_, err := Record(ctx, logger, cfg, RecordConfig{L2Start: 0, L1End: 7, ConcurrentRequests: 2}, l1, nil, l2)
+		require.ErrorIs(t, err, ethereum.NotFound)
+	})
+}
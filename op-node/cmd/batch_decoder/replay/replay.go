@@ -0,0 +1,140 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Types of the entries of the derivation output
+const (
+	EntryTypeReset      = "reset"
+	EntryTypeAttributes = "attributes"
+	EntryTypeSafeHead   = "safe_head"
+)
+
+// maxTemporaryErrors is the number of consecutive temporary errors after which derivation is considered stuck.
+// The data of the archive does not change, so retrying does not help once derivation is stuck.
+const maxTemporaryErrors = 100
+
+// Entry is a line of the derivation output.
+type Entry struct {
+	Type string `json:"type"`
+
+	// Attributes are the derived payload attributes, and Block is the L2 block that was built from them.
+	Attributes *eth.PayloadAttributes `json:"attributes,omitempty"`
+	Block      *eth.L2BlockRef        `json:"block,omitempty"`
+	// Mismatch describes how the block differs from the recorded L2 block at the same height.
+	// It is empty if the block matches the recorded block.
+	Mismatch string `json:"mismatch,omitempty"`
+
+	// SafeHead is the new safe head, after a reset or after processing the data of the L1 block.
+	SafeHead *eth.L2BlockRef `json:"safe_head,omitempty"`
+	L1Block  *eth.BlockID    `json:"l1_block,omitempty"`
+}
+
+// Result summarizes a replay of derivation.
+type Result struct {
+	SafeHead   eth.L2BlockRef
+	Blocks     uint64
+	Mismatches uint64
+}
+
+type replay struct {
+	enc    *json.Encoder
+	result Result
+	err    error
+}
+
+func (r *replay) write(entry Entry) {
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(entry); err != nil {
+		r.err = fmt.Errorf("failed to write derivation output: %w", err)
+	}
+}
+
+func (r *replay) onBuild(attrs *eth.PayloadAttributes, block eth.L2BlockRef, mismatch string) {
+	r.result.Blocks++
+	if mismatch != "" {
+		r.result.Mismatches++
+	}
+	r.write(Entry{Type: EntryTypeAttributes, Attributes: attrs, Block: &block, Mismatch: mismatch})
+}
+
+func (r *replay) Enabled() bool {
+	return true
+}
+
+func (r *replay) SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	r.write(Entry{Type: EntryTypeSafeHead, SafeHead: &newSafeHead, L1Block: &l1Block})
+	return nil
+}
+
+func (r *replay) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	r.write(Entry{Type: EntryTypeReset, SafeHead: &resetSafeHead})
+	return nil
+}
+
+// Derive runs the derivation pipeline over the L1 blocks of the archive, and writes the derived payload attributes
+// and the progression of the safe head to out, as JSON lines.
+// The L2 blocks are built by a mocked engine, so no L2 execution client is needed.
+func Derive(ctx context.Context, logger log.Logger, cfg *rollup.Config, archive *Archive, out io.Writer) (*Result, error) {
+	if cfg.UseAltDA {
+		return nil, errors.New("derivation from alt-DA inputs is not supported")
+	}
+	l1, err := newL1Source(archive)
+	if err != nil {
+		return nil, err
+	}
+	r := &replay{enc: json.NewEncoder(out)}
+	eng, err := newEngine(cfg, archive, r.onBuild)
+	if err != nil {
+		return nil, err
+	}
+	ec := derive.NewEngineController(eng, logger, metrics.NoopMetrics, cfg, sync.CLSync)
+	pipeline := derive.NewDerivationPipeline(logger, cfg, l1, l1, nil, eng, ec, metrics.NoopMetrics, &sync.Config{}, r)
+	pipeline.Reset()
+	temporaryErrors := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := pipeline.Step(ctx)
+		if r.err != nil {
+			return nil, r.err
+		}
+		if errors.Is(err, derive.ErrTemporary) {
+			temporaryErrors++
+		} else {
+			temporaryErrors = 0
+		}
+		if errors.Is(err, io.EOF) {
+			logger.Info("Derivation complete: reached the last L1 block of the archive", "safe_head", ec.SafeL2Head(), "origin", pipeline.Origin())
+			break
+		} else if errors.Is(err, ErrMissingData) {
+			return nil, err
+		} else if errors.Is(err, derive.ErrReset) {
+			logger.Warn("Derivation pipeline is reset", "err", err)
+			pipeline.Reset()
+		} else if temporaryErrors >= maxTemporaryErrors {
+			return nil, fmt.Errorf("derivation is stuck: %w", err)
+		} else if errors.Is(err, derive.ErrTemporary) || errors.Is(err, derive.NotEnoughData) {
+			logger.Debug("Derivation step did not make progress", "err", err)
+		} else if err != nil {
+			return nil, fmt.Errorf("derivation failed: %w", err)
+		}
+	}
+	r.result.SafeHead = ec.SafeL2Head()
+	return &r.result, nil
+}
@@ -0,0 +1,302 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+var testBatcherKey, _ = crypto.HexToECDSA("8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba")
+
+// testArchive creates an archive of L1 blocks without batches, from the L2 genesis block.
+func testArchive(t *testing.T, l1Blocks int) (*rollup.Config, *Archive) {
+	l2Genesis := types.NewBlockWithHeader(&types.Header{
+		Number:   big.NewInt(0),
+		Time:     1000,
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(7),
+	})
+	var archive Archive
+	var parent *types.Header
+	for i := 0; i < l1Blocks; i++ {
+		header := &types.Header{
+			Number:  big.NewInt(int64(i)),
+			Time:    1000 + uint64(i)*12,
+			BaseFee: big.NewInt(10),
+		}
+		if parent != nil {
+			header.ParentHash = parent.Hash()
+		}
+		data, err := rlp.EncodeToBytes(header)
+		require.NoError(t, err)
+		archive.L1Blocks = append(archive.L1Blocks, L1Block{Header: data})
+		parent = header
+	}
+	l1Genesis, err := decodeHeader(archive.L1Blocks[0].Header)
+	require.NoError(t, err)
+	data, err := encodeL2Block(l2Genesis)
+	require.NoError(t, err)
+	archive.L2Blocks = []hexutil.Bytes{data}
+
+	zero := uint64(0)
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L1:     eth.BlockID{Hash: l1Genesis.Hash(), Number: 0},
+			L2:     eth.BlockID{Hash: l2Genesis.Hash(), Number: 0},
+			L2Time: l2Genesis.Time(),
+			SystemConfig: eth.SystemConfig{
+				BatcherAddr: crypto.PubkeyToAddress(testBatcherKey.PublicKey),
+				Overhead:    eth.Bytes32{31: 188},
+				Scalar:      eth.Bytes32{30: 0x0a, 31: 0x6f},
+				GasLimit:    30_000_000,
+			},
+		},
+		BlockTime:              2,
+		MaxSequencerDrift:      600,
+		SeqWindowSize:          4,
+		ChannelTimeout:         10,
+		L1ChainID:              big.NewInt(900),
+		L2ChainID:              big.NewInt(901),
+		RegolithTime:           &zero,
+		BatchInboxAddress:      common.Address{0xff, 0x01},
+		DepositContractAddress: common.Address{0xde},
+		L1SystemConfigAddress:  common.Address{0x5c},
+	}
+	return cfg, &archive
+}
+
+func runDerive(t *testing.T, cfg *rollup.Config, archive *Archive) (*Result, []Entry) {
+	var out bytes.Buffer
+	result, err := Derive(context.Background(), testlog.Logger(t, log.LevelError), cfg, archive, &out)
+	require.NoError(t, err)
+	var entries []Entry
+	for scanner := bufio.NewScanner(&out); scanner.Scan(); {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return result, entries
+}
+
+// channelTx returns a transaction to the batch inbox, signed with the given key,
+// of a channel with the batch in a single frame.
+func channelTx(t *testing.T, cfg *rollup.Config, key *ecdsa.PrivateKey, batch *derive.SingularBatch) hexutil.Bytes {
+	var channel bytes.Buffer
+	zw := zlib.NewWriter(&channel)
+	require.NoError(t, rlp.Encode(zw, derive.NewBatchData(batch)))
+	require.NoError(t, zw.Close())
+	frame := derive.Frame{ID: derive.ChannelID{0x01}, Data: channel.Bytes(), IsLast: true}
+	data := bytes.NewBuffer([]byte{derive.DerivationVersion0})
+	require.NoError(t, frame.MarshalBinary(data))
+	tx, err := types.SignNewTx(key, cfg.L1Signer(), &types.DynamicFeeTx{
+		ChainID:   cfg.L1ChainID,
+		Gas:       100_000,
+		GasFeeCap: big.NewInt(10),
+		To:        &cfg.BatchInboxAddress,
+		Data:      data.Bytes(),
+	})
+	require.NoError(t, err)
+	enc, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return enc
+}
+
+// withL1Block returns a copy of the archive, in which the L1 block with the given number is modified.
+func withL1Block(archive *Archive, num int, modify func(block *L1Block)) *Archive {
+	modified := *archive
+	modified.L1Blocks = append([]L1Block{}, archive.L1Blocks...)
+	modify(&modified.L1Blocks[num])
+	return &modified
+}
+
+// attributes returns the derived attributes entries.
+func attributes(entries []Entry) []Entry {
+	var blocks []Entry
+	for _, entry := range entries {
+		if entry.Type == EntryTypeAttributes {
+			blocks = append(blocks, entry)
+		}
+	}
+	return blocks
+}
+
+func TestDerive(t *testing.T) {
+	cfg, archive := testArchive(t, 12)
+	path := filepath.Join(t.TempDir(), "archive.json")
+	require.NoError(t, WriteArchive(path, archive))
+	archive, err := LoadArchive(path)
+	require.NoError(t, err)
+
+	// Without batches, only empty blocks of the epochs with a passed sequencing window are derived.
+	result, entries := runDerive(t, cfg, archive)
+	require.NotZero(t, result.Blocks)
+	require.Equal(t, result.Blocks, result.Mismatches, "no L2 blocks are recorded")
+	require.Equal(t, result.Blocks, result.SafeHead.Number)
+	require.Equal(t, EntryTypeReset, entries[0].Type)
+	require.Equal(t, cfg.Genesis.L2.Hash, entries[0].SafeHead.Hash)
+	var blocks []Entry
+	var safeHead eth.L2BlockRef
+	for _, entry := range entries[1:] {
+		switch entry.Type {
+		case EntryTypeAttributes:
+			require.Len(t, entry.Attributes.Transactions, 1, "only the L1 info deposit")
+			require.Equal(t, "no recorded L2 block", entry.Mismatch)
+			blocks = append(blocks, entry)
+		case EntryTypeSafeHead:
+			require.Greater(t, entry.SafeHead.Number, safeHead.Number)
+			safeHead = *entry.SafeHead
+		default:
+			t.Fatalf("unexpected entry type %q", entry.Type)
+		}
+	}
+	require.Equal(t, result.SafeHead, safeHead)
+	require.Len(t, blocks, int(result.Blocks))
+	for i, entry := range blocks {
+		require.Equal(t, uint64(i+1), entry.Block.Number)
+		require.Equal(t, cfg.Genesis.L2Time+uint64(i+1)*cfg.BlockTime, entry.Block.Time)
+	}
+
+	t.Run("recorded L2 headers", func(t *testing.T) {
+		// the recorded block has execution results, which the mocked engine does not compute
+		eng, err := newEngine(cfg, archive, func(*eth.PayloadAttributes, eth.L2BlockRef, string) {})
+		require.NoError(t, err)
+		block, err := eng.build(eng.blocks[cfg.Genesis.L2.Hash], blocks[0].Attributes)
+		require.NoError(t, err)
+		recorded := block.Header()
+		recorded.Root = common.Hash{0x01}
+		recorded.GasUsed = 50_000
+		data, err := rlp.EncodeToBytes(recorded)
+		require.NoError(t, err)
+		// the second recorded block was not derived
+		mismatched := types.CopyHeader(recorded)
+		mismatched.Number = big.NewInt(2)
+		mismatched.TxHash = common.Hash{0x02}
+		mismatchedData, err := rlp.EncodeToBytes(mismatched)
+		require.NoError(t, err)
+		withHeaders := *archive
+		withHeaders.L2Headers = []hexutil.Bytes{data, mismatchedData}
+
+		result, entries := runDerive(t, cfg, &withHeaders)
+		require.Equal(t, result.Blocks-1, result.Mismatches)
+		var blocks []Entry
+		for _, entry := range entries {
+			if entry.Type == EntryTypeAttributes {
+				blocks = append(blocks, entry)
+			}
+		}
+		require.Empty(t, blocks[0].Mismatch)
+		require.Equal(t, recorded.Hash(), blocks[0].Block.Hash, "the derived block has the recorded hash")
+		require.Equal(t, recorded.Hash(), blocks[1].Block.ParentHash)
+		require.Contains(t, blocks[1].Mismatch, "transactions root")
+	})
+
+	t.Run("batcher calldata", func(t *testing.T) {
+		batch := func(nonce uint64) *derive.SingularBatch {
+			tx, err := types.NewTx(&types.LegacyTx{Nonce: nonce, Gas: 21_000, GasPrice: big.NewInt(10)}).MarshalBinary()
+			require.NoError(t, err)
+			return &derive.SingularBatch{
+				ParentHash:   cfg.Genesis.L2.Hash,
+				EpochHash:    cfg.Genesis.L1.Hash,
+				Timestamp:    cfg.Genesis.L2Time + cfg.BlockTime,
+				Transactions: []hexutil.Bytes{tx},
+			}
+		}
+		otherKey, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		valid := batch(1)
+		withBatch := withL1Block(archive, 1, func(block *L1Block) {
+			block.Transactions = []hexutil.Bytes{
+				// not sent by the batcher, so it is ignored
+				channelTx(t, cfg, otherKey, batch(2)),
+				channelTx(t, cfg, testBatcherKey, valid),
+			}
+		})
+
+		_, entries := runDerive(t, cfg, withBatch)
+		blocks := attributes(entries)
+		require.Equal(t, valid.Timestamp, blocks[0].Block.Time)
+		require.Len(t, blocks[0].Attributes.Transactions, 2)
+		require.Equal(t, eth.Data(valid.Transactions[0]), blocks[0].Attributes.Transactions[1])
+		require.True(t, bool(blocks[0].Attributes.NoTxPool))
+		for _, entry := range blocks[1:] {
+			require.Len(t, entry.Attributes.Transactions, 1, "only the L1 info deposit")
+		}
+	})
+
+	t.Run("deposit receipt", func(t *testing.T) {
+		header, err := decodeHeader(archive.L1Blocks[1].Header)
+		require.NoError(t, err)
+		to := common.Address{0x70}
+		deposit := &types.DepositTx{
+			From:  common.Address{0xf0},
+			To:    &to,
+			Mint:  big.NewInt(100),
+			Value: big.NewInt(50),
+			Gas:   100_000,
+			Data:  []byte{0x01, 0x02},
+		}
+		l, err := derive.MarshalDepositLogEvent(cfg.DepositContractAddress, deposit)
+		require.NoError(t, err)
+		l.BlockHash = header.Hash()
+		withDeposit := withL1Block(archive, 1, func(block *L1Block) {
+			block.Receipts = []*types.Receipt{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{l}}}
+		})
+		// the receipts are encoded in the archive file
+		path := filepath.Join(t.TempDir(), "archive.json")
+		require.NoError(t, WriteArchive(path, withDeposit))
+		withDeposit, err = LoadArchive(path)
+		require.NoError(t, err)
+
+		_, entries := runDerive(t, cfg, withDeposit)
+		var deposits int
+		for _, entry := range attributes(entries) {
+			txs := entry.Attributes.Transactions
+			// the deposit is included in the first L2 block of the epoch of the L1 block
+			if entry.Block.L1Origin.Number != 1 || entry.Block.SequenceNumber != 0 {
+				require.Len(t, txs, 1, "only the L1 info deposit")
+				continue
+			}
+			require.Len(t, txs, 2)
+			var tx types.Transaction
+			require.NoError(t, tx.UnmarshalBinary(txs[1]))
+			require.Equal(t, uint8(types.DepositTxType), tx.Type())
+			from, err := types.NewLondonSigner(cfg.L2ChainID).Sender(&tx)
+			require.NoError(t, err)
+			require.Equal(t, deposit.From, from)
+			require.Equal(t, deposit.To, tx.To())
+			require.Equal(t, deposit.Mint, tx.Mint())
+			require.Equal(t, deposit.Value, tx.Value())
+			require.Equal(t, deposit.Gas, tx.Gas())
+			require.Equal(t, deposit.Data, tx.Data())
+			deposits++
+		}
+		require.Equal(t, 1, deposits)
+	})
+
+	t.Run("inconsistent L1 blocks", func(t *testing.T) {
+		incomplete := *archive
+		incomplete.L1Blocks = append([]L1Block{}, archive.L1Blocks...)
+		incomplete.L1Blocks[5].Header = incomplete.L1Blocks[4].Header
+		_, err := Derive(context.Background(), testlog.Logger(t, log.LevelError), cfg, &incomplete, &bytes.Buffer{})
+		require.ErrorContains(t, err, "does not build on the previous L1 block")
+	})
+}
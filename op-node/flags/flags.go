@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	opflags "github.com/ethereum-optimism/optimism/op-service/flags"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -228,6 +229,27 @@ var (
 		Usage:   "Load protocol versions from the superchain L1 ProtocolVersions contract (if available), and report in logs and metrics",
 		EnvVars: prefixEnvVars("ROLLUP_LOAD_PROTOCOL_VERSIONS"),
 	}
+	SafeDBPath = &cli.StringFlag{
+		Name:    "safedb.path",
+		Usage:   "File path used to persist safe head update data. Disabled if not set.",
+		EnvVars: prefixEnvVars("SAFEDB_PATH"),
+	}
+	SafeDBPruneL1Blocks = &cli.Uint64Flag{
+		Name:    "safedb.prune-l1-blocks",
+		Usage:   "Number of most recent L1 blocks to keep safe head update data for. Data is never pruned if 0.",
+		EnvVars: prefixEnvVars("SAFEDB_PRUNE_L1_BLOCKS"),
+		Value:   0,
+	}
+	InteropPeerRPCs = &cli.StringSliceFlag{
+		Name:    "interop.peer-rpcs",
+		Usage:   "RPC endpoints of the other L2 chains in the interop dependency set, to verify cross-chain messages against.",
+		EnvVars: prefixEnvVars("INTEROP_PEER_RPCS"),
+	}
+	DerivationTracePath = &cli.StringFlag{
+		Name:    "derivation.trace-path",
+		Usage:   "File path to record a trace of the state transitions of the derivation pipeline stages to, as JSON lines. Disabled if not set.",
+		EnvVars: prefixEnvVars("DERIVATION_TRACE_PATH"),
+	}
 	/* Deprecated Flags */
 	L2EngineSyncEnabled = &cli.BoolFlag{
 		Name:    "l2.engine-sync",
@@ -324,6 +346,10 @@ var optionalFlags = []cli.Flag{
 	ConductorEnabledFlag,
 	ConductorRpcFlag,
 	ConductorRpcTimeoutFlag,
+	SafeDBPath,
+	SafeDBPruneL1Blocks,
+	InteropPeerRPCs,
+	DerivationTracePath,
 }
 
 var DeprecatedFlags = []cli.Flag{
@@ -345,6 +371,7 @@ func init() {
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, DeprecatedFlags...)
 	optionalFlags = append(optionalFlags, opflags.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, altda.CLIFlags(EnvVarPrefix)...)
 	Flags = append(requiredFlags, optionalFlags...)
 }
 
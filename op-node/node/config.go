@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -69,6 +70,21 @@ type Config struct {
 	ConductorEnabled    bool
 	ConductorRpc        string
 	ConductorRpcTimeout time.Duration
+
+	// AltDA configures the DA server to retrieve the inputs of alt-DA commitments from
+	AltDA altda.CLIConfig
+
+	// [OPTIONAL] Path of the database recording the L1 block each L2 safe head was derived from. Disabled if empty.
+	SafeDBPath string
+	// Number of most recent L1 blocks to keep safe head entries for. Entries are never pruned if 0.
+	SafeDBPruneL1Blocks uint64
+
+	// RPC endpoints of the L2 chains in the interop dependency set, to verify cross-chain messages against.
+	// Only used if the interop upgrade is scheduled.
+	InteropPeerRPCs []string
+
+	// [OPTIONAL] Path of the file to record a trace of the state of the derivation pipeline to. Disabled if empty.
+	DerivationTracePath string
 }
 
 type RPCConfig struct {
@@ -156,6 +172,15 @@ func (cfg *Config) Check() error {
 	if !(cfg.RollupHalt == "" || cfg.RollupHalt == "major" || cfg.RollupHalt == "minor" || cfg.RollupHalt == "patch") {
 		return fmt.Errorf("invalid rollup halting option: %q", cfg.RollupHalt)
 	}
+	if err := cfg.AltDA.Check(); err != nil {
+		return fmt.Errorf("alt-DA config error: %w", err)
+	}
+	if cfg.Rollup.UseAltDA && !cfg.AltDA.Enabled {
+		return fmt.Errorf("the rollup uses alt-DA but alt-DA is not enabled")
+	}
+	if cfg.AltDA.Enabled && !cfg.Rollup.UseAltDA {
+		return fmt.Errorf("alt-DA is enabled, but the rollup does not use alt-DA")
+	}
 	if cfg.ConductorEnabled {
 		if state, _ := cfg.ConfigPersistence.SequencerState(); state != StateUnset {
 			return fmt.Errorf("config persistence must be disabled when conductor is enabled")
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"sync/atomic"
 	"time"
 
@@ -18,9 +20,12 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/heartbeat"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/conductor"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -32,6 +37,12 @@ import (
 
 var ErrAlreadyClosed = errors.New("node is already closed")
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	SafeDBReader
+	io.Closer
+}
+
 type OpNode struct {
 	log        log.Logger
 	appVersion string
@@ -44,6 +55,8 @@ type OpNode struct {
 	l1Source  *sources.L1Client     // L1 Client to fetch data from
 	l2Driver  *driver.Driver        // L2 Engine to Sync
 	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
+	safeDB    closableSafeDB        // Records the L1 block each L2 safe head was derived from
+	interop   []*sources.EthClient  // RPC bindings of the other L2 chains in the interop dependency set
 	server    *rpcServer            // RPC server hosting the rollup-node API
 	p2pNode   *p2p.NodeP2P          // P2P node functionality
 	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
@@ -57,6 +70,9 @@ type OpNode struct {
 
 	beacon *sources.L1BeaconClient
 
+	// pipelineTracer records the state of the derivation pipeline, nil if disabled
+	pipelineTracer *derive.PipelineTracer
+
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
 	resourcesCtx   context.Context
@@ -116,6 +132,9 @@ func (n *OpNode) init(ctx context.Context, cfg *Config, snapshotLog log.Logger)
 	if err := n.initL1BeaconAPI(ctx, cfg); err != nil {
 		return err
 	}
+	if err := n.initSafeDB(cfg); err != nil {
+		return fmt.Errorf("failed to init safe head db: %w", err)
+	}
 	if err := n.initL2(ctx, cfg, snapshotLog); err != nil {
 		return fmt.Errorf("failed to init L2: %w", err)
 	}
@@ -352,6 +371,20 @@ func (n *OpNode) initL1BeaconAPI(ctx context.Context, cfg *Config) error {
 	}
 }
 
+func (n *OpNode) initSafeDB(cfg *Config) error {
+	if cfg.SafeDBPath == "" {
+		n.safeDB = safedb.Disabled
+		return nil
+	}
+	n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath, "prune_l1_blocks", cfg.SafeDBPruneL1Blocks)
+	safeDB, err := safedb.NewSafeDB(n.log, cfg.SafeDBPath, cfg.SafeDBPruneL1Blocks)
+	if err != nil {
+		return err
+	}
+	n.safeDB = safeDB
+	return nil
+}
+
 func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger) error {
 	rpcClient, rpcCfg, err := cfg.L2.Setup(ctx, n.log, &cfg.Rollup)
 	if err != nil {
@@ -373,13 +406,62 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 	if cfg.ConductorEnabled {
 		sequencerConductor = NewConductorClient(cfg, n.log, n.metrics)
 	}
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n.beacon, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, &cfg.Sync, sequencerConductor)
+	var altDA derive.AltDAInputFetcher
+	if cfg.AltDA.Enabled {
+		altDA = cfg.AltDA.NewDAClient()
+	}
+	var interopVerifier interop.BlockChecker
+	if cfg.Rollup.InteropTime != nil {
+		interopVerifier, err = n.initInterop(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	}
+	var pipelineTracer driver.PipelineTracer
+	if cfg.DerivationTracePath != "" {
+		f, err := os.Create(cfg.DerivationTracePath)
+		if err != nil {
+			return fmt.Errorf("failed to create derivation trace file: %w", err)
+		}
+		n.log.Info("Tracing derivation pipeline", "path", cfg.DerivationTracePath)
+		n.pipelineTracer = derive.NewPipelineTracer(f)
+		pipelineTracer = n.pipelineTracer
+	}
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n.beacon, altDA, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, &cfg.Sync, sequencerConductor, n.safeDB, interopVerifier, pipelineTracer)
 
 	return nil
 }
 
+// initInterop connects to the other L2 chains in the interop dependency set,
+// and creates the verifier of the cross-chain messages of the L2 blocks.
+func (n *OpNode) initInterop(ctx context.Context, cfg *Config) (*interop.Verifier, error) {
+	peers := make(map[uint64]interop.Source, len(cfg.InteropPeerRPCs))
+	for _, addr := range cfg.InteropPeerRPCs {
+		rpcClient, err := client.NewRPC(ctx, n.log, addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial interop peer RPC %s: %w", addr, err)
+		}
+		peer, err := sources.NewEthClient(rpcClient, n.log, nil, &sources.L2ClientDefaultConfig(&cfg.Rollup, false).EthClientConfig)
+		if err != nil {
+			rpcClient.Close()
+			return nil, fmt.Errorf("failed to create interop peer client for %s: %w", addr, err)
+		}
+		n.interop = append(n.interop, peer)
+		chainID, err := peer.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch chain ID of interop peer %s: %w", addr, err)
+		}
+		if _, ok := peers[chainID.Uint64()]; ok || chainID.Cmp(cfg.Rollup.L2ChainID) == 0 {
+			return nil, fmt.Errorf("interop peer %s serves duplicate chain %d", addr, chainID)
+		}
+		peers[chainID.Uint64()] = peer
+		n.log.Info("Connected to interop peer", "chain_id", chainID, "rpc", addr)
+	}
+	return interop.NewVerifier(n.log, &cfg.Rollup, n.l2Source, peers), nil
+}
+
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
@@ -647,11 +729,30 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		<-n.runtimeConfigReloaderDone
 	}
 
+	// close the derivation trace, after the driver stopped writing to it
+	if n.pipelineTracer != nil {
+		if err := n.pipelineTracer.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close derivation trace: %w", err))
+		}
+	}
+
+	// close the safe head db, after the driver stopped updating it
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head db: %w", err))
+		}
+	}
+
 	// close L2 engine RPC client
 	if n.l2Source != nil {
 		n.l2Source.Close()
 	}
 
+	// close the RPC clients of the interop peers
+	for _, peer := range n.interop {
+		peer.Close()
+	}
+
 	// close L1 data source
 	if n.l1Source != nil {
 		n.l1Source.Close()
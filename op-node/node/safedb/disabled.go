@@ -0,0 +1,35 @@
+package safedb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var ErrNotEnabled = errors.New("safe head database not enabled")
+
+type DisabledDB struct{}
+
+// Disabled is the safe head database of nodes that don't record safe heads.
+var Disabled = &DisabledDB{}
+
+func (d *DisabledDB) Enabled() bool {
+	return false
+}
+
+func (d *DisabledDB) SafeHeadUpdated(_ eth.L2BlockRef, _ eth.BlockID) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadReset(_ eth.L2BlockRef) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadAtL1(_ context.Context, _ uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	return eth.BlockID{}, eth.BlockID{}, ErrNotEnabled
+}
+
+func (d *DisabledDB) Close() error {
+	return nil
+}
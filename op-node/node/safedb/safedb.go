@@ -0,0 +1,218 @@
+// Package safedb persists which L2 block was the safe head as of each L1 block,
+// so the safe head at a past L1 block can still be looked up after the node restarts.
+package safedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotFound     = errors.New("safe head not found")
+	ErrInvalidEntry = errors.New("invalid db entry")
+)
+
+const (
+	// keyPrefixSafeByL1BlockNum prefixes the keys of the entries, which are indexed by L1 block number
+	keyPrefixSafeByL1BlockNum byte = 0
+
+	keyLen   = 1 + 8
+	entryLen = common.HashLength + common.HashLength + 8
+)
+
+// SafeDB records the L2 safe head, and the L1 block it was derived from, every time the safe head changes.
+// The safe head at an L1 block is the entry of the L1 block, or of the last L1 block before it that has an entry.
+// SafeDB is safe for concurrent use.
+type SafeDB struct {
+	// m ensures a reset can't interleave with an update or a lookup
+	m   sync.RWMutex
+	log log.Logger
+	db  *pebble.DB
+
+	// pruneL1Blocks is the number of the most recent L1 blocks to keep entries for, or 0 to keep all entries
+	pruneL1Blocks uint64
+}
+
+// NewSafeDB opens the safe head database in the given directory path, and creates it if it doesn't exist yet.
+// Entries older than the most recent pruneL1Blocks L1 blocks are pruned, unless pruneL1Blocks is 0.
+func NewSafeDB(logger log.Logger, path string, pruneL1Blocks uint64) (*SafeDB, error) {
+	db, err := pebble.Open(path, &pebble.Options{Logger: pebbleLogger{logger}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head db at %s: %w", path, err)
+	}
+	return &SafeDB{
+		log:           logger,
+		db:            db,
+		pruneL1Blocks: pruneL1Blocks,
+	}, nil
+}
+
+func (d *SafeDB) Enabled() bool {
+	return true
+}
+
+// SafeHeadUpdated records that safeHead became the safe head when deriving from the L1 chain up to l1Head.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.log.Debug("Record safe head", "l2", safeHead.ID(), "l1", l1Head)
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(safeByL1BlockNumKey(l1Head.Number), encodeEntry(l1Head, safeHead.ID()), nil); err != nil {
+		return fmt.Errorf("failed to record safe head %s at L1 block %s: %w", safeHead.ID(), l1Head, err)
+	}
+	if d.pruneL1Blocks != 0 && l1Head.Number > d.pruneL1Blocks {
+		if err := d.prune(batch, l1Head.Number-d.pruneL1Blocks); err != nil {
+			return err
+		}
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit safe head %s at L1 block %s: %w", safeHead.ID(), l1Head, err)
+	}
+	return nil
+}
+
+// prune deletes the entries before the L1 block number cutoff,
+// except for the last one, which remains the safe head of the L1 blocks from the cutoff up to the next entry.
+func (d *SafeDB) prune(batch *pebble.Batch, cutoff uint64) error {
+	iter, err := d.db.NewIter(safeByL1BlockNumRange())
+	if err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	defer iter.Close()
+	if !iter.SeekLT(safeByL1BlockNumKey(cutoff)) {
+		return iter.Error()
+	}
+	if err := batch.DeleteRange(safeByL1BlockNumKey(0), iter.Key(), nil); err != nil {
+		return fmt.Errorf("failed to prune safe heads before L1 block %d: %w", cutoff, err)
+	}
+	return nil
+}
+
+// SafeHeadReset removes the entries of safe heads after the given safe head, which is where the pipeline was reset to.
+// The entries of conflicting L2 blocks of the same height are removed as well.
+func (d *SafeDB) SafeHeadReset(safeHead eth.L2BlockRef) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	iter, err := d.db.NewIter(safeByL1BlockNumRange())
+	if err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	defer iter.Close()
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	removed := 0
+	for valid := iter.Last(); valid; valid = iter.Prev() {
+		_, l2, err := decodeEntry(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+		if l2.Number < safeHead.Number || l2 == safeHead.ID() {
+			break
+		}
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			return fmt.Errorf("failed to remove safe head %s: %w", l2, err)
+		}
+		removed++
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	if removed == 0 {
+		return nil
+	}
+	d.log.Warn("Removed safe heads after reset", "safe_head", safeHead.ID(), "removed", removed)
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to reset safe heads to %s: %w", safeHead.ID(), err)
+	}
+	return nil
+}
+
+// SafeHeadAtL1 returns the L2 safe head as of the given L1 block number,
+// and the L1 block at which that safe head was recorded, which may be an earlier L1 block.
+func (d *SafeDB) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	iter, err := d.db.NewIterWithContext(ctx, safeByL1BlockNumRange())
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	defer iter.Close()
+	var found bool
+	if l1BlockNum == math.MaxUint64 {
+		found = iter.Last()
+	} else {
+		found = iter.SeekLT(safeByL1BlockNumKey(l1BlockNum + 1))
+	}
+	if !found {
+		if err := iter.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to iterate safe heads: %w", err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	return decodeEntry(iter.Key(), iter.Value())
+}
+
+func (d *SafeDB) Close() error {
+	return d.db.Close()
+}
+
+func safeByL1BlockNumKey(l1BlockNum uint64) []byte {
+	key := make([]byte, keyLen)
+	key[0] = keyPrefixSafeByL1BlockNum
+	binary.BigEndian.PutUint64(key[1:], l1BlockNum)
+	return key
+}
+
+func safeByL1BlockNumRange() *pebble.IterOptions {
+	return &pebble.IterOptions{
+		LowerBound: []byte{keyPrefixSafeByL1BlockNum},
+		UpperBound: []byte{keyPrefixSafeByL1BlockNum + 1},
+	}
+}
+
+// encodeEntry encodes an entry as the L1 block hash, followed by the L2 block hash and the big-endian L2 block number.
+// The L1 block number is part of the key.
+func encodeEntry(l1 eth.BlockID, l2 eth.BlockID) []byte {
+	val := make([]byte, 0, entryLen)
+	val = append(val, l1.Hash[:]...)
+	val = append(val, l2.Hash[:]...)
+	return binary.BigEndian.AppendUint64(val, l2.Number)
+}
+
+func decodeEntry(key []byte, val []byte) (l1 eth.BlockID, l2 eth.BlockID, err error) {
+	if len(key) != keyLen {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("%w: expected %d byte key, got %d", ErrInvalidEntry, keyLen, len(key))
+	}
+	if len(val) != entryLen {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidEntry, entryLen, len(val))
+	}
+	l1.Number = binary.BigEndian.Uint64(key[1:])
+	l1.Hash = common.BytesToHash(val[:common.HashLength])
+	l2.Hash = common.BytesToHash(val[common.HashLength : 2*common.HashLength])
+	l2.Number = binary.BigEndian.Uint64(val[2*common.HashLength:])
+	return l1, l2, nil
+}
+
+// pebbleLogger forwards the logs of pebble to the node logger, at debug level to not flood the node logs.
+type pebbleLogger struct {
+	log log.Logger
+}
+
+func (l pebbleLogger) Infof(format string, args ...interface{}) {
+	l.log.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l pebbleLogger) Fatalf(format string, args ...interface{}) {
+	l.log.Crit(fmt.Sprintf(format, args...))
+}
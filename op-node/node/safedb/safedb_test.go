@@ -0,0 +1,115 @@
+package safedb
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func l1Block(num uint64) eth.BlockID {
+	return eth.BlockID{Hash: common.Hash{0x01, byte(num)}, Number: num}
+}
+
+func l2Block(num uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{0x02, byte(num)}, Number: num}
+}
+
+func newTestDB(t *testing.T, dir string, pruneL1Blocks uint64) *SafeDB {
+	db, err := NewSafeDB(testlog.Logger(t, log.LevelInfo), dir, pruneL1Blocks)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
+
+func requireSafeHead(t *testing.T, db *SafeDB, l1Num uint64, expectedL1 eth.BlockID, expectedL2 eth.L2BlockRef) {
+	actualL1, actualL2, err := db.SafeHeadAtL1(context.Background(), l1Num)
+	require.NoError(t, err)
+	require.Equal(t, expectedL1, actualL1)
+	require.Equal(t, expectedL2.ID(), actualL2)
+}
+
+func TestStoreSafeHeads(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewSafeDB(testlog.Logger(t, log.LevelInfo), dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, db.SafeHeadUpdated(l2Block(10), l1Block(100)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(12), l1Block(102)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(13), l1Block(102)))
+	require.NoError(t, db.Close())
+
+	// entries persist across restarts
+	db = newTestDB(t, dir, 0)
+
+	_, _, err = db.SafeHeadAtL1(context.Background(), 99)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	requireSafeHead(t, db, 100, l1Block(100), l2Block(10))
+	requireSafeHead(t, db, 101, l1Block(100), l2Block(10))
+	// the latest safe head recorded at an L1 block replaces earlier ones
+	requireSafeHead(t, db, 102, l1Block(102), l2Block(13))
+	requireSafeHead(t, db, 5000, l1Block(102), l2Block(13))
+	requireSafeHead(t, db, math.MaxUint64, l1Block(102), l2Block(13))
+}
+
+func TestSafeHeadReset(t *testing.T) {
+	db := newTestDB(t, t.TempDir(), 0)
+	require.NoError(t, db.SafeHeadUpdated(l2Block(10), l1Block(100)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(12), l1Block(102)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(14), l1Block(104)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(16), l1Block(106)))
+
+	t.Run("keep matching safe head", func(t *testing.T) {
+		require.NoError(t, db.SafeHeadReset(l2Block(16)))
+		requireSafeHead(t, db, 106, l1Block(106), l2Block(16))
+	})
+
+	t.Run("remove later safe heads", func(t *testing.T) {
+		require.NoError(t, db.SafeHeadReset(l2Block(13)))
+		requireSafeHead(t, db, 106, l1Block(102), l2Block(12))
+	})
+
+	t.Run("remove conflicting safe head", func(t *testing.T) {
+		conflicting := l2Block(12)
+		conflicting.Hash = common.Hash{0xff}
+		require.NoError(t, db.SafeHeadReset(conflicting))
+		requireSafeHead(t, db, 106, l1Block(100), l2Block(10))
+	})
+
+	t.Run("remove all", func(t *testing.T) {
+		require.NoError(t, db.SafeHeadReset(l2Block(1)))
+		_, _, err := db.SafeHeadAtL1(context.Background(), 106)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestPruneSafeHeads(t *testing.T) {
+	db := newTestDB(t, t.TempDir(), 10)
+	require.NoError(t, db.SafeHeadUpdated(l2Block(1), l1Block(100)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(2), l1Block(101)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(3), l1Block(105)))
+	require.NoError(t, db.SafeHeadUpdated(l2Block(4), l1Block(112)))
+
+	// block 101 is before the cutoff, but remains the safe head until block 105
+	_, _, err := db.SafeHeadAtL1(context.Background(), 100)
+	require.ErrorIs(t, err, ErrNotFound)
+	requireSafeHead(t, db, 102, l1Block(101), l2Block(2))
+	requireSafeHead(t, db, 105, l1Block(105), l2Block(3))
+	requireSafeHead(t, db, 112, l1Block(112), l2Block(4))
+}
+
+func TestDisabled(t *testing.T) {
+	require.False(t, Disabled.Enabled())
+	require.NoError(t, Disabled.SafeHeadUpdated(l2Block(1), l1Block(1)))
+	require.NoError(t, Disabled.SafeHeadReset(l2Block(1)))
+	_, _, err := Disabled.SafeHeadAtL1(context.Background(), 1)
+	require.ErrorIs(t, err, ErrNotEnabled)
+}
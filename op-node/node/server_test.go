@@ -14,10 +14,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	rpcclient "github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
 )
@@ -100,7 +102,7 @@ func TestOutputAtBlock(t *testing.T) {
 	status := randomSyncStatus(rand.New(rand.NewSource(123)))
 	drClient.ExpectBlockRefWithStatus(0xdcdc89, ref, status, nil)
 
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 	defer func() {
@@ -134,7 +136,7 @@ func TestVersion(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer func() {
@@ -179,7 +181,7 @@ func TestSyncStatus(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer func() {
@@ -195,6 +197,82 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, status, out)
 }
 
+func TestSafeHeadAtL1Block(t *testing.T) {
+	log := testlog.Logger(t, log.LevelError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	rng := rand.New(rand.NewSource(1234))
+	l1Block := testutils.RandomBlockRef(rng)
+	l1Block.Number = 100
+	safeHead := testutils.RandomL2BlockRef(rng)
+
+	db, err := safedb.NewSafeDB(log, t.TempDir(), 0)
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.SafeHeadUpdated(safeHead, l1Block.ID()))
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, db, log, "0.0", metrics.NoopMetrics)
+	assert.NoError(t, err)
+	assert.NoError(t, server.Start())
+	defer func() {
+		require.NoError(t, server.Stop(context.Background()))
+	}()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	assert.NoError(t, err)
+	rollupClient := sources.NewRollupClient(client)
+
+	out, err := rollupClient.SafeHeadAtL1Block(context.Background(), 105)
+	require.NoError(t, err)
+	require.Equal(t, &eth.SafeHeadResponse{L1Block: l1Block.ID(), SafeHead: safeHead.ID()}, out)
+
+	_, err = rollupClient.SafeHeadAtL1Block(context.Background(), 99)
+	require.ErrorContains(t, err, safedb.ErrNotFound.Error())
+}
+
+func TestDerivationPipelineState(t *testing.T) {
+	log := testlog.Logger(t, log.LevelError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	rng := rand.New(rand.NewSource(1234))
+	state := &eth.DerivationPipelineState{
+		L1Traversal: eth.L1TraversalState{Origin: testutils.RandomBlockRef(rng), Done: true},
+		FrameQueue:  eth.FrameQueueState{Frames: []eth.FrameState{{ChannelID: "aa", FrameNumber: 1, DataLength: 100}}},
+		ChannelBank: eth.ChannelBankState{Channels: []eth.ChannelState{{ID: "bb", OpenBlock: testutils.RandomBlockRef(rng), Size: 1000, Ready: true}}},
+		BatchQueue: eth.BatchQueueState{
+			L1Blocks:     []eth.L1BlockRef{testutils.RandomBlockRef(rng)},
+			Batches:      []eth.BatchState{{Type: "singular", Timestamp: 10, Validity: "future"}},
+			RecentChecks: []eth.BatchState{{Type: "span", Timestamp: 8, Validity: "drop", Reason: "no new blocks"}},
+		},
+	}
+	drClient.On("DerivationPipelineState").Return(state)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, &rollup.Config{}, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	server.EnableAdminAPI(NewAdminAPI(drClient, metrics.NoopMetrics, log))
+	require.NoError(t, server.Start())
+	defer func() {
+		require.NoError(t, server.Stop(context.Background()))
+	}()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	out, err := sources.NewRollupClient(client).DerivationPipelineState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, state, out)
+}
+
 type mockDriverClient struct {
 	mock.Mock
 }
@@ -228,6 +306,10 @@ func (c *mockDriverClient) SequencerActive(ctx context.Context) (bool, error) {
 	return c.Mock.MethodCalled("SequencerActive").Get(0).(bool), nil
 }
 
+func (c *mockDriverClient) DerivationPipelineState(ctx context.Context) (*eth.DerivationPipelineState, error) {
+	return c.Mock.MethodCalled("DerivationPipelineState").Get(0).(*eth.DerivationPipelineState), nil
+}
+
 func (c *mockDriverClient) OnUnsafeL2Payload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error {
 	return c.Mock.MethodCalled("OnUnsafeL2Payload").Get(0).(error)
 }
@@ -0,0 +1,305 @@
+package derive
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// AltDAInputFetcher retrieves the input of an alt-DA commitment, verified against the commitment.
+type AltDAInputFetcher interface {
+	GetInput(ctx context.Context, comm altda.Keccak256Commitment) ([]byte, error)
+}
+
+// AltDAChallengeFetcher provides the transactions and receipts of the L1 blocks, to find the challenges
+// and resolutions of the availability of alt-DA inputs in.
+type AltDAChallengeFetcher interface {
+	L1TransactionFetcher
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+// altDAItem is a piece of batcher data, or a commitment to an alt-DA input, in the order it was posted to L1.
+type altDAItem struct {
+	// block is the number of the L1 block the item is included in
+	block uint64
+	// data is the batcher data, or the input of the commitment once it is decided to be available on L1
+	data eth.Data
+	// comm is the commitment to the input, or nil if the batcher data was posted to L1 directly
+	comm *altda.Keccak256Commitment
+	// decided is true once the availability of the input is decided, or if the item is not a commitment
+	decided bool
+	// fetch is true if the input is available and is retrieved from the DA server
+	fetch bool
+	// skip is true if the input is unavailable, and the commitment is skipped
+	skip bool
+}
+
+// AltDAState replaces the commitments to alt-DA inputs in the batcher data with the inputs,
+// keeping track of the commitments and their challenges as the L1 origin of the pipeline progresses.
+// Batcher data that is not a commitment is passed through, so the batcher can fall back to posting frames on L1.
+//
+// Whether an input is available is decided from the L1 chain that the pipeline traversed only,
+// so all nodes derive the same chain, and an L1 reorg of the blocks a decision depends on resets the pipeline:
+//   - An input is decided on once the L1 origin reaches the end of the challenge window of its commitment,
+//     AltDAChallengeWindow blocks after the L1 block with the commitment.
+//   - A challenge is a successful transaction to the challenge address that pays at least AltDAChallengeBond,
+//     with the challenge transaction data of the commitment, in the challenge window.
+//   - A resolution is a successful transaction to the challenge address with the resolve transaction data of the input.
+//   - An input that is resolved on L1 up to the end of the resolve window, AltDAResolveWindow blocks after
+//     the challenge window, is taken from L1.
+//   - An input that is not challenged is retrieved from the DA server, which is retried until it has the input.
+//   - An input that is challenged, and not resolved by the end of the resolve window, is unavailable,
+//     and its commitment is skipped.
+//
+// The batcher data is passed on in the order it was posted in, once every earlier input is decided on.
+type AltDAState struct {
+	log     log.Logger
+	cfg     *rollup.Config
+	fetcher AltDAInputFetcher
+	l1      AltDAChallengeFetcher
+
+	// origin is the last L1 block that is searched for batcher data, challenges and resolutions
+	origin eth.L1BlockRef
+	// start is the first L1 origin at which batcher data is passed on. Data that is decided on before,
+	// after a reset, was already passed on before the reset, or is older than the channel timeout.
+	start uint64
+	// items are the batcher data and commitments that are not passed on yet, in the order they were posted in
+	items []*altDAItem
+	// challenged are the L1 block numbers that the commitments are challenged in
+	challenged map[altda.Keccak256Commitment][]uint64
+	// resolved are the inputs that are posted to L1, with the numbers of the L1 blocks they are posted in
+	resolved map[altda.Keccak256Commitment]*altDAResolution
+}
+
+type altDAResolution struct {
+	blocks []uint64
+	input  []byte
+}
+
+func NewAltDAState(log log.Logger, cfg *rollup.Config, fetcher AltDAInputFetcher, l1 AltDAChallengeFetcher) *AltDAState {
+	return &AltDAState{
+		log:        log,
+		cfg:        cfg,
+		fetcher:    fetcher,
+		l1:         l1,
+		challenged: make(map[altda.Keccak256Commitment][]uint64),
+		resolved:   make(map[altda.Keccak256Commitment]*altDAResolution),
+	}
+}
+
+// Reset clears the state, to search for batcher data from the given L1 block.
+// The data of the L1 blocks in the alt-DA window after the base is not passed on, as it may depend
+// on commitments from before the base. The pipeline resets to a base that is far enough back for this.
+func (s *AltDAState) Reset(base eth.L1BlockRef) {
+	s.origin = eth.L1BlockRef{}
+	s.start = base.Number
+	if base.Number > s.cfg.Genesis.L1.Number {
+		s.start += s.cfg.AltDAWindow()
+	}
+	s.items = nil
+	clear(s.challenged)
+	clear(s.resolved)
+}
+
+// Open returns the batcher data that is passed on at the given L1 origin, with the batcher data of the origin from src.
+func (s *AltDAState) Open(ref eth.L1BlockRef, src DataIter) *AltDADataSource {
+	return &AltDADataSource{state: s, ref: ref, src: src}
+}
+
+// AltDADataSource returns the batcher data that is passed on by the AltDAState at an L1 origin.
+type AltDADataSource struct {
+	state *AltDAState
+	ref   eth.L1BlockRef
+	src   DataIter
+	// data is the batcher data of the L1 origin, collected from src
+	data []eth.Data
+	// added is true once the batcher data, challenges and resolutions of the L1 origin are added to the state
+	added bool
+}
+
+// Next returns the next piece of batcher data, with commitments replaced by their input, or an io.EOF error if
+// no data is passed on at the L1 origin anymore. It returns a TemporaryError if an input cannot be retrieved
+// from the DA server, and retries the same input on the next call.
+func (s *AltDADataSource) Next(ctx context.Context) (eth.Data, error) {
+	if !s.added {
+		for {
+			data, err := s.src.Next(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			s.data = append(s.data, data)
+		}
+		if err := s.state.add(ctx, s.ref, s.data); err != nil {
+			return nil, err
+		}
+		s.added = true
+	}
+	return s.state.next(ctx)
+}
+
+// add adds the batcher data, challenges and resolutions of the L1 block to the state,
+// and decides on the inputs of the commitments that are at the end of their challenge or resolve window.
+func (s *AltDAState) add(ctx context.Context, ref eth.L1BlockRef, data []eth.Data) error {
+	if s.origin != (eth.L1BlockRef{}) {
+		if ref.Number <= s.origin.Number {
+			// the pipeline opens the data of its base again after a reset
+			return nil
+		}
+		if ref.ParentHash != s.origin.Hash {
+			return NewResetError(fmt.Errorf("L1 block %s does not build on %s", ref, s.origin))
+		}
+	}
+	if err := s.searchChallenges(ctx, ref); err != nil {
+		return err
+	}
+	for _, d := range data {
+		item := &altDAItem{block: ref.Number, data: d, decided: true}
+		if len(d) > 0 && d[0] == altda.TxDataVersion1 {
+			comm, err := altda.DecodeKeccak256Commitment(d[1:])
+			if err != nil {
+				s.log.Warn("ignoring invalid alt-DA commitment", "l1", ref, "err", err)
+				continue
+			}
+			item = &altDAItem{block: ref.Number, comm: &comm}
+		}
+		s.items = append(s.items, item)
+	}
+	s.origin = ref
+	for _, item := range s.items {
+		if !item.decided {
+			s.decide(item)
+		}
+	}
+	s.prune()
+	return nil
+}
+
+// decide decides on the availability of the input of a commitment, if the L1 origin is far enough after its L1 block.
+func (s *AltDAState) decide(item *altDAItem) {
+	challengeEnd := item.block + s.cfg.AltDAChallengeWindow
+	resolveEnd := challengeEnd + s.cfg.AltDAResolveWindow
+	if s.origin.Number < challengeEnd {
+		return
+	}
+	if res, ok := s.resolved[*item.comm]; ok && inWindow(res.blocks, item.block, resolveEnd) {
+		s.log.Debug("alt-DA input is resolved on L1", "commitment", item.comm, "l1", item.block)
+		item.data, item.decided = res.input, true
+		return
+	}
+	challenged := inWindow(s.challenged[*item.comm], item.block, challengeEnd)
+	if !challenged {
+		item.fetch, item.decided = true, true
+		return
+	}
+	if s.origin.Number >= resolveEnd {
+		s.log.Warn("skipping alt-DA commitment, its input was challenged and not resolved", "commitment", item.comm, "l1", item.block)
+		item.skip, item.decided = true, true
+	}
+}
+
+// next returns the next piece of batcher data that is decided on, in the order the batcher data was posted in.
+func (s *AltDAState) next(ctx context.Context) (eth.Data, error) {
+	for len(s.items) > 0 && s.items[0].decided {
+		item := s.items[0]
+		if item.skip || s.origin.Number < s.start {
+			s.items = s.items[1:]
+			continue
+		}
+		if item.fetch {
+			input, err := s.fetcher.GetInput(ctx, *item.comm)
+			if err != nil {
+				return nil, NewTemporaryError(fmt.Errorf("failed to retrieve alt-DA input %s: %w", item.comm, err))
+			}
+			s.log.Debug("retrieved alt-DA input", "commitment", item.comm, "size", len(input))
+			item.data = input
+		}
+		s.items = s.items[1:]
+		return item.data, nil
+	}
+	return nil, io.EOF
+}
+
+// searchChallenges adds the challenges and resolutions in the L1 block to the state.
+func (s *AltDAState) searchChallenges(ctx context.Context, ref eth.L1BlockRef) error {
+	_, txs, err := s.l1.InfoAndTxsByHash(ctx, ref.Hash)
+	if err != nil {
+		return NewTemporaryError(fmt.Errorf("failed to fetch transactions of L1 block %s: %w", ref, err))
+	}
+	var receipts types.Receipts
+	for i, tx := range txs {
+		if to := tx.To(); to == nil || *to != s.cfg.AltDAChallengeAddress {
+			continue
+		}
+		comm, input, err := altda.DecodeChallengeTxData(tx.Data())
+		if err != nil {
+			s.log.Warn("ignoring invalid alt-DA challenge transaction", "tx", tx.Hash(), "err", err)
+			continue
+		}
+		if input == nil && tx.Value().Cmp(s.cfg.AltDAChallengeBond) < 0 {
+			s.log.Warn("ignoring alt-DA challenge without bond", "tx", tx.Hash(), "commitment", comm, "value", tx.Value())
+			continue
+		}
+		if receipts == nil {
+			if _, receipts, err = s.l1.FetchReceipts(ctx, ref.Hash); err != nil {
+				return NewTemporaryError(fmt.Errorf("failed to fetch receipts of L1 block %s: %w", ref, err))
+			}
+			if len(receipts) != len(txs) {
+				return NewResetError(fmt.Errorf("L1 block %s has %d receipts for %d transactions", ref, len(receipts), len(txs)))
+			}
+		}
+		if receipts[i].Status != types.ReceiptStatusSuccessful {
+			s.log.Debug("ignoring failed alt-DA challenge transaction", "tx", tx.Hash())
+			continue
+		}
+		if input != nil {
+			if res, ok := s.resolved[comm]; ok {
+				res.blocks = append(res.blocks, ref.Number)
+			} else {
+				s.resolved[comm] = &altDAResolution{blocks: []uint64{ref.Number}, input: input}
+			}
+		} else {
+			s.challenged[comm] = append(s.challenged[comm], ref.Number)
+		}
+	}
+	return nil
+}
+
+// prune removes the challenges and resolutions that no commitment that is not decided on yet can depend on.
+func (s *AltDAState) prune() {
+	oldest := s.origin.Number
+	for _, item := range s.items {
+		if !item.decided && item.block < oldest {
+			oldest = item.block
+		}
+	}
+	for comm, blocks := range s.challenged {
+		if blocks[len(blocks)-1] <= oldest {
+			delete(s.challenged, comm)
+		}
+	}
+	for comm, res := range s.resolved {
+		if res.blocks[len(res.blocks)-1] <= oldest {
+			delete(s.resolved, comm)
+		}
+	}
+}
+
+// inWindow returns true if any of the L1 block numbers is after the start block, up to and including the end block.
+func inWindow(blocks []uint64, start uint64, end uint64) bool {
+	for _, block := range blocks {
+		if block > start && block <= end {
+			return true
+		}
+	}
+	return false
+}
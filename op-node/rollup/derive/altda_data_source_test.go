@@ -0,0 +1,223 @@
+package derive
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type testDataIter struct {
+	data []eth.Data
+}
+
+func (it *testDataIter) Next(ctx context.Context) (eth.Data, error) {
+	if len(it.data) == 0 {
+		return nil, io.EOF
+	}
+	data := it.data[0]
+	it.data = it.data[1:]
+	return data, nil
+}
+
+type testAltDAFetcher struct {
+	inputs map[altda.Keccak256Commitment][]byte
+	calls  int
+}
+
+func (f *testAltDAFetcher) GetInput(ctx context.Context, comm altda.Keccak256Commitment) ([]byte, error) {
+	f.calls++
+	input, ok := f.inputs[comm]
+	if !ok {
+		return nil, altda.ErrNotFound
+	}
+	return input, nil
+}
+
+type testChallengeL1 struct {
+	txs      map[common.Hash]types.Transactions
+	receipts map[common.Hash]types.Receipts
+}
+
+func (l *testChallengeL1) addTx(ref eth.L1BlockRef, to common.Address, value int64, data []byte, status uint64) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: uint64(len(l.txs[ref.Hash])), To: &to, Value: big.NewInt(value), Data: data})
+	l.txs[ref.Hash] = append(l.txs[ref.Hash], tx)
+	l.receipts[ref.Hash] = append(l.receipts[ref.Hash], &types.Receipt{TxHash: tx.Hash(), Status: status})
+}
+
+func (l *testChallengeL1) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	return nil, l.txs[hash], nil
+}
+
+func (l *testChallengeL1) FetchReceipts(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	return nil, l.receipts[hash], nil
+}
+
+type altDATest struct {
+	t       *testing.T
+	cfg     *rollup.Config
+	state   *AltDAState
+	l1      *testChallengeL1
+	fetcher *testAltDAFetcher
+	refs    []eth.L1BlockRef
+}
+
+func newAltDATest(t *testing.T) *altDATest {
+	cfg := &rollup.Config{
+		UseAltDA:              true,
+		AltDAChallengeAddress: common.Address{0xda},
+		AltDAChallengeWindow:  3,
+		AltDAResolveWindow:    5,
+		AltDAChallengeBond:    big.NewInt(100),
+	}
+	l1 := &testChallengeL1{txs: make(map[common.Hash]types.Transactions), receipts: make(map[common.Hash]types.Receipts)}
+	fetcher := &testAltDAFetcher{inputs: make(map[altda.Keccak256Commitment][]byte)}
+	state := NewAltDAState(testlog.Logger(t, log.LevelCrit), cfg, fetcher, l1)
+	a := &altDATest{t: t, cfg: cfg, state: state, l1: l1, fetcher: fetcher}
+	state.Reset(a.block(0))
+	return a
+}
+
+// block returns the L1 block with the given number, extending the L1 chain as needed.
+func (a *altDATest) block(num uint64) eth.L1BlockRef {
+	for uint64(len(a.refs)) <= num {
+		ref := eth.L1BlockRef{Hash: common.Hash{byte(len(a.refs) + 1)}, Number: uint64(len(a.refs))}
+		if len(a.refs) > 0 {
+			ref.ParentHash = a.refs[len(a.refs)-1].Hash
+		}
+		a.refs = append(a.refs, ref)
+	}
+	return a.refs[num]
+}
+
+func (a *altDATest) challenge(num uint64, comm altda.Keccak256Commitment) {
+	a.l1.addTx(a.block(num), a.cfg.AltDAChallengeAddress, a.cfg.AltDAChallengeBond.Int64(), comm.ChallengeTxData(), types.ReceiptStatusSuccessful)
+}
+
+func (a *altDATest) resolve(num uint64, input []byte) {
+	a.l1.addTx(a.block(num), a.cfg.AltDAChallengeAddress, 0, altda.ResolveTxData(input), types.ReceiptStatusSuccessful)
+}
+
+// derive opens the L1 block with the given batcher data, and returns the data that is passed on at the L1 block.
+func (a *altDATest) derive(num uint64, data ...eth.Data) []eth.Data {
+	return a.drain(a.state.Open(a.block(num), &testDataIter{data: data}))
+}
+
+func (a *altDATest) drain(src DataIter) []eth.Data {
+	var out []eth.Data
+	for {
+		data, err := src.Next(context.Background())
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(a.t, err)
+		out = append(out, data)
+	}
+}
+
+func TestAltDAState(t *testing.T) {
+	frame1 := eth.Data{DerivationVersion0, 0xaa}
+	frame2 := eth.Data{DerivationVersion0, 0xab}
+	input := []byte{DerivationVersion0, 0xbb}
+	comm := altda.NewKeccak256Commitment(input)
+	invalid := append(eth.Data{altda.TxDataVersion1}, comm[:]...) // missing commitment type
+
+	t.Run("NotChallenged", func(t *testing.T) {
+		a := newAltDATest(t)
+		// frames posted on L1 directly are passed on, unless an earlier input is not decided on yet
+		require.Equal(t, []eth.Data{frame1}, a.derive(1, frame1, invalid, comm.TxData()))
+		require.Empty(t, a.derive(2, frame2))
+		require.Empty(t, a.derive(3))
+		require.Zero(t, a.fetcher.calls)
+
+		// at the end of the challenge window the input is retrieved, until the DA server has it
+		src := a.state.Open(a.block(4), &testDataIter{})
+		_, err := src.Next(context.Background())
+		require.ErrorIs(t, err, ErrTemporary)
+		require.ErrorIs(t, err, altda.ErrNotFound)
+		a.fetcher.inputs[comm] = input
+		require.Equal(t, []eth.Data{input, frame2}, a.drain(src))
+		require.Equal(t, 2, a.fetcher.calls)
+	})
+
+	t.Run("ChallengedAndResolved", func(t *testing.T) {
+		a := newAltDATest(t)
+		a.challenge(2, comm)
+		a.resolve(6, input)
+		require.Empty(t, a.derive(1, comm.TxData()))
+		for i := uint64(2); i < 6; i++ {
+			require.Empty(t, a.derive(i), "input is challenged and not resolved yet at block %d", i)
+		}
+		require.Equal(t, []eth.Data{input}, a.derive(6))
+		require.Zero(t, a.fetcher.calls, "input is taken from L1")
+	})
+
+	t.Run("ChallengedAndNotResolved", func(t *testing.T) {
+		a := newAltDATest(t)
+		a.fetcher.inputs[comm] = input
+		a.challenge(4, comm)
+		// resolutions that are sent to another address, fail, or are after the resolve window don't count
+		a.l1.addTx(a.block(5), common.Address{0xaa}, 0, altda.ResolveTxData(input), types.ReceiptStatusSuccessful)
+		a.l1.addTx(a.block(6), a.cfg.AltDAChallengeAddress, 0, altda.ResolveTxData(input), types.ReceiptStatusFailed)
+		a.resolve(10, input)
+		require.Empty(t, a.derive(1, comm.TxData(), frame1))
+		for i := uint64(2); i < 9; i++ {
+			require.Empty(t, a.derive(i), "resolve window is still open at block %d", i)
+		}
+		// the commitment is skipped, even though the DA server has the input
+		require.Equal(t, []eth.Data{frame1}, a.derive(9))
+		require.Empty(t, a.derive(10))
+		require.Zero(t, a.fetcher.calls)
+	})
+
+	t.Run("IgnoredChallenges", func(t *testing.T) {
+		a := newAltDATest(t)
+		a.fetcher.inputs[comm] = input
+		// a challenge without the bond, a failed challenge, and challenges outside of the challenge window don't count
+		a.l1.addTx(a.block(1), a.cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), types.ReceiptStatusSuccessful)
+		a.l1.addTx(a.block(2), a.cfg.AltDAChallengeAddress, 99, comm.ChallengeTxData(), types.ReceiptStatusSuccessful)
+		a.l1.addTx(a.block(3), a.cfg.AltDAChallengeAddress, 100, comm.ChallengeTxData(), types.ReceiptStatusFailed)
+		a.challenge(5, comm)
+		require.Empty(t, a.derive(1, comm.TxData()))
+		require.Empty(t, a.derive(2))
+		require.Empty(t, a.derive(3))
+		require.Equal(t, []eth.Data{input}, a.derive(4))
+		require.Equal(t, 1, a.fetcher.calls)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		a := newAltDATest(t)
+		a.fetcher.inputs[comm] = input
+		require.Empty(t, a.derive(1, comm.TxData()))
+
+		base := a.block(10)
+		a.state.Reset(base)
+		// the data that is decided on in the alt-DA window after the base was passed on before the reset
+		require.Empty(t, a.derive(10, comm.TxData(), frame1))
+		require.Empty(t, a.derive(10, comm.TxData(), frame1), "data of the base is only added once")
+		for i := uint64(11); i < 18; i++ {
+			require.Empty(t, a.derive(i))
+		}
+		require.Equal(t, []eth.Data{frame2}, a.derive(18, frame2))
+		require.Zero(t, a.fetcher.calls)
+	})
+
+	t.Run("Reorg", func(t *testing.T) {
+		a := newAltDATest(t)
+		require.Empty(t, a.derive(1, comm.TxData()))
+		reorged := a.block(2)
+		reorged.ParentHash = common.Hash{0xff}
+		_, err := a.state.Open(reorged, &testDataIter{}).Next(context.Background())
+		require.ErrorIs(t, err, ErrReset)
+	})
+}
@@ -32,6 +32,9 @@ type NextBatchProvider interface {
 	NextBatch(ctx context.Context) (Batch, error)
 }
 
+// maxRecentBatchChecks is the number of batch checks the batch queue retains for inspection.
+const maxRecentBatchChecks = 64
+
 type SafeBlockFetcher interface {
 	L2BlockRefByNumber(context.Context, uint64) (eth.L2BlockRef, error)
 	PayloadByNumber(context.Context, uint64) (*eth.ExecutionPayloadEnvelope, error)
@@ -59,6 +62,9 @@ type BatchQueue struct {
 	// nextSpan is cached SingularBatches derived from SpanBatch
 	nextSpan []*SingularBatch
 
+	// recentChecks are the outcomes of the most recent batch checks, for inspection only
+	recentChecks []eth.BatchState
+
 	l2 SafeBlockFetcher
 }
 
@@ -221,6 +227,7 @@ func (bq *BatchQueue) Reset(ctx context.Context, base eth.L1BlockRef, _ eth.Syst
 	bq.l1Blocks = bq.l1Blocks[:0]
 	bq.l1Blocks = append(bq.l1Blocks, base)
 	bq.nextSpan = bq.nextSpan[:0]
+	bq.recentChecks = bq.recentChecks[:0]
 	return io.EOF
 }
 
@@ -232,7 +239,7 @@ func (bq *BatchQueue) AddBatch(ctx context.Context, batch Batch, parent eth.L2Bl
 		L1InclusionBlock: bq.origin,
 		Batch:            batch,
 	}
-	validity := CheckBatch(ctx, bq.config, bq.log, bq.l1Blocks, parent, &data, bq.l2)
+	validity := bq.checkBatch(ctx, bq.log, parent, &data)
 	if validity == BatchDrop {
 		return // if we do drop the batch, CheckBatch will log the drop reason with WARN level.
 	}
@@ -269,7 +276,7 @@ func (bq *BatchQueue) deriveNextBatch(ctx context.Context, outOfData bool, paren
 	var remaining []*BatchWithL1InclusionBlock
 batchLoop:
 	for i, batch := range bq.batches {
-		validity := CheckBatch(ctx, bq.config, bq.log.New("batch_index", i), bq.l1Blocks, parent, batch, bq.l2)
+		validity := bq.checkBatch(ctx, bq.log.New("batch_index", i), parent, batch)
 		switch validity {
 		case BatchFuture:
 			remaining = append(remaining, batch)
@@ -342,3 +349,62 @@ batchLoop:
 	bq.l1Blocks = bq.l1Blocks[1:]
 	return nil, io.EOF
 }
+
+// checkBatch checks the batch with CheckBatch, and records the outcome for inspection.
+func (bq *BatchQueue) checkBatch(ctx context.Context, log log.Logger, parent eth.L2BlockRef, batch *BatchWithL1InclusionBlock) BatchValidity {
+	validity, reason := CheckBatch(ctx, bq.config, log, bq.l1Blocks, parent, batch, bq.l2)
+	if len(bq.recentChecks) >= maxRecentBatchChecks {
+		bq.recentChecks = append(bq.recentChecks[:0], bq.recentChecks[1:]...)
+	}
+	state := batchState(batch)
+	state.Parent = parent.ID()
+	state.Validity = validity.String()
+	state.Reason = string(reason)
+	bq.recentChecks = append(bq.recentChecks, state)
+	return validity
+}
+
+// Inspect returns the current state of the stage.
+func (bq *BatchQueue) Inspect() eth.BatchQueueState {
+	batches := make([]eth.BatchState, 0, len(bq.batches))
+	for _, batch := range bq.batches {
+		state := batchState(batch)
+		// find the last check of the buffered batch, if it was checked recently
+		for i := len(bq.recentChecks) - 1; i >= 0; i-- {
+			if c := bq.recentChecks[i]; sameBatch(c, state) {
+				state = c
+				break
+			}
+		}
+		batches = append(batches, state)
+	}
+	return eth.BatchQueueState{
+		L1Blocks:          append([]eth.L1BlockRef{}, bq.l1Blocks...),
+		Batches:           batches,
+		PendingSpanBlocks: len(bq.nextSpan),
+		RecentChecks:      append([]eth.BatchState{}, bq.recentChecks...),
+	}
+}
+
+// sameBatch returns true if the batch states describe the same batch, regardless of the outcome of their checks.
+func sameBatch(a, b eth.BatchState) bool {
+	return a.Type == b.Type && a.Timestamp == b.Timestamp && a.Epoch == b.Epoch && a.L1InclusionBlock == b.L1InclusionBlock
+}
+
+func batchState(batch *BatchWithL1InclusionBlock) eth.BatchState {
+	state := eth.BatchState{
+		Timestamp:        batch.Batch.GetTimestamp(),
+		L1InclusionBlock: batch.L1InclusionBlock,
+	}
+	switch b := batch.Batch.(type) {
+	case *SingularBatch:
+		state.Type = "singular"
+		state.Epoch = uint64(b.EpochNum)
+	case *SpanBatch:
+		state.Type = "span"
+		state.Epoch = uint64(b.GetStartEpochNum())
+	default:
+		state.Type = fmt.Sprintf("unknown(%d)", batch.Batch.GetBatchType())
+	}
+	return state
+}
@@ -484,6 +484,17 @@ func BatchQueueMissing(t *testing.T, batchType int) {
 		require.Nil(t, b)
 	}
 
+	// the batches are buffered, with the outcome of their last check
+	state := bq.Inspect()
+	require.Equal(t, []eth.L1BlockRef{l1[0]}, state.L1Blocks)
+	require.Len(t, state.Batches, len(inputBatches))
+	for i, batch := range state.Batches {
+		require.Equal(t, inputBatches[i].GetTimestamp(), batch.Timestamp)
+		require.Equal(t, l1[0], batch.L1InclusionBlock)
+		require.Equal(t, safeHead.ID(), batch.Parent)
+		require.Contains(t, []string{"future", "undecided"}, batch.Validity)
+	}
+
 	// advance origin. Underlying stage still has no more inputBatches
 	// This is not enough to auto advance yet
 	input.origin = l1[1]
@@ -1014,3 +1025,43 @@ func TestBatchQueueResetSpan(t *testing.T) {
 	require.ErrorIs(t, err, io.EOF)
 	require.Equal(t, len(bq.nextSpan), 0)
 }
+
+func TestBatchQueueInspectDropReason(t *testing.T) {
+	log := testlog.Logger(t, log.LevelCrit)
+	l1 := L1Chain([]uint64{10, 15, 20, 25})
+	chainId := big.NewInt(1234)
+	safeHead := eth.L2BlockRef{
+		Hash:     mockHash(20, 2),
+		Number:   5,
+		Time:     20,
+		L1Origin: l1[0].ID(),
+	}
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L2Time: 10,
+		},
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+		SeqWindowSize:     2,
+		L2ChainID:         chainId,
+	}
+
+	bq := NewBatchQueue(log, cfg, &fakeBatchQueueInput{origin: l1[0]}, nil)
+	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
+
+	old := b(chainId, 16, l1[0])
+	future := b(chainId, 26, l1[0])
+	bq.AddBatch(context.Background(), old, safeHead)
+	bq.AddBatch(context.Background(), future, safeHead)
+
+	state := bq.Inspect()
+	require.Len(t, state.RecentChecks, 2)
+	require.Equal(t, uint64(16), state.RecentChecks[0].Timestamp)
+	require.Equal(t, "drop", state.RecentChecks[0].Validity)
+	require.Equal(t, string(DropOldTimestamp), state.RecentChecks[0].Reason)
+	require.Equal(t, "future", state.RecentChecks[1].Validity)
+	require.Empty(t, state.RecentChecks[1].Reason)
+
+	// only the future batch is buffered, with the outcome of its check
+	require.Equal(t, []eth.BatchState{state.RecentChecks[1]}, state.Batches)
+}
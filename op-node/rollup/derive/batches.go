@@ -3,6 +3,7 @@ package derive
 import (
 	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -28,64 +29,102 @@ const (
 	BatchFuture
 )
 
+func (v BatchValidity) String() string {
+	switch v {
+	case BatchDrop:
+		return "drop"
+	case BatchAccept:
+		return "accept"
+	case BatchUndecided:
+		return "undecided"
+	case BatchFuture:
+		return "future"
+	default:
+		return fmt.Sprintf("BatchValidity(%d)", uint8(v))
+	}
+}
+
+// BatchDropReason is why a batch is dropped. It is empty if the batch is not dropped.
+type BatchDropReason string
+
+const (
+	DropUnknownBatchType        BatchDropReason = "unknown batch type"
+	DropOldTimestamp            BatchDropReason = "old timestamp"
+	DropParentHashMismatch      BatchDropReason = "parent hash mismatch"
+	DropSeqWindowExpired        BatchDropReason = "sequence window expired"
+	DropEpochTooOld             BatchDropReason = "epoch too old"
+	DropEpochTooFarAhead        BatchDropReason = "epoch too far ahead"
+	DropEpochHashMismatch       BatchDropReason = "epoch hash mismatch"
+	DropTimestampBeforeL1Origin BatchDropReason = "timestamp before L1 origin"
+	DropNextOriginNotAdopted    BatchDropReason = "next L1 origin not adopted"
+	DropSequencerDriftExceeded  BatchDropReason = "sequencer drift exceeded"
+	DropEmptyTransaction        BatchDropReason = "empty transaction"
+	DropDepositTransaction      BatchDropReason = "deposit transaction"
+	DropSpanBatchBeforeDelta    BatchDropReason = "span batch before Delta"
+	DropNoNewBlocks             BatchDropReason = "no new blocks"
+	DropMisalignedTimestamp     BatchDropReason = "misaligned timestamp"
+	DropOverlappedBlockMismatch BatchDropReason = "overlapped block mismatch"
+)
+
 // CheckBatch checks if the given batch can be applied on top of the given l2SafeHead, given the contextual L1 blocks the batch was included in.
 // The first entry of the l1Blocks should match the origin of the l2SafeHead. One or more consecutive l1Blocks should be provided.
 // In case of only a single L1 block, the decision whether a batch is valid may have to stay undecided.
+// If the batch is dropped, the reason for dropping it is returned too.
 func CheckBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef,
-	l2SafeHead eth.L2BlockRef, batch *BatchWithL1InclusionBlock, l2Fetcher SafeBlockFetcher) BatchValidity {
+	l2SafeHead eth.L2BlockRef, batch *BatchWithL1InclusionBlock, l2Fetcher SafeBlockFetcher) (BatchValidity, BatchDropReason) {
 	switch batch.Batch.GetBatchType() {
 	case SingularBatchType:
 		singularBatch, ok := batch.Batch.(*SingularBatch)
 		if !ok {
 			log.Error("failed type assertion to SingularBatch")
-			return BatchDrop
+			return BatchDrop, DropUnknownBatchType
 		}
 		return checkSingularBatch(cfg, log, l1Blocks, l2SafeHead, singularBatch, batch.L1InclusionBlock)
 	case SpanBatchType:
 		spanBatch, ok := batch.Batch.(*SpanBatch)
 		if !ok {
 			log.Error("failed type assertion to SpanBatch")
-			return BatchDrop
+			return BatchDrop, DropUnknownBatchType
 		}
 		return checkSpanBatch(ctx, cfg, log, l1Blocks, l2SafeHead, spanBatch, batch.L1InclusionBlock, l2Fetcher)
 	default:
 		log.Warn("Unrecognized batch type: %d", batch.Batch.GetBatchType())
-		return BatchDrop
+		return BatchDrop, DropUnknownBatchType
 	}
 }
 
 // checkSingularBatch implements SingularBatch validation rule.
-func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef, batch *SingularBatch, l1InclusionBlock eth.L1BlockRef) BatchValidity {
+func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef, batch *SingularBatch, l1InclusionBlock eth.L1BlockRef) (BatchValidity, BatchDropReason) {
 	// add details to the log
 	log = batch.LogContext(log)
 
 	// sanity check we have consistent inputs
 	if len(l1Blocks) == 0 {
 		log.Warn("missing L1 block input, cannot proceed with batch checking")
-		return BatchUndecided
+		return BatchUndecided, ""
 	}
 	epoch := l1Blocks[0]
 
 	nextTimestamp := l2SafeHead.Time + cfg.BlockTime
 	if batch.Timestamp > nextTimestamp {
 		log.Trace("received out-of-order batch for future processing after next batch", "next_timestamp", nextTimestamp)
-		return BatchFuture
+		return BatchFuture, ""
 	}
 	if batch.Timestamp < nextTimestamp {
 		log.Warn("dropping batch with old timestamp", "min_timestamp", nextTimestamp)
-		return BatchDrop
+		return BatchDrop, DropOldTimestamp
 	}
 
 	// dependent on above timestamp check. If the timestamp is correct, then it must build on top of the safe head.
 	if batch.ParentHash != l2SafeHead.Hash {
 		log.Warn("ignoring batch with mismatching parent hash", "current_safe_head", l2SafeHead.Hash)
-		return BatchDrop
+		return BatchDrop, DropParentHashMismatch
 	}
 
 	// Filter out batches that were included too late.
 	if uint64(batch.EpochNum)+cfg.SeqWindowSize < l1InclusionBlock.Number {
 		log.Warn("batch was included too late, sequence window expired")
-		return BatchDrop
+		return BatchDrop, DropSeqWindowExpired
 	}
 
 	// Check the L1 origin of the batch
@@ -93,7 +132,7 @@ func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1Blo
 	if uint64(batch.EpochNum) < epoch.Number {
 		log.Warn("dropped batch, epoch is too old", "minimum", epoch.ID())
 		// batch epoch too old
-		return BatchDrop
+		return BatchDrop, DropEpochTooOld
 	} else if uint64(batch.EpochNum) == epoch.Number {
 		// Batch is sticking to the current epoch, continue.
 	} else if uint64(batch.EpochNum) == epoch.Number+1 {
@@ -104,22 +143,22 @@ func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1Blo
 		// algorithm.
 		if len(l1Blocks) < 2 {
 			log.Info("eager batch wants to advance epoch, but could not without more L1 blocks", "current_epoch", epoch.ID())
-			return BatchUndecided
+			return BatchUndecided, ""
 		}
 		batchOrigin = l1Blocks[1]
 	} else {
 		log.Warn("batch is for future epoch too far ahead, while it has the next timestamp, so it must be invalid", "current_epoch", epoch.ID())
-		return BatchDrop
+		return BatchDrop, DropEpochTooFarAhead
 	}
 
 	if batch.EpochHash != batchOrigin.Hash {
 		log.Warn("batch is for different L1 chain, epoch hash does not match", "expected", batchOrigin.ID())
-		return BatchDrop
+		return BatchDrop, DropEpochHashMismatch
 	}
 
 	if batch.Timestamp < batchOrigin.Time {
 		log.Warn("batch timestamp is less than L1 origin timestamp", "l2_timestamp", batch.Timestamp, "l1_timestamp", batchOrigin.Time, "origin", batchOrigin.ID())
-		return BatchDrop
+		return BatchDrop, DropTimestampBeforeL1Origin
 	}
 
 	// Check if we ran out of sequencer time drift
@@ -131,12 +170,12 @@ func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1Blo
 			if epoch.Number == batchOrigin.Number {
 				if len(l1Blocks) < 2 {
 					log.Info("without the next L1 origin we cannot determine yet if this empty batch that exceeds the time drift is still valid")
-					return BatchUndecided
+					return BatchUndecided, ""
 				}
 				nextOrigin := l1Blocks[1]
 				if batch.Timestamp >= nextOrigin.Time { // check if the next L1 origin could have been adopted
 					log.Info("batch exceeded sequencer time drift without adopting next origin, and next L1 origin would have been valid")
-					return BatchDrop
+					return BatchDrop, DropNextOriginNotAdopted
 				} else {
 					log.Info("continuing with empty batch before late L1 block to preserve L2 time invariant")
 				}
@@ -145,7 +184,7 @@ func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1Blo
 			// If the sequencer is ignoring the time drift rule, then drop the batch and force an empty batch instead,
 			// as the sequencer is not allowed to include anything past this point without moving to the next epoch.
 			log.Warn("batch exceeded sequencer time drift, sequencer must adopt new L1 origin to include transactions again", "max_time", max)
-			return BatchDrop
+			return BatchDrop, DropSequencerDriftExceeded
 		}
 	}
 
@@ -153,27 +192,27 @@ func checkSingularBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1Blo
 	for i, txBytes := range batch.Transactions {
 		if len(txBytes) == 0 {
 			log.Warn("transaction data must not be empty, but found empty tx", "tx_index", i)
-			return BatchDrop
+			return BatchDrop, DropEmptyTransaction
 		}
 		if txBytes[0] == types.DepositTxType {
 			log.Warn("sequencers may not embed any deposits into batch data, but found tx that has one", "tx_index", i)
-			return BatchDrop
+			return BatchDrop, DropDepositTransaction
 		}
 	}
 
-	return BatchAccept
+	return BatchAccept, ""
 }
 
 // checkSpanBatch implements SpanBatch validation rule.
 func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef,
-	batch *SpanBatch, l1InclusionBlock eth.L1BlockRef, l2Fetcher SafeBlockFetcher) BatchValidity {
+	batch *SpanBatch, l1InclusionBlock eth.L1BlockRef, l2Fetcher SafeBlockFetcher) (BatchValidity, BatchDropReason) {
 	// add details to the log
 	log = batch.LogContext(log)
 
 	// sanity check we have consistent inputs
 	if len(l1Blocks) == 0 {
 		log.Warn("missing L1 block input, cannot proceed with batch checking")
-		return BatchUndecided
+		return BatchUndecided, ""
 	}
 	epoch := l1Blocks[0]
 
@@ -182,24 +221,24 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 	if startEpochNum == batchOrigin.Number+1 {
 		if len(l1Blocks) < 2 {
 			log.Info("eager batch wants to advance epoch, but could not without more L1 blocks", "current_epoch", epoch.ID())
-			return BatchUndecided
+			return BatchUndecided, ""
 		}
 		batchOrigin = l1Blocks[1]
 	}
 	if !cfg.IsDelta(batchOrigin.Time) {
 		log.Warn("received SpanBatch with L1 origin before Delta hard fork", "l1_origin", batchOrigin.ID(), "l1_origin_time", batchOrigin.Time)
-		return BatchDrop
+		return BatchDrop, DropSpanBatchBeforeDelta
 	}
 
 	nextTimestamp := l2SafeHead.Time + cfg.BlockTime
 
 	if batch.GetTimestamp() > nextTimestamp {
 		log.Trace("received out-of-order batch for future processing after next batch", "next_timestamp", nextTimestamp)
-		return BatchFuture
+		return BatchFuture, ""
 	}
 	if batch.GetBlockTimestamp(batch.GetBlockCount()-1) < nextTimestamp {
 		log.Warn("span batch has no new blocks after safe head")
-		return BatchDrop
+		return BatchDrop, DropNoNewBlocks
 	}
 
 	// finding parent block of the span batch.
@@ -210,11 +249,11 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 		if batch.GetTimestamp() > l2SafeHead.Time {
 			// batch timestamp cannot be between safe head and next timestamp
 			log.Warn("batch has misaligned timestamp, block time is too short")
-			return BatchDrop
+			return BatchDrop, DropMisalignedTimestamp
 		}
 		if (l2SafeHead.Time-batch.GetTimestamp())%cfg.BlockTime != 0 {
 			log.Warn("batch has misaligned timestamp, not overlapped exactly")
-			return BatchDrop
+			return BatchDrop, DropMisalignedTimestamp
 		}
 		parentNum = l2SafeHead.Number - (l2SafeHead.Time-batch.GetTimestamp())/cfg.BlockTime - 1
 		var err error
@@ -222,24 +261,24 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 		if err != nil {
 			log.Warn("failed to fetch L2 block", "number", parentNum, "err", err)
 			// unable to validate the batch for now. retry later.
-			return BatchUndecided
+			return BatchUndecided, ""
 		}
 	}
 	if !batch.CheckParentHash(parentBlock.Hash) {
 		log.Warn("ignoring batch with mismatching parent hash", "parent_block", parentBlock.Hash)
-		return BatchDrop
+		return BatchDrop, DropParentHashMismatch
 	}
 
 	// Filter out batches that were included too late.
 	if startEpochNum+cfg.SeqWindowSize < l1InclusionBlock.Number {
 		log.Warn("batch was included too late, sequence window expired")
-		return BatchDrop
+		return BatchDrop, DropSeqWindowExpired
 	}
 
 	// Check the L1 origin of the batch
 	if startEpochNum > parentBlock.L1Origin.Number+1 {
 		log.Warn("batch is for future epoch too far ahead, while it has the next timestamp, so it must be invalid", "current_epoch", epoch.ID())
-		return BatchDrop
+		return BatchDrop, DropEpochTooFarAhead
 	}
 
 	endEpochNum := batch.GetBlockEpochNum(batch.GetBlockCount() - 1)
@@ -249,7 +288,7 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 		if l1Block.Number == endEpochNum {
 			if !batch.CheckOriginHash(l1Block.Hash) {
 				log.Warn("batch is for different L1 chain, epoch hash does not match", "expected", l1Block.Hash)
-				return BatchDrop
+				return BatchDrop, DropEpochHashMismatch
 			}
 			originChecked = true
 			break
@@ -257,12 +296,12 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 	}
 	if !originChecked {
 		log.Info("need more l1 blocks to check entire origins of span batch")
-		return BatchUndecided
+		return BatchUndecided, ""
 	}
 
 	if startEpochNum < parentBlock.L1Origin.Number {
 		log.Warn("dropped batch, epoch is too old", "minimum", parentBlock.ID())
-		return BatchDrop
+		return BatchDrop, DropEpochTooOld
 	}
 
 	originIdx := 0
@@ -293,7 +332,7 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 		blockTimestamp := batch.GetBlockTimestamp(i)
 		if blockTimestamp < l1Origin.Time {
 			log.Warn("block timestamp is less than L1 origin timestamp", "l2_timestamp", blockTimestamp, "l1_timestamp", l1Origin.Time, "origin", l1Origin.ID())
-			return BatchDrop
+			return BatchDrop, DropTimestampBeforeL1Origin
 		}
 
 		// Check if we ran out of sequencer time drift
@@ -305,11 +344,11 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 				if !originAdvanced {
 					if originIdx+1 >= len(l1Blocks) {
 						log.Info("without the next L1 origin we cannot determine yet if this empty batch that exceeds the time drift is still valid")
-						return BatchUndecided
+						return BatchUndecided, ""
 					}
 					if blockTimestamp >= l1Blocks[originIdx+1].Time { // check if the next L1 origin could have been adopted
 						log.Info("batch exceeded sequencer time drift without adopting next origin, and next L1 origin would have been valid")
-						return BatchDrop
+						return BatchDrop, DropNextOriginNotAdopted
 					} else {
 						log.Info("continuing with empty batch before late L1 block to preserve L2 time invariant")
 					}
@@ -318,18 +357,18 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 				// If the sequencer is ignoring the time drift rule, then drop the batch and force an empty batch instead,
 				// as the sequencer is not allowed to include anything past this point without moving to the next epoch.
 				log.Warn("batch exceeded sequencer time drift, sequencer must adopt new L1 origin to include transactions again", "max_time", max)
-				return BatchDrop
+				return BatchDrop, DropSequencerDriftExceeded
 			}
 		}
 
 		for i, txBytes := range batch.GetBlockTransactions(i) {
 			if len(txBytes) == 0 {
 				log.Warn("transaction data must not be empty, but found empty tx", "tx_index", i)
-				return BatchDrop
+				return BatchDrop, DropEmptyTransaction
 			}
 			if txBytes[0] == types.DepositTxType {
 				log.Warn("sequencers may not embed any deposits into batch data, but found tx that has one", "tx_index", i)
-				return BatchDrop
+				return BatchDrop, DropDepositTransaction
 			}
 		}
 	}
@@ -342,7 +381,7 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 			if err != nil {
 				log.Warn("failed to fetch L2 block payload", "number", parentNum, "err", err)
 				// unable to validate the batch for now. retry later.
-				return BatchUndecided
+				return BatchUndecided, ""
 			}
 			safeBlockTxs := safeBlockPayload.ExecutionPayload.Transactions
 			batchTxs := batch.GetBlockTransactions(int(i))
@@ -355,25 +394,25 @@ func checkSpanBatch(ctx context.Context, cfg *rollup.Config, log log.Logger, l1B
 			}
 			if len(safeBlockTxs)-depositCount != len(batchTxs) {
 				log.Warn("overlapped block's tx count does not match", "safeBlockTxs", len(safeBlockTxs), "batchTxs", len(batchTxs))
-				return BatchDrop
+				return BatchDrop, DropOverlappedBlockMismatch
 			}
 			for j := 0; j < len(batchTxs); j++ {
 				if !bytes.Equal(safeBlockTxs[j+depositCount], batchTxs[j]) {
 					log.Warn("overlapped block's transaction does not match")
-					return BatchDrop
+					return BatchDrop, DropOverlappedBlockMismatch
 				}
 			}
 			safeBlockRef, err := PayloadToBlockRef(cfg, safeBlockPayload.ExecutionPayload)
 			if err != nil {
 				log.Error("failed to extract L2BlockRef from execution payload", "hash", safeBlockPayload.ExecutionPayload.BlockHash, "err", err)
-				return BatchDrop
+				return BatchDrop, DropOverlappedBlockMismatch
 			}
 			if safeBlockRef.L1Origin.Number != batch.GetBlockEpochNum(int(i)) {
 				log.Warn("overlapped block's L1 origin number does not match")
-				return BatchDrop
+				return BatchDrop, DropOverlappedBlockMismatch
 			}
 		}
 	}
 
-	return BatchAccept
+	return BatchAccept, ""
 }
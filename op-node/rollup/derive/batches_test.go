@@ -1519,8 +1519,9 @@ func TestValidBatch(t *testing.T) {
 		if testCase.DeltaTime != nil {
 			rcfg.DeltaTime = testCase.DeltaTime
 		}
-		validity := CheckBatch(ctx, &rcfg, logger, testCase.L1Blocks, testCase.L2SafeHead, &testCase.Batch, &l2Client)
+		validity, reason := CheckBatch(ctx, &rcfg, logger, testCase.L1Blocks, testCase.L2SafeHead, &testCase.Batch, &l2Client)
 		require.Equal(t, testCase.Expected, validity, "batch check must return expected validity level")
+		require.Equal(t, validity == BatchDrop, reason != "", "batch check must return a reason only if the batch is dropped")
 		if expLog := testCase.ExpectedLog; expLog != "" {
 			// Check if ExpectedLog is contained in the log buffer
 			if _, ok := logs.FindLogContaining(expLog); !ok {
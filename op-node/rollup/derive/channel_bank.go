@@ -60,6 +60,27 @@ func (cb *ChannelBank) Origin() eth.L1BlockRef {
 	return cb.prev.Origin()
 }
 
+// Inspect returns the current state of the stage.
+func (cb *ChannelBank) Inspect() eth.ChannelBankState {
+	origin := cb.Origin()
+	channels := make([]eth.ChannelState, 0, len(cb.channelQueue))
+	for _, id := range cb.channelQueue {
+		ch := cb.channels[id]
+		timeoutBlock := ch.OpenBlockNumber() + cb.cfg.ChannelTimeout
+		channels = append(channels, eth.ChannelState{
+			ID:           id.String(),
+			OpenBlock:    ch.openBlock,
+			TimeoutBlock: timeoutBlock,
+			TimedOut:     timeoutBlock < origin.Number,
+			Size:         ch.Size(),
+			FrameCount:   len(ch.inputs),
+			Closed:       ch.closed,
+			Ready:        ch.IsReady(),
+		})
+	}
+	return eth.ChannelBankState{Channels: channels}
+}
+
 func (cb *ChannelBank) prune() {
 	// check total size
 	totalSize := uint64(0)
@@ -130,6 +130,40 @@ func TestChannelBankSimple(t *testing.T) {
 	require.Equal(t, io.EOF, err)
 }
 
+func TestChannelBankInspect(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	a := testutils.RandomBlockRef(rng)
+
+	input := &fakeChannelBankInput{origin: a}
+	input.AddFrames("a:0:first", "b:0:only!")
+	cfg := &rollup.Config{ChannelTimeout: 10}
+	cb := NewChannelBank(testlog.Logger(t, log.LevelCrit), cfg, input, nil, metrics.NoopMetrics)
+
+	require.Empty(t, cb.Inspect().Channels)
+	for i := 0; i < 2; i++ {
+		_, err := cb.NextData(context.Background())
+		require.ErrorIs(t, err, NotEnoughData)
+	}
+
+	state := cb.Inspect()
+	require.Len(t, state.Channels, 2)
+	chA, chB := state.Channels[0], state.Channels[1]
+	require.Equal(t, testFrame("a:0:").ChannelID().String(), chA.ID)
+	require.Equal(t, a, chA.OpenBlock)
+	require.Equal(t, a.Number+10, chA.TimeoutBlock)
+	require.False(t, chA.TimedOut)
+	require.Equal(t, 1, chA.FrameCount)
+	require.False(t, chA.Closed)
+	require.False(t, chA.Ready)
+	require.NotZero(t, chA.Size)
+	require.True(t, chB.Closed)
+	require.True(t, chB.Ready)
+
+	input.origin = testutils.NextRandomRef(rng, a)
+	input.origin.Number = a.Number + 11
+	require.True(t, cb.Inspect().Channels[0].TimedOut)
+}
+
 // TestChannelBankInterleavedPreCanyon ensure that the channel bank can handle frames from multiple channels
 // that arrive out of order. Per the specs, the first channel to arrive (not the first to be completed)
 // is returned first prior to the Canyon network upgrade
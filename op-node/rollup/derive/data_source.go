@@ -3,6 +3,7 @@ package derive
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -31,28 +32,59 @@ type L1BlobsFetcher interface {
 type DataSourceFactory struct {
 	log          log.Logger
 	dsCfg        DataSourceConfig
-	fetcher      L1TransactionFetcher
+	fetcher      L1Fetcher
 	blobsFetcher L1BlobsFetcher
+	altDAFetcher AltDAInputFetcher
+	altDA        *AltDAState
 	ecotoneTime  *uint64
 }
 
-func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, blobsFetcher L1BlobsFetcher) *DataSourceFactory {
+func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1Fetcher, blobsFetcher L1BlobsFetcher, altDAFetcher AltDAInputFetcher) *DataSourceFactory {
 	config := DataSourceConfig{
 		l1Signer:          cfg.L1Signer(),
 		batchInboxAddress: cfg.BatchInboxAddress,
 	}
-	return &DataSourceFactory{log: log, dsCfg: config, fetcher: fetcher, blobsFetcher: blobsFetcher, ecotoneTime: cfg.EcotoneTime}
+	ds := &DataSourceFactory{
+		log:          log,
+		dsCfg:        config,
+		fetcher:      fetcher,
+		blobsFetcher: blobsFetcher,
+		altDAFetcher: altDAFetcher,
+		ecotoneTime:  cfg.EcotoneTime,
+	}
+	if cfg.UseAltDA {
+		ds.altDA = NewAltDAState(log, cfg, altDAFetcher, fetcher)
+	}
+	return ds
+}
+
+// Reset clears the alt-DA commitments that are tracked across L1 blocks, if alt-DA is used.
+// It must be reset before the L1 Retrieval stage, which opens the data of the base.
+func (ds *DataSourceFactory) Reset(ctx context.Context, base eth.L1BlockRef, _ eth.SystemConfig) error {
+	if ds.altDA != nil {
+		ds.altDA.Reset(base)
+	}
+	return io.EOF
 }
 
 // OpenData returns the appropriate data source for the L1 block `ref`.
 func (ds *DataSourceFactory) OpenData(ctx context.Context, ref eth.L1BlockRef, batcherAddr common.Address) (DataIter, error) {
+	var src DataIter
 	if ds.ecotoneTime != nil && ref.Time >= *ds.ecotoneTime {
 		if ds.blobsFetcher == nil {
 			return nil, fmt.Errorf("ecotone upgrade active but beacon endpoint not configured")
 		}
-		return NewBlobDataSource(ctx, ds.log, ds.dsCfg, ds.fetcher, ds.blobsFetcher, ref, batcherAddr), nil
+		src = NewBlobDataSource(ctx, ds.log, ds.dsCfg, ds.fetcher, ds.blobsFetcher, ref, batcherAddr)
+	} else {
+		src = NewCalldataSource(ctx, ds.log, ds.dsCfg, ds.fetcher, ref, batcherAddr)
+	}
+	if ds.altDA != nil {
+		if ds.altDAFetcher == nil {
+			return nil, fmt.Errorf("alt-DA enabled but DA server not configured")
+		}
+		return ds.altDA.Open(ref, src), nil
 	}
-	return NewCalldataSource(ctx, ds.log, ds.dsCfg, ds.fetcher, ref, batcherAddr), nil
+	return src, nil
 }
 
 // DataSourceConfig regroups the mandatory rollup.Config fields needed for DataFromEVMTransactions.
@@ -61,6 +61,11 @@ type EngineController struct {
 	finalizedHead   eth.L2BlockRef
 	needFCUCall     bool
 
+	// Cross-chain Head State, only tracked after the interop upgrade is scheduled.
+	// The cross-unsafe and cross-safe heads are the last unsafe and safe blocks of which all cross-chain dependencies are verified.
+	crossUnsafeHead eth.L2BlockRef
+	crossSafeHead   eth.L2BlockRef
+
 	// Building State
 	buildingOnto eth.L2BlockRef
 	buildingInfo eth.PayloadInfo
@@ -103,6 +108,23 @@ func (e *EngineController) Finalized() eth.L2BlockRef {
 	return e.finalizedHead
 }
 
+// CrossUnsafeL2Head returns the cross-unsafe head, which is the unsafe head if the interop upgrade is not scheduled.
+func (e *EngineController) CrossUnsafeL2Head() eth.L2BlockRef {
+	if e.rollupCfg.InteropTime == nil {
+		return e.unsafeHead
+	}
+	return e.crossUnsafeHead
+}
+
+// CrossSafeL2Head returns the cross-safe head, which is the safe head if the interop upgrade is not scheduled.
+// The cross-safe head is the safe block of the forkchoice state of the engine.
+func (e *EngineController) CrossSafeL2Head() eth.L2BlockRef {
+	if e.rollupCfg.InteropTime == nil {
+		return e.safeHead
+	}
+	return e.crossSafeHead
+}
+
 func (e *EngineController) BuildingPayload() (eth.L2BlockRef, eth.PayloadID, bool) {
 	return e.buildingOnto, e.buildingInfo.ID, e.buildingSafe
 }
@@ -140,6 +162,19 @@ func (e *EngineController) SetUnsafeHead(r eth.L2BlockRef) {
 	e.needFCUCall = true
 }
 
+// SetCrossUnsafeHead implements LocalEngineControl.
+func (e *EngineController) SetCrossUnsafeHead(r eth.L2BlockRef) {
+	e.metrics.RecordL2Ref("l2_cross_unsafe", r)
+	e.crossUnsafeHead = r
+}
+
+// SetCrossSafeHead implements LocalEngineControl.
+func (e *EngineController) SetCrossSafeHead(r eth.L2BlockRef) {
+	e.metrics.RecordL2Ref("l2_cross_safe", r)
+	e.crossSafeHead = r
+	e.needFCUCall = true
+}
+
 // Engine Methods
 
 func (e *EngineController) StartPayload(ctx context.Context, parent eth.L2BlockRef, attrs *AttributesWithParent, updateSafe bool) (errType BlockInsertionErrType, err error) {
@@ -152,7 +187,7 @@ func (e *EngineController) StartPayload(ctx context.Context, parent eth.L2BlockR
 	}
 	fc := eth.ForkchoiceState{
 		HeadBlockHash:      parent.Hash,
-		SafeBlockHash:      e.safeHead.Hash,
+		SafeBlockHash:      e.CrossSafeL2Head().Hash,
 		FinalizedBlockHash: e.finalizedHead.Hash,
 	}
 
@@ -180,12 +215,14 @@ func (e *EngineController) ConfirmPayload(ctx context.Context, agossip async.Asy
 	}
 	fc := eth.ForkchoiceState{
 		HeadBlockHash:      common.Hash{}, // gets overridden
-		SafeBlockHash:      e.safeHead.Hash,
+		SafeBlockHash:      e.CrossSafeL2Head().Hash,
 		FinalizedBlockHash: e.finalizedHead.Hash,
 	}
 	// Update the safe head if the payload is built with the last attributes in the batch.
 	updateSafe := e.buildingSafe && e.safeAttrs != nil && e.safeAttrs.isLastInSpan
-	envelope, errTyp, err := confirmPayload(ctx, e.log, e.engine, fc, e.buildingInfo, updateSafe, agossip, sequencerConductor)
+	// After the interop upgrade is scheduled, the safe block of the engine only moves with the cross-safe head.
+	updateEngineSafe := updateSafe && e.rollupCfg.InteropTime == nil
+	envelope, errTyp, err := confirmPayload(ctx, e.log, e.engine, fc, e.buildingInfo, updateEngineSafe, agossip, sequencerConductor)
 	if err != nil {
 		return nil, errTyp, fmt.Errorf("failed to complete building on top of L2 chain %s, id: %s, error (%d): %w", e.buildingOnto, e.buildingInfo.ID, errTyp, err)
 	}
@@ -273,7 +310,7 @@ func (e *EngineController) TryUpdateEngine(ctx context.Context) error {
 	}
 	fc := eth.ForkchoiceState{
 		HeadBlockHash:      e.unsafeHead.Hash,
-		SafeBlockHash:      e.safeHead.Hash,
+		SafeBlockHash:      e.CrossSafeL2Head().Hash,
 		FinalizedBlockHash: e.finalizedHead.Hash,
 	}
 	_, err := e.engine.ForkchoiceUpdate(ctx, &fc, nil)
@@ -324,7 +361,7 @@ func (e *EngineController) InsertUnsafePayload(ctx context.Context, envelope *et
 	// Mark the new payload as valid
 	fc := eth.ForkchoiceState{
 		HeadBlockHash:      envelope.ExecutionPayload.BlockHash,
-		SafeBlockHash:      e.safeHead.Hash,
+		SafeBlockHash:      e.CrossSafeL2Head().Hash,
 		FinalizedBlockHash: e.finalizedHead.Hash,
 	}
 	if e.syncStatus == syncStatusFinishedELButNotFinalized {
@@ -332,6 +369,10 @@ func (e *EngineController) InsertUnsafePayload(ctx context.Context, envelope *et
 		fc.FinalizedBlockHash = envelope.ExecutionPayload.BlockHash
 		e.SetSafeHead(ref)
 		e.SetFinalizedHead(ref)
+		if e.rollupCfg.InteropTime != nil {
+			e.SetCrossUnsafeHead(ref)
+			e.SetCrossSafeHead(ref)
+		}
 	}
 	fcRes, err := e.engine.ForkchoiceUpdate(ctx, &fc, nil)
 	if err != nil {
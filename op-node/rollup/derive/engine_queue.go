@@ -90,6 +90,11 @@ type LocalEngineControl interface {
 	SetSafeHead(eth.L2BlockRef)
 	SetFinalizedHead(eth.L2BlockRef)
 	SetPendingSafeL2Head(eth.L2BlockRef)
+
+	CrossUnsafeL2Head() eth.L2BlockRef
+	CrossSafeL2Head() eth.L2BlockRef
+	SetCrossUnsafeHead(eth.L2BlockRef)
+	SetCrossSafeHead(eth.L2BlockRef)
 }
 
 // Max memory used for buffering unsafe payloads
@@ -122,6 +127,20 @@ type FinalityData struct {
 	L1Block eth.BlockID
 }
 
+// SafeHeadListener is notified of the L1 block each L2 safe head was derived from.
+type SafeHeadListener interface {
+	// Enabled reports if the listener is interested in notifications.
+	Enabled() bool
+
+	// SafeHeadUpdated indicates that the safe head has been updated in response to processing batch data.
+	// The l1Block specified is the latest L1 block containing all batch data required to reproduce the safe head.
+	SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error
+
+	// SafeHeadReset indicates that the derivation pipeline reset back to the specified safe head.
+	// The L1 block that made the new safe head safe is unknown.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
 // EngineQueue queues up payload attributes to consolidate or process with the provided Engine
 type EngineQueue struct {
 	log log.Logger
@@ -143,6 +162,9 @@ type EngineQueue struct {
 	// Tracks which L2 blocks where last derived from which L1 block. At most finalityLookback large.
 	finalityData []FinalityData
 
+	safeHeadNotifs       SafeHeadListener // notified when the safe head is updated
+	lastNotifiedSafeHead eth.L2BlockRef
+
 	engine L2Source
 	prev   NextAttributesProvider
 
@@ -156,7 +178,7 @@ type EngineQueue struct {
 }
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, l2Source L2Source, engine LocalEngineControl, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config) *EngineQueue {
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, l2Source L2Source, engine LocalEngineControl, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config, safeHeadListener SafeHeadListener) *EngineQueue {
 	return &EngineQueue{
 		log:            log,
 		cfg:            cfg,
@@ -168,6 +190,7 @@ func NewEngineQueue(log log.Logger, cfg *rollup.Config, l2Source L2Source, engin
 		prev:           prev,
 		l1Fetcher:      l1Fetcher,
 		syncCfg:        syncCfg,
+		safeHeadNotifs: safeHeadListener,
 	}
 }
 
@@ -271,7 +294,10 @@ func (eq *EngineQueue) Step(ctx context.Context) error {
 		return err
 	}
 	eq.origin = newOrigin
-	eq.postProcessSafeL2() // make sure we track the last L2 safe head for every new L1 block
+	// make sure we track the last L2 safe head for every new L1 block
+	if err := eq.postProcessSafeL2(); err != nil {
+		return err
+	}
 	// try to finalize the L2 blocks we have synced so far (no-op if L1 finality is behind)
 	if err := eq.tryFinalizePastL2Blocks(ctx); err != nil {
 		return err
@@ -361,6 +387,7 @@ func (eq *EngineQueue) tryFinalizePastL2Blocks(ctx context.Context) error {
 // tryFinalizeL2 traverses the past L1 blocks, checks if any has been finalized,
 // and then marks the latest fully derived L2 block from this as finalized,
 // or defaults to the current finalized L2 block.
+// L2 blocks are not finalized before all their cross-chain dependencies are verified to be safe.
 func (eq *EngineQueue) tryFinalizeL2() {
 	if eq.finalizedL1 == (eth.L1BlockRef{}) {
 		return // if no L1 information is finalized yet, then skip this
@@ -368,9 +395,10 @@ func (eq *EngineQueue) tryFinalizeL2() {
 	eq.triedFinalizeAt = eq.origin
 	// default to keep the same finalized block
 	finalizedL2 := eq.ec.Finalized()
+	crossSafe := eq.ec.CrossSafeL2Head()
 	// go through the latest inclusion data, and find the last L2 block that was derived from a finalized L1 block
 	for _, fd := range eq.finalityData {
-		if fd.L2Block.Number > finalizedL2.Number && fd.L1Block.Number <= eq.finalizedL1.Number {
+		if fd.L2Block.Number > finalizedL2.Number && fd.L1Block.Number <= eq.finalizedL1.Number && fd.L2Block.Number <= crossSafe.Number {
 			finalizedL2 = fd.L2Block
 		}
 	}
@@ -379,7 +407,8 @@ func (eq *EngineQueue) tryFinalizeL2() {
 
 // postProcessSafeL2 buffers the L1 block the safe head was fully derived from,
 // to finalize it once the L1 block, or later, finalizes.
-func (eq *EngineQueue) postProcessSafeL2() {
+// The safe head listener is notified if the safe head changed since the last notification.
+func (eq *EngineQueue) postProcessSafeL2() error {
 	// prune finality data if necessary
 	if len(eq.finalityData) >= finalityLookback {
 		eq.finalityData = append(eq.finalityData[:0], eq.finalityData[1:finalityLookback]...)
@@ -401,6 +430,14 @@ func (eq *EngineQueue) postProcessSafeL2() {
 			eq.log.Debug("updated finality-data", "last_l1", last.L1Block, "last_l2", last.L2Block)
 		}
 	}
+	if safeHead := eq.ec.SafeL2Head(); eq.safeHeadNotifs.Enabled() && safeHead != eq.lastNotifiedSafeHead {
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(safeHead, eq.origin.ID()); err != nil {
+			// the safe head stays updated, the notification is retried when the safe head is processed again
+			return NewTemporaryError(fmt.Errorf("failed to notify safe-head listener: %w", err))
+		}
+		eq.lastNotifiedSafeHead = safeHead
+	}
+	return nil
 }
 
 func (eq *EngineQueue) logSyncProgress(reason string) {
@@ -518,14 +555,16 @@ func (eq *EngineQueue) consolidateNextSafeAttributes(ctx context.Context) error
 		return NewResetError(fmt.Errorf("failed to decode L2 block ref from payload: %w", err))
 	}
 	eq.ec.SetPendingSafeL2Head(ref)
-	if eq.safeAttributes.isLastInSpan {
+	lastInSpan := eq.safeAttributes.isLastInSpan
+	if lastInSpan {
 		eq.ec.SetSafeHead(ref)
-		eq.postProcessSafeL2()
 	}
 	// unsafe head stays the same, we did not reorg the chain.
 	eq.safeAttributes = nil
 	eq.logSyncProgress("reconciled with L1")
-
+	if lastInSpan {
+		return eq.postProcessSafeL2()
+	}
 	return nil
 }
 
@@ -581,7 +620,7 @@ func (eq *EngineQueue) forceNextSafeAttributes(ctx context.Context) error {
 	eq.safeAttributes = nil
 	eq.logSyncProgress("processed safe block derived from L1")
 	if lastInSpan {
-		eq.postProcessSafeL2()
+		return eq.postProcessSafeL2()
 	}
 
 	return nil
@@ -621,11 +660,13 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	}
 
 	// Walk back L2 chain to find the L1 origin that is old enough to start buffering channel data from.
+	// With alt-DA, batcher data is only passed on once the availability of the alt-DA inputs posted before it
+	// is decided, so the alt-DA commitments are tracked from the alt-DA window before that.
 	pipelineL2 := safe
 	for {
 		afterL2Genesis := pipelineL2.Number > eq.cfg.Genesis.L2.Number
 		afterL1Genesis := pipelineL2.L1Origin.Number > eq.cfg.Genesis.L1.Number
-		afterChannelTimeout := pipelineL2.L1Origin.Number+eq.cfg.ChannelTimeout > l1Origin.Number
+		afterChannelTimeout := pipelineL2.L1Origin.Number+eq.cfg.ChannelTimeout+eq.cfg.AltDAWindow() > l1Origin.Number
 		if afterL2Genesis && afterL1Genesis && afterChannelTimeout {
 			parent, err := eq.engine.L2BlockRefByHash(ctx, pipelineL2.ParentHash)
 			if err != nil {
@@ -644,11 +685,24 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	if err != nil {
 		return NewTemporaryError(fmt.Errorf("failed to fetch L1 config of L2 block %s: %w", pipelineL2.ID(), err))
 	}
+	if eq.safeHeadNotifs.Enabled() {
+		if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+			return NewTemporaryError(fmt.Errorf("failed to reset safe-head listener to %s: %w", safe, err))
+		}
+	}
+	eq.lastNotifiedSafeHead = safe
 	eq.log.Debug("Reset engine queue", "safeHead", safe, "unsafe", unsafe, "safe_timestamp", safe.Time, "unsafe_timestamp", unsafe.Time, "l1Origin", l1Origin)
 	eq.ec.SetUnsafeHead(unsafe)
 	eq.ec.SetSafeHead(safe)
 	eq.ec.SetPendingSafeL2Head(safe)
 	eq.ec.SetFinalizedHead(finalized)
+	// the engine only knows the cross-safe head, so the cross-chain dependencies of later blocks are verified again
+	eq.ec.SetCrossSafeHead(safe)
+	if eq.cfg.IsInterop(unsafe.Time) {
+		eq.ec.SetCrossUnsafeHead(safe)
+	} else {
+		eq.ec.SetCrossUnsafeHead(unsafe)
+	}
 	eq.safeAttributes = nil
 	eq.ec.ResetBuildingState()
 	eq.finalityData = eq.finalityData[:0]
@@ -2,6 +2,7 @@ package derive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -15,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/async"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/conductor"
@@ -251,7 +253,7 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	prev := &fakeAttributesQueue{}
 
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -262,13 +264,13 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	eq.origin = refD
 	prev.origin = refD
 	eq.ec.SetSafeHead(refC1)
-	eq.postProcessSafeL2()
+	require.NoError(t, eq.postProcessSafeL2())
 
 	// now say D0 was included in E and became the new safe head
 	eq.origin = refE
 	prev.origin = refE
 	eq.ec.SetSafeHead(refD0)
-	eq.postProcessSafeL2()
+	require.NoError(t, eq.postProcessSafeL2())
 
 	// let's finalize D (current L1), from which we fully derived C1 (it was safe head), but not D0 (included in E)
 	eq.Finalize(refD)
@@ -279,6 +281,89 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	eng.AssertExpectations(t)
 }
 
+type safeHeadUpdate struct {
+	safeHead eth.L2BlockRef
+	l1Block  eth.BlockID
+}
+
+type recordingSafeHeadListener struct {
+	updates []safeHeadUpdate
+	err     error
+}
+
+func (r *recordingSafeHeadListener) Enabled() bool {
+	return true
+}
+
+func (r *recordingSafeHeadListener) SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.updates = append(r.updates, safeHeadUpdate{newSafeHead, l1Block})
+	return nil
+}
+
+func (r *recordingSafeHeadListener) SafeHeadReset(_ eth.L2BlockRef) error {
+	return r.err
+}
+
+func TestEngineQueue_NotifySafeHeadListener(t *testing.T) {
+	logger := testlog.Logger(t, log.LevelInfo)
+	rng := rand.New(rand.NewSource(1234))
+	refA := testutils.RandomBlockRef(rng)
+	refB := testutils.NextRandomRef(rng, refA)
+	refA0 := testutils.RandomL2BlockRef(rng)
+	refB0 := testutils.NextRandomL2Ref(rng, 2, refA0, refB.ID())
+
+	listener := &recordingSafeHeadListener{}
+	ec := NewEngineController(&testutils.MockEngine{}, logger, metrics.NoopMetrics, &rollup.Config{}, sync.CLSync)
+	eq := NewEngineQueue(logger, &rollup.Config{}, nil, ec, metrics.NoopMetrics, &fakeAttributesQueue{}, nil, &sync.Config{}, listener)
+
+	eq.origin = refA
+	ec.SetSafeHead(refA0)
+	require.NoError(t, eq.postProcessSafeL2())
+	// no notification if the safe head did not change, also not when moving to the next L1 block
+	require.NoError(t, eq.postProcessSafeL2())
+	eq.origin = refB
+	require.NoError(t, eq.postProcessSafeL2())
+	require.Equal(t, []safeHeadUpdate{{refA0, refA.ID()}}, listener.updates)
+
+	// failed notifications are retried
+	listener.err = errors.New("boom")
+	ec.SetSafeHead(refB0)
+	require.ErrorIs(t, eq.postProcessSafeL2(), ErrTemporary)
+	listener.err = nil
+	require.NoError(t, eq.postProcessSafeL2())
+	require.Equal(t, []safeHeadUpdate{{refA0, refA.ID()}, {refB0, refB.ID()}}, listener.updates)
+}
+
+func TestEngineQueue_FinalizeCrossSafe(t *testing.T) {
+	logger := testlog.Logger(t, log.LevelInfo)
+	rng := rand.New(rand.NewSource(1234))
+	refA := testutils.RandomBlockRef(rng)
+	refB := testutils.NextRandomRef(rng, refA)
+	refA0 := testutils.RandomL2BlockRef(rng)
+	refB0 := testutils.NextRandomL2Ref(rng, 2, refA0, refB.ID())
+
+	interopTime := uint64(0)
+	cfg := &rollup.Config{InteropTime: &interopTime}
+	ec := NewEngineController(&testutils.MockEngine{}, logger, metrics.NoopMetrics, cfg, sync.CLSync)
+	eq := NewEngineQueue(logger, cfg, nil, ec, metrics.NoopMetrics, &fakeAttributesQueue{}, nil, &sync.Config{}, safedb.Disabled)
+
+	ec.SetSafeHead(refB0)
+	ec.SetCrossSafeHead(refA0)
+	eq.finalityData = []FinalityData{{L2Block: refA0, L1Block: refA.ID()}, {L2Block: refB0, L1Block: refB.ID()}}
+	eq.finalizedL1 = refB
+
+	// the L2 block derived from the finalized L1 block is not finalized before it is cross-safe
+	eq.tryFinalizeL2()
+	require.Equal(t, refA0, ec.Finalized())
+
+	ec.SetCrossSafeHead(refB0)
+	eq.tryFinalizeL2()
+	require.Equal(t, refB0, ec.Finalized())
+}
+
 func TestEngineQueue_ResetWhenUnsafeOriginNotCanonical(t *testing.T) {
 	logger := testlog.Logger(t, log.LevelInfo)
 
@@ -487,7 +572,7 @@ func TestEngineQueue_ResetWhenUnsafeOriginNotCanonical(t *testing.T) {
 	prev := &fakeAttributesQueue{origin: refE}
 
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -817,7 +902,7 @@ func TestVerifyNewL1Origin(t *testing.T) {
 
 			prev := &fakeAttributesQueue{origin: refE}
 			ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-			eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+			eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 			require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 			require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -914,7 +999,7 @@ func TestBlockBuildingRace(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs, islastInSpan: true}
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	id := eth.PayloadID{0xff}
@@ -1086,7 +1171,7 @@ func TestResetLoop(t *testing.T) {
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs, islastInSpan: true}
 
 	ec := NewEngineController(eng, logger, metrics.NoopMetrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	eq.ec.SetUnsafeHead(refA2)
 	eq.ec.SetSafeHead(refA1)
 	eq.ec.SetFinalizedHead(refA0)
@@ -1192,7 +1277,7 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 	prev := &fakeAttributesQueue{origin: refA}
 
 	ec := NewEngineController(eng, logger, metrics.NoopMetrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	eq.ec.SetUnsafeHead(refA2)
 	eq.ec.SetSafeHead(refA0)
 	eq.ec.SetFinalizedHead(refA0)
@@ -33,6 +33,20 @@ func (fq *FrameQueue) Origin() eth.L1BlockRef {
 	return fq.prev.Origin()
 }
 
+// Inspect returns the current state of the stage.
+func (fq *FrameQueue) Inspect() eth.FrameQueueState {
+	frames := make([]eth.FrameState, 0, len(fq.frames))
+	for _, f := range fq.frames {
+		frames = append(frames, eth.FrameState{
+			ChannelID:   f.ID.String(),
+			FrameNumber: f.FrameNumber,
+			DataLength:  len(f.Data),
+			IsLast:      f.IsLast,
+		})
+	}
+	return eth.FrameQueueState{Frames: frames}
+}
+
 func (fq *FrameQueue) NextFrame(ctx context.Context) (Frame, error) {
 	// Find more frames if we need to
 	if len(fq.frames) == 0 {
@@ -45,6 +45,11 @@ func (l1t *L1Traversal) Origin() eth.L1BlockRef {
 	return l1t.block
 }
 
+// Inspect returns the current state of the stage.
+func (l1t *L1Traversal) Inspect() eth.L1TraversalState {
+	return eth.L1TraversalState{Origin: l1t.block, Done: l1t.done}
+}
+
 // NextL1Block returns the next block. It does not advance, but it can only be
 // called once before returning io.EOF
 func (l1t *L1Traversal) NextL1Block(_ context.Context) (eth.L1BlockRef, error) {
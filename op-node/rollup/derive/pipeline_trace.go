@@ -0,0 +1,145 @@
+package derive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Names of the stages in a pipeline trace
+const (
+	TraceStageResetting   = "resetting"
+	TraceStageL1Traversal = "l1_traversal"
+	TraceStageFrameQueue  = "frame_queue"
+	TraceStageChannelBank = "channel_bank"
+	TraceStageBatchQueue  = "batch_queue"
+)
+
+// PipelineTraceEntry is a line of a pipeline trace. An entry records either the new state of a stage,
+// or the error of a step.
+type PipelineTraceEntry struct {
+	// Step is the number of the pipeline step after which the entry was recorded, starting at 1.
+	Step  uint64          `json:"step"`
+	Stage string          `json:"stage,omitempty"`
+	State json.RawMessage `json:"state,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// PipelineTracer records the transitions of the stages of the derivation pipeline as JSON lines:
+// after every step, an entry is written for each stage of which the state changed.
+// PipelineTracer is not safe for concurrent use.
+type PipelineTracer struct {
+	out  io.WriteCloser
+	w    *bufio.Writer
+	enc  *json.Encoder
+	step uint64
+	// last is the last recorded state of every stage, encoded as JSON
+	last map[string][]byte
+}
+
+func NewPipelineTracer(out io.WriteCloser) *PipelineTracer {
+	w := bufio.NewWriter(out)
+	return &PipelineTracer{
+		out:  out,
+		w:    w,
+		enc:  json.NewEncoder(w),
+		last: make(map[string][]byte),
+	}
+}
+
+// OnStep records the state of the pipeline after a step, and the error of the step, if any.
+func (t *PipelineTracer) OnStep(state *eth.DerivationPipelineState, stepErr error) error {
+	t.step++
+	stages := []struct {
+		name  string
+		state any
+	}{
+		{TraceStageResetting, state.Resetting},
+		{TraceStageL1Traversal, state.L1Traversal},
+		{TraceStageFrameQueue, state.FrameQueue},
+		{TraceStageChannelBank, state.ChannelBank},
+		{TraceStageBatchQueue, state.BatchQueue},
+	}
+	for _, stage := range stages {
+		data, err := json.Marshal(stage.state)
+		if err != nil {
+			return fmt.Errorf("failed to encode state of stage %s: %w", stage.name, err)
+		}
+		if last, ok := t.last[stage.name]; ok && bytes.Equal(last, data) {
+			continue
+		}
+		t.last[stage.name] = data
+		if err := t.enc.Encode(PipelineTraceEntry{Step: t.step, Stage: stage.name, State: data}); err != nil {
+			return fmt.Errorf("failed to write trace entry: %w", err)
+		}
+	}
+	if stepErr != nil && !errors.Is(stepErr, io.EOF) {
+		if err := t.enc.Encode(PipelineTraceEntry{Step: t.step, Error: stepErr.Error()}); err != nil {
+			return fmt.Errorf("failed to write trace entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close flushes the trace, and closes the output.
+func (t *PipelineTracer) Close() error {
+	if err := t.w.Flush(); err != nil {
+		_ = t.out.Close()
+		return fmt.Errorf("failed to flush trace: %w", err)
+	}
+	return t.out.Close()
+}
+
+// ReplayPipelineTrace reads a trace recorded by a PipelineTracer, and reconstructs the state of the pipeline after the given step.
+// The errors of the steps up to and including the given step are returned as well.
+func ReplayPipelineTrace(r io.Reader, step uint64) (*eth.DerivationPipelineState, []PipelineTraceEntry, error) {
+	var state eth.DerivationPipelineState
+	var stepErrs []PipelineTraceEntry
+	dec := json.NewDecoder(r)
+	for {
+		var entry PipelineTraceEntry
+		if err := dec.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("failed to read trace entry: %w", err)
+		}
+		if entry.Step > step {
+			break
+		}
+		if entry.Error != "" {
+			stepErrs = append(stepErrs, entry)
+			continue
+		}
+		// decode into a new value, to not merge the state into the previous state of the stage
+		var err error
+		switch entry.Stage {
+		case TraceStageResetting:
+			err = json.Unmarshal(entry.State, &state.Resetting)
+		case TraceStageL1Traversal:
+			state.L1Traversal, err = decodeStageState[eth.L1TraversalState](entry.State)
+		case TraceStageFrameQueue:
+			state.FrameQueue, err = decodeStageState[eth.FrameQueueState](entry.State)
+		case TraceStageChannelBank:
+			state.ChannelBank, err = decodeStageState[eth.ChannelBankState](entry.State)
+		case TraceStageBatchQueue:
+			state.BatchQueue, err = decodeStageState[eth.BatchQueueState](entry.State)
+		default:
+			return nil, nil, fmt.Errorf("unknown stage %q in trace entry of step %d", entry.Stage, entry.Step)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode state of stage %s at step %d: %w", entry.Stage, entry.Step, err)
+		}
+	}
+	return &state, stepErrs, nil
+}
+
+func decodeStageState[T any](data json.RawMessage) (T, error) {
+	var state T
+	err := json.Unmarshal(data, &state)
+	return state, err
+}
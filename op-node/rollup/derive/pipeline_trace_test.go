@@ -0,0 +1,78 @@
+package derive
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestPipelineTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	tracer := NewPipelineTracer(f)
+
+	state1 := &eth.DerivationPipelineState{
+		Resetting:   true,
+		L1Traversal: eth.L1TraversalState{Origin: eth.L1BlockRef{Number: 1}},
+		FrameQueue:  eth.FrameQueueState{Frames: []eth.FrameState{{ChannelID: "aa", DataLength: 10}}},
+	}
+	state2 := &eth.DerivationPipelineState{
+		L1Traversal: eth.L1TraversalState{Origin: eth.L1BlockRef{Number: 1}},
+		ChannelBank: eth.ChannelBankState{Channels: []eth.ChannelState{{ID: "aa", Size: 1000}}},
+	}
+	state3 := &eth.DerivationPipelineState{
+		L1Traversal: eth.L1TraversalState{Origin: eth.L1BlockRef{Number: 1}, Done: true},
+		ChannelBank: eth.ChannelBankState{Channels: []eth.ChannelState{{ID: "aa", Size: 1000}}},
+		BatchQueue:  eth.BatchQueueState{Batches: []eth.BatchState{{Type: "singular", Validity: "future"}}},
+	}
+	require.NoError(t, tracer.OnStep(state1, nil))
+	require.NoError(t, tracer.OnStep(state2, NewTemporaryError(errors.New("boom"))))
+	require.NoError(t, tracer.OnStep(state2, io.EOF))
+	require.NoError(t, tracer.OnStep(state3, nil))
+	require.NoError(t, tracer.Close())
+
+	t.Run("only changes are recorded", func(t *testing.T) {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		lines := 0
+		for scanner := bufio.NewScanner(f); scanner.Scan(); {
+			lines++
+		}
+		// all 5 stages at step 1, 3 stages and an error at step 2, 2 stages at step 4
+		require.Equal(t, 11, lines)
+	})
+
+	replay := func(t *testing.T, step uint64) (*eth.DerivationPipelineState, []PipelineTraceEntry) {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		state, stepErrs, err := ReplayPipelineTrace(f, step)
+		require.NoError(t, err)
+		return state, stepErrs
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		state, stepErrs := replay(t, 1)
+		require.Equal(t, state1, state)
+		require.Empty(t, stepErrs)
+
+		state, stepErrs = replay(t, 3)
+		require.Equal(t, state2, state)
+		require.Len(t, stepErrs, 1)
+		require.Equal(t, uint64(2), stepErrs[0].Step)
+		require.Contains(t, stepErrs[0].Error, "boom")
+
+		state, _ = replay(t, math.MaxUint64)
+		require.Equal(t, state3, state)
+	})
+}
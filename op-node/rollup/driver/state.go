@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/rollup/async"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
@@ -44,6 +45,13 @@ type Driver struct {
 	// We will also use it for EL sync in a future PR.
 	engineController *derive.EngineController
 
+	// The pipeline tracer records the state of the derivation pipeline after every step, nil if tracing is disabled.
+	pipelineTracer PipelineTracer
+
+	// The cross validator advances the cross-unsafe and cross-safe heads,
+	// nil if the interop upgrade is not scheduled.
+	crossValidator *interop.CrossValidator
+
 	// Requests to block the event loop for synchronous execution to avoid reading an inconsistent state
 	stateReq chan chan struct{}
 
@@ -268,6 +276,31 @@ func (s *Driver) eventLoop() {
 	defer altSyncTicker.Stop()
 	lastUnsafeL2 := s.engineController.UnsafeL2Head()
 
+	// Create a ticker to verify the cross-chain dependencies of new blocks, if the interop upgrade is scheduled.
+	// The blocks are verified in the background, as that takes RPCs to the chains of the dependency set,
+	// and the results are applied to the cross heads by the event loop.
+	var crossCheckCh <-chan time.Time
+	crossResults := make(chan crossResult, 1)
+	crossVerifying := false
+	verifyCross := func() {
+		if crossVerifying {
+			return
+		}
+		crossVerifying = true
+		prev := s.crossValidator.Snapshot()
+		go func() {
+			ctx, cancel := context.WithTimeout(s.driverCtx, time.Second*10)
+			defer cancel()
+			next, err := s.crossValidator.Verify(ctx, prev)
+			crossResults <- crossResult{prev: prev, next: next, err: err}
+		}()
+	}
+	if s.crossValidator != nil {
+		crossCheckTicker := time.NewTicker(time.Duration(s.config.BlockTime) * time.Second)
+		defer crossCheckTicker.Stop()
+		crossCheckCh = crossCheckTicker.C
+	}
+
 	for {
 		if s.driverCtx.Err() != nil { // don't try to schedule/handle more work when we are closing.
 			return
@@ -327,6 +360,20 @@ func (s *Driver) eventLoop() {
 			if err != nil {
 				s.log.Warn("failed to check for unsafe L2 blocks to sync", "err", err)
 			}
+		case <-crossCheckCh:
+			verifyCross()
+		case res := <-crossResults:
+			crossVerifying = false
+			var invalidErr *interop.InvalidBlockError
+			if errors.As(res.err, &invalidErr) {
+				s.log.Error("Block executes an invalid message, cross heads can't advance past it", "block", invalidErr.Block, "err", res.err)
+			} else if res.err != nil {
+				s.log.Warn("failed to update cross heads", "err", res.err)
+			}
+			if s.crossValidator.Apply(res.prev, res.next) {
+				reqStep()     // the engine needs to be updated with the cross-safe head, and more L2 data may be finalized now
+				verifyCross() // more blocks may be ready to verify, as the number of blocks per update is bounded
+			}
 		case envelope := <-s.unsafeL2Payloads:
 			s.snapshot("New unsafe payload")
 			// If we are doing CL sync or done with engine syncing, fallback to the unsafe payload queue & CL P2P sync.
@@ -371,6 +418,11 @@ func (s *Driver) eventLoop() {
 			s.metrics.SetDerivationIdle(false)
 			s.log.Debug("Derivation process step", "onto_origin", s.derivation.Origin(), "attempts", stepAttempts)
 			err := s.derivation.Step(s.driverCtx)
+			if s.pipelineTracer != nil {
+				if traceErr := s.pipelineTracer.OnStep(s.derivation.Inspect(), err); traceErr != nil {
+					s.log.Warn("Failed to trace derivation pipeline", "err", traceErr)
+				}
+			}
 			stepAttempts += 1 // count as attempt by default. We reset to 0 if we are making healthy progress.
 			if err == io.EOF {
 				s.log.Debug("Derivation process went idle", "progress", s.derivation.Origin(), "err", err)
@@ -546,6 +598,8 @@ func (s *Driver) syncStatus() *eth.SyncStatus {
 		SafeL2:             s.engineController.SafeL2Head(),
 		FinalizedL2:        s.engineController.Finalized(),
 		PendingSafeL2:      s.engineController.PendingSafeL2Head(),
+		CrossUnsafeL2:      s.engineController.CrossUnsafeL2Head(),
+		CrossSafeL2:        s.engineController.CrossSafeL2Head(),
 	}
 }
 
@@ -579,6 +633,20 @@ func (s *Driver) BlockRefWithStatus(ctx context.Context, num uint64) (eth.L2Bloc
 	}
 }
 
+// DerivationPipelineState blocks the driver event loop and captures the state of the stages of the derivation pipeline.
+// If the event loop is too busy and the context expires, a context error is returned.
+func (s *Driver) DerivationPipelineState(ctx context.Context) (*eth.DerivationPipelineState, error) {
+	wait := make(chan struct{})
+	select {
+	case s.stateReq <- wait:
+		resp := s.derivation.Inspect()
+		<-wait
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // deferJSONString helps avoid a JSON-encoding performance hit if the snapshot logger does not run
 type deferJSONString struct {
 	x any
@@ -609,6 +677,13 @@ type hashAndErrorChannel struct {
 	err  chan error
 }
 
+// crossResult is the result of verifying the blocks after the cross heads of a snapshot of the heads.
+type crossResult struct {
+	prev interop.HeadsSnapshot
+	next interop.HeadsSnapshot
+	err  error
+}
+
 // checkForGapInUnsafeQueue checks if there is a gap in the unsafe queue and attempts to retrieve the missing payloads from an alt-sync method.
 // WARNING: This is only an outgoing signal, the blocks are not guaranteed to be retrieved.
 // Results are received through OnUnsafeL2Payload.
@@ -0,0 +1,249 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// maxBlocksPerUpdate bounds the number of blocks that are checked per update of the cross heads,
+// so an update completes and is applied quickly when catching up.
+const maxBlocksPerUpdate = 20
+
+var errCrossReorg = errors.New("cross head is not an ancestor of the next block")
+
+// InvalidBlockError is returned when a block executes an invalid message.
+// The block can't become cross-unsafe or cross-safe, so the cross heads don't advance past its parent.
+//
+// The block is checked again on every update. If the message was reported as invalid by a faulty or misconfigured
+// source of a chain in the dependency set, fixing the source is enough to recover. Otherwise the block has to be
+// reorged out of the chain: an unsafe block is replaced when the sequencer builds a different block at its height,
+// but a safe block is derived from the batches on L1, and the cross-safe head stays at its parent until the chain
+// operator recovers the chain.
+type InvalidBlockError struct {
+	Block eth.L2BlockRef
+	Err   error
+}
+
+func (e *InvalidBlockError) Error() string {
+	return fmt.Sprintf("block %s is invalid: %v", e.Block, e.Err)
+}
+
+func (e *InvalidBlockError) Unwrap() error {
+	return e.Err
+}
+
+// CrossHeads provides and updates the L2 heads, and the cross-unsafe and cross-safe heads that follow them.
+type CrossHeads interface {
+	UnsafeL2Head() eth.L2BlockRef
+	SafeL2Head() eth.L2BlockRef
+	Finalized() eth.L2BlockRef
+	CrossUnsafeL2Head() eth.L2BlockRef
+	CrossSafeL2Head() eth.L2BlockRef
+	SetCrossUnsafeHead(eth.L2BlockRef)
+	SetCrossSafeHead(eth.L2BlockRef)
+}
+
+type L2Source interface {
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+}
+
+// BlockChecker returns the safety level of the cross-chain dependencies of a block, see Verifier.CheckBlock.
+type BlockChecker interface {
+	CheckBlock(ctx context.Context, block eth.L2BlockRef) (SafetyLevel, error)
+}
+
+// CrossValidator advances the cross-unsafe and cross-safe heads towards the unsafe and safe heads,
+// as the cross-chain dependencies of the blocks in between are verified.
+type CrossValidator struct {
+	log     log.Logger
+	cfg     *rollup.Config
+	heads   CrossHeads
+	l2      L2Source
+	checker BlockChecker
+}
+
+func NewCrossValidator(log log.Logger, cfg *rollup.Config, heads CrossHeads, l2 L2Source, checker BlockChecker) *CrossValidator {
+	return &CrossValidator{
+		log:     log,
+		cfg:     cfg,
+		heads:   heads,
+		l2:      l2,
+		checker: checker,
+	}
+}
+
+// HeadsSnapshot is a copy of the L2 heads and the cross heads that follow them,
+// to verify the blocks after the cross heads outside of the driver event loop.
+type HeadsSnapshot struct {
+	Unsafe      eth.L2BlockRef
+	Safe        eth.L2BlockRef
+	Finalized   eth.L2BlockRef
+	CrossUnsafe eth.L2BlockRef
+	CrossSafe   eth.L2BlockRef
+}
+
+// Snapshot returns a copy of the current heads.
+func (c *CrossValidator) Snapshot() HeadsSnapshot {
+	return HeadsSnapshot{
+		Unsafe:      c.heads.UnsafeL2Head(),
+		Safe:        c.heads.SafeL2Head(),
+		Finalized:   c.heads.Finalized(),
+		CrossUnsafe: c.heads.CrossUnsafeL2Head(),
+		CrossSafe:   c.heads.CrossSafeL2Head(),
+	}
+}
+
+// UpdateCrossHeads moves the cross heads back if the heads they follow were reorged,
+// and then advances them as far as the cross-chain dependencies of the blocks allow.
+// It returns true if any of the cross heads changed.
+func (c *CrossValidator) UpdateCrossHeads(ctx context.Context) (changed bool, err error) {
+	prev := c.Snapshot()
+	next, err := c.Verify(ctx, prev)
+	return c.Apply(prev, next), err
+}
+
+// Verify returns the snapshot with the cross heads moved back if the heads they follow were reorged,
+// and then advanced as far as the cross-chain dependencies of the blocks allow.
+// The cross heads are returned with the progress made so far if an error occurs.
+// Verify doesn't access the current heads, so it can run concurrently with the driver, see Apply.
+func (c *CrossValidator) Verify(ctx context.Context, heads HeadsSnapshot) (HeadsSnapshot, error) {
+	unsafe, safe := heads.Unsafe, heads.Safe
+	crossUnsafe, crossSafe := heads.CrossUnsafe, heads.CrossSafe
+	result := func() HeadsSnapshot {
+		heads.CrossUnsafe, heads.CrossSafe = crossUnsafe, crossSafe
+		return heads
+	}
+
+	if crossSafe.Number > safe.Number {
+		crossSafe = safe
+	}
+	crossSafe, err := c.advance(ctx, crossSafe, safe, Safe)
+	if errors.Is(err, errCrossReorg) {
+		// The finalized head can't be reorged, so the blocks after it are verified again.
+		// The unsafe chain builds on the safe chain, so it was reorged too.
+		c.log.Warn("Safe chain was reorged, verifying cross-safe blocks again", "cross_safe", crossSafe, "finalized", heads.Finalized)
+		crossSafe = heads.Finalized
+		crossUnsafe = heads.Finalized
+		return result(), nil
+	}
+	if err != nil {
+		return result(), fmt.Errorf("failed to advance cross-safe head %s: %w", crossSafe, err)
+	}
+
+	if crossUnsafe.Number > unsafe.Number || crossUnsafe.Number < crossSafe.Number {
+		crossUnsafe = crossSafe
+	}
+	crossUnsafe, err = c.advance(ctx, crossUnsafe, unsafe, Unsafe)
+	if errors.Is(err, errCrossReorg) {
+		c.log.Warn("Unsafe chain was reorged, verifying cross-unsafe blocks again", "cross_unsafe", crossUnsafe, "cross_safe", crossSafe)
+		crossUnsafe = crossSafe
+		return result(), nil
+	}
+	if err != nil {
+		return result(), fmt.Errorf("failed to advance cross-unsafe head %s: %w", crossUnsafe, err)
+	}
+	return result(), nil
+}
+
+// Apply sets the cross heads to the ones that Verify returned for the prev snapshot.
+// The heads may have changed while the blocks were verified, so the verified blocks may not be canonical anymore.
+// The cross-safe head is only set if it is unchanged, and if the safe head is unchanged or extended by one block, or
+// the cross-safe head is moved back to the finalized head after a reorg. The same applies to the cross-unsafe head.
+// Skipped updates are verified again by the next update. Apply returns true if any of the cross heads changed.
+func (c *CrossValidator) Apply(prev HeadsSnapshot, next HeadsSnapshot) (changed bool) {
+	cur := c.Snapshot()
+	if next.CrossSafe != prev.CrossSafe && cur.CrossSafe == prev.CrossSafe &&
+		(extends(cur.Safe, prev.Safe) || next.CrossSafe == prev.Finalized) {
+		c.heads.SetCrossSafeHead(next.CrossSafe)
+		changed = true
+	}
+	if next.CrossUnsafe != prev.CrossUnsafe && cur.CrossUnsafe == prev.CrossUnsafe &&
+		(extends(cur.Unsafe, prev.Unsafe) || next.CrossUnsafe == prev.Finalized) {
+		c.heads.SetCrossUnsafeHead(next.CrossUnsafe)
+		changed = true
+	}
+	// the unsafe chain builds on the safe chain
+	if crossSafe := c.heads.CrossSafeL2Head(); c.heads.CrossUnsafeL2Head().Number < crossSafe.Number {
+		c.heads.SetCrossUnsafeHead(crossSafe)
+		changed = true
+	}
+	return changed
+}
+
+// extends returns true if the head is the previous head or a child of it.
+func extends(head eth.L2BlockRef, prev eth.L2BlockRef) bool {
+	return head == prev || head.ParentHash == prev.Hash
+}
+
+// advance walks the chain from the cross head up to the target, while the blocks meet the required safety level,
+// and returns the new cross head.
+func (c *CrossValidator) advance(ctx context.Context, cross eth.L2BlockRef, target eth.L2BlockRef, required SafetyLevel) (eth.L2BlockRef, error) {
+	if cross == target {
+		return cross, nil
+	}
+	// blocks before the interop upgrade have no cross-chain dependencies
+	if !c.cfg.IsInterop(target.Time) {
+		return target, nil
+	}
+	if !c.cfg.IsInterop(cross.Time + c.cfg.BlockTime) {
+		lastPreInterop, err := c.lastPreInteropBlock(ctx)
+		if err != nil {
+			return cross, err
+		}
+		if lastPreInterop.Number > cross.Number {
+			cross = lastPreInterop
+		}
+	}
+	if cross.Number == target.Number {
+		// the cross head is at the height of the target, but is not the target
+		return cross, fmt.Errorf("%w: %s", errCrossReorg, target)
+	}
+	for i := 0; cross.Number < target.Number && i < maxBlocksPerUpdate; i++ {
+		next := target
+		if cross.Number+1 < target.Number {
+			var err error
+			next, err = c.l2.L2BlockRefByNumber(ctx, cross.Number+1)
+			if err != nil {
+				return cross, fmt.Errorf("failed to fetch block %d: %w", cross.Number+1, err)
+			}
+		}
+		if next.ParentHash != cross.Hash {
+			return cross, fmt.Errorf("%w: %s", errCrossReorg, next)
+		}
+		lvl, err := c.checker.CheckBlock(ctx, next)
+		if errors.Is(err, ErrInvalidMessage) {
+			return cross, &InvalidBlockError{Block: next, Err: err}
+		}
+		if err != nil {
+			return cross, fmt.Errorf("failed to check block %s: %w", next, err)
+		}
+		if lvl < required {
+			c.log.Debug("Cross-chain dependencies not verified yet", "block", next, "level", lvl, "required", required)
+			return cross, nil
+		}
+		cross = next
+	}
+	return cross, nil
+}
+
+func (c *CrossValidator) lastPreInteropBlock(ctx context.Context) (eth.L2BlockRef, error) {
+	interopTime := *c.cfg.InteropTime
+	if interopTime <= c.cfg.Genesis.L2Time {
+		return c.l2.L2BlockRefByNumber(ctx, c.cfg.Genesis.L2.Number)
+	}
+	num, err := c.cfg.TargetBlockNumber(interopTime - 1)
+	if err != nil {
+		return eth.L2BlockRef{}, err
+	}
+	ref, err := c.l2.L2BlockRefByNumber(ctx, num)
+	if err != nil {
+		return eth.L2BlockRef{}, fmt.Errorf("failed to fetch last block before interop upgrade: %w", err)
+	}
+	return ref, nil
+}
@@ -0,0 +1,237 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type testHeads struct {
+	unsafe, safe, finalized, crossUnsafe, crossSafe eth.L2BlockRef
+}
+
+func (h *testHeads) UnsafeL2Head() eth.L2BlockRef        { return h.unsafe }
+func (h *testHeads) SafeL2Head() eth.L2BlockRef          { return h.safe }
+func (h *testHeads) Finalized() eth.L2BlockRef           { return h.finalized }
+func (h *testHeads) CrossUnsafeL2Head() eth.L2BlockRef   { return h.crossUnsafe }
+func (h *testHeads) CrossSafeL2Head() eth.L2BlockRef     { return h.crossSafe }
+func (h *testHeads) SetCrossUnsafeHead(r eth.L2BlockRef) { h.crossUnsafe = r }
+func (h *testHeads) SetCrossSafeHead(r eth.L2BlockRef)   { h.crossSafe = r }
+
+type testL2Chain []eth.L2BlockRef
+
+func (c testL2Chain) L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error) {
+	if num >= uint64(len(c)) {
+		return eth.L2BlockRef{}, ethereum.NotFound
+	}
+	return c[num], nil
+}
+
+type testChecker struct {
+	levels map[uint64]SafetyLevel
+	errs   map[uint64]error
+	checks int
+}
+
+func (c *testChecker) CheckBlock(ctx context.Context, block eth.L2BlockRef) (SafetyLevel, error) {
+	c.checks++
+	if err, ok := c.errs[block.Number]; ok {
+		return Unverified, err
+	}
+	if lvl, ok := c.levels[block.Number]; ok {
+		return lvl, nil
+	}
+	return Safe, nil
+}
+
+func newTestL2Chain(count uint64, fork byte) testL2Chain {
+	chain := make(testL2Chain, count)
+	for i := range chain {
+		chain[i] = eth.L2BlockRef{Hash: common.Hash{fork, byte(i)}, Number: uint64(i), Time: uint64(i) * 2}
+		if i > 0 {
+			chain[i].ParentHash = chain[i-1].Hash
+		}
+	}
+	return chain
+}
+
+func TestCrossValidator(t *testing.T) {
+	ctx := context.Background()
+	interopTime := uint64(20) // block 10 is the first interop block
+	cfg := &rollup.Config{BlockTime: 2, InteropTime: &interopTime}
+	chain := newTestL2Chain(30, 0xaa)
+	setup := func(levels map[uint64]SafetyLevel) (*CrossValidator, *testHeads, *testChecker) {
+		heads := &testHeads{unsafe: chain[25], safe: chain[18], finalized: chain[0], crossUnsafe: chain[0], crossSafe: chain[0]}
+		checker := &testChecker{levels: levels}
+		return NewCrossValidator(testlog.Logger(t, log.LevelError), cfg, heads, chain, checker), heads, checker
+	}
+
+	t.Run("pre-interop", func(t *testing.T) {
+		v, heads, checker := setup(nil)
+		heads.unsafe = chain[8]
+		heads.safe = chain[5]
+		changed, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, chain[8], heads.crossUnsafe)
+		require.Equal(t, chain[5], heads.crossSafe)
+		require.Zero(t, checker.checks)
+	})
+
+	t.Run("all verified", func(t *testing.T) {
+		v, heads, checker := setup(nil)
+		changed, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, chain[25], heads.crossUnsafe)
+		require.Equal(t, chain[18], heads.crossSafe)
+		// only interop blocks are checked
+		require.Equal(t, 16, checker.checks)
+
+		changed, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.False(t, changed)
+	})
+
+	t.Run("pending dependencies", func(t *testing.T) {
+		v, heads, _ := setup(map[uint64]SafetyLevel{13: Unsafe, 21: Unverified})
+		_, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, chain[20], heads.crossUnsafe)
+		require.Equal(t, chain[12], heads.crossSafe)
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		v, heads, checker := setup(map[uint64]SafetyLevel{13: Unsafe})
+		checker.errs = map[uint64]error{15: fmt.Errorf("%w: log not found", ErrInvalidMessage)}
+		changed, err := v.UpdateCrossHeads(ctx)
+		var invalidErr *InvalidBlockError
+		require.ErrorAs(t, err, &invalidErr)
+		require.ErrorIs(t, err, ErrInvalidMessage)
+		require.Equal(t, chain[15], invalidErr.Block)
+		require.True(t, changed)
+		require.Equal(t, chain[12], heads.crossSafe)
+		require.Equal(t, chain[14], heads.crossUnsafe)
+	})
+
+	t.Run("heads changed during verification", func(t *testing.T) {
+		v, heads, _ := setup(nil)
+		prev := v.Snapshot()
+		next, err := v.Verify(ctx, prev)
+		require.NoError(t, err)
+
+		// the safe head is extended by a block, and the unsafe chain is reorged
+		heads.safe = chain[19]
+		heads.unsafe = newTestL2Chain(30, 0xbb)[25]
+		require.True(t, v.Apply(prev, next))
+		require.Equal(t, chain[18], heads.crossSafe)
+		require.Equal(t, chain[18], heads.crossUnsafe, "cross-unsafe head follows the cross-safe head")
+
+		// the cross heads were reset by the derivation pipeline
+		prev = v.Snapshot()
+		heads.safe = chain[22]
+		heads.unsafe = chain[25]
+		next, err = v.Verify(ctx, prev)
+		require.NoError(t, err)
+		heads.crossSafe = chain[5]
+		heads.crossUnsafe = chain[5]
+		require.False(t, v.Apply(prev, next))
+		require.Equal(t, chain[5], heads.crossSafe)
+		require.Equal(t, chain[5], heads.crossUnsafe)
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		v, heads, _ := setup(nil)
+		_, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+
+		// the unsafe chain after block 20 is replaced
+		reorged := append(testL2Chain{}, chain[:21]...)
+		for _, ref := range newTestL2Chain(30, 0xbb)[21:] {
+			ref.ParentHash = reorged[len(reorged)-1].Hash
+			reorged = append(reorged, ref)
+		}
+		v.l2 = reorged
+		heads.unsafe = reorged[27]
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, chain[18], heads.crossSafe, "cross-safe head is not affected by unsafe reorgs")
+		require.Equal(t, chain[18], heads.crossUnsafe, "cross-unsafe head is verified again from the cross-safe head")
+
+		heads.safe = reorged[22]
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, reorged[27], heads.crossUnsafe)
+		require.Equal(t, reorged[22], heads.crossSafe)
+
+		// the safe chain is reset to an earlier block
+		heads.unsafe = reorged[15]
+		heads.safe = reorged[15]
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, reorged[15], heads.crossUnsafe)
+		require.Equal(t, reorged[15], heads.crossSafe)
+	})
+
+	t.Run("safe reorg", func(t *testing.T) {
+		v, heads, _ := setup(nil)
+		_, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, chain[18], heads.crossSafe)
+
+		// the safe chain after block 15 is replaced, which includes the cross-safe head
+		reorged := append(testL2Chain{}, chain[:16]...)
+		for _, ref := range newTestL2Chain(30, 0xbb)[16:] {
+			ref.ParentHash = reorged[len(reorged)-1].Hash
+			reorged = append(reorged, ref)
+		}
+		v.l2 = reorged
+		heads.unsafe = reorged[25]
+		heads.safe = reorged[20]
+		heads.finalized = reorged[12]
+		changed, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, reorged[12], heads.crossSafe, "cross-safe head is verified again from the finalized head")
+		require.Equal(t, reorged[12], heads.crossUnsafe, "cross-unsafe head is verified again from the finalized head")
+
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, reorged[20], heads.crossSafe)
+		require.Equal(t, reorged[25], heads.crossUnsafe)
+	})
+
+	t.Run("safe reorg at the same height", func(t *testing.T) {
+		v, heads, _ := setup(nil)
+		_, err := v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+
+		// the safe head is replaced by a different block at the height of the cross-safe head
+		reorged := append(testL2Chain{}, chain[:18]...)
+		for _, ref := range newTestL2Chain(30, 0xbb)[18:] {
+			ref.ParentHash = reorged[len(reorged)-1].Hash
+			reorged = append(reorged, ref)
+		}
+		v.l2 = reorged
+		heads.unsafe = reorged[18]
+		heads.safe = reorged[18]
+		heads.finalized = reorged[12]
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, reorged[12], heads.crossSafe)
+
+		_, err = v.UpdateCrossHeads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, reorged[18], heads.crossSafe)
+		require.Equal(t, reorged[18], heads.crossUnsafe)
+	})
+}
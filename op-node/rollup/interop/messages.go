@@ -0,0 +1,114 @@
+// Package interop validates the cross-chain messages of L2 blocks after the interop upgrade,
+// and tracks which L2 blocks have all their cross-chain dependencies verified.
+package interop
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// CrossL2InboxAddress is the address of the predeploy that emits an ExecutingMessage log for every executing message.
+	CrossL2InboxAddress = common.HexToAddress("0x4200000000000000000000000000000000000022")
+
+	ExecutingMessageEventABI     = "ExecutingMessage(bytes32,(address,uint256,uint256,uint256,uint256))"
+	ExecutingMessageEventABIHash = crypto.Keccak256Hash([]byte(ExecutingMessageEventABI))
+)
+
+var ErrInvalidExecutingMessageLog = errors.New("invalid executing message log")
+
+// Identifier uniquely identifies the initiating message of an executing message:
+// the log emitted by Origin, at LogIndex of the block with BlockNumber and Timestamp on the chain with ChainID.
+type Identifier struct {
+	Origin      common.Address
+	BlockNumber uint64
+	LogIndex    uint64
+	Timestamp   uint64
+	ChainID     uint64
+}
+
+// ExecutingMessage is a message executed on this chain, which references an initiating message on a source chain.
+type ExecutingMessage struct {
+	// MsgHash is the hash of the payload of the initiating message, see MessagePayloadHash.
+	MsgHash common.Hash
+	Identifier
+	// ExecLogIndex is the index of the ExecutingMessage log in the block that executes the message.
+	ExecLogIndex uint64
+}
+
+func (m ExecutingMessage) String() string {
+	return fmt.Sprintf("%s (chain %d, block %d, log %d)", m.MsgHash, m.ChainID, m.BlockNumber, m.LogIndex)
+}
+
+// MessagePayloadHash returns the hash of the payload of an initiating message:
+// the concatenated topics and data of the log.
+func MessagePayloadHash(l *types.Log) common.Hash {
+	payload := make([]byte, 0, len(l.Topics)*common.HashLength+len(l.Data))
+	for _, topic := range l.Topics {
+		payload = append(payload, topic[:]...)
+	}
+	payload = append(payload, l.Data...)
+	return crypto.Keccak256Hash(payload)
+}
+
+// DecodeExecutingMessageLog decodes an ExecutingMessage log of the CrossL2Inbox.
+// The message hash is the indexed topic, and the data is the ABI encoded identifier.
+func DecodeExecutingMessageLog(l *types.Log) (ExecutingMessage, error) {
+	if l.Address != CrossL2InboxAddress {
+		return ExecutingMessage{}, fmt.Errorf("%w: emitted by %s", ErrInvalidExecutingMessageLog, l.Address)
+	}
+	if len(l.Topics) != 2 || l.Topics[0] != ExecutingMessageEventABIHash {
+		return ExecutingMessage{}, fmt.Errorf("%w: unexpected topics", ErrInvalidExecutingMessageLog)
+	}
+	if len(l.Data) != 5*32 {
+		return ExecutingMessage{}, fmt.Errorf("%w: expected %d bytes of data, got %d", ErrInvalidExecutingMessageLog, 5*32, len(l.Data))
+	}
+	words := make([]common.Hash, 5)
+	for i := range words {
+		words[i] = common.BytesToHash(l.Data[i*32 : (i+1)*32])
+	}
+	if words[0] != common.BytesToHash(words[0][12:]) {
+		return ExecutingMessage{}, fmt.Errorf("%w: invalid origin address", ErrInvalidExecutingMessageLog)
+	}
+	var nums [4]uint64
+	for i, w := range words[1:] {
+		if w != common.BytesToHash(w[24:]) {
+			return ExecutingMessage{}, fmt.Errorf("%w: identifier field %d overflows uint64", ErrInvalidExecutingMessageLog, i+1)
+		}
+		nums[i] = binary.BigEndian.Uint64(w[24:])
+	}
+	return ExecutingMessage{
+		MsgHash: l.Topics[1],
+		Identifier: Identifier{
+			Origin:      common.BytesToAddress(words[0][12:]),
+			BlockNumber: nums[0],
+			LogIndex:    nums[1],
+			Timestamp:   nums[2],
+			ChainID:     nums[3],
+		},
+		ExecLogIndex: uint64(l.Index),
+	}, nil
+}
+
+// ExecutingMessages returns the executing messages of a block, from the logs of its receipts.
+func ExecutingMessages(receipts types.Receipts) ([]ExecutingMessage, error) {
+	var msgs []ExecutingMessage
+	for _, rec := range receipts {
+		for _, l := range rec.Logs {
+			if l.Address != CrossL2InboxAddress || len(l.Topics) == 0 || l.Topics[0] != ExecutingMessageEventABIHash {
+				continue
+			}
+			msg, err := DecodeExecutingMessageLog(l)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode log %d of tx %s: %w", l.Index, l.TxHash, err)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,97 @@
+package interop
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func executingMessageLog(msg ExecutingMessage) *types.Log {
+	data := make([]byte, 5*32)
+	copy(data[12:32], msg.Origin[:])
+	for i, v := range []uint64{msg.BlockNumber, msg.LogIndex, msg.Timestamp, msg.ChainID} {
+		binary.BigEndian.PutUint64(data[(i+2)*32-8:(i+2)*32], v)
+	}
+	return &types.Log{
+		Address: CrossL2InboxAddress,
+		Topics:  []common.Hash{ExecutingMessageEventABIHash, msg.MsgHash},
+		Data:    data,
+	}
+}
+
+func TestDecodeExecutingMessageLog(t *testing.T) {
+	msg := ExecutingMessage{
+		MsgHash: common.Hash{0xaa},
+		Identifier: Identifier{
+			Origin:      common.Address{0xbb},
+			BlockNumber: 123,
+			LogIndex:    4,
+			Timestamp:   5678,
+			ChainID:     901,
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		actual, err := DecodeExecutingMessageLog(executingMessageLog(msg))
+		require.NoError(t, err)
+		require.Equal(t, msg, actual)
+	})
+
+	t.Run("other emitter", func(t *testing.T) {
+		l := executingMessageLog(msg)
+		l.Address = common.Address{0x01}
+		_, err := DecodeExecutingMessageLog(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+	})
+
+	t.Run("missing message hash", func(t *testing.T) {
+		l := executingMessageLog(msg)
+		l.Topics = l.Topics[:1]
+		_, err := DecodeExecutingMessageLog(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		l := executingMessageLog(msg)
+		l.Data = l.Data[:4*32]
+		_, err := DecodeExecutingMessageLog(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+	})
+
+	t.Run("dirty address word", func(t *testing.T) {
+		l := executingMessageLog(msg)
+		l.Data[0] = 1
+		_, err := DecodeExecutingMessageLog(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+	})
+
+	t.Run("overflowing block number", func(t *testing.T) {
+		l := executingMessageLog(msg)
+		l.Data[32] = 1
+		_, err := DecodeExecutingMessageLog(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+	})
+}
+
+func TestExecutingMessages(t *testing.T) {
+	msgA := ExecutingMessage{MsgHash: common.Hash{0x01}, Identifier: Identifier{ChainID: 1}}
+	msgB := ExecutingMessage{MsgHash: common.Hash{0x02}, Identifier: Identifier{ChainID: 2}}
+	other := &types.Log{Address: CrossL2InboxAddress, Topics: []common.Hash{{0x03}}}
+	receipts := types.Receipts{
+		{Logs: []*types.Log{executingMessageLog(msgA), other}},
+		{},
+		{Logs: []*types.Log{{Address: common.Address{0x04}}, executingMessageLog(msgB)}},
+	}
+	msgs, err := ExecutingMessages(receipts)
+	require.NoError(t, err)
+	require.Equal(t, []ExecutingMessage{msgA, msgB}, msgs)
+
+	invalid := executingMessageLog(msgA)
+	invalid.Data = nil
+	receipts = append(receipts, &types.Receipt{Logs: []*types.Log{invalid}})
+	_, err = ExecutingMessages(receipts)
+	require.ErrorIs(t, err, ErrInvalidExecutingMessageLog)
+}
@@ -0,0 +1,209 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	// ErrInvalidMessage is returned when an executing message does not match an initiating message,
+	// which makes the block that executes it invalid.
+	ErrInvalidMessage = errors.New("invalid executing message")
+	// ErrUnknownChain is returned when an executing message references a chain that no source is configured for.
+	ErrUnknownChain = errors.New("unknown chain")
+)
+
+// SafetyLevel is the level of safety of the initiating messages of an L2 block, on their source chains.
+type SafetyLevel int
+
+const (
+	// Unverified means an initiating message could not be found yet.
+	Unverified SafetyLevel = iota
+	// Unsafe means all initiating messages are in the unsafe chains of the source chains.
+	Unsafe
+	// Safe means all initiating messages are in the safe chains of the source chains.
+	Safe
+)
+
+func (lvl SafetyLevel) String() string {
+	switch lvl {
+	case Unverified:
+		return "unverified"
+	case Unsafe:
+		return "unsafe"
+	case Safe:
+		return "safe"
+	default:
+		return fmt.Sprintf("SafetyLevel(%d)", int(lvl))
+	}
+}
+
+// Source provides the blocks and receipts of a chain, to look up initiating messages in.
+type Source interface {
+	InfoByNumber(ctx context.Context, number uint64) (eth.BlockInfo, error)
+	InfoByLabel(ctx context.Context, label eth.BlockLabel) (eth.BlockInfo, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+// Verifier checks the executing messages of L2 blocks against the initiating messages on the source chains.
+type Verifier struct {
+	log  log.Logger
+	cfg  *rollup.Config
+	self Source
+	// chains are the sources of the chains in the dependency set by chain ID, including the chain itself
+	chains map[uint64]Source
+}
+
+// NewVerifier creates a Verifier of the blocks of the self chain, with the chains in the dependency set served by peers.
+func NewVerifier(log log.Logger, cfg *rollup.Config, self Source, peers map[uint64]Source) *Verifier {
+	chains := make(map[uint64]Source, len(peers)+1)
+	for id, src := range peers {
+		chains[id] = src
+	}
+	chains[cfg.L2ChainID.Uint64()] = self
+	return &Verifier{
+		log:    log,
+		cfg:    cfg,
+		self:   self,
+		chains: chains,
+	}
+}
+
+// CheckBlock returns the lowest safety level of the initiating messages of the executing messages of the block.
+// Blocks before the interop upgrade, and blocks without executing messages, are safe.
+// An ErrInvalidMessage error is returned if an executing message does not match its initiating message.
+func (v *Verifier) CheckBlock(ctx context.Context, block eth.L2BlockRef) (SafetyLevel, error) {
+	if !v.cfg.IsInterop(block.Time) {
+		return Safe, nil
+	}
+	_, receipts, err := v.self.FetchReceipts(ctx, block.Hash)
+	if err != nil {
+		return Unverified, fmt.Errorf("failed to fetch receipts of block %s: %w", block, err)
+	}
+	msgs, err := ExecutingMessages(receipts)
+	if err != nil {
+		return Unverified, fmt.Errorf("%w: block %s: %w", ErrInvalidMessage, block, err)
+	}
+	// The heads of each peer chain are only fetched once, and shared by all the messages of the block.
+	heads := make(map[uint64]chainHeads)
+	lvl := Safe
+	for _, msg := range msgs {
+		msgLvl, err := v.checkMessage(ctx, msg, block, heads)
+		if err != nil {
+			return Unverified, fmt.Errorf("failed to check message %s of block %s: %w", msg, block, err)
+		}
+		lvl = min(lvl, msgLvl)
+		if lvl == Unverified {
+			break
+		}
+	}
+	return lvl, nil
+}
+
+// chainHeads are the unsafe and safe head numbers of a peer chain.
+type chainHeads struct {
+	unsafe uint64
+	safe   uint64
+}
+
+// checkMessage returns the safety level of the initiating message of an executing message, executed in the given block.
+// Initiating messages of the chain itself are as safe as the block that executes them.
+// The heads of peer chains are looked up in heads, and fetched and added to it if not present yet.
+func (v *Verifier) checkMessage(ctx context.Context, msg ExecutingMessage, exec eth.L2BlockRef, heads map[uint64]chainHeads) (SafetyLevel, error) {
+	if msg.Timestamp > exec.Time {
+		return Unverified, fmt.Errorf("%w: initiated at %d, after it was executed at %d", ErrInvalidMessage, msg.Timestamp, exec.Time)
+	}
+	self := msg.ChainID == v.cfg.L2ChainID.Uint64()
+	if self && msg.BlockNumber > exec.Number {
+		return Unverified, fmt.Errorf("%w: initiated in block %d, after it was executed in block %d", ErrInvalidMessage, msg.BlockNumber, exec.Number)
+	}
+	if self && msg.BlockNumber == exec.Number && msg.LogIndex >= msg.ExecLogIndex {
+		return Unverified, fmt.Errorf("%w: initiated by log %d, not before it was executed by log %d", ErrInvalidMessage, msg.LogIndex, msg.ExecLogIndex)
+	}
+	src, ok := v.chains[msg.ChainID]
+	if !ok {
+		return Unverified, fmt.Errorf("%w: %d", ErrUnknownChain, msg.ChainID)
+	}
+	var head chainHeads
+	if !self {
+		var err error
+		head, err = v.peerHeads(ctx, msg.ChainID, src, heads)
+		if err != nil {
+			return Unverified, err
+		}
+		if head.unsafe < msg.BlockNumber {
+			v.log.Debug("Initiating message not yet available", "msg", msg, "head", head.unsafe)
+			return Unverified, nil
+		}
+	}
+	info, receipts, err := v.fetchBlock(ctx, src, msg.BlockNumber)
+	if err != nil {
+		return Unverified, fmt.Errorf("failed to fetch block %d of chain %d: %w", msg.BlockNumber, msg.ChainID, err)
+	}
+	if info.Time() != msg.Timestamp {
+		return Unverified, fmt.Errorf("%w: block %d has timestamp %d, not %d", ErrInvalidMessage, msg.BlockNumber, info.Time(), msg.Timestamp)
+	}
+	l := findLog(receipts, msg.LogIndex)
+	if l == nil {
+		return Unverified, fmt.Errorf("%w: block %d has no log %d", ErrInvalidMessage, msg.BlockNumber, msg.LogIndex)
+	}
+	if l.Address != msg.Origin {
+		return Unverified, fmt.Errorf("%w: log emitted by %s, not %s", ErrInvalidMessage, l.Address, msg.Origin)
+	}
+	if hash := MessagePayloadHash(l); hash != msg.MsgHash {
+		return Unverified, fmt.Errorf("%w: log has payload hash %s", ErrInvalidMessage, hash)
+	}
+	if self || head.safe >= msg.BlockNumber {
+		return Safe, nil
+	}
+	return Unsafe, nil
+}
+
+// peerHeads returns the heads of the peer chain with the given ID from heads, or fetches and adds them if not present.
+func (v *Verifier) peerHeads(ctx context.Context, chainID uint64, src Source, heads map[uint64]chainHeads) (chainHeads, error) {
+	if head, ok := heads[chainID]; ok {
+		return head, nil
+	}
+	unsafe, err := src.InfoByLabel(ctx, eth.Unsafe)
+	if err != nil {
+		return chainHeads{}, fmt.Errorf("failed to fetch unsafe head of chain %d: %w", chainID, err)
+	}
+	safe, err := src.InfoByLabel(ctx, eth.Safe)
+	if err != nil {
+		return chainHeads{}, fmt.Errorf("failed to fetch safe head of chain %d: %w", chainID, err)
+	}
+	head := chainHeads{unsafe: unsafe.NumberU64(), safe: safe.NumberU64()}
+	heads[chainID] = head
+	return head, nil
+}
+
+func (v *Verifier) fetchBlock(ctx context.Context, src Source, num uint64) (eth.BlockInfo, types.Receipts, error) {
+	info, err := src.InfoByNumber(ctx, num)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, receipts, err := src.FetchReceipts(ctx, info.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, receipts, nil
+}
+
+func findLog(receipts types.Receipts, logIndex uint64) *types.Log {
+	for _, rec := range receipts {
+		for _, l := range rec.Logs {
+			if uint64(l.Index) == logIndex {
+				return l
+			}
+		}
+	}
+	return nil
+}
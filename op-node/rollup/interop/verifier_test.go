@@ -0,0 +1,215 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+type stubChain struct {
+	blocks   []*testutils.MockBlockInfo
+	receipts map[common.Hash]types.Receipts
+	unsafe   uint64
+	safe     uint64
+	// headRequests counts the requests for the unsafe and safe heads
+	headRequests int
+}
+
+func newStubChain(count uint64, blockTime uint64) *stubChain {
+	c := &stubChain{receipts: make(map[common.Hash]types.Receipts), unsafe: count - 1}
+	for i := uint64(0); i < count; i++ {
+		c.blocks = append(c.blocks, &testutils.MockBlockInfo{
+			InfoHash: common.Hash{0xcc, byte(i)},
+			InfoNum:  i,
+			InfoTime: i * blockTime,
+		})
+	}
+	return c
+}
+
+func (c *stubChain) InfoByNumber(ctx context.Context, number uint64) (eth.BlockInfo, error) {
+	if number >= uint64(len(c.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return c.blocks[number], nil
+}
+
+func (c *stubChain) InfoByLabel(ctx context.Context, label eth.BlockLabel) (eth.BlockInfo, error) {
+	c.headRequests++
+	switch label {
+	case eth.Unsafe:
+		return c.blocks[c.unsafe], nil
+	case eth.Safe:
+		return c.blocks[c.safe], nil
+	default:
+		return nil, errors.New("unexpected label")
+	}
+}
+
+func (c *stubChain) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	for _, b := range c.blocks {
+		if b.InfoHash == blockHash {
+			return b, c.receipts[blockHash], nil
+		}
+	}
+	return nil, nil, ethereum.NotFound
+}
+
+// initiate adds an initiating message log at the given block, and returns the executing message that references it.
+func (c *stubChain) initiate(chainID uint64, num uint64, logIndex uint) ExecutingMessage {
+	b := c.blocks[num]
+	l := &types.Log{
+		Address: common.Address{0xee},
+		Topics:  []common.Hash{{0x01}, {byte(logIndex)}},
+		Data:    []byte{0x02, byte(num)},
+		Index:   logIndex,
+	}
+	c.receipts[b.InfoHash] = append(c.receipts[b.InfoHash], &types.Receipt{Logs: []*types.Log{l}})
+	return ExecutingMessage{
+		MsgHash: MessagePayloadHash(l),
+		Identifier: Identifier{
+			Origin:      l.Address,
+			BlockNumber: num,
+			LogIndex:    uint64(logIndex),
+			Timestamp:   b.InfoTime,
+			ChainID:     chainID,
+		},
+	}
+}
+
+// execute adds executing message logs to the given block, after its existing logs, and returns the block.
+func (c *stubChain) execute(num uint64, msgs ...ExecutingMessage) eth.L2BlockRef {
+	b := c.blocks[num]
+	for _, msg := range msgs {
+		l := executingMessageLog(msg)
+		l.Index = uint(len(c.receipts[b.InfoHash]))
+		c.receipts[b.InfoHash] = append(c.receipts[b.InfoHash], &types.Receipt{Logs: []*types.Log{l}})
+	}
+	return eth.L2BlockRef{Hash: b.InfoHash, Number: num, Time: b.InfoTime}
+}
+
+const (
+	selfChainID = 900
+	peerChainID = 901
+)
+
+func testInteropConfig(interopTime uint64) *rollup.Config {
+	return &rollup.Config{
+		L2ChainID:   big.NewInt(selfChainID),
+		BlockTime:   2,
+		InteropTime: &interopTime,
+	}
+}
+
+func TestVerifierCheckBlock(t *testing.T) {
+	ctx := context.Background()
+	setup := func() (*Verifier, *stubChain, *stubChain) {
+		self := newStubChain(20, 2)
+		peer := newStubChain(20, 2)
+		peer.unsafe = 15
+		peer.safe = 10
+		v := NewVerifier(testlog.Logger(t, log.LevelError), testInteropConfig(10), self, map[uint64]Source{peerChainID: peer})
+		return v, self, peer
+	}
+
+	t.Run("pre-interop", func(t *testing.T) {
+		v, self, _ := setup()
+		block := self.execute(4, ExecutingMessage{MsgHash: common.Hash{0x01}, Identifier: Identifier{ChainID: 12345}})
+		lvl, err := v.CheckBlock(ctx, block)
+		require.NoError(t, err)
+		require.Equal(t, Safe, lvl)
+	})
+
+	t.Run("no messages", func(t *testing.T) {
+		v, self, _ := setup()
+		lvl, err := v.CheckBlock(ctx, self.execute(12))
+		require.NoError(t, err)
+		require.Equal(t, Safe, lvl)
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		v, self, peer := setup()
+		safeMsg := peer.initiate(peerChainID, 9, 0)
+		unsafeMsg := peer.initiate(peerChainID, 12, 1)
+		ownMsg := self.initiate(selfChainID, 13, 7)
+
+		lvl, err := v.CheckBlock(ctx, self.execute(14, safeMsg, ownMsg))
+		require.NoError(t, err)
+		require.Equal(t, Safe, lvl)
+
+		lvl, err = v.CheckBlock(ctx, self.execute(15, safeMsg, unsafeMsg))
+		require.NoError(t, err)
+		require.Equal(t, Unsafe, lvl)
+
+		peer.unsafe = 11
+		lvl, err = v.CheckBlock(ctx, self.execute(16, unsafeMsg))
+		require.NoError(t, err)
+		require.Equal(t, Unverified, lvl)
+	})
+
+	t.Run("fetch peer heads once per block", func(t *testing.T) {
+		v, self, peer := setup()
+		msgA := peer.initiate(peerChainID, 9, 0)
+		msgB := peer.initiate(peerChainID, 12, 1)
+		lvl, err := v.CheckBlock(ctx, self.execute(14, msgA, msgB, msgA))
+		require.NoError(t, err)
+		require.Equal(t, Unsafe, lvl)
+		require.Equal(t, 2, peer.headRequests)
+	})
+
+	t.Run("same block", func(t *testing.T) {
+		v, self, _ := setup()
+		earlier := self.initiate(selfChainID, 14, 0)
+		lvl, err := v.CheckBlock(ctx, self.execute(14, earlier))
+		require.NoError(t, err)
+		require.Equal(t, Safe, lvl)
+
+		// The executing log is at index 1, so it can't execute a message initiated by itself or a later log.
+		for _, logIndex := range []uint{1, 5} {
+			self.receipts[self.blocks[15].InfoHash] = nil
+			later := self.initiate(selfChainID, 15, logIndex)
+			_, err = v.CheckBlock(ctx, self.execute(15, later))
+			require.ErrorIs(t, err, ErrInvalidMessage, "log %d", logIndex)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		v, self, peer := setup()
+		msg := peer.initiate(peerChainID, 12, 3)
+
+		wrongHash := msg
+		wrongHash.MsgHash = common.Hash{0xff}
+		wrongOrigin := msg
+		wrongOrigin.Origin = common.Address{0xff}
+		wrongLog := msg
+		wrongLog.LogIndex = 4
+		wrongTime := msg
+		wrongTime.Timestamp++
+		future := peer.initiate(peerChainID, 15, 0)
+		ownFuture := self.initiate(selfChainID, 14, 0)
+		for i, invalid := range []ExecutingMessage{wrongHash, wrongOrigin, wrongLog, wrongTime, future, ownFuture} {
+			_, err := v.CheckBlock(ctx, self.execute(uint64(13), invalid))
+			require.ErrorIs(t, err, ErrInvalidMessage, "message %d", i)
+			self.receipts[self.blocks[13].InfoHash] = nil
+		}
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		v, self, _ := setup()
+		msg := ExecutingMessage{Identifier: Identifier{ChainID: 12345}}
+		_, err := v.CheckBlock(ctx, self.execute(12, msg))
+		require.ErrorIs(t, err, ErrUnknownChain)
+	})
+}
@@ -34,6 +34,11 @@ var (
 	ErrChainIDsSame                  = errors.New("L1 and L2 chain IDs must be different")
 	ErrL1ChainIDNotPositive          = errors.New("L1 chain ID must be non-zero and positive")
 	ErrL2ChainIDNotPositive          = errors.New("L2 chain ID must be non-zero and positive")
+	ErrMissingAltDAChallengeAddress  = errors.New("missing alt-DA challenge address")
+	ErrMissingAltDAChallengeWindow   = errors.New("alt-DA challenge window must be set")
+	ErrMissingAltDAResolveWindow     = errors.New("alt-DA resolve window must be set")
+	ErrAltDAChallengeBondNotPositive = errors.New("alt-DA challenge bond must be positive")
+	ErrAltDAWindowTooLarge           = errors.New("alt-DA challenge and resolve windows must be shorter than the sequencing window")
 )
 
 type Genesis struct {
@@ -110,6 +115,23 @@ type Config struct {
 
 	// L1 block timestamp to start reading blobs as batch data-source. Optional.
 	BlobsEnabledL1Timestamp *uint64 `json:"blobs_data,omitempty"`
+
+	// UseAltDA enables the alternative data-availability source: batcher transactions may hold a commitment
+	// to batch data stored on a DA server, instead of the batch data itself. Optional.
+	UseAltDA bool `json:"use_alt_da,omitempty"`
+	// AltDAChallengeAddress is the L1 address that transactions challenging the availability of alt-DA inputs,
+	// and resolving these challenges by posting the inputs, are sent to. Required with UseAltDA.
+	// Only successful transactions count, so the address must accept them, e.g. an account that holds the bonds.
+	AltDAChallengeAddress common.Address `json:"alt_da_challenge_address,omitempty"`
+	// AltDAChallengeBond is the minimum value in wei that a challenge transaction must pay to the challenge address
+	// to count as a challenge. Required with UseAltDA.
+	AltDAChallengeBond *big.Int `json:"alt_da_challenge_bond,omitempty"`
+	// AltDAChallengeWindow is the number of L1 blocks after the block with an alt-DA commitment,
+	// in which the availability of its input can be challenged. Required with UseAltDA.
+	AltDAChallengeWindow uint64 `json:"alt_da_challenge_window,omitempty"`
+	// AltDAResolveWindow is the number of L1 blocks after the challenge window, in which a challenge can still be
+	// resolved by posting the input to L1. A challenged input that is not resolved is skipped. Required with UseAltDA.
+	AltDAResolveWindow uint64 `json:"alt_da_resolve_window,omitempty"`
 }
 
 // ValidateL1Config checks L1 config variables for errors.
@@ -278,6 +300,24 @@ func (cfg *Config) Check() error {
 		return ErrL2ChainIDNotPositive
 	}
 
+	if cfg.UseAltDA {
+		if cfg.AltDAChallengeAddress == (common.Address{}) {
+			return ErrMissingAltDAChallengeAddress
+		}
+		if cfg.AltDAChallengeWindow == 0 {
+			return ErrMissingAltDAChallengeWindow
+		}
+		if cfg.AltDAResolveWindow == 0 {
+			return ErrMissingAltDAResolveWindow
+		}
+		if cfg.AltDAChallengeBond == nil || cfg.AltDAChallengeBond.Sign() < 1 {
+			return ErrAltDAChallengeBondNotPositive
+		}
+		if cfg.AltDAWindow() >= cfg.SeqWindowSize {
+			return ErrAltDAWindowTooLarge
+		}
+	}
+
 	if err := checkFork(cfg.RegolithTime, cfg.CanyonTime, "regolith", "canyon"); err != nil {
 		return err
 	}
@@ -315,6 +355,15 @@ func (c *Config) L1Signer() types.Signer {
 	return types.NewCancunSigner(c.L1ChainID)
 }
 
+// AltDAWindow returns the number of L1 blocks after the block with an alt-DA commitment, until which the availability
+// of its input is decided: the challenge and resolve windows. It is zero if the rollup does not use alt-DA.
+func (c *Config) AltDAWindow() uint64 {
+	if !c.UseAltDA {
+		return 0
+	}
+	return c.AltDAChallengeWindow + c.AltDAResolveWindow
+}
+
 // IsRegolith returns true if the Regolith hardfork is active at or past the given timestamp.
 func (c *Config) IsRegolith(timestamp uint64) bool {
 	return c.RegolithTime != nil && timestamp >= *c.RegolithTime
@@ -425,6 +474,10 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	banner += fmt.Sprintf("  - Ecotone: %s\n", fmtForkTimeOrUnset(c.EcotoneTime))
 	banner += fmt.Sprintf("  - Fjord: %s\n", fmtForkTimeOrUnset(c.FjordTime))
 	banner += fmt.Sprintf("  - Interop: %s\n", fmtForkTimeOrUnset(c.InteropTime))
+	if c.UseAltDA {
+		banner += fmt.Sprintf("Alternative data-availability source: enabled, challenge window: %d, resolve window: %d, challenge bond: %s wei\n",
+			c.AltDAChallengeWindow, c.AltDAResolveWindow, c.AltDAChallengeBond)
+	}
 	// Report the protocol version
 	banner += fmt.Sprintf("Node supports up to OP-Stack Protocol Version: %s\n", OPStackSupport)
 	return banner
@@ -455,6 +508,7 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 		"ecotone_time", fmtForkTimeOrUnset(c.EcotoneTime),
 		"fjord_time", fmtForkTimeOrUnset(c.FjordTime),
 		"interop_time", fmtForkTimeOrUnset(c.InteropTime),
+		"use_alt_da", c.UseAltDA,
 	)
 }
 
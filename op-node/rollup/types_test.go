@@ -399,6 +399,54 @@ func TestConfig_Check(t *testing.T) {
 			modifier:    func(cfg *Config) { cfg.L2ChainID = big.NewInt(0) },
 			expectedErr: ErrL2ChainIDNotPositive,
 		},
+		{
+			name: "NoAltDAChallengeAddress",
+			modifier: func(cfg *Config) {
+				cfg.UseAltDA = true
+				cfg.AltDAChallengeWindow = 10
+				cfg.AltDAResolveWindow = 10
+			},
+			expectedErr: ErrMissingAltDAChallengeAddress,
+		},
+		{
+			name: "NoAltDAChallengeWindow",
+			modifier: func(cfg *Config) {
+				cfg.UseAltDA = true
+				cfg.AltDAChallengeAddress = common.Address{0xda}
+				cfg.AltDAResolveWindow = 10
+			},
+			expectedErr: ErrMissingAltDAChallengeWindow,
+		},
+		{
+			name: "NoAltDAResolveWindow",
+			modifier: func(cfg *Config) {
+				cfg.UseAltDA = true
+				cfg.AltDAChallengeAddress = common.Address{0xda}
+				cfg.AltDAChallengeWindow = 10
+			},
+			expectedErr: ErrMissingAltDAResolveWindow,
+		},
+		{
+			name: "NoAltDAChallengeBond",
+			modifier: func(cfg *Config) {
+				cfg.UseAltDA = true
+				cfg.AltDAChallengeAddress = common.Address{0xda}
+				cfg.AltDAChallengeWindow = 10
+				cfg.AltDAResolveWindow = 10
+			},
+			expectedErr: ErrAltDAChallengeBondNotPositive,
+		},
+		{
+			name: "AltDAWindowTooLarge",
+			modifier: func(cfg *Config) {
+				cfg.UseAltDA = true
+				cfg.AltDAChallengeAddress = common.Address{0xda}
+				cfg.AltDAChallengeWindow = cfg.SeqWindowSize / 2
+				cfg.AltDAResolveWindow = cfg.SeqWindowSize - cfg.AltDAChallengeWindow
+				cfg.AltDAChallengeBond = big.NewInt(1)
+			},
+			expectedErr: ErrAltDAWindowTooLarge,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
@@ -107,6 +108,15 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		ConductorEnabled:    ctx.Bool(flags.ConductorEnabledFlag.Name),
 		ConductorRpc:        ctx.String(flags.ConductorRpcFlag.Name),
 		ConductorRpcTimeout: ctx.Duration(flags.ConductorRpcTimeoutFlag.Name),
+
+		AltDA: altda.ReadCLIConfig(ctx),
+
+		SafeDBPath:          ctx.String(flags.SafeDBPath.Name),
+		SafeDBPruneL1Blocks: ctx.Uint64(flags.SafeDBPruneL1Blocks.Name),
+
+		InteropPeerRPCs: ctx.StringSlice(flags.InteropPeerRPCs.Name),
+
+		DerivationTracePath: ctx.String(flags.DerivationTracePath.Name),
 	}
 
 	if err := cfg.LoadPersisted(log); err != nil {
@@ -0,0 +1,32 @@
+package l1
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
+)
+
+// AltDAInputFetcher retrieves alt-DA inputs from the pre-image oracle, instead of from a DA server.
+type AltDAInputFetcher struct {
+	logger log.Logger
+	oracle Oracle
+}
+
+var _ derive.AltDAInputFetcher = (*AltDAInputFetcher)(nil)
+
+func NewAltDAInputFetcher(logger log.Logger, oracle Oracle) *AltDAInputFetcher {
+	return &AltDAInputFetcher{
+		logger: logger,
+		oracle: oracle,
+	}
+}
+
+// GetInput fetches the alt-DA input with the given commitment.
+// The pre-image oracle verifies the input against the commitment, as the commitment is its keccak256 hash.
+func (f *AltDAInputFetcher) GetInput(ctx context.Context, comm altda.Keccak256Commitment) ([]byte, error) {
+	f.logger.Info("Fetching alt-DA input", "commitment", comm)
+	return f.oracle.GetAltDAInput(comm), nil
+}
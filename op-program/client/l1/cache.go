@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -22,6 +23,7 @@ type CachingOracle struct {
 	txs    *simplelru.LRU[common.Hash, types.Transactions]
 	rcpts  *simplelru.LRU[common.Hash, types.Receipts]
 	blobs  *simplelru.LRU[common.Hash, *eth.Blob]
+	altDA  *simplelru.LRU[altda.Keccak256Commitment, []byte]
 }
 
 func NewCachingOracle(oracle Oracle) *CachingOracle {
@@ -29,12 +31,14 @@ func NewCachingOracle(oracle Oracle) *CachingOracle {
 	txsLRU, _ := simplelru.NewLRU[common.Hash, types.Transactions](cacheSize, nil)
 	rcptsLRU, _ := simplelru.NewLRU[common.Hash, types.Receipts](cacheSize, nil)
 	blobsLRU, _ := simplelru.NewLRU[common.Hash, *eth.Blob](cacheSize, nil)
+	altDALRU, _ := simplelru.NewLRU[altda.Keccak256Commitment, []byte](cacheSize, nil)
 	return &CachingOracle{
 		oracle: oracle,
 		blocks: blockLRU,
 		txs:    txsLRU,
 		rcpts:  rcptsLRU,
 		blobs:  blobsLRU,
+		altDA:  altDALRU,
 	}
 }
 
@@ -86,3 +90,13 @@ func (o *CachingOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash
 	o.blobs.Add(cacheKey, blob)
 	return blob
 }
+
+func (o *CachingOracle) GetAltDAInput(comm altda.Keccak256Commitment) []byte {
+	input, ok := o.altDA.Get(comm)
+	if ok {
+		return input
+	}
+	input = o.oracle.GetAltDAInput(comm)
+	o.altDA.Add(comm, input)
+	return input
+}
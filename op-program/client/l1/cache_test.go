@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-program/client/l1/test"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
 	"github.com/stretchr/testify/require"
@@ -91,3 +92,19 @@ func TestCachingOracle_GetBlobs(t *testing.T) {
 	actualBlob = oracle.GetBlob(l1BlockRef, indexedBlobHash)
 	require.Equal(t, &blob, actualBlob)
 }
+
+func TestCachingOracle_GetAltDAInput(t *testing.T) {
+	stub := test.NewStubOracle(t)
+	oracle := NewCachingOracle(stub)
+
+	input := []byte{0xaa, 0xbb}
+	comm := altda.NewKeccak256Commitment(input)
+
+	// Initial call retrieves from the stub
+	stub.AltDAInputs[comm] = input
+	require.Equal(t, input, oracle.GetAltDAInput(comm))
+
+	// Later calls should retrieve from cache
+	delete(stub.AltDAInputs, comm)
+	require.Equal(t, input, oracle.GetAltDAInput(comm))
+}
@@ -12,6 +12,7 @@ const (
 	HintL1Transactions = "l1-transactions"
 	HintL1Receipts     = "l1-receipts"
 	HintL1Blob         = "l1-blob"
+	HintAltDAInput     = "altda-input"
 )
 
 type BlockHeaderHint common.Hash
@@ -45,3 +46,11 @@ var _ preimage.Hint = BlobHint{}
 func (l BlobHint) Hint() string {
 	return HintL1Blob + " " + hexutil.Encode(l)
 }
+
+type AltDAInputHint common.Hash
+
+var _ preimage.Hint = AltDAInputHint{}
+
+func (l AltDAInputHint) Hint() string {
+	return HintAltDAInput + " " + (common.Hash)(l).String()
+}
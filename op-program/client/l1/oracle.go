@@ -13,6 +13,7 @@ import (
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -28,6 +29,10 @@ type Oracle interface {
 
 	// GetBlobField retrieves the field element at the given index from the blob with the given hash.
 	GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob
+
+	// GetAltDAInput retrieves the alt-DA input with the given commitment.
+	// It is only requested for inputs that were not challenged on L1, which the DA server must provide.
+	GetAltDAInput(comm altda.Keccak256Commitment) []byte
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -121,3 +126,9 @@ func (p *PreimageOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHas
 
 	return &blob
 }
+
+func (p *PreimageOracle) GetAltDAInput(comm altda.Keccak256Commitment) []byte {
+	// The commitment is the keccak256 hash of the input, so the input is its keccak256 pre-image.
+	p.hint.Hint(AltDAInputHint(comm))
+	return p.oracle.Get(preimage.Keccak256Key(comm))
+}
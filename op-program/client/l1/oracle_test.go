@@ -15,6 +15,7 @@ import (
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
 )
@@ -103,3 +104,23 @@ func TestPreimageOracleBlockByHash(t *testing.T) {
 		})
 	}
 }
+
+func TestPreimageOracleAltDAInput(t *testing.T) {
+	input := []byte("some batcher data")
+	comm := altda.NewKeccak256Commitment(input)
+
+	var hints mock.Mock
+	po := &PreimageOracle{
+		oracle: preimage.OracleFn(func(key preimage.Key) []byte {
+			require.Equal(t, preimage.Keccak256Key(comm).PreimageKey(), key.PreimageKey())
+			return input
+		}),
+		hint: preimage.HinterFn(func(v preimage.Hint) {
+			hints.MethodCalled("hint", v.Hint())
+		}),
+	}
+
+	hints.On("hint", AltDAInputHint(comm).Hint()).Once().Return()
+	require.Equal(t, input, po.GetAltDAInput(comm))
+	hints.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"testing"
 
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -22,6 +23,9 @@ type StubOracle struct {
 
 	// Blobs maps indexed blob hash to l1 block ref to blob
 	Blobs map[eth.L1BlockRef]map[eth.IndexedBlobHash]*eth.Blob
+
+	// AltDAInputs maps commitment to alt-DA input
+	AltDAInputs map[altda.Keccak256Commitment][]byte
 }
 
 func NewStubOracle(t *testing.T) *StubOracle {
@@ -31,6 +35,8 @@ func NewStubOracle(t *testing.T) *StubOracle {
 		Txs:    make(map[common.Hash]types.Transactions),
 		Rcpts:  make(map[common.Hash]types.Receipts),
 		Blobs:  make(map[eth.L1BlockRef]map[eth.IndexedBlobHash]*eth.Blob),
+
+		AltDAInputs: make(map[altda.Keccak256Commitment][]byte),
 	}
 }
 
@@ -69,3 +75,11 @@ func (o StubOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *e
 	}
 	return blob
 }
+
+func (o StubOracle) GetAltDAInput(comm altda.Keccak256Commitment) []byte {
+	input, ok := o.AltDAInputs[comm]
+	if !ok {
+		o.t.Fatalf("unknown alt-DA input %s", comm)
+	}
+	return input
+}
@@ -74,3 +74,7 @@ func (o *CachingOracle) OutputByRoot(root common.Hash) eth.Output {
 	o.outputs.Add(root, output)
 	return output
 }
+
+func (o *CachingOracle) PrefetchAccounts(blockHash common.Hash, accounts []common.Address) {
+	o.oracle.PrefetchAccounts(blockHash, accounts)
+}
@@ -39,6 +39,7 @@ type OracleBackedL2Chain struct {
 }
 
 var _ engineapi.EngineBackend = (*OracleBackedL2Chain)(nil)
+var _ engineapi.AccountPrefetcher = (*OracleBackedL2Chain)(nil)
 
 func NewOracleBackedL2Chain(logger log.Logger, oracle Oracle, chainCfg *params.ChainConfig, l2OutputRoot common.Hash) (*OracleBackedL2Chain, error) {
 	output := oracle.OutputByRoot(l2OutputRoot)
@@ -170,6 +171,10 @@ func (o *OracleBackedL2Chain) StateAt(root common.Hash) (*state.StateDB, error)
 	return state.New(root, state.NewDatabase(rawdb.NewDatabase(o.db)), nil)
 }
 
+func (o *OracleBackedL2Chain) PrefetchAccounts(parent common.Hash, accounts []common.Address) {
+	o.oracle.PrefetchAccounts(parent, accounts)
+}
+
 func (o *OracleBackedL2Chain) InsertBlockWithoutSetHead(block *types.Block) error {
 	processor, err := engineapi.NewBlockProcessorFromHeader(o, block.Header())
 	if err != nil {
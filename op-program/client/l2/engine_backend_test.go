@@ -1,6 +1,7 @@
 package l2
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
@@ -269,6 +270,50 @@ func createBlock(t *testing.T, chain *OracleBackedL2Chain) *types.Block {
 	return blocks[0]
 }
 
+func TestPrefetchAccountsWhenBuildingBlock(t *testing.T) {
+	_, chain := setupOracleBackedChain(t, 1)
+	backend := &prefetchRecordingChain{OracleBackedL2Chain: chain}
+	engine := engineapi.NewL2EngineAPI(testlog.Logger(t, log.LevelDebug), backend, nil)
+	block := createBlock(t, chain)
+	parent := chain.CurrentHeader()
+	var txData []eth.Data
+	for _, tx := range block.Transactions() {
+		rlp, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		txData = append(txData, rlp)
+	}
+	feeRecipient := common.Address{0xfe}
+	gasLimit := eth.Uint64Quantity(block.GasLimit())
+
+	result, err := engine.ForkchoiceUpdatedV2(context.Background(), &eth.ForkchoiceState{
+		HeadBlockHash:      parent.Hash(),
+		SafeBlockHash:      parent.Hash(),
+		FinalizedBlockHash: parent.Hash(),
+	}, &eth.PayloadAttributes{
+		Timestamp:             eth.Uint64Quantity(block.Time()),
+		PrevRandao:            eth.Bytes32(block.MixDigest()),
+		SuggestedFeeRecipient: feeRecipient,
+		Transactions:          txData,
+		NoTxPool:              true,
+		GasLimit:              &gasLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, eth.ExecutionValid, result.PayloadStatus.Status)
+	require.Equal(t, parent.Hash(), backend.parent)
+	require.Equal(t, []common.Address{feeRecipient, fundedAddress, targetAddress}, backend.accounts)
+}
+
+type prefetchRecordingChain struct {
+	*OracleBackedL2Chain
+	parent   common.Hash
+	accounts []common.Address
+}
+
+func (c *prefetchRecordingChain) PrefetchAccounts(parent common.Hash, accounts []common.Address) {
+	c.parent = parent
+	c.accounts = accounts
+}
+
 func TestEngineAPITests(t *testing.T) {
 	test.RunEngineAPITests(t, func(t *testing.T) engineapi.EngineBackend {
 		_, chain := setupOracleBackedChain(t, 0)
@@ -45,6 +45,13 @@ type EngineBackend interface {
 	consensus.ChainHeaderReader
 }
 
+// AccountPrefetcher is optionally implemented by an EngineBackend that can prepare the state of accounts in bulk.
+type AccountPrefetcher interface {
+	// PrefetchAccounts is called with the accounts the transactions of a new block will access,
+	// before the transactions are processed on top of the state of the parent block.
+	PrefetchAccounts(parent common.Hash, accounts []common.Address)
+}
+
 // L2EngineAPI wraps an engine actor, and implements the RPC backend required to serve the engine API.
 // This re-implements some of the Geth API work, but changes the API backend so we can deterministically
 // build and control the L2 block contents to reach very specific edge cases as desired for testing.
@@ -154,21 +161,52 @@ func (ea *L2EngineAPI) startBlock(parent common.Hash, params *eth.PayloadAttribu
 	ea.l2ForceEmpty = params.NoTxPool
 	ea.payloadID = computePayloadId(parent, params)
 
-	// pre-process the deposits
+	txs := make([]*types.Transaction, len(params.Transactions))
 	for i, otx := range params.Transactions {
 		var tx types.Transaction
 		if err := tx.UnmarshalBinary(otx); err != nil {
 			return fmt.Errorf("transaction %d is not valid: %w", i, err)
 		}
-		err := ea.blockProcessor.AddTx(&tx)
+		txs[i] = &tx
+	}
+	if prefetcher, ok := ea.backend.(AccountPrefetcher); ok {
+		prefetcher.PrefetchAccounts(parent, ea.accessedAccounts(params.SuggestedFeeRecipient, txs))
+	}
+
+	// pre-process the deposits
+	for i, tx := range txs {
+		err := ea.blockProcessor.AddTx(tx)
 		if err != nil {
-			ea.l2TxFailed = append(ea.l2TxFailed, &tx)
+			ea.l2TxFailed = append(ea.l2TxFailed, tx)
 			return fmt.Errorf("failed to apply deposit transaction to L2 block (tx %d): %w", i, err)
 		}
 	}
 	return nil
 }
 
+// accessedAccounts returns the fee recipient, and the senders and recipients of the transactions, without duplicates.
+func (ea *L2EngineAPI) accessedAccounts(feeRecipient common.Address, txs []*types.Transaction) []common.Address {
+	signer := types.LatestSigner(ea.config())
+	seen := map[common.Address]bool{feeRecipient: true}
+	accounts := []common.Address{feeRecipient}
+	add := func(addr common.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			accounts = append(accounts, addr)
+		}
+	}
+	for _, tx := range txs {
+		// Transactions with an invalid signature are rejected when they are processed
+		if from, err := types.Sender(signer, tx); err == nil {
+			add(from)
+		}
+		if to := tx.To(); to != nil {
+			add(*to)
+		}
+	}
+	return accounts
+}
+
 func (ea *L2EngineAPI) endBlock() (*types.Block, error) {
 	if ea.blockProcessor == nil {
 		return nil, fmt.Errorf("no block is being built currently (id %s)", ea.payloadID)
@@ -2,16 +2,18 @@ package l2
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
 
 const (
-	HintL2BlockHeader  = "l2-block-header"
-	HintL2Transactions = "l2-transactions"
-	HintL2Code         = "l2-code"
-	HintL2StateNode    = "l2-state-node"
-	HintL2Output       = "l2-output"
+	HintL2BlockHeader   = "l2-block-header"
+	HintL2Transactions  = "l2-transactions"
+	HintL2Code          = "l2-code"
+	HintL2StateNode     = "l2-state-node"
+	HintL2Output        = "l2-output"
+	HintL2AccountProofs = "l2-account-proofs"
 )
 
 type BlockHeaderHint common.Hash
@@ -53,3 +55,21 @@ var _ preimage.Hint = L2OutputHint{}
 func (l L2OutputHint) Hint() string {
 	return HintL2Output + " " + (common.Hash)(l).String()
 }
+
+// AccountProofsHint requests the state trie nodes along the proofs of all the accounts,
+// in the state of the block with the given hash, so they can be fetched in a single batch.
+type AccountProofsHint struct {
+	BlockHash common.Hash
+	Accounts  []common.Address
+}
+
+var _ preimage.Hint = AccountProofsHint{}
+
+func (l AccountProofsHint) Hint() string {
+	data := make([]byte, 0, common.HashLength+len(l.Accounts)*common.AddressLength)
+	data = append(data, l.BlockHash[:]...)
+	for _, account := range l.Accounts {
+		data = append(data, account[:]...)
+	}
+	return HintL2AccountProofs + " " + hexutil.Encode(data)
+}
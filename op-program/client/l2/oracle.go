@@ -34,6 +34,10 @@ type Oracle interface {
 	BlockByHash(blockHash common.Hash) *types.Block
 
 	OutputByRoot(root common.Hash) eth.Output
+
+	// PrefetchAccounts hints that the state of the accounts in the block with the given hash is about to be read,
+	// so the trie nodes of the accounts can be prepared in a single batch, instead of one node at a time.
+	PrefetchAccounts(blockHash common.Hash, accounts []common.Address)
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -102,3 +106,7 @@ func (p *PreimageOracle) OutputByRoot(l2OutputRoot common.Hash) eth.Output {
 	}
 	return output
 }
+
+func (p *PreimageOracle) PrefetchAccounts(blockHash common.Hash, accounts []common.Address) {
+	p.hint.Hint(AccountProofsHint{BlockHash: blockHash, Accounts: accounts})
+}
@@ -140,3 +140,13 @@ func TestPreimageOracleOutputByRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestPreimageOraclePrefetchAccounts(t *testing.T) {
+	po, hints, _ := mockPreimageOracle(t)
+	blockHash := common.Hash{0xaa}
+	accounts := []common.Address{{0x01}, {0x02}}
+	expected := HintL2AccountProofs + " " + hexutil.Encode(append(append(blockHash.Bytes(), accounts[0][:]...), accounts[1][:]...))
+	hints.On("hint", expected).Once().Return()
+	po.PrefetchAccounts(blockHash, accounts)
+	hints.AssertExpectations(t)
+}
@@ -67,6 +67,9 @@ func (o StubBlockOracle) OutputByRoot(root common.Hash) eth.Output {
 	return output
 }
 
+func (o StubBlockOracle) PrefetchAccounts(blockHash common.Hash, accounts []common.Address) {
+}
+
 // KvStateOracle loads data from a source ethdb.KeyValueStore
 type KvStateOracle struct {
 	t      *testing.T
@@ -2,13 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 
@@ -119,6 +123,46 @@ func TestDataDir(t *testing.T) {
 	require.Equal(t, expected, cfg.DataDir)
 }
 
+func TestDataFormat(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, types.DataFormatFile, cfg.DataFormat)
+	})
+
+	for _, format := range types.SupportedDataFormats {
+		format := format
+		t.Run(fmt.Sprintf("Valid-%v", format), func(t *testing.T) {
+			cfg := configForArgs(t, addRequiredArgs("--data.format", string(format)))
+			require.Equal(t, format, cfg.DataFormat)
+		})
+	}
+}
+
+func TestMigrateKV(t *testing.T) {
+	dir := t.TempDir()
+	src := kvstore.NewDiskKV(filepath.Join(dir, "src"))
+	require.NoError(t, src.Put(common.Hash{0xaa}, []byte("hello world")))
+
+	_, _, err := runWithArgs([]string{"migrate-kv", "--from.datadir", filepath.Join(dir, "src"), "--to.datadir", filepath.Join(dir, "dst"), "--to.format", "archive"})
+	require.NoError(t, err)
+	dst, err := kvstore.NewArchiveKV(filepath.Join(dir, "dst", kvstore.ArchiveFileName))
+	require.NoError(t, err)
+	defer dst.Close()
+	dat, err := dst.Get(common.Hash{0xaa})
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(dat))
+
+	t.Run("RejectInvalidFormat", func(t *testing.T) {
+		_, _, err := runWithArgs([]string{"migrate-kv", "--from.datadir", filepath.Join(dir, "src"), "--to.datadir", filepath.Join(dir, "other"), "--to.format", "foo"})
+		require.ErrorContains(t, err, "invalid data format: foo")
+	})
+
+	t.Run("RejectSameDir", func(t *testing.T) {
+		_, _, err := runWithArgs([]string{"migrate-kv", "--from.datadir", filepath.Join(dir, "src"), "--to.datadir", filepath.Join(dir, "src"), "--to.format", "pebble"})
+		require.ErrorContains(t, err, "to itself")
+	})
+}
+
 func TestL2(t *testing.T) {
 	expected := "https://example.com:8545"
 	cfg := configForArgs(t, addRequiredArgs("--l2", expected))
@@ -307,6 +351,29 @@ func TestServerMode(t *testing.T) {
 	})
 }
 
+func TestBundleExport(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.BundleExportPath)
+	})
+	t.Run("Set", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--bundle.export", "./bundle"))
+		require.Equal(t, "./bundle", cfg.BundleExportPath)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("RequireBundle", func(t *testing.T) {
+		verifyArgsInvalid(t, "Required flag \"bundle\" not set", []string{"replay"})
+	})
+	t.Run("RejectExecAndServerMode", func(t *testing.T) {
+		verifyArgsInvalid(t, config.ErrNoExecInServerMode.Error(), []string{"replay", "--bundle", "./bundle", "--exec", "echo", "--server"})
+	})
+	t.Run("RejectMissingBundle", func(t *testing.T) {
+		verifyArgsInvalid(t, "failed to open archive", []string{"replay", "--bundle", filepath.Join(t.TempDir(), "bundle")})
+	})
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := runWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
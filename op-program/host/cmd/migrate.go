@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	openum "github.com/ethereum-optimism/optimism/op-service/enum"
+)
+
+var (
+	migrateFromDataDir = &cli.StringFlag{
+		Name:     "from.datadir",
+		Usage:    "Directory of the preimage data to migrate",
+		Required: true,
+	}
+	migrateFromDataFormat = &cli.StringFlag{
+		Name:  "from.format",
+		Usage: fmt.Sprintf("Format of the preimage data to migrate. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
+		Value: string(types.DataFormatFile),
+	}
+	migrateToDataDir = &cli.StringFlag{
+		Name:     "to.datadir",
+		Usage:    "Directory to write the migrated preimage data to",
+		Required: true,
+	}
+	migrateToDataFormat = &cli.StringFlag{
+		Name:     "to.format",
+		Usage:    fmt.Sprintf("Format to migrate the preimage data to. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
+		Required: true,
+	}
+)
+
+var migrateKVCommand = &cli.Command{
+	Name:  "migrate-kv",
+	Usage: "Copy preimage data to a store of another format",
+	Description: "Copies all preimages of the data directory to a new data directory with another format. " +
+		"Migrating to the archive format creates a single read-only file that can be shipped alongside a game.",
+	Flags: []cli.Flag{
+		migrateFromDataDir,
+		migrateFromDataFormat,
+		migrateToDataDir,
+		migrateToDataFormat,
+	},
+	Action: func(ctx *cli.Context) error {
+		logger, err := setupLogging(ctx)
+		if err != nil {
+			return err
+		}
+		fromFormat := types.DataFormat(ctx.String(migrateFromDataFormat.Name))
+		toFormat := types.DataFormat(ctx.String(migrateToDataFormat.Name))
+		for _, format := range []types.DataFormat{fromFormat, toFormat} {
+			if !types.ValidDataFormat(format) {
+				return fmt.Errorf("invalid data format: %s", format)
+			}
+		}
+		fromDir := ctx.String(migrateFromDataDir.Name)
+		toDir := ctx.String(migrateToDataDir.Name)
+		if fromDir == toDir {
+			return fmt.Errorf("can not migrate data directory %s to itself", fromDir)
+		}
+		logger.Info("Migrating preimages", "from", fromDir, "from_format", fromFormat, "to", toDir, "to_format", toFormat)
+		count, err := kvstore.Migrate(fromFormat, fromDir, toFormat, toDir)
+		if err != nil {
+			return fmt.Errorf("failed to migrate preimages: %w", err)
+		}
+		logger.Info("Migrated preimages", "count", count)
+		return nil
+	},
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-program/host"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+)
+
+var (
+	replayBundle = &cli.StringFlag{
+		Name:     "bundle",
+		Usage:    "Path of the pre-image bundle, exported with --bundle.export",
+		Required: true,
+	}
+	replayExec = &cli.StringFlag{
+		Name:  "exec",
+		Usage: "Run the specified client program as a separate process detached from the host. Default is to run the client program in the host process.",
+	}
+	replayServer = &cli.BoolFlag{
+		Name:  "server",
+		Usage: "Run in pre-image server mode, serving the pre-images of the bundle, without executing any client program.",
+	}
+)
+
+var replayCommand = &cli.Command{
+	Name:  "replay",
+	Usage: "Replay the fault proof program offline from a pre-image bundle",
+	Description: "Executes the fault proof program with only the pre-images of a bundle exported by a previous run, " +
+		"without any L1 or L2 RPC. The bundle includes the boot info of the program, so no other inputs are needed.",
+	Flags: []cli.Flag{
+		replayBundle,
+		replayExec,
+		replayServer,
+	},
+	Action: func(ctx *cli.Context) error {
+		logger, err := setupLogging(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info("Replaying fault proof program", "version", VersionWithMeta, "bundle", ctx.String(replayBundle.Name))
+		if ctx.Bool(replayServer.Name) && ctx.String(replayExec.Name) != "" {
+			return config.ErrNoExecInServerMode
+		}
+		if err := host.ReplayBundle(context.Background(), logger, ctx.String(replayBundle.Name), ctx.String(replayExec.Name), ctx.Bool(replayServer.Name)); err != nil {
+			return err
+		}
+		if !ctx.Bool(replayServer.Name) {
+			logger.Info("Claim successfully verified")
+		}
+		return nil
+	},
+}
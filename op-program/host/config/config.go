@@ -11,6 +11,8 @@ import (
 	opnode "github.com/ethereum-optimism/optimism/op-node"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -20,16 +22,20 @@ import (
 )
 
 var (
-	ErrMissingRollupConfig = errors.New("missing rollup config")
-	ErrMissingL2Genesis    = errors.New("missing l2 genesis")
-	ErrInvalidL1Head       = errors.New("invalid l1 head")
-	ErrInvalidL2Head       = errors.New("invalid l2 head")
-	ErrInvalidL2OutputRoot = errors.New("invalid l2 output root")
-	ErrL1AndL2Inconsistent = errors.New("l1 and l2 options must be specified together or both omitted")
-	ErrInvalidL2Claim      = errors.New("invalid l2 claim")
-	ErrInvalidL2ClaimBlock = errors.New("invalid l2 claim block number")
-	ErrDataDirRequired     = errors.New("datadir must be specified when in non-fetching mode")
-	ErrNoExecInServerMode  = errors.New("exec command must not be set when in server mode")
+	ErrMissingRollupConfig  = errors.New("missing rollup config")
+	ErrMissingL2Genesis     = errors.New("missing l2 genesis")
+	ErrInvalidL1Head        = errors.New("invalid l1 head")
+	ErrInvalidL2Head        = errors.New("invalid l2 head")
+	ErrInvalidL2OutputRoot  = errors.New("invalid l2 output root")
+	ErrL1AndL2Inconsistent  = errors.New("l1 and l2 options must be specified together or both omitted")
+	ErrInvalidL2Claim       = errors.New("invalid l2 claim")
+	ErrInvalidL2ClaimBlock  = errors.New("invalid l2 claim block number")
+	ErrDataDirRequired      = errors.New("datadir must be specified when in non-fetching mode")
+	ErrNoExecInServerMode   = errors.New("exec command must not be set when in server mode")
+	ErrInvalidDataFormat    = errors.New("invalid data format")
+	ErrReadOnlyDataFormat   = errors.New("data format is read-only and can not be used when fetching is enabled")
+	ErrNoBundleInServerMode = errors.New("pre-image bundle can not be exported when in server mode")
+	ErrAltDARequired        = errors.New("alt-DA must be enabled to fetch data of a chain that uses alt-DA")
 )
 
 type Config struct {
@@ -37,6 +43,8 @@ type Config struct {
 	// DataDir is the directory to read/write pre-image data from/to.
 	// If not set, an in-memory key-value store is used and fetching data must be enabled
 	DataDir string
+	// DataFormat specifies the format of the pre-image data in DataDir.
+	DataFormat types.DataFormat
 
 	// L1Head is the block has of the L1 chain head block
 	L1Head      common.Hash
@@ -45,6 +53,10 @@ type Config struct {
 	L1TrustRPC  bool
 	L1RPCKind   sources.RPCProviderKind
 
+	// AltDA configures the DA server to fetch the inputs of alt-DA commitments from.
+	// It must be enabled to fetch data of a chain that uses alt-DA.
+	AltDA altda.CLIConfig
+
 	// L2Head is the l2 block hash contained in the L2 Output referenced by the L2OutputRoot
 	// TODO(inphi): This can be made optional with hardcoded rollup configs and output oracle addresses by searching the oracle for the l2 output root
 	L2Head common.Hash
@@ -62,6 +74,10 @@ type Config struct {
 	// If unset, the fault proof client is run in the same process.
 	ExecCmd string
 
+	// BundleExportPath is the path to export the pre-images read by the client program to, after it completed.
+	// The bundle can be used to replay the program offline. No bundle is exported if unset.
+	BundleExportPath string
+
 	// ServerMode indicates that the program should run in pre-image server mode and wait for requests.
 	// No client program is run.
 	ServerMode bool
@@ -101,6 +117,21 @@ func (c *Config) Check() error {
 	if c.ServerMode && c.ExecCmd != "" {
 		return ErrNoExecInServerMode
 	}
+	if c.ServerMode && c.BundleExportPath != "" {
+		return ErrNoBundleInServerMode
+	}
+	if c.DataDir != "" && !types.ValidDataFormat(c.DataFormat) {
+		return fmt.Errorf("%w: %v", ErrInvalidDataFormat, c.DataFormat)
+	}
+	if c.DataDir != "" && c.DataFormat == types.DataFormatArchive && c.FetchingEnabled() {
+		return ErrReadOnlyDataFormat
+	}
+	if err := c.AltDA.Check(); err != nil {
+		return err
+	}
+	if c.Rollup.UseAltDA && c.FetchingEnabled() && !c.AltDA.Enabled {
+		return ErrAltDARequired
+	}
 	return nil
 }
 
@@ -131,6 +162,7 @@ func NewConfig(
 		L2ClaimBlockNumber:  l2ClaimBlockNum,
 		L1RPCKind:           sources.RPCKindStandard,
 		IsCustomChainConfig: isCustomConfig,
+		DataFormat:          types.DataFormatFile,
 	}
 }
 
@@ -187,6 +219,7 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 	return &Config{
 		Rollup:              rollupCfg,
 		DataDir:             ctx.String(flags.DataDir.Name),
+		DataFormat:          types.DataFormat(ctx.String(flags.DataFormat.Name)),
 		L2URL:               ctx.String(flags.L2NodeAddr.Name),
 		L2ChainConfig:       l2ChainConfig,
 		L2Head:              l2Head,
@@ -198,8 +231,10 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 		L1BeaconURL:         ctx.String(flags.L1BeaconAddr.Name),
 		L1TrustRPC:          ctx.Bool(flags.L1TrustRPC.Name),
 		L1RPCKind:           sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
+		AltDA:               altda.ReadCLIConfig(ctx),
 		ExecCmd:             ctx.String(flags.Exec.Name),
 		ServerMode:          ctx.Bool(flags.Server.Name),
+		BundleExportPath:    ctx.String(flags.BundleExport.Name),
 		IsCustomChainConfig: isCustomConfig,
 	}, nil
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
@@ -160,6 +162,63 @@ func TestRejectExecAndServerMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoExecInServerMode)
 }
 
+func TestRejectBundleExportAndServerMode(t *testing.T) {
+	cfg := validConfig()
+	cfg.BundleExportPath = "bundle"
+	require.NoError(t, cfg.Check())
+	cfg.ServerMode = true
+	require.ErrorIs(t, cfg.Check(), ErrNoBundleInServerMode)
+}
+
+func TestDataFormat(t *testing.T) {
+	t.Run("RejectInvalid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataFormat = "foo"
+		require.ErrorIs(t, cfg.Check(), ErrInvalidDataFormat)
+	})
+	t.Run("IgnoredWithoutDataDir", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataDir = ""
+		cfg.DataFormat = "foo"
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("RejectArchiveWhenFetching", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataFormat = types.DataFormatArchive
+		require.NoError(t, cfg.Check())
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		require.ErrorIs(t, cfg.Check(), ErrReadOnlyDataFormat)
+	})
+}
+
+func TestAltDA(t *testing.T) {
+	altDARollupConfig := *validRollupConfig
+	altDARollupConfig.UseAltDA = true
+	altDARollupConfig.AltDAChallengeAddress = common.Address{0xda}
+	altDARollupConfig.AltDAChallengeWindow = 100
+	altDARollupConfig.AltDAResolveWindow = 100
+	altDARollupConfig.AltDAChallengeBond = big.NewInt(1)
+
+	t.Run("RequiredWhenFetching", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Rollup = &altDARollupConfig
+		require.NoError(t, cfg.Check())
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		require.ErrorIs(t, cfg.Check(), ErrAltDARequired)
+		cfg.AltDA = altda.CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("RejectMissingDAServer", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.AltDA = altda.CLIConfig{Enabled: true}
+		require.ErrorIs(t, cfg.Check(), altda.ErrNoDAServer)
+	})
+}
+
 func TestIsCustomChainConfig(t *testing.T) {
 	t.Run("nonCustom", func(t *testing.T) {
 		cfg := validConfig()
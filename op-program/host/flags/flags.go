@@ -7,7 +7,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	service "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
@@ -35,6 +37,12 @@ var (
 		Usage:   "Directory to use for preimage data storage. Default uses in-memory storage",
 		EnvVars: prefixEnvVars("DATADIR"),
 	}
+	DataFormat = &cli.StringFlag{
+		Name:    "data.format",
+		Usage:   fmt.Sprintf("Format to use for preimage data storage. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
+		EnvVars: prefixEnvVars("DATA_FORMAT"),
+		Value:   string(types.DataFormatFile),
+	}
 	L2NodeAddr = &cli.StringFlag{
 		Name:    "l2",
 		Usage:   "Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)",
@@ -106,6 +114,11 @@ var (
 		Usage:   "Run in pre-image server mode without executing any client program.",
 		EnvVars: prefixEnvVars("SERVER"),
 	}
+	BundleExport = &cli.StringFlag{
+		Name:    "bundle.export",
+		Usage:   "Path to export the pre-images read by the client program to, once it completed. The bundle can be replayed offline with the replay command.",
+		EnvVars: prefixEnvVars("BUNDLE_EXPORT"),
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
@@ -123,6 +136,7 @@ var programFlags = []cli.Flag{
 	RollupConfig,
 	Network,
 	DataDir,
+	DataFormat,
 	L2NodeAddr,
 	L2GenesisPath,
 	L1NodeAddr,
@@ -131,12 +145,14 @@ var programFlags = []cli.Flag{
 	L1RPCProviderKind,
 	Exec,
 	Server,
+	BundleExport,
 }
 
 func init() {
 	Flags = append(Flags, oplog.CLIFlags(EnvVarPrefix)...)
 	Flags = append(Flags, requiredFlags...)
 	Flags = append(Flags, programFlags...)
+	Flags = append(Flags, altda.CLIFlags(EnvVarPrefix)...)
 }
 
 func CheckRequired(ctx *cli.Context) error {
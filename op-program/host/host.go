@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	cl "github.com/ethereum-optimism/optimism/op-program/client"
+	cldr "github.com/ethereum-optimism/optimism/op-program/client/driver"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
@@ -27,6 +28,7 @@ import (
 type L2Source struct {
 	*L2Client
 	*sources.DebugClient
+	*L2BatchClient
 }
 
 func Main(logger log.Logger, cfg *config.Config) error {
@@ -52,6 +54,52 @@ func Main(logger log.Logger, cfg *config.Config) error {
 
 // FaultProofProgram is the programmatic entry-point for the fault proof program
 func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Config) error {
+	var recorder *kvstore.MemKV
+	if cfg.BundleExportPath != "" {
+		recorder = kvstore.NewMemKV()
+	}
+	err := runProgram(ctx, logger, cfg.ExecCmd, func(pHostRW oppio.FileChannel, hHostRW oppio.FileChannel) error {
+		return preimageServer(ctx, logger, cfg, pHostRW, hHostRW, recorder)
+	})
+	// The pre-image server has stopped once the program completes, so all read pre-images are recorded.
+	if recorder != nil && programCompleted(err) {
+		logger.Info("Exporting pre-image bundle", "path", cfg.BundleExportPath)
+		if exportErr := kvstore.WriteArchive(cfg.BundleExportPath, recorder); exportErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to export pre-image bundle: %w", exportErr))
+		}
+	}
+	return err
+}
+
+// ReplayBundle executes the fault proof program offline, with only the pre-images of a bundle exported by a previous run.
+// In server mode, it serves the pre-images of the bundle to a client program executed by the caller instead.
+func ReplayBundle(ctx context.Context, logger log.Logger, bundlePath string, execCmd string, serverMode bool) error {
+	if serverMode {
+		return BundleServer(logger, bundlePath, cl.CreatePreimageChannel(), cl.CreateHinterChannel())
+	}
+	// Open the bundle before starting the client program, so it can't fail after the program started.
+	bundle, err := kvstore.NewArchiveKV(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to open pre-image bundle: %w", err)
+	}
+	defer bundle.Close()
+	return runProgram(ctx, logger, execCmd, func(pHostRW oppio.FileChannel, hHostRW oppio.FileChannel) error {
+		return serveBundle(logger, bundle, pHostRW, hHostRW)
+	})
+}
+
+// programCompleted returns true if the program ran to completion, whether the claim was valid or not.
+func programCompleted(err error) bool {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// the client program exits with code 1 if the claim is invalid
+		return exitErr.ExitCode() == 1
+	}
+	return err == nil || errors.Is(err, cldr.ErrClaimNotValid)
+}
+
+// runProgram runs the client program, in a separate process if execCmd is set, with a pre-image server started by serve.
+func runProgram(ctx context.Context, logger log.Logger, execCmd string, serve func(pHostRW oppio.FileChannel, hHostRW oppio.FileChannel) error) error {
 	var (
 		serverErr chan error
 		pClientRW oppio.FileChannel
@@ -88,12 +136,12 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 	serverErr = make(chan error)
 	go func() {
 		defer close(serverErr)
-		serverErr <- PreimageServer(ctx, logger, cfg, pHostRW, hHostRW)
+		serverErr <- serve(pHostRW, hHostRW)
 	}()
 
 	var cmd *exec.Cmd
-	if cfg.ExecCmd != "" {
-		cmd = exec.CommandContext(ctx, cfg.ExecCmd)
+	if execCmd != "" {
+		cmd = exec.CommandContext(ctx, execCmd)
 		cmd.ExtraFiles = make([]*os.File, cl.MaxFd-3) // not including stdin, stdout and stderr
 		cmd.ExtraFiles[cl.HClientRFd-3] = hClientRW.Reader()
 		cmd.ExtraFiles[cl.HClientWFd-3] = hClientRW.Writer()
@@ -121,31 +169,32 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 // If either returns an error both handlers are stopped.
 // The supplied preimageChannel and hintChannel will be closed before this function returns.
 func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel) error {
-	var serverDone chan error
-	var hinterDone chan error
-	defer func() {
-		preimageChannel.Close()
-		hintChannel.Close()
-		if serverDone != nil {
-			// Wait for pre-image server to complete
-			<-serverDone
-		}
-		if hinterDone != nil {
-			// Wait for hinter to complete
-			<-hinterDone
-		}
-	}()
+	return preimageServer(ctx, logger, cfg, preimageChannel, hintChannel, nil)
+}
+
+// preimageServer is PreimageServer, and additionally puts every served pre-image in recorder if it is not nil.
+func preimageServer(ctx context.Context, logger log.Logger, cfg *config.Config, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel, recorder kvstore.KV) error {
 	logger.Info("Starting preimage server")
 	var kv kvstore.KV
 	if cfg.DataDir == "" {
 		logger.Info("Using in-memory storage")
 		kv = kvstore.NewMemKV()
 	} else {
-		logger.Info("Creating disk storage", "datadir", cfg.DataDir)
-		if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
-			return fmt.Errorf("creating datadir: %w", err)
+		logger.Info("Creating disk storage", "datadir", cfg.DataDir, "format", cfg.DataFormat)
+		diskKV, err := kvstore.NewKV(cfg.DataFormat, cfg.DataDir)
+		if err != nil {
+			closeChannels(preimageChannel, hintChannel)
+			return fmt.Errorf("creating kv store: %w", err)
+		}
+		if closer, ok := diskKV.(io.Closer); ok {
+			// Only close the kv store once the server stopped and nothing uses it anymore
+			defer func() {
+				if err := closer.Close(); err != nil {
+					logger.Error("Failed to close kv store", "err", err)
+				}
+			}()
 		}
-		kv = kvstore.NewDiskKV(cfg.DataDir)
+		kv = diskKV
 	}
 
 	var (
@@ -155,6 +204,7 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 	if cfg.FetchingEnabled() {
 		prefetch, err := makePrefetcher(ctx, logger, kv, cfg)
 		if err != nil {
+			closeChannels(preimageChannel, hintChannel)
 			return fmt.Errorf("failed to create prefetcher: %w", err)
 		}
 		getPreimage = func(key common.Hash) ([]byte, error) { return prefetch.GetPreimage(ctx, key) }
@@ -162,18 +212,48 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 	} else {
 		logger.Info("Using offline mode. All required pre-images must be pre-populated.")
 		getPreimage = kv.Get
-		hinter = func(hint string) error {
-			logger.Debug("ignoring prefetch hint", "hint", hint)
-			return nil
-		}
+		hinter = ignoreHints(logger)
 	}
 
 	localPreimageSource := kvstore.NewLocalPreimageSource(cfg)
 	splitter := kvstore.NewPreimageSourceSplitter(localPreimageSource.Get, getPreimage)
 	preimageGetter := preimage.WithVerification(splitter.Get)
+	if recorder != nil {
+		preimageGetter = recordPreimages(preimageGetter, recorder)
+	}
+	return serve(logger, preimageChannel, hintChannel, preimageGetter, hinter)
+}
+
+// BundleServer serves the pre-images of a bundle, exported by a previous run of the program, without fetching any data.
+// Like PreimageServer, it blocks until both the hinter and preimage handlers complete,
+// and closes the supplied channels before it returns.
+func BundleServer(logger log.Logger, bundlePath string, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel) error {
+	logger.Info("Starting preimage server from bundle", "bundle", bundlePath)
+	bundle, err := kvstore.NewArchiveKV(bundlePath)
+	if err != nil {
+		closeChannels(preimageChannel, hintChannel)
+		return fmt.Errorf("failed to open pre-image bundle: %w", err)
+	}
+	defer bundle.Close()
+	return serveBundle(logger, bundle, preimageChannel, hintChannel)
+}
+
+func serveBundle(logger log.Logger, bundle kvstore.KV, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel) error {
+	return serve(logger, preimageChannel, hintChannel, preimage.WithVerification(func(key [32]byte) ([]byte, error) { return bundle.Get(key) }), ignoreHints(logger))
+}
 
-	serverDone = launchOracleServer(logger, preimageChannel, preimageGetter)
-	hinterDone = routeHints(logger, hintChannel, hinter)
+// serve handles pre-image requests with getter and hints with hinter, until either handler stops.
+// Both handlers are stopped, and the channels are closed, before it returns.
+func serve(logger log.Logger, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel, getter preimage.PreimageGetter, hinter preimage.HintHandler) error {
+	serverDone := launchOracleServer(logger, preimageChannel, getter)
+	hinterDone := routeHints(logger, hintChannel, hinter)
+	defer func() {
+		closeChannels(preimageChannel, hintChannel)
+		// Wait for pre-image server to complete
+		<-serverDone
+		// Wait for hinter to complete
+		<-hinterDone
+	}()
 	select {
 	case err := <-serverDone:
 		return err
@@ -182,6 +262,32 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 	}
 }
 
+func closeChannels(preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel) {
+	preimageChannel.Close()
+	hintChannel.Close()
+}
+
+func ignoreHints(logger log.Logger) preimage.HintHandler {
+	return func(hint string) error {
+		logger.Debug("ignoring prefetch hint", "hint", hint)
+		return nil
+	}
+}
+
+// recordPreimages puts every pre-image that is successfully retrieved with getter in recorder.
+func recordPreimages(getter preimage.PreimageGetter, recorder kvstore.KV) preimage.PreimageGetter {
+	return func(key [32]byte) ([]byte, error) {
+		v, err := getter(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := recorder.Put(key, v); err != nil {
+			return nil, fmt.Errorf("failed to record pre-image %x: %w", key, err)
+		}
+		return v, nil
+	}
+}
+
 func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *config.Config) (*prefetcher.Prefetcher, error) {
 	logger.Info("Connecting to L1 node", "l1", cfg.L1URL)
 	l1RPC, err := client.NewRPC(ctx, logger, cfg.L1URL, client.WithDialBackoff(10))
@@ -207,8 +313,17 @@ func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *
 	if err != nil {
 		return nil, fmt.Errorf("failed to create L2 client: %w", err)
 	}
-	l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
-	return prefetcher.NewPrefetcher(logger, l1Cl, l1BlobFetcher, l2DebugCl, kv), nil
+	l2DebugCl := &L2Source{
+		L2Client:      l2Cl,
+		DebugClient:   sources.NewDebugClient(l2RPC.CallContext),
+		L2BatchClient: NewL2BatchClient(l2RPC, l2BatchSize, l2BatchConcurrency),
+	}
+	var altDAFetcher prefetcher.AltDASource
+	if cfg.AltDA.Enabled {
+		logger.Info("Using DA server", "url", cfg.AltDA.DAServerURL)
+		altDAFetcher = cfg.AltDA.NewDAClient()
+	}
+	return prefetcher.NewPrefetcher(logger, l1Cl, l1BlobFetcher, altDAFetcher, l2DebugCl, kv), nil
 }
 
 func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHandler) chan error {
@@ -3,6 +3,9 @@ package host
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,12 +13,14 @@ import (
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/client"
+	cldr "github.com/ethereum-optimism/optimism/op-program/client/driver"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-program/io"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +60,71 @@ func TestServerMode(t *testing.T) {
 	require.ErrorIs(t, waitFor(result), kvstore.ErrNotFound)
 }
 
+func TestExportAndReplayBundle(t *testing.T) {
+	dir := t.TempDir()
+	l1Head := common.Hash{0x11}
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, l1Head, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = filepath.Join(dir, "data")
+	data := []byte("hello world")
+	dataKey := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	unreadData := []byte("unread")
+	kv := kvstore.NewDiskKV(cfg.DataDir)
+	require.NoError(t, kv.Put(dataKey.PreimageKey(), data))
+	require.NoError(t, kv.Put(preimage.Keccak256Key(crypto.Keccak256Hash(unreadData)).PreimageKey(), unreadData))
+
+	// serves the pre-images with serve, and reads the l1 head and data from a client
+	readPreimages := func(serve func(preimageChannel io.FileChannel, hintChannel io.FileChannel) error) {
+		preimageServer, preimageClient, err := io.CreateBidirectionalChannel()
+		require.NoError(t, err)
+		hintServer, hintClient, err := io.CreateBidirectionalChannel()
+		require.NoError(t, err)
+		result := make(chan error)
+		go func() {
+			result <- serve(preimageServer, hintServer)
+		}()
+		pClient := preimage.NewOracleClient(preimageClient)
+		require.Equal(t, l1Head.Bytes(), pClient.Get(client.L1HeadLocalIndex))
+		require.Equal(t, data, pClient.Get(dataKey))
+		require.NoError(t, preimageClient.Close())
+		require.NoError(t, hintClient.Close())
+		require.NoError(t, waitFor(result))
+	}
+
+	logger := testlog.Logger(t, log.LevelTrace)
+	recorder := kvstore.NewMemKV()
+	readPreimages(func(preimageChannel io.FileChannel, hintChannel io.FileChannel) error {
+		return preimageServer(context.Background(), logger, cfg, preimageChannel, hintChannel, recorder)
+	})
+	bundlePath := filepath.Join(dir, "bundle")
+	require.NoError(t, kvstore.WriteArchive(bundlePath, recorder))
+
+	bundle, err := kvstore.NewArchiveKV(bundlePath)
+	require.NoError(t, err)
+	count := 0
+	require.NoError(t, bundle.ForEach(func(k common.Hash, v []byte) error {
+		count++
+		return nil
+	}))
+	require.NoError(t, bundle.Close())
+	require.Equal(t, 2, count, "bundle must only contain the read pre-images")
+
+	readPreimages(func(preimageChannel io.FileChannel, hintChannel io.FileChannel) error {
+		return BundleServer(logger, bundlePath, preimageChannel, hintChannel)
+	})
+}
+
+func TestProgramCompleted(t *testing.T) {
+	require.True(t, programCompleted(nil))
+	require.True(t, programCompleted(fmt.Errorf("wrapped: %w", cldr.ErrClaimNotValid)))
+	require.False(t, programCompleted(errors.New("boom")))
+
+	exitWith := func(code int) error {
+		return exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	}
+	require.True(t, programCompleted(fmt.Errorf("failed to wait for child program: %w", exitWith(1))), "claim is invalid")
+	require.False(t, programCompleted(fmt.Errorf("failed to wait for child program: %w", exitWith(2))), "program failed")
+}
+
 func waitFor(ch chan error) error {
 	timeout := time.After(30 * time.Second)
 	select {
@@ -0,0 +1,243 @@
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ArchiveFileName is the name of the archive file in a data directory with the archive format.
+const ArchiveFileName = "preimages.archive"
+
+// archiveMagic identifies archive files, and the version of their format.
+var archiveMagic = [8]byte{'O', 'P', 'P', 'I', 'M', 'G', 0, 1}
+
+const (
+	// archiveHeaderSize is the size of the magic and the number of pre-images.
+	archiveHeaderSize = 8 + 8
+	// archiveEntrySize is the size of an index entry: the key, and the offset and length of the value.
+	archiveEntrySize = common.HashLength + 8 + 8
+)
+
+// ArchiveKV is a read-only key-value store, with all pre-images in a single archive file,
+// so the pre-images needed to execute the program can be shipped alongside a game.
+//
+// The archive starts with a header of the archive magic and the number of pre-images, as big-endian uint64.
+// It's followed by an index of an entry per pre-image, in ascending order of the keys,
+// with the key, and the offset (from the start of the values) and the length of the value, as big-endian uint64.
+// The values follow the index. Pre-images are looked up with a binary search of the index,
+// so the archive doesn't need to be loaded in memory.
+// ArchiveKV is safe for concurrent use.
+type ArchiveKV struct {
+	f         *os.File
+	count     uint64
+	dataStart uint64
+	dataSize  uint64
+}
+
+// NewArchiveKV opens the archive file at the given path.
+func NewArchiveKV(path string) (*ArchiveKV, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", path, err)
+	}
+	kv, err := newArchiveKV(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("invalid archive %s: %w", path, err)
+	}
+	return kv, nil
+}
+
+func newArchiveKV(f *os.File) (*ArchiveKV, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var header [archiveHeaderSize]byte
+	if _, err := f.ReadAt(header[:], 0); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if !bytes.Equal(header[:8], archiveMagic[:]) {
+		return nil, fmt.Errorf("unexpected magic %x", header[:8])
+	}
+	count := binary.BigEndian.Uint64(header[8:])
+	size := uint64(info.Size())
+	if count > (size-archiveHeaderSize)/archiveEntrySize {
+		return nil, fmt.Errorf("index of %d pre-images exceeds the archive size %d", count, size)
+	}
+	dataStart := archiveHeaderSize + count*archiveEntrySize
+	return &ArchiveKV{f: f, count: count, dataStart: dataStart, dataSize: size - dataStart}, nil
+}
+
+func (a *ArchiveKV) Put(k common.Hash, v []byte) error {
+	return ErrReadOnly
+}
+
+func (a *ArchiveKV) Get(k common.Hash) ([]byte, error) {
+	var readErr error
+	i := sort.Search(int(a.count), func(i int) bool {
+		if readErr != nil {
+			return true
+		}
+		var key common.Hash
+		if _, err := a.f.ReadAt(key[:], archiveHeaderSize+int64(i)*archiveEntrySize); err != nil {
+			readErr = err
+			return true
+		}
+		return bytes.Compare(key[:], k[:]) >= 0
+	})
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read archive index: %w", readErr)
+	}
+	if uint64(i) == a.count {
+		return nil, ErrNotFound
+	}
+	key, offset, length, err := a.entry(uint64(i))
+	if err != nil {
+		return nil, err
+	}
+	if key != k {
+		return nil, ErrNotFound
+	}
+	return a.value(offset, length)
+}
+
+func (a *ArchiveKV) entry(i uint64) (key common.Hash, offset uint64, length uint64, err error) {
+	var entry [archiveEntrySize]byte
+	if _, err := a.f.ReadAt(entry[:], int64(archiveHeaderSize+i*archiveEntrySize)); err != nil {
+		return common.Hash{}, 0, 0, fmt.Errorf("failed to read archive index: %w", err)
+	}
+	copy(key[:], entry[:common.HashLength])
+	offset = binary.BigEndian.Uint64(entry[common.HashLength:])
+	length = binary.BigEndian.Uint64(entry[common.HashLength+8:])
+	return key, offset, length, nil
+}
+
+func (a *ArchiveKV) value(offset uint64, length uint64) ([]byte, error) {
+	if offset > a.dataSize || length > a.dataSize-offset {
+		return nil, fmt.Errorf("value at offset %d with length %d exceeds the archive", offset, length)
+	}
+	v := make([]byte, length)
+	if _, err := a.f.ReadAt(v, int64(a.dataStart+offset)); err != nil {
+		return nil, fmt.Errorf("failed to read archive value: %w", err)
+	}
+	return v, nil
+}
+
+func (a *ArchiveKV) ForEach(fn func(k common.Hash, v []byte) error) error {
+	for i := uint64(0); i < a.count; i++ {
+		key, offset, length, err := a.entry(i)
+		if err != nil {
+			return err
+		}
+		v, err := a.value(offset, length)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ArchiveKV) Close() error {
+	return a.f.Close()
+}
+
+var _ IterableKV = (*ArchiveKV)(nil)
+
+type archiveEntry struct {
+	key    common.Hash
+	offset uint64
+	length uint64
+}
+
+// WriteArchive writes all pre-images of src to a new archive file at the given path.
+// The values are buffered in a temporary file next to the archive, as the size of the index is only known
+// after all pre-images are read. The archive is only moved into place once it is complete.
+func WriteArchive(path string, src IterableKV) error {
+	dir := filepath.Dir(path)
+	data, err := openTempFile(dir, filepath.Base(path)+".data.*")
+	if err != nil {
+		return fmt.Errorf("failed to open temp file for archive values: %w", err)
+	}
+	defer os.Remove(data.Name())
+	defer data.Close()
+
+	var entries []archiveEntry
+	var offset uint64
+	dataBuf := bufio.NewWriter(data)
+	err = src.ForEach(func(k common.Hash, v []byte) error {
+		if _, err := dataBuf.Write(v); err != nil {
+			return err
+		}
+		entries = append(entries, archiveEntry{key: k, offset: offset, length: uint64(len(v))})
+		offset += uint64(len(v))
+		return nil
+	})
+	if err == nil {
+		err = dataBuf.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write archive values: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key[:], entries[j].key[:]) < 0
+	})
+	for i := 1; i < len(entries); i++ {
+		if entries[i].key == entries[i-1].key {
+			return fmt.Errorf("duplicate pre-image %s", entries[i].key)
+		}
+	}
+
+	f, err := openTempFile(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to open temp file for archive: %w", err)
+	}
+	defer os.Remove(f.Name()) // Clean up the temp file if it doesn't actually get moved into place
+	if err := writeArchive(f, entries, data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close temp archive file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to move temp archive %v to final destination %v: %w", f.Name(), path, err)
+	}
+	return nil
+}
+
+func writeArchive(f *os.File, entries []archiveEntry, data *os.File) error {
+	var header [archiveHeaderSize]byte
+	copy(header[:], archiveMagic[:])
+	binary.BigEndian.PutUint64(header[8:], uint64(len(entries)))
+	if _, err := f.Write(header[:]); err != nil {
+		return err
+	}
+	index := make([]byte, 0, len(entries)*archiveEntrySize)
+	for _, e := range entries {
+		index = append(index, e.key[:]...)
+		index = binary.BigEndian.AppendUint64(index, e.offset)
+		index = binary.BigEndian.AppendUint64(index, e.length)
+	}
+	if _, err := f.Write(index); err != nil {
+		return err
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, data); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package kvstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveKV(t *testing.T) {
+	src := NewMemKV()
+	for i := 0; i < 100; i++ {
+		v := make([]byte, i)
+		for j := range v {
+			v[j] = byte(i)
+		}
+		require.NoError(t, src.Put(crypto.Keccak256Hash(v), v))
+	}
+	path := filepath.Join(t.TempDir(), ArchiveFileName)
+	require.NoError(t, WriteArchive(path, src))
+	kv, err := NewArchiveKV(path)
+	require.NoError(t, err)
+	defer kv.Close()
+
+	t.Run("Get", func(t *testing.T) {
+		require.NoError(t, src.ForEach(func(k common.Hash, v []byte) error {
+			dat, err := kv.Get(k)
+			require.NoError(t, err)
+			require.Equal(t, v, dat)
+			return nil
+		}))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		for _, k := range []common.Hash{{}, {0x01}, {0xff, 0xff}} {
+			_, err := kv.Get(k)
+			require.ErrorIs(t, err, ErrNotFound)
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		require.ErrorIs(t, kv.Put(common.Hash{0xaa}, []byte{1}), ErrReadOnly)
+	})
+
+	t.Run("ForEach", func(t *testing.T) {
+		count := 0
+		require.NoError(t, kv.ForEach(func(k common.Hash, v []byte) error {
+			count++
+			require.Equal(t, crypto.Keccak256Hash(v), k)
+			return nil
+		}))
+		require.Equal(t, 100, count)
+	})
+}
+
+func TestEmptyArchiveKV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ArchiveFileName)
+	require.NoError(t, WriteArchive(path, NewMemKV()))
+	kv, err := NewArchiveKV(path)
+	require.NoError(t, err)
+	defer kv.Close()
+	_, err = kv.Get(common.Hash{})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestInvalidArchiveKV(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("Missing", func(t *testing.T) {
+		_, err := NewArchiveKV(filepath.Join(dir, "missing"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("Magic", func(t *testing.T) {
+		path := filepath.Join(dir, "magic")
+		require.NoError(t, os.WriteFile(path, make([]byte, archiveHeaderSize), 0644))
+		_, err := NewArchiveKV(path)
+		require.ErrorContains(t, err, "unexpected magic")
+	})
+	t.Run("Truncated", func(t *testing.T) {
+		src := NewMemKV()
+		require.NoError(t, src.Put(common.Hash{0xaa}, []byte("hello")))
+		path := filepath.Join(dir, "truncated")
+		require.NoError(t, WriteArchive(path, src))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data[:archiveHeaderSize+archiveEntrySize-1], 0644))
+		_, err = NewArchiveKV(path)
+		require.ErrorContains(t, err, "exceeds the archive size")
+	})
+}
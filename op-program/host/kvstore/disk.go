@@ -7,9 +7,11 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // read/write mode for user/group/other, not executable.
@@ -90,4 +92,30 @@ func (d *DiskKV) Get(k common.Hash) ([]byte, error) {
 	return hex.DecodeString(string(dat))
 }
 
-var _ KV = (*DiskKV)(nil)
+func (d *DiskKV) ForEach(fn func(k common.Hash, v []byte) error) error {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to list pre-images: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		// skip temp files and any other files that are not pre-images
+		if entry.IsDir() || len(name) != len("0x")+common.HashLength*2+len(".txt") || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		key, err := hexutil.Decode(strings.TrimSuffix(name, ".txt"))
+		if err != nil {
+			continue
+		}
+		v, err := d.Get(common.BytesToHash(key))
+		if err != nil {
+			return err
+		}
+		if err := fn(common.BytesToHash(key), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ IterableKV = (*DiskKV)(nil)
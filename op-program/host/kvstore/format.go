@@ -0,0 +1,85 @@
+package kvstore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NewKV opens the pre-image store of the given format in the data directory.
+// The directory is created if it doesn't exist yet, except for the read-only archive format.
+// The returned store implements io.Closer if it must be closed after use.
+func NewKV(format types.DataFormat, dataDir string) (IterableKV, error) {
+	switch format {
+	case types.DataFormatFile:
+		if err := os.MkdirAll(dataDir, 0755); err != nil {
+			return nil, fmt.Errorf("creating datadir: %w", err)
+		}
+		return NewDiskKV(dataDir), nil
+	case types.DataFormatPebble:
+		if err := os.MkdirAll(dataDir, 0755); err != nil {
+			return nil, fmt.Errorf("creating datadir: %w", err)
+		}
+		return NewPebbleKV(dataDir)
+	case types.DataFormatArchive:
+		return NewArchiveKV(filepath.Join(dataDir, ArchiveFileName))
+	default:
+		return nil, fmt.Errorf("invalid data format: %s", format)
+	}
+}
+
+// Migrate copies all pre-images of the store in srcDir to a store of another format in dstDir,
+// and returns the number of copied pre-images.
+func Migrate(srcFormat types.DataFormat, srcDir string, dstFormat types.DataFormat, dstDir string) (int, error) {
+	src, err := NewKV(srcFormat, srcDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open source: %w", err)
+	}
+	if closer, ok := src.(io.Closer); ok {
+		defer closer.Close()
+	}
+	count := 0
+	counted := &countingKV{IterableKV: src, count: &count}
+	if dstFormat == types.DataFormatArchive {
+		if err := os.MkdirAll(dstDir, 0755); err != nil {
+			return 0, fmt.Errorf("creating datadir: %w", err)
+		}
+		if err := WriteArchive(filepath.Join(dstDir, ArchiveFileName), counted); err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+	dst, err := NewKV(dstFormat, dstDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open destination: %w", err)
+	}
+	err = counted.ForEach(func(k common.Hash, v []byte) error {
+		return dst.Put(k, v)
+	})
+	if closer, ok := dst.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close destination: %w", closeErr)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy pre-images: %w", err)
+	}
+	return count, nil
+}
+
+// countingKV counts the pre-images that are iterated over.
+type countingKV struct {
+	IterableKV
+	count *int
+}
+
+func (c *countingKV) ForEach(fn func(k common.Hash, v []byte) error) error {
+	return c.IterableKV.ForEach(func(k common.Hash, v []byte) error {
+		*c.count++
+		return fn(k, v)
+	})
+}
@@ -0,0 +1,54 @@
+package kvstore
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	expected := map[common.Hash][]byte{
+		{0xaa}: []byte("hello world"),
+		{0xbb}: {},
+		{}:     {4, 2},
+	}
+	src := NewDiskKV(filepath.Join(dir, "file"))
+	for k, v := range expected {
+		require.NoError(t, src.Put(k, v))
+	}
+
+	// Migrate through all formats and back to the file format
+	formats := []types.DataFormat{types.DataFormatFile, types.DataFormatPebble, types.DataFormatArchive, types.DataFormatFile}
+	for i := 1; i < len(formats); i++ {
+		srcDir := filepath.Join(dir, string(formats[i-1]))
+		dstDir := filepath.Join(dir, string(formats[i]))
+		if i == len(formats)-1 {
+			dstDir = filepath.Join(dir, "roundtrip")
+		}
+		count, err := Migrate(formats[i-1], srcDir, formats[i], dstDir)
+		require.NoError(t, err)
+		require.Equal(t, len(expected), count)
+
+		kv, err := NewKV(formats[i], dstDir)
+		require.NoError(t, err)
+		actual := make(map[common.Hash][]byte)
+		require.NoError(t, kv.ForEach(func(k common.Hash, v []byte) error {
+			actual[k] = append([]byte{}, v...)
+			return nil
+		}))
+		require.Equal(t, expected, actual, "migrated %v to %v", formats[i-1], formats[i])
+		if closer, ok := kv.(io.Closer); ok {
+			require.NoError(t, closer.Close())
+		}
+	}
+}
+
+func TestNewKVInvalidFormat(t *testing.T) {
+	_, err := NewKV("foo", t.TempDir())
+	require.ErrorContains(t, err, "invalid data format")
+}
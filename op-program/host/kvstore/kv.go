@@ -6,8 +6,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// ErrNotFound is returned when a pre-image cannot be found in the KV store.
-var ErrNotFound = errors.New("not found")
+var (
+	// ErrNotFound is returned when a pre-image cannot be found in the KV store.
+	ErrNotFound = errors.New("not found")
+	// ErrReadOnly is returned when a pre-image is put into a read-only KV store.
+	ErrReadOnly = errors.New("read-only key-value store")
+)
 
 // KV is a Key-Value store interface for pre-image data.
 type KV interface {
@@ -20,3 +24,13 @@ type KV interface {
 	// KV store implementations may return additional errors specific to the KV storage.
 	Get(k common.Hash) ([]byte, error)
 }
+
+// IterableKV is a KV store that can enumerate all of its pre-images, to migrate them to another KV store.
+type IterableKV interface {
+	KV
+
+	// ForEach calls fn with every pre-image in the key-value store, in no particular order,
+	// and stops at the first error returned by fn.
+	// The value passed to fn is only valid until fn returns, and fn must not modify the key-value store.
+	ForEach(fn func(k common.Hash, v []byte) error) error
+}
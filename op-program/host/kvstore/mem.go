@@ -14,7 +14,7 @@ type MemKV struct {
 	m map[common.Hash][]byte
 }
 
-var _ KV = (*MemKV)(nil)
+var _ IterableKV = (*MemKV)(nil)
 
 func NewMemKV() *MemKV {
 	return &MemKV{m: make(map[common.Hash][]byte)}
@@ -36,3 +36,14 @@ func (m *MemKV) Get(k common.Hash) ([]byte, error) {
 	}
 	return v, nil
 }
+
+func (m *MemKV) ForEach(fn func(k common.Hash, v []byte) error) error {
+	m.RLock()
+	defer m.RUnlock()
+	for k, v := range m.m {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
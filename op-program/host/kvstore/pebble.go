@@ -0,0 +1,85 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PebbleKV is a disk-backed key-value store, with all key-value pairs stored in a single pebble database.
+// Unlike DiskKV it doesn't create a file per pre-image, so it scales to the millions of pre-images of long-running hosts.
+// PebbleKV is safe for concurrent use with a single PebbleKV instance.
+// The database must not be opened by multiple PebbleKV instances at the same time.
+type PebbleKV struct {
+	db *pebble.DB
+}
+
+// NewPebbleKV creates a PebbleKV that puts/gets pre-images from the pebble database in the given directory path.
+// The database is created if it doesn't exist yet.
+func NewPebbleKV(path string) (*PebbleKV, error) {
+	opts := &pebble.Options{
+		Cache:                    pebble.NewCache(int64(32 * 1024 * 1024)),
+		MaxConcurrentCompactions: runtime.NumCPU,
+		Logger:                   pebbleLogger{},
+	}
+	db, err := pebble.Open(path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble db at %s: %w", path, err)
+	}
+	return &PebbleKV{db: db}, nil
+}
+
+func (d *PebbleKV) Put(k common.Hash, v []byte) error {
+	return d.db.Set(k.Bytes(), v, pebble.NoSync)
+}
+
+func (d *PebbleKV) Get(k common.Hash) ([]byte, error) {
+	dat, closer, err := d.db.Get(k.Bytes())
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get pre-image %s: %w", k, err)
+	}
+	// the value is only valid until the closer is closed
+	ret := make([]byte, len(dat))
+	copy(ret, dat)
+	_ = closer.Close()
+	return ret, nil
+}
+
+func (d *PebbleKV) ForEach(fn func(k common.Hash, v []byte) error) error {
+	iter, err := d.db.NewIter(nil)
+	if err != nil {
+		return fmt.Errorf("failed to iterate pre-images: %w", err)
+	}
+	defer iter.Close()
+	for iter.First(); iter.Valid(); iter.Next() {
+		if len(iter.Key()) != common.HashLength {
+			return fmt.Errorf("invalid pre-image key %x", iter.Key())
+		}
+		if err := fn(common.BytesToHash(iter.Key()), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// Close flushes the database to disk and closes it.
+func (d *PebbleKV) Close() error {
+	return d.db.Close()
+}
+
+// pebbleLogger discards the informational logs of pebble, which are too verbose for the host.
+type pebbleLogger struct{}
+
+func (pebbleLogger) Infof(format string, args ...interface{}) {}
+
+func (pebbleLogger) Fatalf(format string, args ...interface{}) {
+	pebble.DefaultLogger.Fatalf(format, args...)
+}
+
+var _ IterableKV = (*PebbleKV)(nil)
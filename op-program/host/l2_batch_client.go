@@ -0,0 +1,84 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// l2BatchSize is the maximum number of requests in a single batch RPC call.
+	l2BatchSize = 50
+	// l2BatchConcurrency is the number of batch RPC calls to have in flight at once.
+	l2BatchConcurrency = 4
+)
+
+// L2BatchClient fetches many pieces of L2 state at once,
+// with batched RPC requests that are split over concurrent workers.
+type L2BatchClient struct {
+	rpc         client.RPC
+	batchSize   int
+	concurrency int
+}
+
+func NewL2BatchClient(rpc client.RPC, batchSize int, concurrency int) *L2BatchClient {
+	return &L2BatchClient{
+		rpc:         rpc,
+		batchSize:   batchSize,
+		concurrency: concurrency,
+	}
+}
+
+// AccountProofs fetches the proofs of the accounts, in the state of the block with the given hash.
+func (c *L2BatchClient) AccountProofs(ctx context.Context, blockHash common.Hash, accounts []common.Address) ([]*eth.AccountResult, error) {
+	call := batching.NewIterativeBatchCall[common.Address, *eth.AccountResult](
+		accounts,
+		func(account common.Address) (*eth.AccountResult, rpc.BatchElem) {
+			out := new(eth.AccountResult)
+			return out, rpc.BatchElem{
+				Method: "eth_getProof",
+				Args:   []any{account, []common.Hash{}, blockHash},
+				Result: out,
+			}
+		},
+		c.rpc.BatchCallContext,
+		c.rpc.CallContext,
+		c.batchSize)
+	if err := fetchConcurrently(ctx, call, c.concurrency); err != nil {
+		return nil, fmt.Errorf("failed to fetch account proofs at block %s: %w", blockHash, err)
+	}
+	proofs, err := call.Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, proof := range proofs {
+		if proof.Address != accounts[i] {
+			return nil, fmt.Errorf("received proof of account %s, expected %s", proof.Address, accounts[i])
+		}
+	}
+	return proofs, nil
+}
+
+// fetchConcurrently completes the batch call with the given number of concurrent workers.
+func fetchConcurrently[K any, V any](ctx context.Context, call *batching.IterativeBatchCall[K, V], concurrency int) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < concurrency; i++ {
+		g.Go(func() error {
+			for {
+				if err := call.Fetch(ctx); err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+		})
+	}
+	return g.Wait()
+}
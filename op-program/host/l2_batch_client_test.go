@@ -0,0 +1,118 @@
+package host
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestL2BatchClientAccountProofs(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	blockHash := testutils.RandomHash(rng)
+	accounts := make([]common.Address, 120)
+	for i := range accounts {
+		accounts[i] = testutils.RandomAddress(rng)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		rpc := &stubProofRPC{t: t, blockHash: blockHash}
+		cl := NewL2BatchClient(rpc, 50, 4)
+		proofs, err := cl.AccountProofs(context.Background(), blockHash, accounts)
+		require.NoError(t, err)
+		require.Len(t, proofs, len(accounts))
+		for i, proof := range proofs {
+			require.Equal(t, accounts[i], proof.Address)
+			require.Equal(t, []hexutil.Bytes{accounts[i][:]}, proof.AccountProof)
+		}
+		total := 0
+		for _, size := range rpc.batchSizes {
+			require.LessOrEqual(t, size, 50)
+			total += size
+		}
+		require.Equal(t, len(accounts), total)
+	})
+
+	t.Run("NoAccounts", func(t *testing.T) {
+		rpc := &stubProofRPC{t: t, blockHash: blockHash}
+		cl := NewL2BatchClient(rpc, 50, 4)
+		proofs, err := cl.AccountProofs(context.Background(), blockHash, nil)
+		require.NoError(t, err)
+		require.Empty(t, proofs)
+		require.Empty(t, rpc.batchSizes)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		expectedErr := errors.New("boom")
+		rpc := &stubProofRPC{t: t, blockHash: blockHash, err: expectedErr}
+		cl := NewL2BatchClient(rpc, 50, 4)
+		_, err := cl.AccountProofs(context.Background(), blockHash, accounts)
+		require.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("WrongAccount", func(t *testing.T) {
+		rpc := &stubProofRPC{t: t, blockHash: blockHash, wrongAccount: true}
+		cl := NewL2BatchClient(rpc, 50, 4)
+		_, err := cl.AccountProofs(context.Background(), blockHash, accounts)
+		require.ErrorContains(t, err, "received proof of account")
+	})
+}
+
+// stubProofRPC serves eth_getProof requests with a proof of a single node, with the address as content.
+type stubProofRPC struct {
+	t            *testing.T
+	blockHash    common.Hash
+	err          error
+	wrongAccount bool
+
+	mu         sync.Mutex
+	batchSizes []int
+}
+
+func (s *stubProofRPC) Close() {}
+
+func (s *stubProofRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	elem := rpc.BatchElem{Method: method, Args: args, Result: result}
+	if err := s.BatchCallContext(ctx, []rpc.BatchElem{elem}); err != nil {
+		return err
+	}
+	return elem.Error
+}
+
+func (s *stubProofRPC) BatchCallContext(_ context.Context, b []rpc.BatchElem) error {
+	s.mu.Lock()
+	s.batchSizes = append(s.batchSizes, len(b))
+	s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	for _, elem := range b {
+		require.Equal(s.t, "eth_getProof", elem.Method)
+		require.Len(s.t, elem.Args, 3)
+		require.Equal(s.t, s.blockHash, elem.Args[2])
+		account := elem.Args[0].(common.Address)
+		out := elem.Result.(*eth.AccountResult)
+		out.Address = account
+		if s.wrongAccount {
+			out.Address = common.Address{}
+		}
+		out.AccountProof = []hexutil.Bytes{account[:]}
+	}
+	return nil
+}
+
+func (s *stubProofRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	panic("not supported")
+}
+
+var _ client.RPC = (*stubProofRPC)(nil)
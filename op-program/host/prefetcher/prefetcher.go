@@ -6,12 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1"
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -21,6 +23,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// batchHintTimeout limits how long the client waits for a batch hint to be prefetched.
+// The retrying sources keep retrying until the context is done, so without it a failing L2 RPC blocks the hint forever.
+const batchHintTimeout = 2 * time.Minute
+
 type L1Source interface {
 	InfoByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, error)
 	InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error)
@@ -32,38 +38,67 @@ type L1BlobSource interface {
 	GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error)
 }
 
+type AltDASource interface {
+	GetInput(ctx context.Context, comm altda.Keccak256Commitment) ([]byte, error)
+}
+
 type L2Source interface {
 	InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error)
 	NodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
 	CodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
 	OutputByRoot(ctx context.Context, root common.Hash) (eth.Output, error)
+	AccountProofs(ctx context.Context, blockHash common.Hash, accounts []common.Address) ([]*eth.AccountResult, error)
 }
 
 type Prefetcher struct {
 	logger        log.Logger
 	l1Fetcher     L1Source
 	l1BlobFetcher L1BlobSource
+	altDAFetcher  AltDASource
 	l2Fetcher     L2Source
 	lastHint      string
 	kvStore       kvstore.KV
+
+	batchHintTimeout time.Duration
 }
 
-func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
+// NewPrefetcher creates a Prefetcher. The altDAFetcher may be nil if the chain doesn't use alt-DA.
+func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, altDAFetcher AltDASource, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
 	return &Prefetcher{
 		logger:        logger,
 		l1Fetcher:     NewRetryingL1Source(logger, l1Fetcher),
 		l1BlobFetcher: NewRetryingL1BlobSource(logger, l1BlobFetcher),
+		altDAFetcher:  altDAFetcher,
 		l2Fetcher:     NewRetryingL2Source(logger, l2Fetcher),
 		kvStore:       kvStore,
+
+		batchHintTimeout: batchHintTimeout,
 	}
 }
 
 func (p *Prefetcher) Hint(hint string) error {
 	p.logger.Trace("Received hint", "hint", hint)
+	if isBatchHint(hint) {
+		// Batch hints prepare pre-images the client is about to request, rather than the next requested pre-image.
+		// They are fetched right away, while the client waits for the hint to be acknowledged.
+		// Any pre-image that fails to be fetched is fetched with its own hint later, so failures are only logged.
+		ctx, cancel := context.WithTimeout(context.Background(), p.batchHintTimeout)
+		defer cancel()
+		if err := p.prefetch(ctx, hint); err != nil {
+			p.logger.Warn("Failed to prefetch batch hint", "hint", hint, "err", err)
+		}
+		return nil
+	}
 	p.lastHint = hint
 	return nil
 }
 
+// isBatchHint returns true if the hint requests many pre-images at once.
+func isBatchHint(hint string) bool {
+	hintType, _, _ := strings.Cut(hint, " ")
+	return hintType == l2.HintL2AccountProofs
+}
+
 func (p *Prefetcher) GetPreimage(ctx context.Context, key common.Hash) ([]byte, error) {
 	p.logger.Trace("Pre-image requested", "key", key)
 	pre, err := p.kvStore.Get(key)
@@ -162,6 +197,19 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 			}
 		}
 		return nil
+	case l1.HintAltDAInput:
+		if len(hintBytes) != 32 {
+			return fmt.Errorf("invalid alt-DA input hint: %x", hint)
+		}
+		if p.altDAFetcher == nil {
+			return errors.New("alt-DA input requested, but no DA server is configured")
+		}
+		comm := altda.Keccak256Commitment(hintBytes)
+		input, err := p.altDAFetcher.GetInput(ctx, comm)
+		if err != nil {
+			return fmt.Errorf("failed to fetch alt-DA input %s: %w", comm, err)
+		}
+		return p.kvStore.Put(preimage.Keccak256Key(comm).PreimageKey(), input)
 	case l2.HintL2BlockHeader, l2.HintL2Transactions:
 		if len(hintBytes) != 32 {
 			return fmt.Errorf("invalid L2 header/tx hint: %x", hint)
@@ -200,6 +248,27 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 			return fmt.Errorf("failed to fetch L2 contract code %s: %w", hash, err)
 		}
 		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), code)
+	case l2.HintL2AccountProofs:
+		if len(hintBytes) < 32 || (len(hintBytes)-32)%common.AddressLength != 0 {
+			return fmt.Errorf("invalid L2 account proofs hint: %x", hint)
+		}
+		blockHash := common.Hash(hintBytes[:32])
+		accounts := make([]common.Address, 0, (len(hintBytes)-32)/common.AddressLength)
+		for i := 32; i < len(hintBytes); i += common.AddressLength {
+			accounts = append(accounts, common.BytesToAddress(hintBytes[i:i+common.AddressLength]))
+		}
+		proofs, err := p.l2Fetcher.AccountProofs(ctx, blockHash, accounts)
+		if err != nil {
+			return fmt.Errorf("failed to fetch L2 account proofs at block %s: %w", blockHash, err)
+		}
+		for _, proof := range proofs {
+			for _, node := range proof.AccountProof {
+				if err := p.kvStore.Put(preimage.Keccak256Key(crypto.Keccak256Hash(node)).PreimageKey(), node); err != nil {
+					return fmt.Errorf("failed to store node: %w", err)
+				}
+			}
+		}
+		return nil
 	case l2.HintL2Output:
 		if len(hintBytes) != 32 {
 			return fmt.Errorf("invalid L2 output hint: %x", hint)
@@ -4,12 +4,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
@@ -22,6 +25,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/altda"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
@@ -217,6 +221,36 @@ func TestFetchL1Blob(t *testing.T) {
 	})
 }
 
+type stubAltDASource map[altda.Keccak256Commitment][]byte
+
+func (s stubAltDASource) GetInput(_ context.Context, comm altda.Keccak256Commitment) ([]byte, error) {
+	input, ok := s[comm]
+	if !ok {
+		return nil, altda.ErrNotFound
+	}
+	return input, nil
+}
+
+func TestFetchAltDAInput(t *testing.T) {
+	input := []byte("some batcher data")
+	comm := altda.NewKeccak256Commitment(input)
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, l1Source, l1BlobSource, l2Cl, kv := createPrefetcher(t)
+		prefetcher := NewPrefetcher(testlog.Logger(t, log.LevelInfo), l1Source, l1BlobSource, stubAltDASource{comm: input}, l2Cl, kv)
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		require.Equal(t, input, oracle.GetAltDAInput(comm))
+	})
+
+	t.Run("NoDAServer", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+		require.NoError(t, prefetcher.Hint(l1.AltDAInputHint(comm).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(comm).PreimageKey())
+		require.ErrorContains(t, err, "no DA server is configured")
+	})
+}
+
 func TestFetchL2Block(t *testing.T) {
 	rng := rand.New(rand.NewSource(123))
 	block, rcpts := testutils.RandomBlock(rng, 10)
@@ -321,6 +355,69 @@ func TestFetchL2Code(t *testing.T) {
 	})
 }
 
+func TestFetchL2AccountProofs(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	blockHash := testutils.RandomHash(rng)
+	accounts := []common.Address{testutils.RandomAddress(rng), testutils.RandomAddress(rng)}
+	nodes := [][]byte{testutils.RandomData(rng, 30), testutils.RandomData(rng, 30), testutils.RandomData(rng, 30)}
+	proofs := []*eth.AccountResult{
+		{Address: accounts[0], AccountProof: []hexutil.Bytes{nodes[0], nodes[1]}},
+		{Address: accounts[1], AccountProof: []hexutil.Bytes{nodes[0], nodes[2]}},
+	}
+
+	t.Run("Prefetch", func(t *testing.T) {
+		prefetcher, _, _, l2Cl, _ := createPrefetcher(t)
+		l2Cl.ExpectAccountProofs(blockHash, accounts, proofs, nil)
+		defer l2Cl.MockDebugClient.AssertExpectations(t)
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		oracle.PrefetchAccounts(blockHash, accounts)
+		// All nodes are available without fetching them one by one
+		for _, node := range nodes {
+			require.EqualValues(t, node, oracle.NodeByHash(crypto.Keccak256Hash(node)))
+		}
+	})
+
+	t.Run("InvalidHint", func(t *testing.T) {
+		prefetcher, _, _, l2Cl, _ := createPrefetcher(t)
+		defer l2Cl.MockDebugClient.AssertExpectations(t)
+		// Batch hints are only an optimization, so invalid ones are ignored
+		require.NoError(t, prefetcher.Hint(l2.HintL2AccountProofs+" 0x1234"))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		prefetcher, _, _, l2Cl, _ := createPrefetcher(t)
+		prefetcher.batchHintTimeout = 10 * time.Millisecond
+		l2Cl.ExpectAccountProofs(blockHash, accounts, nil, errors.New("boom"))
+		node := nodes[0]
+		hash := crypto.Keccak256Hash(node)
+		l2Cl.ExpectNodeByHash(hash, node, nil)
+		defer l2Cl.MockDebugClient.AssertExpectations(t)
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		// The retrying source keeps retrying until the batch hint times out, and the hint still succeeds
+		oracle.PrefetchAccounts(blockHash, accounts)
+		// The nodes are fetched one by one instead
+		require.EqualValues(t, node, oracle.NodeByHash(hash))
+	})
+
+	t.Run("KeepLastHint", func(t *testing.T) {
+		prefetcher, _, _, l2Cl, _ := createPrefetcher(t)
+		node := nodes[0]
+		hash := crypto.Keccak256Hash(node)
+		l2Cl.ExpectNodeByHash(hash, node, nil)
+		l2Cl.ExpectAccountProofs(blockHash, accounts, []*eth.AccountResult{}, nil)
+		defer l2Cl.MockDebugClient.AssertExpectations(t)
+
+		require.NoError(t, prefetcher.Hint(l2.StateNodeHint(hash).Hint()))
+		require.NoError(t, prefetcher.Hint(l2.AccountProofsHint{BlockHash: blockHash, Accounts: accounts}.Hint()))
+		// The node is fetched with the last hint that isn't a batch hint
+		pre, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(hash).PreimageKey())
+		require.NoError(t, err)
+		require.EqualValues(t, node, pre)
+	})
+}
+
 func TestBadHints(t *testing.T) {
 	prefetcher, _, _, _, kv := createPrefetcher(t)
 	hash := common.Hash{0xad}
@@ -378,7 +475,7 @@ func TestRetryWhenNotAvailableAfterPrefetching(t *testing.T) {
 	_, l1Source, l1BlobSource, l2Cl, kv := createPrefetcher(t)
 	putsToIgnore := 2
 	kv = &unreliableKvStore{KV: kv, putsToIgnore: putsToIgnore}
-	prefetcher := NewPrefetcher(testlog.Logger(t, log.LevelInfo), l1Source, l1BlobSource, l2Cl, kv)
+	prefetcher := NewPrefetcher(testlog.Logger(t, log.LevelInfo), l1Source, l1BlobSource, nil, l2Cl, kv)
 
 	// Expect one call for each ignored put, plus one more request for when the put succeeds
 	for i := 0; i < putsToIgnore+1; i++ {
@@ -419,6 +516,15 @@ func (m *l2Client) ExpectOutputByRoot(root common.Hash, output eth.Output, err e
 	m.Mock.On("OutputByRoot", root).Once().Return(output, &err)
 }
 
+func (m *l2Client) AccountProofs(ctx context.Context, blockHash common.Hash, accounts []common.Address) ([]*eth.AccountResult, error) {
+	out := m.Mock.MethodCalled("AccountProofs", blockHash, accounts)
+	return out[0].([]*eth.AccountResult), *out[1].(*error)
+}
+
+func (m *l2Client) ExpectAccountProofs(blockHash common.Hash, accounts []common.Address, proofs []*eth.AccountResult, err error) {
+	m.Mock.On("AccountProofs", blockHash, accounts).Once().Return(proofs, &err)
+}
+
 func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *testutils.MockBlobsFetcher, *l2Client, kvstore.KV) {
 	logger := testlog.Logger(t, log.LevelDebug)
 	kv := kvstore.NewMemKV()
@@ -430,7 +536,7 @@ func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *test
 		MockDebugClient: new(testutils.MockDebugClient),
 	}
 
-	prefetcher := NewPrefetcher(logger, l1Source, l1BlobSource, l2Source, kv)
+	prefetcher := NewPrefetcher(logger, l1Source, l1BlobSource, nil, l2Source, kv)
 	return prefetcher, l1Source, l1BlobSource, l2Source, kv
 }
 
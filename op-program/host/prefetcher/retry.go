@@ -142,6 +142,16 @@ func (s *RetryingL2Source) OutputByRoot(ctx context.Context, root common.Hash) (
 	})
 }
 
+func (s *RetryingL2Source) AccountProofs(ctx context.Context, blockHash common.Hash, accounts []common.Address) ([]*eth.AccountResult, error) {
+	return retry.Do(ctx, maxAttempts, s.strategy, func() ([]*eth.AccountResult, error) {
+		p, err := s.source.AccountProofs(ctx, blockHash, accounts)
+		if err != nil {
+			s.logger.Warn("Failed to fetch account proofs", "block", blockHash, "accounts", len(accounts), "err", err)
+		}
+		return p, err
+	})
+}
+
 func NewRetryingL2Source(logger log.Logger, source L2Source) *RetryingL2Source {
 	return &RetryingL2Source{
 		logger:   logger,
@@ -326,6 +326,30 @@ func TestRetryingL2Source(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, output, actualOutput)
 	})
+
+	accounts := []common.Address{{0x01}, {0x02}}
+	proofs := []*eth.AccountResult{{Address: accounts[0]}, {Address: accounts[1]}}
+	t.Run("AccountProofs Success", func(t *testing.T) {
+		source, mock := createL2Source(t)
+		defer mock.AssertExpectations(t)
+		mock.ExpectAccountProofs(hash, accounts, proofs, nil)
+
+		actual, err := source.AccountProofs(ctx, hash, accounts)
+		require.NoError(t, err)
+		require.Equal(t, proofs, actual)
+	})
+
+	t.Run("AccountProofs Error", func(t *testing.T) {
+		source, mock := createL2Source(t)
+		defer mock.AssertExpectations(t)
+		expectedErr := errors.New("boom")
+		mock.ExpectAccountProofs(hash, accounts, nil, expectedErr)
+		mock.ExpectAccountProofs(hash, accounts, proofs, nil)
+
+		actual, err := source.AccountProofs(ctx, hash, accounts)
+		require.NoError(t, err)
+		require.Equal(t, proofs, actual)
+	})
 }
 
 func createL2Source(t *testing.T) (*RetryingL2Source, *MockL2Source) {
@@ -361,6 +385,11 @@ func (m *MockL2Source) OutputByRoot(ctx context.Context, root common.Hash) (eth.
 	return out[0].(eth.Output), *out[1].(*error)
 }
 
+func (m *MockL2Source) AccountProofs(ctx context.Context, blockHash common.Hash, accounts []common.Address) ([]*eth.AccountResult, error) {
+	out := m.Mock.MethodCalled("AccountProofs", blockHash, accounts)
+	return out[0].([]*eth.AccountResult), *out[1].(*error)
+}
+
 func (m *MockL2Source) ExpectInfoAndTxsByHash(blockHash common.Hash, info eth.BlockInfo, txs types.Transactions, err error) {
 	m.Mock.On("InfoAndTxsByHash", blockHash).Once().Return(info, txs, &err)
 }
@@ -377,4 +406,8 @@ func (m *MockL2Source) ExpectOutputByRoot(root common.Hash, output eth.Output, e
 	m.Mock.On("OutputByRoot", root).Once().Return(output, &err)
 }
 
+func (m *MockL2Source) ExpectAccountProofs(blockHash common.Hash, accounts []common.Address, proofs []*eth.AccountResult, err error) {
+	m.Mock.On("AccountProofs", blockHash, accounts).Once().Return(proofs, &err)
+}
+
 var _ L2Source = (*MockL2Source)(nil)
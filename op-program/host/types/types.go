@@ -0,0 +1,24 @@
+package types
+
+// DataFormat is the format of the pre-image data storage of the host.
+type DataFormat string
+
+const (
+	// DataFormatFile stores every pre-image in its own file.
+	DataFormatFile DataFormat = "file"
+	// DataFormatPebble stores all pre-images in a single pebble database.
+	DataFormatPebble DataFormat = "pebble"
+	// DataFormatArchive reads pre-images from a single read-only archive file.
+	DataFormatArchive DataFormat = "archive"
+)
+
+var SupportedDataFormats = []DataFormat{DataFormatFile, DataFormatPebble, DataFormatArchive}
+
+func ValidDataFormat(format DataFormat) bool {
+	for _, f := range SupportedDataFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package altda
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The availability of an input is challenged and resolved with transactions to the DA challenge address of the rollup,
+// so all nodes decide from L1 data alone whether the input of a commitment is available.
+// Only successful transactions count, and a challenge must pay the challenge bond of the rollup.
+const (
+	// ChallengeTxVersion is the version byte of the calldata of a transaction that challenges the availability of
+	// the input of a commitment. It is followed by the encoded commitment.
+	ChallengeTxVersion = 0
+	// ResolveTxVersion is the version byte of the calldata of a transaction that resolves a challenge by posting
+	// the input to L1. It is followed by the input.
+	ResolveTxVersion = 1
+)
+
+var ErrInvalidChallengeTx = errors.New("invalid challenge transaction data")
+
+// ChallengeTxData returns the calldata of a transaction that challenges the availability of the input.
+func (c Keccak256Commitment) ChallengeTxData() []byte {
+	return append([]byte{ChallengeTxVersion}, c.Encode()...)
+}
+
+// ResolveTxData returns the calldata of a transaction that resolves a challenge of the availability of the input.
+func ResolveTxData(input []byte) []byte {
+	return append([]byte{ResolveTxVersion}, input...)
+}
+
+// DecodeChallengeTxData decodes the calldata of a transaction to the DA challenge address.
+// It returns the commitment that is challenged or resolved, and the input if the challenge is resolved.
+func DecodeChallengeTxData(data []byte) (comm Keccak256Commitment, input []byte, err error) {
+	if len(data) == 0 {
+		return Keccak256Commitment{}, nil, fmt.Errorf("%w: empty", ErrInvalidChallengeTx)
+	}
+	switch data[0] {
+	case ChallengeTxVersion:
+		comm, err = DecodeKeccak256Commitment(data[1:])
+		return comm, nil, err
+	case ResolveTxVersion:
+		input = data[1:]
+		return NewKeccak256Commitment(input), input, nil
+	default:
+		return Keccak256Commitment{}, nil, fmt.Errorf("%w: unknown version %d", ErrInvalidChallengeTx, data[0])
+	}
+}
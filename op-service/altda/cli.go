@@ -0,0 +1,62 @@
+package altda
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/urfave/cli/v2"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+)
+
+const (
+	EnabledFlagName  = "altda.enabled"
+	DAServerFlagName = "altda.da-server"
+)
+
+var ErrNoDAServer = errors.New("alt-DA is enabled but no DA server is configured")
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    EnabledFlagName,
+			Usage:   "Enable the alternative data-availability mode, where batch data is stored on a DA server and only a commitment to it on L1",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "ALTDA_ENABLED"),
+		},
+		&cli.StringFlag{
+			Name:    DAServerFlagName,
+			Usage:   "HTTP address of the DA server",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "ALTDA_DA_SERVER"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	Enabled     bool
+	DAServerURL string
+}
+
+func (c CLIConfig) Check() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.DAServerURL == "" {
+		return ErrNoDAServer
+	}
+	if _, err := url.ParseRequestURI(c.DAServerURL); err != nil {
+		return fmt.Errorf("invalid DA server URL %q: %w", c.DAServerURL, err)
+	}
+	return nil
+}
+
+func (c CLIConfig) NewDAClient() *DAClient {
+	return NewDAClient(c.DAServerURL)
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		Enabled:     ctx.Bool(EnabledFlagName),
+		DAServerURL: ctx.String(DAServerFlagName),
+	}
+}
@@ -0,0 +1,96 @@
+package altda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout is the timeout of a single request to the DA server.
+const DefaultTimeout = 30 * time.Second
+
+// MaxInputSize is the maximum size of an input. Inputs are the data of a batcher transaction,
+// so they are limited by the maximum size of an L1 transaction, which is well below this.
+const MaxInputSize = 1 << 20
+
+var (
+	// ErrNotFound is returned when the DA server does not have the input of a commitment.
+	ErrNotFound = errors.New("not found")
+	// ErrInputTooLarge is returned when an input is larger than MaxInputSize.
+	ErrInputTooLarge = errors.New("input too large")
+)
+
+// DAClient stores and retrieves inputs on a DA server, by their commitment.
+// Inputs are retrieved with GET <url>/get/<commitment>, and stored with PUT <url>/put/<commitment>,
+// where the commitment is hex encoded with Keccak256Commitment.String.
+type DAClient struct {
+	url    string
+	client *http.Client
+}
+
+func NewDAClient(url string) *DAClient {
+	return &DAClient{
+		url:    url,
+		client: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// GetInput retrieves the input of the commitment from the DA server, and verifies it matches the commitment.
+func (c *DAClient) GetInput(ctx context.Context, comm Keccak256Commitment) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/get/%s", c.url, comm), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get input %s: unexpected status %s", comm, resp.Status)
+	}
+	// read one more byte than allowed, to detect inputs that are too large without reading all of them
+	input, err := io.ReadAll(io.LimitReader(resp.Body, MaxInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input %s: %w", comm, err)
+	}
+	if len(input) > MaxInputSize {
+		return nil, fmt.Errorf("input %s: %w", comm, ErrInputTooLarge)
+	}
+	if err := comm.Verify(input); err != nil {
+		return nil, fmt.Errorf("invalid input %s: %w", comm, err)
+	}
+	return input, nil
+}
+
+// SetInput stores the input on the DA server, and returns its commitment.
+func (c *DAClient) SetInput(ctx context.Context, input []byte) (Keccak256Commitment, error) {
+	if len(input) == 0 {
+		return Keccak256Commitment{}, errors.New("input is empty")
+	}
+	if len(input) > MaxInputSize {
+		return Keccak256Commitment{}, fmt.Errorf("input of %d bytes: %w", len(input), ErrInputTooLarge)
+	}
+	comm := NewKeccak256Commitment(input)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/put/%s", c.url, comm), bytes.NewReader(input))
+	if err != nil {
+		return Keccak256Commitment{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Keccak256Commitment{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Keccak256Commitment{}, fmt.Errorf("failed to store input %s: unexpected status %s", comm, resp.Status)
+	}
+	return comm, nil
+}
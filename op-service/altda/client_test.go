@@ -0,0 +1,103 @@
+package altda
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitmentEncoding(t *testing.T) {
+	comm := NewKeccak256Commitment([]byte("hello"))
+	decoded, err := DecodeKeccak256Commitment(comm.Encode())
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+	require.Equal(t, byte(TxDataVersion1), comm.TxData()[0])
+	require.Equal(t, comm.Encode(), comm.TxData()[1:])
+
+	_, err = DecodeKeccak256Commitment(comm.Encode()[1:])
+	require.ErrorIs(t, err, ErrInvalidCommitment)
+	_, err = DecodeKeccak256Commitment(append([]byte{1}, comm[:]...))
+	require.ErrorIs(t, err, ErrInvalidCommitment)
+
+	require.NoError(t, comm.Verify([]byte("hello")))
+	require.ErrorIs(t, comm.Verify([]byte("world")), ErrCommitmentMismatch)
+}
+
+func TestChallengeTxEncoding(t *testing.T) {
+	input := []byte("hello")
+	comm := NewKeccak256Commitment(input)
+
+	decoded, resolved, err := DecodeChallengeTxData(comm.ChallengeTxData())
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+	require.Nil(t, resolved)
+
+	decoded, resolved, err = DecodeChallengeTxData(ResolveTxData(input))
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+	require.Equal(t, input, resolved)
+
+	_, _, err = DecodeChallengeTxData(nil)
+	require.ErrorIs(t, err, ErrInvalidChallengeTx)
+	_, _, err = DecodeChallengeTxData([]byte{2, 0xaa})
+	require.ErrorIs(t, err, ErrInvalidChallengeTx)
+	_, _, err = DecodeChallengeTxData(comm.ChallengeTxData()[:10])
+	require.ErrorIs(t, err, ErrInvalidCommitment)
+}
+
+func TestDAClient(t *testing.T) {
+	server := NewMemDAServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := NewDAClient(ts.URL)
+	ctx := context.Background()
+
+	input := []byte("some batch data")
+	comm, err := client.SetInput(ctx, input)
+	require.NoError(t, err)
+	require.Equal(t, NewKeccak256Commitment(input), comm)
+
+	actual, err := client.GetInput(ctx, comm)
+	require.NoError(t, err)
+	require.Equal(t, input, actual)
+
+	server.Delete(comm)
+	_, err = client.GetInput(ctx, comm)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.SetInput(ctx, nil)
+	require.ErrorContains(t, err, "empty")
+	_, err = client.SetInput(ctx, make([]byte, MaxInputSize+1))
+	require.ErrorIs(t, err, ErrInputTooLarge)
+}
+
+func TestDAClientRejectsTooLargeInput(t *testing.T) {
+	input := make([]byte, MaxInputSize+1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(input)
+	}))
+	defer ts.Close()
+	client := NewDAClient(ts.URL)
+	_, err := client.GetInput(context.Background(), NewKeccak256Commitment(input))
+	require.ErrorIs(t, err, ErrInputTooLarge)
+}
+
+func TestDAClientRejectsMismatchingInput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not the input"))
+	}))
+	defer ts.Close()
+	client := NewDAClient(ts.URL)
+	_, err := client.GetInput(context.Background(), NewKeccak256Commitment([]byte("input")))
+	require.ErrorIs(t, err, ErrCommitmentMismatch)
+}
+
+func TestCLIConfigCheck(t *testing.T) {
+	require.NoError(t, CLIConfig{}.Check())
+	require.ErrorIs(t, CLIConfig{Enabled: true}.Check(), ErrNoDAServer)
+	require.ErrorContains(t, CLIConfig{Enabled: true, DAServerURL: "foo"}.Check(), "invalid DA server URL")
+	require.NoError(t, CLIConfig{Enabled: true, DAServerURL: "http://localhost:3100"}.Check())
+}
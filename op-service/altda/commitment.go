@@ -0,0 +1,68 @@
+package altda
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TxDataVersion1 is the version byte of batcher transaction data that holds a commitment to an input
+// stored on a DA server, instead of the frames themselves.
+const TxDataVersion1 = 1
+
+// CommitmentType identifies the scheme used to commit to an input.
+type CommitmentType byte
+
+const (
+	// Keccak256CommitmentType commits to an input with its keccak256 hash.
+	Keccak256CommitmentType CommitmentType = 0
+)
+
+var (
+	ErrInvalidCommitment  = errors.New("invalid commitment")
+	ErrCommitmentMismatch = errors.New("input does not match the commitment")
+)
+
+// Keccak256Commitment is a commitment to an input by its keccak256 hash.
+type Keccak256Commitment common.Hash
+
+// NewKeccak256Commitment returns the commitment to the given input.
+func NewKeccak256Commitment(input []byte) Keccak256Commitment {
+	return Keccak256Commitment(crypto.Keccak256Hash(input))
+}
+
+// DecodeKeccak256Commitment decodes a commitment, encoded with Encode.
+func DecodeKeccak256Commitment(data []byte) (Keccak256Commitment, error) {
+	if len(data) != 1+common.HashLength {
+		return Keccak256Commitment{}, fmt.Errorf("%w: unexpected length %d", ErrInvalidCommitment, len(data))
+	}
+	if CommitmentType(data[0]) != Keccak256CommitmentType {
+		return Keccak256Commitment{}, fmt.Errorf("%w: unknown commitment type %d", ErrInvalidCommitment, data[0])
+	}
+	return Keccak256Commitment(data[1:]), nil
+}
+
+// Encode returns the commitment prefixed with its type, which identifies the input on the DA server.
+func (c Keccak256Commitment) Encode() []byte {
+	return append([]byte{byte(Keccak256CommitmentType)}, c[:]...)
+}
+
+// TxData returns the commitment as batcher transaction data, prefixed with TxDataVersion1.
+func (c Keccak256Commitment) TxData() []byte {
+	return append([]byte{TxDataVersion1}, c.Encode()...)
+}
+
+// Verify returns ErrCommitmentMismatch if the input is not the committed input.
+func (c Keccak256Commitment) Verify(input []byte) error {
+	if NewKeccak256Commitment(input) != c {
+		return ErrCommitmentMismatch
+	}
+	return nil
+}
+
+func (c Keccak256Commitment) String() string {
+	return hexutil.Encode(c.Encode())
+}
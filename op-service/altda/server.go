@@ -0,0 +1,85 @@
+package altda
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MemDAServer is a DA server that keeps the inputs in memory, for tests and local devnets.
+// It implements the DAClient protocol as an http.Handler.
+type MemDAServer struct {
+	mu     sync.RWMutex
+	inputs map[Keccak256Commitment][]byte
+}
+
+func NewMemDAServer() *MemDAServer {
+	return &MemDAServer{inputs: make(map[Keccak256Commitment][]byte)}
+}
+
+func (s *MemDAServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/get/"):
+		comm, ok := parseCommitment(w, strings.TrimPrefix(r.URL.Path, "/get/"))
+		if !ok {
+			return
+		}
+		input, ok := s.Get(comm)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(input)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/put/"):
+		comm, ok := parseCommitment(w, strings.TrimPrefix(r.URL.Path, "/put/"))
+		if !ok {
+			return
+		}
+		input, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := comm.Verify(input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.inputs[comm] = input
+		s.mu.Unlock()
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Get returns the stored input of the commitment, if any.
+func (s *MemDAServer) Get(comm Keccak256Commitment) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	input, ok := s.inputs[comm]
+	return input, ok
+}
+
+// Delete removes the input of the commitment, to simulate data that is not available.
+func (s *MemDAServer) Delete(comm Keccak256Commitment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inputs, comm)
+}
+
+func parseCommitment(w http.ResponseWriter, s string) (Keccak256Commitment, bool) {
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return Keccak256Commitment{}, false
+	}
+	comm, err := DecodeKeccak256Commitment(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return Keccak256Commitment{}, false
+	}
+	return comm, true
+}
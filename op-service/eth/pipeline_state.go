@@ -0,0 +1,74 @@
+package eth
+
+// DerivationPipelineState is a snapshot of the state of the stages of the derivation pipeline.
+type DerivationPipelineState struct {
+	// Resetting is true while the stages of the pipeline are being reset, and their state is not consistent.
+	Resetting bool `json:"resetting"`
+
+	L1Traversal L1TraversalState `json:"l1_traversal"`
+	FrameQueue  FrameQueueState  `json:"frame_queue"`
+	ChannelBank ChannelBankState `json:"channel_bank"`
+	BatchQueue  BatchQueueState  `json:"batch_queue"`
+}
+
+// L1TraversalState is the state of the L1 traversal stage, which determines the origin of the pipeline.
+type L1TraversalState struct {
+	Origin L1BlockRef `json:"origin"`
+	// Done is true if the data of the origin was passed on to the next stage.
+	Done bool `json:"done"`
+}
+
+// FrameQueueState lists the frames that were parsed from L1 data, but not added to the channel bank yet.
+type FrameQueueState struct {
+	Frames []FrameState `json:"frames"`
+}
+
+type FrameState struct {
+	ChannelID   string `json:"channel_id"`
+	FrameNumber uint16 `json:"frame_number"`
+	DataLength  int    `json:"data_length"`
+	IsLast      bool   `json:"is_last"`
+}
+
+// ChannelBankState lists the channels in the channel bank, in the order they are read in.
+type ChannelBankState struct {
+	Channels []ChannelState `json:"channels"`
+}
+
+type ChannelState struct {
+	ID        string     `json:"id"`
+	OpenBlock L1BlockRef `json:"open_block"`
+	// TimeoutBlock is the last L1 block number at which frames are still added to the channel.
+	TimeoutBlock uint64 `json:"timeout_block"`
+	TimedOut     bool   `json:"timed_out"`
+	// Size is the estimated memory size of the channel, which counts towards the size limit of the channel bank.
+	Size       uint64 `json:"size"`
+	FrameCount int    `json:"frame_count"`
+	Closed     bool   `json:"closed"`
+	Ready      bool   `json:"ready"`
+}
+
+// BatchQueueState lists the batches buffered by the batch queue, and the outcome of the most recent batch checks.
+type BatchQueueState struct {
+	// L1Blocks are the L1 blocks that the epochs of the next batches are checked against.
+	L1Blocks []L1BlockRef `json:"l1_blocks"`
+	// Batches are the buffered batches, with the validity of the last check of each batch.
+	Batches []BatchState `json:"batches"`
+	// PendingSpanBlocks is the number of remaining L2 blocks of the span batch that is being processed.
+	PendingSpanBlocks int `json:"pending_span_blocks"`
+	// RecentChecks are the most recent batch checks, including those of batches that were dropped or accepted.
+	RecentChecks []BatchState `json:"recent_checks"`
+}
+
+type BatchState struct {
+	Type             string     `json:"type"`
+	Timestamp        uint64     `json:"timestamp"`
+	Epoch            uint64     `json:"epoch"`
+	L1InclusionBlock L1BlockRef `json:"l1_inclusion_block"`
+	// Parent is the L2 safe head that the batch was last checked against.
+	Parent BlockID `json:"parent"`
+	// Validity is the outcome of the last check: accept, drop, future or undecided. Empty if the batch was not checked yet.
+	Validity string `json:"validity,omitempty"`
+	// Reason is why the batch was dropped, if the validity is drop.
+	Reason string `json:"reason,omitempty"`
+}
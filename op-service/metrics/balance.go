@@ -16,8 +16,8 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 )
 
-// weiToEther divides the wei value by 10^18 to get a number in ether as a float64
-func weiToEther(wei *big.Int) float64 {
+// WeiToEther divides the wei value by 10^18 to get a number in ether as a float64
+func WeiToEther(wei *big.Int) float64 {
 	num := new(big.Rat).SetInt(wei)
 	denom := big.NewRat(params.Ether, 1)
 	num = num.Quo(num, denom)
@@ -42,7 +42,7 @@ func LaunchBalanceMetrics(log log.Logger, r *prometheus.Registry, ns string, cli
 			log.Warn("failed to get balance of account", "err", err, "address", account)
 			return
 		}
-		bal := weiToEther(bigBal)
+		bal := WeiToEther(bigBal)
 		balanceGuage.Set(bal)
 	}, func() error {
 		log.Info("balance metrics shutting down")
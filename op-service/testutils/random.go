@@ -329,6 +329,8 @@ func RandomOutputResponse(rng *rand.Rand) *eth.OutputResponse {
 			SafeL2:             RandomL2BlockRef(rng),
 			FinalizedL2:        RandomL2BlockRef(rng),
 			PendingSafeL2:      RandomL2BlockRef(rng),
+			CrossUnsafeL2:      RandomL2BlockRef(rng),
+			CrossSafeL2:        RandomL2BlockRef(rng),
 		},
 	}
 }